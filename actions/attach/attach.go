@@ -4,14 +4,20 @@ import (
 	"fmt"
 
 	"github.com/launchrctl/launchr/pkg/action"
+
 	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 // AttachResult is the structured result of component:attach.
 type AttachResult struct {
-	Component string `json:"component"`
-	Chassis   string `json:"chassis"`
-	Attached  bool   `json:"attached"`
+	Component   string                `json:"component"`
+	Chassis     string                `json:"chassis"`
+	Attached    bool                  `json:"attached"`
+	Reordered   bool                  `json:"reordered,omitempty"`
+	OrderIssues []playbook.OrderIssue `json:"order_issues,omitempty"`
+	Warnings    warning.List          `json:"warnings,omitempty"`
 }
 
 // Attach implements component:attach command
@@ -22,6 +28,7 @@ type Attach struct {
 	Component string
 	Chassis   string
 	Source    string
+	Reorder   bool
 
 	result *AttachResult
 }
@@ -49,17 +56,102 @@ func (a *Attach) Execute() error {
 	}
 
 	plays, attached := playbook.AddRole(plays, a.Component, a.Chassis)
-	if !attached {
-		a.result = &AttachResult{Component: a.Component, Chassis: a.Chassis, Attached: false}
-		a.Term().Warning().Printfln("Component %s already attached to %s", a.Component, a.Chassis)
-		return nil
+	a.result = &AttachResult{Component: a.Component, Chassis: a.Chassis, Attached: attached}
+
+	// Ordering only produces warnings, it doesn't stop the attach when dependencies can't be read.
+	requires, errOrder := a.requires()
+	if errOrder == nil {
+		if a.Reorder {
+			a.result.Reordered = playbook.Reorder(plays, a.Chassis, requires)
+		}
+		runPlays, target := a.runOrder(playbookPath, plays)
+		a.result.OrderIssues = playbook.CheckOrder(runPlays, target, requires)
 	}
 
-	if err := playbook.Save(playbookPath, plays); err != nil {
-		return err
+	if attached || a.result.Reordered {
+		if err = playbook.Save(playbookPath, plays); err != nil {
+			return err
+		}
+	}
+
+	if attached {
+		a.Term().Success().Printfln("Attached %s to %s", a.Component, a.Chassis)
+	} else {
+		a.Term().Warning().Println(a.result.Warnings.Add(warning.Unchanged, a.Component,
+			"Component %s already attached to %s", a.Component, a.Chassis))
+	}
+
+	if errOrder != nil {
+		a.Term().Warning().Println(a.result.Warnings.Add(warning.Partial, a.Component,
+			"Roles order of %s isn't checked, dependencies can't be read: %s", a.Chassis, errOrder))
+	}
+
+	if a.result.Reordered {
+		a.Term().Success().Printfln("Reordered roles of %s to follow dependencies", a.Chassis)
+	}
+
+	for _, issue := range a.result.OrderIssues {
+		a.Term().Warning().Println(a.result.Warnings.Add(warning.Order, issue.Component,
+			"%s requires %s, attached after it to %s", issue.Component, issue.Dependency, issue.Hosts))
+	}
+	if len(a.result.OrderIssues) > 0 && !a.Reorder {
+		a.Term().Info().Println("Use --reorder to fix roles order")
 	}
 
-	a.result = &AttachResult{Component: a.Component, Chassis: a.Chassis, Attached: true}
-	a.Term().Success().Printfln("Attached %s to %s", a.Component, a.Chassis)
 	return nil
 }
+
+// runOrder returns plays of the playbooks imported by the platform playbook in run order, with the plays of the layer
+// playbook being attached to, and the position of the chassis play among them. Without platform playbook importing
+// the layer playbook, only plays of the layer playbook are returned.
+func (a *Attach) runOrder(playbookPath string, plays []playbook.Play) ([]playbook.Play, int) {
+	paths, err := playbook.PlatformPlaybooks(a.Source)
+	if err != nil {
+		a.Log().Debug("platform playbook unavailable, checking order in the layer playbook only", "error", err)
+		return plays, playbook.FindPlay(plays, a.Chassis)
+	}
+
+	var all []playbook.Play
+	target := -1
+	for _, path := range paths {
+		if path == playbookPath {
+			target = len(all) + playbook.FindPlay(plays, a.Chassis)
+			all = append(all, plays...)
+			continue
+		}
+
+		imported, errLoad := playbook.Load(path)
+		if errLoad != nil {
+			a.Term().Warning().Println(a.result.Warnings.Add(warning.Partial, "",
+				"Roles of %s aren't checked against dependencies: %s", path, errLoad))
+			continue
+		}
+		all = append(all, imported...)
+	}
+
+	if target < 0 {
+		a.Log().Debug("layer playbook isn't imported by the platform playbook, checking order in it only", "path", playbookPath)
+		return plays, playbook.FindPlay(plays, a.Chassis)
+	}
+
+	return all, target
+}
+
+// requires returns a lookup of transitive component dependencies from the source inventory.
+func (a *Attach) requires() (func(string) map[string]bool, error) {
+	inv, err := sync.NewInventory(a.Source, a.Log())
+	if err != nil {
+		return nil, err
+	}
+
+	cache := make(map[string]map[string]bool)
+	return func(name string) map[string]bool {
+		if deps, ok := cache[name]; ok {
+			return deps
+		}
+
+		deps := inv.GetRequiresComponents(name, -1)
+		cache[name] = deps
+		return deps
+	}, nil
+}
@@ -0,0 +1,55 @@
+package attach_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plasmash/plasmactl-component/actions/attach"
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+func TestAttachUnreadableDependencies(t *testing.T) {
+	p := testenv.NewPlatform(t)
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "dependencies.yaml"), "dependencies: [\n")
+
+	att := &attach.Attach{Component: testenv.Dashboards, Chassis: testenv.Chassis, Source: ".", Reorder: true}
+	if err := testenv.Run(t, att); err != nil {
+		t.Fatalf("expected attach despite unreadable dependencies, got %v", err)
+	}
+
+	res := att.Result().(*attach.AttachResult)
+	if !res.Attached || res.Reordered || len(res.Warnings) != 1 || res.Warnings[0].Code != warning.Partial {
+		t.Errorf("expected attached with a partial warning about order, got %+v", res)
+	}
+	if !strings.Contains(p.ReadFile(filepath.Join("interaction", "interaction.yaml")), testenv.Dashboards) {
+		t.Error("expected component in playbook after attach")
+	}
+}
+
+func TestAttachAncestorPlays(t *testing.T) {
+	p := testenv.NewPlatform(t)
+	p.AddPlaybook("foundation", playbook.Play{Hosts: "platform", Roles: []playbook.Role{{Name: testenv.Auth}}})
+	platformPlaybook := filepath.Join("platform", "platform.yaml")
+	p.WriteFile(platformPlaybook, "- import_playbook: ../interaction/interaction.yaml\n- import_playbook: ../foundation/foundation.yaml\n")
+
+	att := &attach.Attach{Component: testenv.Dashboards, Chassis: testenv.Chassis, Source: ".", Reorder: true}
+	if err := testenv.Run(t, att); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+	issues := att.Result().(*attach.AttachResult).OrderIssues
+	if len(issues) != 1 || issues[0].Component != testenv.Dashboards || issues[0].Dependency != testenv.Auth || issues[0].Hosts != "platform" {
+		t.Fatalf("expected %s attached to a later ancestor play reported, got %+v", testenv.Auth, issues)
+	}
+
+	p.WriteFile(platformPlaybook, "- import_playbook: ../foundation/foundation.yaml\n- import_playbook: ../interaction/interaction.yaml\n")
+	att = &attach.Attach{Component: testenv.Dashboards, Chassis: testenv.Chassis, Source: "."}
+	if err := testenv.Run(t, att); err != nil {
+		t.Fatalf("attach again: %v", err)
+	}
+	if issues = att.Result().(*attach.AttachResult).OrderIssues; len(issues) != 0 {
+		t.Errorf("expected no issue with %s attached to an earlier ancestor play, got %+v", testenv.Auth, issues)
+	}
+}
@@ -1,12 +1,23 @@
 package bump
 
 import (
+	"crypto/ed25519"
+	"fmt"
+	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-component/internal/notify"
+	"github.com/plasmash/plasmactl-component/internal/provenance"
 	"github.com/plasmash/plasmactl-component/internal/repository"
 	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 var unversionedFiles = map[string]struct{}{
@@ -14,17 +25,83 @@ var unversionedFiles = map[string]struct{}{
 	"README.svg": {},
 }
 
+// Reasons of changed files not triggering a bump.
+const (
+	skipUnversioned = "unversioned"
+	skipNoComponent = "no-component"
+	skipActions     = "actions"
+	skipFrozen      = "frozen"
+)
+
+// Granularity is how versions are assigned to components changed by the bumped commits.
+type Granularity string
+
+// Granularities of bumps.
+const (
+	// GranularityCommit versions each component with the latest commit changing it.
+	GranularityCommit Granularity = "commit"
+	// GranularityRun versions all components with the latest commit of the range, a single version per bump.
+	GranularityRun Granularity = "run"
+)
+
+// Validate checks the granularity is commit or run. Empty granularity is commit.
+func (g Granularity) Validate() error {
+	switch g {
+	case "", GranularityCommit, GranularityRun:
+		return nil
+	default:
+		return fmt.Errorf("unknown granularity %q (expected: %s or %s)", g, GranularityCommit, GranularityRun)
+	}
+}
+
 // BumpedComponent represents a single component version change.
 type BumpedComponent struct {
 	Name       string `json:"name"`
 	OldVersion string `json:"old_version"`
 	NewVersion string `json:"new_version"`
+	Scheme     string `json:"scheme,omitempty"`
+	Signed     bool   `json:"signed,omitempty"`
+	// Commit is the latest commit changing the component, empty for staged or uncommitted changes.
+	Commit string `json:"commit,omitempty"`
+	// Files are changed files of the component triggering the bump.
+	Files []string `json:"files,omitempty"`
+	// PropagatedFrom is the bumped dependency of a component bumped in cascade.
+	PropagatedFrom string `json:"propagated_from,omitempty"`
+}
+
+// SkippedFile is a changed file which doesn't trigger a bump, with the reason: unversioned (non-versioned
+// or matched by the ignore file), no-component (outside a component), actions (component actions) or frozen
+// (component marked frozen or with manual propagation).
+type SkippedFile struct {
+	File   string `json:"file"`
+	Reason string `json:"reason"`
+}
+
+// RenamedComponent is a component renamed or moved by a bumped commit, its files now belonging to another
+// component. The new component is bumped, the mapping is informational.
+type RenamedComponent struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Commit string `json:"commit,omitempty"`
 }
 
 // BumpResult is the structured result of component:bump.
 type BumpResult struct {
-	Components []BumpedComponent `json:"components"`
-	DryRun     bool              `json:"dry_run"`
+	Components []BumpedComponent  `json:"components"`
+	Skipped    []SkippedFile      `json:"skipped,omitempty"`
+	Renamed    []RenamedComponent `json:"renamed,omitempty"`
+	Warnings   warning.List       `json:"warnings,omitempty"`
+	DryRun     bool               `json:"dry_run"`
+}
+
+// Changes returns bumped components as changes to notify their owners of.
+func (r *BumpResult) Changes() []notify.Change {
+	changes := make([]notify.Change, 0, len(r.Components))
+	for _, c := range r.Components {
+		changes = append(changes, notify.Change{Component: c.Name, OldVersion: c.OldVersion, NewVersion: c.NewVersion, Action: notify.ActionBumped})
+	}
+
+	return changes
 }
 
 // Bump is an action representing versions update of committed components.
@@ -32,10 +109,51 @@ type Bump struct {
 	action.WithLogger
 	action.WithTerm
 
+	// Streams receive the output of hooks, the process streams if nil.
+	Streams launchr.Streams
+
 	Last   bool
 	DryRun bool
-
-	result *BumpResult
+	// Staged bumps components of changes staged in the index and stages updated files instead of committing,
+	// e.g. in a pre-commit hook.
+	Staged bool
+	// Amend recomputes the components of the bump commit at HEAD, including uncommitted changes, and amends it
+	// instead of creating a second bump commit.
+	Amend bool
+	// Cascade also bumps components depending on bumped ones with a propagated version.
+	Cascade bool
+	// Since bumps components changed since the merge base of HEAD and the revision, e.g. a branch or tag.
+	Since string
+	// SinceDate bumps components changed in commits since the date, as YYYY-MM-DD.
+	SinceDate string
+	// Scheme is the versioning scheme of bumped components, stored in their meta. Components keep their own scheme,
+	// hash by default, if empty.
+	Scheme string
+	// Level is the part of semantic versions to increment, patch by default.
+	Level sync.VersionLevel
+	// Granularity assigns versions per commit, by default, or a single version to all bumped components.
+	Granularity Granularity
+	// VersionFormat is the propagated version format, its base part is incremented by semver scheme.
+	VersionFormat sync.VersionFormat
+	// SignKey is the PEM private key signing content hashes of bumped components, if set.
+	SignKey string
+	// CommitMessage customizes the bump commit message and signing.
+	CommitMessage repository.BumpCommit
+	// Hooks are commands run before and after the bump commit.
+	Hooks Hooks
+	// ExtraKinds are component kinds registered by plugins, cascaded as the built-in ones.
+	ExtraKinds []string
+
+	bumper     *repository.Bumper
+	ignore     gitignore.Matcher
+	key        ed25519.PrivateKey
+	signatures []string
+	metas      []string
+	triggers   map[string]*BumpedComponent
+	order      []string
+	// amended are files of the amended bump commit with their content, restored after a dry run.
+	amended map[string][]byte
+	result  *BumpResult
 }
 
 // Result returns the structured result for JSON output.
@@ -43,89 +161,345 @@ func (b *Bump) Result() any {
 	return b.result
 }
 
-func (b *Bump) printMemo() {
+func (b *Bump) printMemo(ignored []string) {
 	b.Log().Info("List of non-versioned files:")
 	for k := range unversionedFiles {
 		b.Log().Info(k)
 	}
+	for _, p := range ignored {
+		b.Log().Info(p, "source", BumpIgnoreFile)
+	}
 }
 
 // Execute the bump action to update committed components.
 func (b *Bump) Execute() error {
+	components, err := b.Collect()
+	if err != nil {
+		return err
+	}
+
+	if len(components) == 0 {
+		b.Term().Info().Println("No component to update")
+		// The amended bump commit is kept as is.
+		return restoreFiles(b.amended)
+	}
+
+	err = b.Update(components)
+	if b.DryRun && b.amended != nil {
+		if errRestore := restoreFiles(b.amended); errRestore != nil && err == nil {
+			err = errRestore
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err = b.runHooks(hookPre, b.Hooks.Pre, ""); err != nil {
+		return err
+	}
+	if b.DryRun {
+		return b.runHooks(hookPost, b.Hooks.Post, "")
+	}
+
+	switch {
+	case b.Staged:
+		err = b.Stage()
+	case b.Amend:
+		err = b.AmendCommit()
+	default:
+		err = b.Commit()
+	}
+	if err != nil {
+		return err
+	}
+
+	var commit string
+	if !b.Staged {
+		commit = b.bumpCommit()
+	}
+
+	return b.runHooks(hookPost, b.Hooks.Post, commit)
+}
+
+// Collect returns components changed since the last bump, grouped by the version to set.
+func (b *Bump) Collect() (map[string]map[string]*sync.Component, error) {
 	b.result = &BumpResult{DryRun: b.DryRun}
+	if b.Scheme != "" {
+		if _, err := sync.GetVersionScheme(b.Scheme); err != nil {
+			return nil, err
+		}
+	}
+	if err := b.Level.Validate(); err != nil {
+		return nil, err
+	}
+	if err := b.Granularity.Validate(); err != nil {
+		return nil, err
+	}
+
+	if b.SignKey != "" {
+		key, err := provenance.LoadPrivateKey(b.SignKey)
+		if err != nil {
+			return nil, err
+		}
+		b.key = key
+	}
+
 	b.Term().Info().Println("Bumping updated components...")
-	b.printMemo()
+	ignore, ignored, err := loadBumpIgnore(BumpIgnoreFile)
+	if err != nil {
+		return nil, err
+	}
+	b.ignore = ignore
+	b.printMemo(ignored)
 
 	bumper, err := repository.NewBumper()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	b.bumper = bumper
+
+	if err = bumper.SetCommitMessage(b.CommitMessage); err != nil {
+		return nil, err
+	}
+	if b.CommitMessage.Sign {
+		if err = bumper.EnableSigning(); err != nil {
+			return nil, err
+		}
+	}
+
+	if b.Staged {
+		return b.collectStaged()
+	}
+	if b.Amend {
+		return b.collectAmend()
 	}
 
 	if bumper.IsOwnCommit() {
 		b.Term().Info().Println("skipping bump, as the latest commit is already by the bumper tool")
-		return nil
+		return nil, nil
 	}
 
-	commits, err := bumper.GetCommits(b.Last)
+	rng, err := b.commitRange()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	components := b.collectComponents(commits)
-	if len(components) == 0 {
-		b.Term().Info().Println("No component to update")
-		return nil
+	commits, err := bumper.GetCommitsInRange(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.collectComponents(commits), nil
+}
+
+// collectStaged returns components of changes staged in the index, versioned with the hash of the staged content.
+func (b *Bump) collectStaged() (map[string]map[string]*sync.Component, error) {
+	if b.Amend || b.Last || b.Since != "" || b.SinceDate != "" {
+		return nil, fmt.Errorf("staged changes can't be combined with amend, last commit, since revision or date")
 	}
 
-	err = b.updateComponents(components)
+	staged, err := b.bumper.GetStagedChanges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read staged changes > %w", err)
+	}
+	if staged == nil {
+		return nil, nil
+	}
+
+	return b.collectComponents([]*repository.Commit{staged}), nil
+}
+
+// collectAmend returns components of the bump commit at HEAD recomputed with uncommitted changes. Commits since
+// the previous bump are versioned as usual, uncommitted changes with the hash of their content. Files of the bump
+// commit unchanged since are restored to their previous content, so versions are bumped once.
+func (b *Bump) collectAmend() (map[string]map[string]*sync.Component, error) {
+	if b.Last || b.Since != "" || b.SinceDate != "" {
+		return nil, fmt.Errorf("amend can't be combined with last commit, since revision or date")
+	}
+
+	bump, err := b.bumper.GetAmendedBump()
+	if err != nil {
+		return nil, err
+	}
+
+	uncommitted, err := b.bumper.GetWorktreeChanges()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read uncommitted changes > %w", err)
+	}
+
+	commits := bump.Commits
+	modified := make(map[string]bool)
+	if uncommitted != nil {
+		commits = append([]*repository.Commit{uncommitted}, commits...)
+		for _, path := range uncommitted.Files {
+			modified[path] = true
+		}
+	}
+
+	previous := make(map[string][]byte, len(bump.Files))
+	b.amended = make(map[string][]byte, len(bump.Files))
+	for name, data := range bump.Files {
+		if modified[name] {
+			b.Log().Warn(b.result.Warnings.Add(warning.Skipped, name, "File %s of the bump commit changed since, it's kept as is", name))
+			continue
+		}
+
+		path := filepath.FromSlash(name)
+		current, errRead := os.ReadFile(path)
+		if errRead != nil && !os.IsNotExist(errRead) {
+			return nil, errRead
+		}
+		b.amended[path] = current
+		previous[path] = data
+	}
+	if err = restoreFiles(previous); err != nil {
+		return nil, err
+	}
+
+	return b.collectComponents(commits), nil
+}
+
+// restoreFiles writes the content of files, removing files without content.
+func restoreFiles(files map[string][]byte) error {
+	for path, data := range files {
+		if data == nil {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil { //nolint:gosec
+			return fmt.Errorf("failed to restore %s > %w", path, err)
+		}
+	}
+
+	return nil
+}
+
+// commitRange returns the range of commits to bump, since the latest bump by default.
+func (b *Bump) commitRange() (repository.CommitRange, error) {
+	rng := repository.CommitRange{Last: b.Last, Since: b.Since}
+	if b.SinceDate != "" {
+		date, err := time.ParseInLocation(time.DateOnly, b.SinceDate, time.Local)
+		if err != nil {
+			return rng, fmt.Errorf("invalid since date %q, expected YYYY-MM-DD > %w", b.SinceDate, err)
+		}
+		rng.SinceDate = date
+	}
+
+	if rng.Last && rng.IsSet() {
+		return rng, fmt.Errorf("last commit can't be combined with a since revision or date")
+	}
+
+	return rng, nil
+}
+
+// Update writes collected versions to components meta files, unless in dry-run mode.
+func (b *Bump) Update(components map[string]map[string]*sync.Component) error {
+	b.result.DryRun = b.DryRun
+	b.result.Components = nil
+	err := b.updateComponents(components)
+	if err == nil && b.Cascade {
+		err = b.cascade()
+	}
 	if err != nil {
 		b.Log().Error("There is an error during components update")
 		return err
 	}
 
-	if b.DryRun {
-		return nil
+	return nil
+}
+
+// Stage adds updated meta files and signatures to the index, to be committed with the staged changes.
+func (b *Bump) Stage() error {
+	return b.bumper.Add(append(b.metas, b.signatures...)...)
+}
+
+// Commit creates bump commit with updated components and their signatures.
+func (b *Bump) Commit() error {
+	if err := b.bumper.Add(b.signatures...); err != nil {
+		return err
 	}
 
-	return bumper.Commit()
+	return b.bumper.Commit()
 }
 
-func (b *Bump) getComponent(path string) *sync.Component {
-	if !isVersionableFile(path) {
-		return nil
+// AmendCommit replaces the bump commit at HEAD with updated components, their signatures and uncommitted changes.
+func (b *Bump) AmendCommit() error {
+	if err := b.bumper.Add(b.signatures...); err != nil {
+		return err
+	}
+
+	return b.bumper.Amend()
+}
+
+// getComponent returns the component of which the file change triggers a bump, or the reason it doesn't.
+func (b *Bump) getComponent(path string) (*sync.Component, string) {
+	if !b.isVersionableFile(path) {
+		return nil, skipUnversioned
 	}
 
 	platform, kind, role, err := sync.ProcessComponentPath(path)
 	if err != nil || (platform == "" || kind == "" || role == "") {
-		return nil
+		return nil, skipNoComponent
 	}
 
 	// skip actions dir from triggering bump.
 	componentActionsDir := filepath.Join(platform, kind, "roles", role, "actions")
 	if strings.Contains(path, componentActionsDir) {
-		return nil
+		return nil, skipActions
 	}
 
 	component, err := sync.NewComponent(sync.PrepareComponentName(platform, kind, role), ".")
 	if err != nil {
-		return nil
+		return nil, skipNoComponent
 	}
 	if !component.IsValidComponent() {
-		return nil
+		return nil, skipNoComponent
 	}
 
-	return component
+	return component, ""
 }
 
 func (b *Bump) collectComponents(commits []*repository.Commit) map[string]map[string]*sync.Component {
 	uniqueVersion := map[string]string{}
+	b.triggers = make(map[string]*BumpedComponent)
+	b.order = nil
+	skipped := make(map[string]bool)
+	frozen := make(map[string]bool)
 
 	components := make(map[string]map[string]*sync.Component)
 	for _, c := range commits {
 		hash := c.Hash[:13]
+		// Commits are ordered latest first, the first one versions the whole run.
+		if b.Granularity == GranularityRun {
+			hash = commits[0].Hash[:13]
+		}
 		for _, path := range c.Files {
-			component := b.getComponent(path)
+			component, reason := b.getComponent(path)
 			if component == nil {
+				if !skipped[path] {
+					skipped[path] = true
+					b.result.Skipped = append(b.result.Skipped, SkippedFile{File: path, Reason: reason})
+				}
+				continue
+			}
+
+			name := component.GetName()
+			isFrozen, checked := frozen[name]
+			if !checked {
+				isFrozen = b.isFrozen(component)
+				frozen[name] = isFrozen
+			}
+			if isFrozen {
+				if !skipped[path] {
+					skipped[path] = true
+					b.result.Skipped = append(b.result.Skipped, SkippedFile{File: path, Reason: skipFrozen})
+				}
 				continue
 			}
 
@@ -133,26 +507,92 @@ func (b *Bump) collectComponents(commits []*repository.Commit) map[string]map[st
 				components[hash] = make(map[string]*sync.Component)
 			}
 
-			if _, ok := uniqueVersion[component.GetName()]; ok {
+			if t, ok := b.triggers[name]; ok {
+				if !slices.Contains(t.Files, path) {
+					t.Files = append(t.Files, path)
+				}
+			} else {
+				t = &BumpedComponent{Name: name, Files: []string{path}}
+				if !c.Uncommitted {
+					t.Commit = c.Hash
+				}
+				b.triggers[name] = t
+			}
+
+			if _, ok := uniqueVersion[name]; ok {
 				continue
 			}
 
-			b.Term().Printfln("Processing component %s", component.GetName())
-			components[hash][component.GetName()] = component
-			uniqueVersion[component.GetName()] = hash
+			b.Term().Printfln("Processing component %s", name)
+			components[hash][name] = component
+			uniqueVersion[name] = hash
+			b.order = append(b.order, name)
 		}
+
+		b.collectRenames(c)
 	}
 
 	return components
 }
 
+// collectRenames records components renamed or moved by the commit: files of a component which no longer exists
+// renamed to another component.
+func (b *Bump) collectRenames(c *repository.Commit) {
+	from := make([]string, 0, len(c.Renames))
+	for path := range c.Renames {
+		from = append(from, path)
+	}
+	sort.Strings(from)
+
+	for _, path := range from {
+		oldName, newName := componentName(path), componentName(c.Renames[path])
+		if oldName == "" || newName == "" || oldName == newName {
+			continue
+		}
+		if slices.ContainsFunc(b.result.Renamed, func(r RenamedComponent) bool { return r.From == oldName && r.To == newName }) {
+			continue
+		}
+		if old, err := sync.NewComponent(oldName, "."); err != nil || old.IsValidComponent() {
+			continue
+		}
+
+		renamed := RenamedComponent{From: oldName, To: newName}
+		if !c.Uncommitted {
+			renamed.Commit = c.Hash
+		}
+		b.result.Renamed = append(b.result.Renamed, renamed)
+		b.Term().Info().Printfln("Component %s renamed to %s", oldName, newName)
+	}
+}
+
+// componentName returns the name of the component of the path, empty outside a component.
+func componentName(path string) string {
+	platform, kind, role, err := sync.ProcessComponentPath(path)
+	if err != nil || platform == "" || kind == "" || role == "" {
+		return ""
+	}
+
+	return sync.PrepareComponentName(platform, kind, role)
+}
+
+// isFrozen tells if the component is frozen or manually propagated, warning it's skipped.
+func (b *Bump) isFrozen(c *sync.Component) bool {
+	frozen, reason, err := c.GetFrozen()
+	if err != nil || !frozen {
+		return false
+	}
+
+	b.Term().Warning().Println(b.result.Warnings.Add(warning.Skipped, c.GetName(), "Skipping component %s (%s)", c.GetName(), reason))
+	return true
+}
+
 func (b *Bump) updateComponents(hashComponentsMap map[string]map[string]*sync.Component) error {
 	if len(hashComponentsMap) == 0 {
 		return nil
 	}
 
 	b.Term().Printf("Updating versions:\n")
-	for version, components := range hashComponentsMap {
+	for hash, components := range hashComponentsMap {
 		for name, c := range components {
 			currentVersion, debug, err := c.GetVersion()
 			for _, d := range debug {
@@ -162,16 +602,41 @@ func (b *Bump) updateComponents(hashComponentsMap map[string]map[string]*sync.Co
 				return err
 			}
 
-			b.result.Components = append(b.result.Components, BumpedComponent{
-				Name:       name,
-				OldVersion: currentVersion,
-				NewVersion: version,
-			})
+			scheme, stored, err := b.versionScheme(c)
+			if err != nil {
+				return err
+			}
+			next, err := sync.GetVersionScheme(scheme)
+			if err != nil {
+				return fmt.Errorf("component %s > %w", name, err)
+			}
+
+			base, _ := b.VersionFormat.Split(currentVersion)
+			version, err := next.Next(base, hash, b.Level)
+			if err != nil {
+				return fmt.Errorf("failed to compute version of %s > %w", name, err)
+			}
+
+			bumped := BumpedComponent{Name: name}
+			if t, ok := b.triggers[name]; ok {
+				bumped = *t
+			}
+			bumped.OldVersion = currentVersion
+			bumped.NewVersion = version
+			bumped.Scheme = scheme
+			bumped.Signed = b.key != nil
+			b.result.Components = append(b.result.Components, bumped)
 			b.Term().Printfln("- %s from %s to %s", name, currentVersion, version)
 			if b.DryRun {
 				continue
 			}
 
+			if scheme != stored {
+				if err = c.UpdateVersioning(scheme); err != nil {
+					return err
+				}
+			}
+
 			debug, err = c.UpdateVersion(version)
 			for _, d := range debug {
 				b.Log().Debug("error", "message", d)
@@ -179,14 +644,65 @@ func (b *Bump) updateComponents(hashComponentsMap map[string]map[string]*sync.Co
 			if err != nil {
 				return err
 			}
+			b.metas = append(b.metas, c.MetaPath())
+
+			if err = b.sign(c); err != nil {
+				return err
+			}
 		}
 	}
 
 	return nil
 }
 
-func isVersionableFile(path string) bool {
+// versionScheme returns the versioning scheme to bump the component with and the one stored in its meta.
+// Components without stored scheme keep the hash scheme implicitly.
+func (b *Bump) versionScheme(c *sync.Component) (string, string, error) {
+	stored, debug, err := c.GetVersioning()
+	for _, d := range debug {
+		b.Log().Debug("error", "message", d)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	scheme := b.Scheme
+	switch {
+	case scheme == "" && stored == "":
+		return sync.HashVersioning, sync.HashVersioning, nil
+	case scheme == "":
+		scheme = stored
+	case scheme == sync.HashVersioning && stored == "":
+		stored = scheme
+	}
+
+	return scheme, stored, nil
+}
+
+// sign writes the signature of the bumped component content, if a signing key is set.
+func (b *Bump) sign(c *sync.Component) error {
+	if b.key == nil {
+		return nil
+	}
+
+	dir := filepath.Dir(filepath.Dir(c.MetaPath()))
+	sig, err := provenance.Sign(dir, b.key)
+	if err != nil {
+		return fmt.Errorf("failed to sign %s > %w", c.GetName(), err)
+	}
+
+	b.Log().Debug("component signed", "component", c.GetName(), "hash", sig.Hash, "key", sig.KeyID)
+	b.signatures = append(b.signatures, filepath.Join(dir, provenance.SignatureFile))
+	return nil
+}
+
+// isVersionableFile tells if changes of the file trigger a bump, i.e. it isn't a non-versioned file
+// or matched by the ignore file.
+func (b *Bump) isVersionableFile(path string) bool {
 	name := filepath.Base(path)
-	_, ok := unversionedFiles[name]
-	return !ok
+	if _, ok := unversionedFiles[name]; ok {
+		return false
+	}
+
+	return b.ignore == nil || !b.ignore.Match(strings.Split(filepath.ToSlash(path), "/"), false)
 }
@@ -0,0 +1,103 @@
+package bump
+
+import (
+	"sort"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// cascade bumps components depending on the bumped ones, directly or not, which weren't bumped themselves.
+// Their version is composed of their base and the version of the dependency, the way sync propagates versions,
+// the dependency changed by the latest commit winning. Kinds not allowed to propagate, frozen components and components
+// opted out of propagation are skipped.
+func (b *Bump) cascade() error {
+	inv, err := sync.NewInventory(".", b.Log())
+	if err != nil {
+		return err
+	}
+
+	bumped := make(map[string]string, len(b.result.Components))
+	for _, c := range b.result.Components {
+		bumped[c.Name] = c.NewVersion
+	}
+
+	components := inv.GetComponentsMap()
+	propagated := make(map[string]bool)
+	var cascaded []BumpedComponent
+	for _, name := range b.order {
+		version, ok := bumped[name]
+		if !ok {
+			continue
+		}
+
+		var dependents []string
+		for dep := range inv.GetRequiredByComponents(name, -1) {
+			dependents = append(dependents, dep)
+		}
+		sort.Strings(dependents)
+
+		for _, dep := range dependents {
+			if _, ok = bumped[dep]; ok || propagated[dep] {
+				continue
+			}
+			propagated[dep] = true
+
+			c, found := components.Get(dep)
+			if !found {
+				continue
+			}
+			if !sync.IsUpdatableKind(c.GetKind(), b.ExtraKinds...) {
+				b.Log().Warn(b.result.Warnings.Add(warning.Skipped, dep, "Component %s kind is not allowed to propagate", dep))
+				continue
+			}
+			if b.isFrozen(c) {
+				continue
+			}
+			if disabled, errDisabled := c.GetPropagationDisabled(); errDisabled == nil && disabled {
+				b.Term().Warning().Println(b.result.Warnings.Add(warning.Skipped, dep, "Skipping component %s (propagation disabled)", dep))
+				continue
+			}
+
+			baseVersion, currentVersion, debug, errVersion := c.GetBaseVersion(b.VersionFormat)
+			for _, d := range debug {
+				b.Log().Debug("error", "message", d)
+			}
+			if errVersion != nil {
+				return errVersion
+			}
+			if baseVersion == version {
+				continue
+			}
+
+			newVersion := b.VersionFormat.Compose(baseVersion, version)
+			cascaded = append(cascaded, BumpedComponent{
+				Name:           dep,
+				OldVersion:     currentVersion,
+				NewVersion:     newVersion,
+				PropagatedFrom: name,
+				Signed:         b.key != nil,
+			})
+			b.Term().Printfln("- %s from %s to %s (depends on %s)", dep, currentVersion, newVersion, name)
+			if b.DryRun {
+				continue
+			}
+
+			debug, errVersion = c.UpdateVersion(newVersion)
+			for _, d := range debug {
+				b.Log().Debug("error", "message", d)
+			}
+			if errVersion != nil {
+				return errVersion
+			}
+			b.metas = append(b.metas, c.MetaPath())
+
+			if err = b.sign(c); err != nil {
+				return err
+			}
+		}
+	}
+
+	b.result.Components = append(b.result.Components, cascaded...)
+	return nil
+}
@@ -0,0 +1,154 @@
+package bump
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"text/template"
+)
+
+// Hook is a command run around the bump commit, e.g. to regenerate a manifest or update a changelog.
+// The command is a Go template of [HookData], run with the shell from the repository root.
+type Hook struct {
+	// Run is the command, run with `sh -c` (`cmd /C` on Windows).
+	Run string `yaml:"run"`
+	// Add are files created by a pre hook to include in the bump commit. Modified tracked files are included anyway.
+	Add []string `yaml:"add"`
+}
+
+// Hooks are commands run before and after the bump commit, in order. A failing hook stops the bump.
+type Hooks struct {
+	// Pre hooks run once versions are updated, before the commit, their changes are part of the bump commit.
+	Pre []Hook `yaml:"pre"`
+	// Post hooks run after the commit.
+	Post []Hook `yaml:"post"`
+}
+
+// HookData is the data of hook templates.
+type HookData struct {
+	// Components are the bumped components.
+	Components []BumpedComponent
+	// Names are names of the bumped components.
+	Names []string
+	// Commit is the hash of the bump commit in post hooks, empty in pre hooks and for staged changes.
+	Commit string
+	// Staged tells if the bump is part of the commit of staged changes rather than a bump commit.
+	Staged bool
+}
+
+// Stages of hooks.
+const (
+	hookPre  = "pre"
+	hookPost = "post"
+)
+
+var hookFuncs = template.FuncMap{
+	"join": strings.Join,
+}
+
+// CommitWithHooks commits updated versions between the pre and post hooks, only printing hooks in dry-run mode.
+func (b *Bump) CommitWithHooks() error {
+	if err := b.runHooks(hookPre, b.Hooks.Pre, ""); err != nil {
+		return err
+	}
+	if b.DryRun {
+		return b.runHooks(hookPost, b.Hooks.Post, "")
+	}
+
+	if err := b.Commit(); err != nil {
+		return err
+	}
+
+	return b.runHooks(hookPost, b.Hooks.Post, b.bumpCommit())
+}
+
+// runHooks runs the hooks with the bumped components, only printing them in dry-run mode.
+func (b *Bump) runHooks(stage string, hooks []Hook, commit string) error {
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	data := HookData{Components: b.result.Components, Commit: commit, Staged: b.Staged}
+	for _, c := range b.result.Components {
+		data.Names = append(data.Names, c.Name)
+	}
+
+	for i, h := range hooks {
+		command, err := h.command(data)
+		if err != nil {
+			return fmt.Errorf("invalid %s-bump hook %d > %w", stage, i+1, err)
+		}
+
+		if b.DryRun {
+			b.Term().Printfln("Would run %s-bump hook: %s", stage, command)
+			continue
+		}
+
+		b.Term().Info().Printfln("Running %s-bump hook: %s", stage, command)
+		if err = b.runHook(command); err != nil {
+			return fmt.Errorf("%s-bump hook %q failed > %w", stage, command, err)
+		}
+		if stage == hookPre {
+			if err = b.bumper.Add(h.Add...); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// command returns the command of the hook for the bump.
+func (h Hook) command(data HookData) (string, error) {
+	tpl, err := template.New("hook").Funcs(hookFuncs).Option("missingkey=error").Parse(h.Run)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	if err = tpl.Execute(&buf, data); err != nil {
+		return "", err
+	}
+
+	command := strings.TrimSpace(buf.String())
+	if command == "" {
+		return "", fmt.Errorf("empty command")
+	}
+
+	return command, nil
+}
+
+func (b *Bump) runHook(command string) error {
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/C", command) //nolint:gosec
+	} else {
+		cmd = exec.Command("sh", "-c", command) //nolint:gosec
+	}
+
+	var out, errOut io.Writer = os.Stdout, os.Stderr
+	if b.Streams != nil {
+		out, errOut = b.Streams.Out(), b.Streams.Err()
+	}
+	cmd.Stdout, cmd.Stderr = out, errOut
+
+	return cmd.Run()
+}
+
+// bumpCommit returns the hash of HEAD if it's the bump commit, nothing may have been committed.
+func (b *Bump) bumpCommit() string {
+	if !b.bumper.IsOwnCommit() {
+		return ""
+	}
+
+	head, err := b.bumper.GetGit().Head()
+	if err != nil {
+		return ""
+	}
+
+	return head.Hash().String()
+}
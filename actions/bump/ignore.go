@@ -0,0 +1,42 @@
+package bump
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// BumpIgnoreFile is the repository file with gitignore-style patterns of paths not triggering a bump.
+const BumpIgnoreFile = ".bumpignore"
+
+// loadBumpIgnore reads patterns of the ignore file, nil matcher if the file doesn't exist.
+func loadBumpIgnore(path string) (gitignore.Matcher, []string, error) {
+	data, err := os.ReadFile(path) //nolint:gosec
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s > %w", path, err)
+	}
+
+	var lines []string
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s > %w", path, err)
+	}
+
+	return gitignore.NewMatcher(patterns), lines, nil
+}
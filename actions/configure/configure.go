@@ -7,8 +7,12 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/launchrctl/keyring"
 	"github.com/launchrctl/launchr/pkg/action"
 	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 // ConfigureResult is the structured result of component:configure.
@@ -17,7 +21,13 @@ type ConfigureResult struct {
 	Key       string                 `json:"key,omitempty"`
 	Value     interface{}            `json:"value,omitempty"`
 	Scope     string                 `json:"scope,omitempty"`
+	Source    string                 `json:"source,omitempty"`
 	Entries   map[string]interface{} `json:"entries,omitempty"`
+	Undefined []UndefinedVariable    `json:"undefined,omitempty"`
+	Files     []string               `json:"files,omitempty"`
+	// Scaffolded is the configuration directory created for the chassis section, if any.
+	Scaffolded string       `json:"scaffolded,omitempty"`
+	Warnings   warning.List `json:"warnings,omitempty"`
 }
 
 // Configure implements the unified component:configure command
@@ -25,6 +35,9 @@ type Configure struct {
 	action.WithLogger
 	action.WithTerm
 
+	// services.
+	Keyring keyring.Keyring
+
 	// Arguments
 	Key   string
 	Value string
@@ -34,26 +47,55 @@ type Configure struct {
 	List     bool
 	Validate bool
 	Generate bool
+	Rekey    bool
 
 	// Scope
 	At string // chassis path for override, empty for component defaults
 
+	// Build reads values from the composed build directory instead of src
+	Build    bool
+	BuildDir string
+
+	// Rekey scope: vault files of packages besides src
+	Packages    bool
+	PackagesDir string
+
+	// Rekey passwords: the current one is given, read from the provider, the keyring or prompted, the new one is
+	// read from its provider or prompted.
+	VaultPass            string
+	VaultPassProvider    vaultpass.Provider
+	NewVaultPassProvider vaultpass.Provider
+
 	// Modifiers
 	Vault      bool
 	Format     string
 	Strict     bool
 	YesIAmSure bool
 
-	result *ConfigureResult
+	scaffolded string
+	warnings   warning.List
+	result     *ConfigureResult
 }
 
-// Result returns the structured result for JSON output.
+// Result returns the structured result for JSON output, with warnings of the run.
 func (c *Configure) Result() any {
+	if c.result != nil {
+		c.result.Warnings = c.warnings
+	}
 	return c.result
 }
 
 // Execute runs the configure action based on flags
 func (c *Configure) Execute() error {
+	if c.Build {
+		if c.Validate || c.Generate || c.Rekey || (c.Key != "" && c.Value != "") {
+			return fmt.Errorf("--build is read-only, use it with --get or --list")
+		}
+		if c.At == "" {
+			return fmt.Errorf("--build requires a chassis scope, set --at")
+		}
+	}
+
 	// Determine operation mode
 	switch {
 	case c.List:
@@ -62,12 +104,14 @@ func (c *Configure) Execute() error {
 		return c.executeValidate()
 	case c.Generate:
 		return c.executeGenerate()
+	case c.Rekey:
+		return c.executeRekey()
 	case c.Get || (c.Key != "" && c.Value == ""):
 		return c.executeGet()
 	case c.Key != "" && c.Value != "":
 		return c.executeSet()
 	default:
-		return fmt.Errorf("usage: configure <key> <value> | configure <key> --get | configure --list | configure --validate | configure <key> --generate")
+		return fmt.Errorf("usage: configure <key> <value> | configure <key> --get | configure --list | configure --validate | configure <key> --generate | configure --rekey")
 	}
 }
 
@@ -106,7 +150,7 @@ func (c *Configure) executeGet() error {
 		return fmt.Errorf("key %q not found", c.Key)
 	}
 
-	c.result = &ConfigureResult{Operation: "get", Key: c.Key, Value: value}
+	c.result = &ConfigureResult{Operation: "get", Key: c.Key, Value: value, Scope: c.At, Source: configDir}
 	c.Term().Printfln("%v", value)
 	return nil
 }
@@ -133,11 +177,14 @@ func (c *Configure) executeSet() error {
 	configFile := filepath.Join(configDir, filename)
 
 	var config map[string]interface{}
+	var header string
 	if data, err := os.ReadFile(configFile); err == nil {
 		if err := yaml.Unmarshal(data, &config); err != nil {
 			return fmt.Errorf("failed to parse existing config: %w", err)
 		}
-	} else {
+		header = leadingComments(data)
+	}
+	if config == nil {
 		config = make(map[string]interface{})
 	}
 
@@ -147,6 +194,7 @@ func (c *Configure) executeSet() error {
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
+	data = append([]byte(header), data...)
 
 	if err := os.WriteFile(configFile, data, 0644); err != nil {
 		return fmt.Errorf("failed to write config: %w", err)
@@ -156,7 +204,7 @@ func (c *Configure) executeSet() error {
 	if c.At != "" {
 		scope = c.At
 	}
-	c.result = &ConfigureResult{Operation: "set", Key: c.Key, Value: c.Value, Scope: scope}
+	c.result = &ConfigureResult{Operation: "set", Key: c.Key, Value: c.Value, Scope: scope, Scaffolded: c.scaffolded}
 	c.Term().Success().Printfln("Set %s = %s (scope: %s)", c.Key, c.Value, scope)
 	return nil
 }
@@ -199,12 +247,12 @@ func (c *Configure) executeList() error {
 	}
 
 	if len(result) == 0 {
-		c.result = &ConfigureResult{Operation: "list"}
+		c.result = &ConfigureResult{Operation: "list", Scope: c.At, Source: configDir}
 		c.Term().Info().Println("No configuration values found")
 		return nil
 	}
 
-	c.result = &ConfigureResult{Operation: "list", Entries: result}
+	c.result = &ConfigureResult{Operation: "list", Scope: c.At, Source: configDir, Entries: result}
 
 	term := c.Term()
 	switch strings.ToLower(c.Format) {
@@ -224,28 +272,15 @@ func (c *Configure) executeList() error {
 	return nil
 }
 
-func (c *Configure) executeValidate() error {
-	c.Term().Info().Println("Validating configuration...")
-
-	// TODO: Implement schema-based validation
-	// 1. Load component schemas from meta/plasma.yaml files
-	// 2. Validate config values against schemas
-	// 3. Report errors and warnings
-
-	c.result = &ConfigureResult{Operation: "validate"}
-	c.Term().Warning().Println("Schema-based validation not yet implemented")
-	c.Term().Success().Println("Basic config structure is valid")
-	return nil
-}
-
 func (c *Configure) executeGenerate() error {
 	if c.Key == "" {
 		return fmt.Errorf("key is required for generate operation")
 	}
 
+	c.result = &ConfigureResult{Operation: "generate", Key: c.Key}
 	if !c.YesIAmSure {
-		c.Term().Warning().Println("Secret generation/rotation will change credentials.")
-		c.Term().Warning().Println("Applications may need to be restarted.")
+		c.Term().Warning().Println(c.warnings.Add(warning.Confirmation, c.Key,
+			"Secret generation/rotation will change credentials. Applications may need to be restarted."))
 		c.Term().Info().Println("Use --yes-i-am-sure to proceed")
 		return nil
 	}
@@ -255,8 +290,7 @@ func (c *Configure) executeGenerate() error {
 	// 2. Update vault.yaml
 	// 3. Optionally trigger re-deployment
 
-	c.result = &ConfigureResult{Operation: "generate", Key: c.Key}
-	c.Term().Warning().Println("Secret generation not yet implemented")
+	c.Term().Warning().Println(c.warnings.Add(warning.Unchanged, c.Key, "Secret generation not yet implemented"))
 	return nil
 }
 
@@ -273,6 +307,10 @@ func (c *Configure) resolveConfigDir() (string, error) {
 	}
 
 	// Chassis-scoped override: src/{layer}/cfg/{chassisPath}/
+	if c.Build {
+		// Composed build: {buildDir}/{layer}/cfg/{chassisPath}/, with package-provided values
+		return resolveChassisConfigDirIn(c.BuildDir, c.At)
+	}
 	return resolveChassisConfigDir(c.At)
 }
 
@@ -286,21 +324,17 @@ func (c *Configure) createConfigDir() (string, error) {
 		return configDir, nil
 	}
 
-	// Chassis-scoped: src/{layer}/cfg/{chassisPath}/
-	parts := strings.Split(c.At, ".")
-	if len(parts) < 2 {
-		return "", fmt.Errorf("invalid chassis path %q (expected format: platform.{layer}.{...})", c.At)
-	}
-	layer := parts[1]
-	configDir := filepath.Join("src", layer, "cfg", c.At)
-	if err := os.MkdirAll(configDir, 0755); err != nil {
-		return "", fmt.Errorf("failed to create config directory: %w", err)
-	}
-	return configDir, nil
+	// Chassis-scoped: src/{layer}/cfg/{chassisPath}/, layer resolved from attachments
+	return c.scaffoldChassisConfig()
 }
 
 // resolveChassisConfigDir finds the configuration directory for a chassis path
 func resolveChassisConfigDir(chassisPath string) (string, error) {
+	return resolveChassisConfigDirIn("src", chassisPath)
+}
+
+// resolveChassisConfigDirIn finds the configuration directory for a chassis path in the source directory
+func resolveChassisConfigDirIn(source, chassisPath string) (string, error) {
 	if chassisPath == "" {
 		return "", fmt.Errorf("chassis path is required")
 	}
@@ -314,7 +348,7 @@ func resolveChassisConfigDir(chassisPath string) (string, error) {
 
 	layer := parts[1] // e.g., "foundation", "integration", "cognition"
 
-	configDir := filepath.Join("src", layer, "cfg", chassisPath)
+	configDir := filepath.Join(source, layer, "cfg", chassisPath)
 	if _, err := os.Stat(configDir); err == nil {
 		return configDir, nil
 	}
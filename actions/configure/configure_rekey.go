@@ -0,0 +1,155 @@
+package configure
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/launchrctl/keyring"
+	vault "github.com/sosedoff/ansible-vault-go"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// executeRekey re-encrypts vault files of src, and of packages if requested, with a new vault password.
+func (c *Configure) executeRekey() error {
+	files, err := c.vaultFiles()
+	if err != nil {
+		return err
+	}
+
+	c.result = &ConfigureResult{Operation: "rekey", Files: files}
+	if len(files) == 0 {
+		c.Term().Info().Println("No vault files found")
+		return nil
+	}
+
+	if !c.YesIAmSure {
+		c.Term().Info().Printfln("%d vault file(s) will be re-encrypted:", len(files))
+		for _, f := range files {
+			c.Term().Printfln("- %s", f)
+		}
+		c.Term().Warning().Println(c.warnings.Add(warning.Confirmation, "",
+			"Re-keying changes the vault password of every file and of the keyring entry."))
+		c.Term().Info().Println("Use --yes-i-am-sure to proceed")
+		return nil
+	}
+
+	oldPass, err := vaultpass.Resolve(c.VaultPass, c.VaultPassProvider, c.Keyring, c.Term(), "Current Ansible vault password")
+	if err != nil {
+		return err
+	}
+
+	// Every vault is decrypted before writing any, so a wrong password leaves files untouched.
+	decrypted := make(map[string]string, len(files))
+	for _, f := range files {
+		content, errDecrypt := vault.DecryptFile(f, oldPass)
+		if errDecrypt != nil {
+			return fmt.Errorf("decrypt %s > %w", f, errDecrypt)
+		}
+		decrypted[f] = content
+	}
+
+	newPass, err := c.newVaultPass(oldPass)
+	if err != nil {
+		return err
+	}
+
+	for i, f := range files {
+		if err = vault.EncryptFile(f, decrypted[f], newPass); err != nil {
+			c.Term().Error().Printfln("Re-keyed %d of %d vault file(s), the rest still use the old password", i, len(files))
+			return fmt.Errorf("encrypt %s > %w", f, err)
+		}
+	}
+
+	c.Term().Success().Printfln("Re-keyed %d vault file(s)", len(files))
+
+	if c.Keyring == nil {
+		return nil
+	}
+
+	err = c.Keyring.AddItem(keyring.KeyValueItem{Key: vaultpass.KeyringKey, Value: newPass})
+	if err == nil {
+		err = c.Keyring.Save()
+	}
+	if err != nil {
+		return fmt.Errorf("vault files are re-keyed but the keyring entry %s wasn't updated > %w", vaultpass.KeyringKey, err)
+	}
+
+	c.Term().Info().Printfln("Updated keyring entry %s", vaultpass.KeyringKey)
+	return nil
+}
+
+// vaultFiles returns encrypted vault files of src and packages, sorted.
+func (c *Configure) vaultFiles() ([]string, error) {
+	dirs := []string{"src"}
+	if c.Packages {
+		dirs = append(dirs, c.PackagesDir)
+	}
+
+	var files []string
+	for _, dir := range dirs {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			continue
+		}
+
+		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() || !sync.IsVaultFile(path) {
+				return err
+			}
+
+			data, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return err
+			}
+			if !bytes.HasPrefix(data, []byte(vaultHeader)) {
+				c.Term().Warning().Println(c.warnings.Add(warning.Skipped, path, "Skipping %s, it isn't encrypted", path))
+				return nil
+			}
+
+			files = append(files, path)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find vault files in %s > %w", dir, err)
+		}
+	}
+
+	sort.Strings(files)
+	return files, nil
+}
+
+// newVaultPass returns the new vault password of the provider, or prompts for it twice.
+func (c *Configure) newVaultPass(oldPass string) (string, error) {
+	pass, err := c.NewVaultPassProvider.Password()
+	if err != nil {
+		return "", fmt.Errorf("new vault password > %w", err)
+	}
+
+	if pass == "" {
+		var values [2]string
+		for i, title := range []string{"New Ansible vault password", "Repeat new Ansible vault password"} {
+			if values[i], err = vaultpass.Prompt(c.Term(), title); err != nil {
+				return "", err
+			}
+		}
+		if values[0] != values[1] {
+			return "", errors.New("new vault passwords don't match")
+		}
+		pass = values[0]
+	}
+
+	switch {
+	case pass == "":
+		return "", errors.New("new vault password is empty")
+	case pass == oldPass:
+		return "", errors.New("new vault password is the same as the current one")
+	}
+
+	return pass, nil
+}
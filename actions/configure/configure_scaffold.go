@@ -0,0 +1,139 @@
+package configure
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pterm/pterm"
+	"golang.org/x/term"
+
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// chassisScaffold is the configuration directory of a chassis section to create.
+type chassisScaffold struct {
+	chassis    string
+	dir        string
+	layer      string
+	components []string
+}
+
+// scaffoldChassisConfig creates the configuration directory of the chassis section with skeleton files.
+// The layer is the one of playbooks attaching components to the section, its ancestors or descendants.
+func (c *Configure) scaffoldChassisConfig() (string, error) {
+	s, err := c.planChassisScaffold()
+	if err != nil {
+		return "", err
+	}
+
+	c.Term().Info().Printfln("Chassis %s has no configuration directory, scaffolding %s", c.At, s.dir)
+	if len(s.components) > 0 {
+		c.Term().Printfln("Attached components: %s", strings.Join(s.components, ", "))
+	} else {
+		c.Term().Warning().Println(c.warnings.Add(warning.NotFound, c.At,
+			"No component is attached to %s, layer %s is guessed from the chassis path", c.At, s.layer))
+	}
+
+	if !c.YesIAmSure && term.IsTerminal(int(os.Stdin.Fd())) {
+		confirmed, err := pterm.DefaultInteractiveConfirm.WithDefaultValue(true).Show("Create the configuration directory?")
+		if err != nil {
+			return "", err
+		}
+		if !confirmed {
+			return "", errors.New("configuration directory wasn't created")
+		}
+	}
+
+	if err = os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	files := map[string]string{"vars.yaml": s.header("Variables")}
+	if c.Vault {
+		files["vault.yaml"] = s.header("Secrets") + "# Encrypt this file with ansible-vault.\n"
+	}
+	for name, header := range files {
+		if err = os.WriteFile(filepath.Join(s.dir, name), []byte(header), 0644); err != nil { //nolint:gosec
+			return "", fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+
+	c.scaffolded = s.dir
+	return s.dir, nil
+}
+
+// planChassisScaffold resolves the layer of the chassis section from playbook attachments.
+func (c *Configure) planChassisScaffold() (*chassisScaffold, error) {
+	attachments, err := component.LoadAttachments(".", "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+
+	layers := make(map[string]bool)
+	components := make(map[string]bool)
+	for _, a := range attachments {
+		if !isRelatedSection(a.Chassis, c.At) {
+			continue
+		}
+		layers[filepath.Base(filepath.Dir(a.Playbook))] = true
+		components[a.Component] = true
+	}
+
+	s := &chassisScaffold{chassis: c.At, components: sortedKeys(components)}
+	switch len(layers) {
+	case 0:
+		parts := strings.Split(c.At, ".")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("invalid chassis path %q (expected format: platform.{layer}.{...})", c.At)
+		}
+		s.layer = parts[1]
+	case 1:
+		s.layer = sortedKeys(layers)[0]
+	default:
+		return nil, fmt.Errorf("chassis %s is attached in layers %s, set the value at a section of one layer",
+			c.At, strings.Join(sortedKeys(layers), ", "))
+	}
+
+	s.dir = filepath.Join("src", s.layer, "cfg", c.At)
+	return s, nil
+}
+
+// header returns the comment heading skeleton files of the chassis section.
+func (s *chassisScaffold) header(title string) string {
+	header := fmt.Sprintf("# %s of chassis section %s (layer %s).\n", title, s.chassis, s.layer)
+	if len(s.components) > 0 {
+		header += fmt.Sprintf("# Attached components: %s.\n", strings.Join(s.components, ", "))
+	}
+	return header
+}
+
+// isRelatedSection reports whether the chassis sections are the same or one contains the other.
+func isRelatedSection(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+".") || strings.HasPrefix(b, a+".")
+}
+
+// leadingComments returns comment lines at the beginning of the file, e.g. skeleton headers.
+func leadingComments(data []byte) string {
+	var header strings.Builder
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			break
+		}
+		header.WriteString(line)
+	}
+	return header.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,373 @@
+package configure
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-model/pkg/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/jinja"
+	"github.com/plasmash/plasmactl-component/internal/strictyaml"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+const vaultHeader = "$ANSIBLE_VAULT"
+
+// builtinVariables are provided by Ansible or Jinja and never need to be configured.
+var builtinVariables = map[string]bool{
+	"item": true, "inventory_hostname": true, "inventory_hostname_short": true, "hostvars": true,
+	"groups": true, "group_names": true, "play_hosts": true, "omit": true, "lookup": true,
+	"query": true, "q": true, "range": true, "playbook_dir": true, "role_path": true,
+	"role_name": true, "environment": true, "vars": true, "loop": true, "now": true, "ansible_facts": true,
+}
+
+// UndefinedVariable represents a variable required by a component but not configured in its chassis scope.
+type UndefinedVariable struct {
+	Component string   `json:"component"`
+	Chassis   string   `json:"chassis"`
+	Variable  string   `json:"variable"`
+	Files     []string `json:"files"`
+}
+
+// executeValidate checks that variables required by attached components are defined in their chassis scope.
+func (c *Configure) executeValidate() error {
+	c.Term().Info().Println("Validating configuration...")
+
+	attachments, err := component.LoadAttachments(".", c.At)
+	if err != nil {
+		return fmt.Errorf("failed to load attachments: %w", err)
+	}
+
+	c.result = &ConfigureResult{Operation: "validate", Scope: c.At}
+	if len(attachments) == 0 {
+		c.Term().Warning().Println(c.warnings.Add(warning.Empty, c.At, "No attached components found"))
+		return nil
+	}
+
+	if c.Strict {
+		if err = c.checkStrictYAML(attachments); err != nil {
+			return err
+		}
+	}
+
+	warnings := 0
+	skippedVaults := make(map[string]bool)
+	for _, a := range attachments {
+		dir := componentDir(a.Component)
+		if dir == "" {
+			c.Term().Warning().Println(c.warnings.Add(warning.NotFound, a.Component,
+				"Component %s attached to %s not found", a.Component, a.Chassis))
+			warnings++
+			continue
+		}
+
+		required, err := requiredVariables(dir)
+		if err != nil {
+			return fmt.Errorf("component %s > %w", a.Component, err)
+		}
+
+		defined, skipped, err := chassisVariables(dir, a.Component, a.Chassis)
+		if err != nil {
+			return fmt.Errorf("component %s > %w", a.Component, err)
+		}
+		for _, s := range skipped {
+			skippedVaults[s] = true
+		}
+
+		names := make([]string, 0, len(required))
+		for name := range required {
+			if !defined[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			c.result.Undefined = append(c.result.Undefined, UndefinedVariable{
+				Component: a.Component,
+				Chassis:   a.Chassis,
+				Variable:  name,
+				Files:     required[name],
+			})
+		}
+	}
+
+	vaults := make([]string, 0, len(skippedVaults))
+	for v := range skippedVaults {
+		vaults = append(vaults, v)
+	}
+	sort.Strings(vaults)
+	for _, v := range vaults {
+		c.Term().Warning().Println(c.warnings.Add(warning.Partial, v,
+			"Encrypted vault %s skipped, its variables are not taken into account", v))
+		warnings++
+	}
+
+	for _, u := range c.result.Undefined {
+		c.Term().Error().Printfln("%s on %s: %s is undefined (used in %s)", u.Component, u.Chassis, u.Variable, strings.Join(u.Files, ", "))
+	}
+
+	if len(c.result.Undefined) > 0 {
+		return fmt.Errorf("found %d undefined variable(s)", len(c.result.Undefined))
+	}
+
+	if c.Strict && warnings > 0 {
+		return fmt.Errorf("validation finished with %d warning(s)", warnings)
+	}
+
+	c.Term().Success().Printfln("All variables of %d attached component(s) are defined", len(attachments))
+	return nil
+}
+
+// checkStrictYAML strictly parses layer playbooks and meta and dependencies of attached components,
+// as lenient loading silently skips malformed files.
+func (c *Configure) checkStrictYAML(attachments []component.Attachment) error {
+	var errs []*strictyaml.Error
+	checked := make(map[string]bool)
+	check := func(path string, fn func(string) error) {
+		if checked[path] {
+			return
+		}
+		checked[path] = true
+		if _, err := os.Stat(path); err != nil {
+			return
+		}
+		errs = append(errs, strictyaml.Errors(fn(path))...)
+	}
+
+	playbooks, _ := filepath.Glob(filepath.Join("src", "*", "*.yaml"))
+	for _, path := range playbooks {
+		if filepath.Base(path) == filepath.Base(filepath.Dir(path))+".yaml" {
+			check(path, strictyaml.CheckPlaybook)
+		}
+	}
+
+	for _, a := range attachments {
+		dir := componentDir(a.Component)
+		if dir == "" {
+			continue
+		}
+		check(filepath.Join(dir, "meta", "plasma.yaml"), strictyaml.CheckMeta)
+		check(filepath.Join(dir, "tasks", "dependencies.yaml"), strictyaml.CheckDependencies)
+	}
+
+	for _, e := range errs {
+		c.Term().Error().Println(e.Error())
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("found %d YAML error(s)", len(errs))
+	}
+
+	return nil
+}
+
+// componentDir locates component sources, preferring domain sources over the composed ones.
+func componentDir(name string) string {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	candidates := []string{
+		filepath.Join("src", parts[0], parts[1], parts[2]),
+		filepath.Join("src", parts[0], parts[1], "roles", parts[2]),
+		filepath.Join(model.MergedSrcDir, parts[0], parts[1], parts[2]),
+	}
+	for _, dir := range candidates {
+		if stat, err := os.Stat(dir); err == nil && stat.IsDir() {
+			return dir
+		}
+	}
+
+	return ""
+}
+
+// requiredVariables returns variables referenced in component templates and tasks without default values,
+// mapped to files using them. Variables set by the component itself are excluded.
+func requiredVariables(dir string) (map[string][]string, error) {
+	required := make(map[string][]string)
+	local := make(map[string]bool)
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+
+		rel, _ := filepath.Rel(dir, path)
+		section := strings.Split(rel, string(filepath.Separator))[0]
+		ext := filepath.Ext(path)
+		isYaml := ext == ".yaml" || ext == ".yml"
+		if !(section == "templates" && ext == ".j2") && !(section == "tasks" && isYaml) {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		if section == "tasks" {
+			collectTaskVariables(data, local)
+		}
+
+		for _, name := range jinjaVariables(string(data), local) {
+			required[name] = appendUniqueString(required[name], rel)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for name := range local {
+		delete(required, name)
+	}
+
+	return required, nil
+}
+
+// jinjaVariables extracts variables used in Jinja expressions and statements, unless the content gives them a default
+// value or tests their definition, e.g. `{% if x is defined %}{{ x.y }}{% endif %}`. Variables assigned by the
+// template are added to local ones.
+func jinjaVariables(content string, local map[string]bool) []string {
+	names := jinja.Names(content)
+	guarded := make(map[string]bool)
+	for _, n := range names {
+		if n.Assigned {
+			local[n.Name] = true
+		}
+		if n.Guarded {
+			guarded[n.Name] = true
+		}
+	}
+
+	var result []string
+	for _, n := range names {
+		if guarded[n.Name] || builtinVariables[n.Name] || strings.HasPrefix(n.Name, "ansible_") || local[n.Name] {
+			continue
+		}
+		result = append(result, n.Name)
+	}
+
+	return result
+}
+
+// collectTaskVariables gathers variables defined by tasks: registered results, facts, vars and loop variables.
+func collectTaskVariables(data []byte, local map[string]bool) {
+	var tasks []map[string]any
+	if err := yaml.Unmarshal(data, &tasks); err != nil {
+		return
+	}
+
+	var walk func(tasks []map[string]any)
+	walk = func(tasks []map[string]any) {
+		for _, t := range tasks {
+			if r, ok := t["register"].(string); ok {
+				local[r] = true
+			}
+
+			for _, key := range []string{"vars", "set_fact", "ansible.builtin.set_fact"} {
+				if m, ok := t[key].(map[string]any); ok {
+					for k := range m {
+						local[k] = true
+					}
+				}
+			}
+
+			if lc, ok := t["loop_control"].(map[string]any); ok {
+				if lv, ok := lc["loop_var"].(string); ok {
+					local[lv] = true
+				}
+			}
+
+			for _, key := range []string{"block", "rescue", "always"} {
+				if items, ok := t[key].([]any); ok {
+					var nested []map[string]any
+					for _, i := range items {
+						if m, ok := i.(map[string]any); ok {
+							nested = append(nested, m)
+						}
+					}
+					walk(nested)
+				}
+			}
+		}
+	}
+
+	walk(tasks)
+}
+
+// chassisVariables returns variables defined for component in chassis scope:
+// component defaults and vars, layer and platform group_vars, and chassis config overrides of the path and its ancestors.
+// Encrypted vault files which keys can't be read are returned separately.
+func chassisVariables(dir, componentName, chassisPath string) (map[string]bool, []string, error) {
+	defined := make(map[string]bool)
+	var skipped []string
+
+	var files []string
+	for _, section := range []string{"defaults", "vars"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, section, "*.y*ml"))
+		files = append(files, matches...)
+	}
+
+	layer := strings.Split(componentName, ".")[0]
+	for _, l := range []string{"platform", layer} {
+		_ = filepath.Walk(filepath.Join("src", l, "group_vars"), func(path string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() && (filepath.Ext(path) == ".yaml" || filepath.Ext(path) == ".yml") {
+				files = append(files, path)
+			}
+			return nil
+		})
+	}
+
+	// Chassis overrides are inherited from ancestors: platform.foundation, platform.foundation.cluster, ...
+	parts := strings.Split(chassisPath, ".")
+	for i := 2; i <= len(parts); i++ {
+		scope := strings.Join(parts[:i], ".")
+		for _, name := range []string{"vars.yaml", "vault.yaml"} {
+			files = append(files, filepath.Join("src", parts[1], "cfg", scope, name))
+		}
+	}
+
+	for _, f := range files {
+		data, err := os.ReadFile(filepath.Clean(f))
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, nil, err
+		}
+
+		if strings.HasPrefix(strings.TrimSpace(string(data)), vaultHeader) {
+			skipped = append(skipped, f)
+			continue
+		}
+
+		var values map[string]any
+		if err = yaml.Unmarshal(data, &values); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse %s: %w", f, err)
+		}
+
+		for k := range values {
+			defined[k] = true
+		}
+	}
+
+	return defined, skipped, nil
+}
+
+func appendUniqueString(slice []string, value string) []string {
+	for _, v := range slice {
+		if v == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
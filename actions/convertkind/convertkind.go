@@ -0,0 +1,209 @@
+package convertkind
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// ChangedFile represents a file which references were rewritten.
+type ChangedFile struct {
+	Path    string `json:"path"`
+	Changes int    `json:"changes"`
+}
+
+// ConvertKindResult is the structured result of component:convert-kind.
+type ConvertKindResult struct {
+	Old     string        `json:"old"`
+	New     string        `json:"new"`
+	OldPath string        `json:"old_path"`
+	NewPath string        `json:"new_path"`
+	Files   []ChangedFile `json:"files"`
+	DryRun  bool          `json:"dry_run"`
+}
+
+// ConvertKind implements component:convert-kind command
+type ConvertKind struct {
+	action.WithLogger
+	action.WithTerm
+
+	Component string
+	Kind      string
+	Source    string
+	DryRun    bool
+	// ExtraKinds are component kinds registered by plugins, accepted as the built-in ones.
+	ExtraKinds []string
+
+	result *ConvertKindResult
+}
+
+// Result returns the structured result for JSON output.
+func (c *ConvertKind) Result() any {
+	return c.result
+}
+
+// Execute runs the convert-kind action
+func (c *ConvertKind) Execute() error {
+	parts := strings.Split(c.Component, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("invalid component name %q (expected: layer.kind.name)", c.Component)
+	}
+	if !sync.IsUpdatableKind(c.Kind, c.ExtraKinds...) {
+		return fmt.Errorf("invalid kind %q (expected one of: %s)", c.Kind, strings.Join(kinds(c.ExtraKinds), ", "))
+	}
+	if parts[1] == c.Kind {
+		return fmt.Errorf("component %s is already of kind %s", c.Component, c.Kind)
+	}
+
+	oldPath, newPath := c.paths(parts)
+	if oldPath == "" {
+		return fmt.Errorf("component %s not found in %s", c.Component, c.Source)
+	}
+	if _, err := os.Stat(filepath.Join(c.Source, newPath)); err == nil {
+		return fmt.Errorf("target path %s already exists", newPath)
+	}
+
+	newName := sync.PrepareComponentName(parts[0], c.Kind, parts[2])
+	c.result = &ConvertKindResult{Old: c.Component, New: newName, OldPath: oldPath, NewPath: newPath, DryRun: c.DryRun}
+
+	files, err := c.referencingFiles()
+	if err != nil {
+		return err
+	}
+
+	re := referenceRegexp(c.Component)
+	for _, path := range files {
+		changes, errFile := c.rewrite(path, re, newName)
+		if errFile != nil {
+			return errFile
+		}
+		if changes > 0 {
+			c.result.Files = append(c.result.Files, ChangedFile{Path: path, Changes: changes})
+		}
+	}
+
+	c.Term().Printfln("move %s -> %s", oldPath, newPath)
+	if c.DryRun {
+		c.Term().Info().Printfln("Dry-run: %s would be converted to %s, %d file(s) referencing it", c.Component, newName, len(c.result.Files))
+		return nil
+	}
+
+	target := filepath.Join(c.Source, newPath)
+	if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+		return err
+	}
+	if err = os.Rename(filepath.Join(c.Source, oldPath), target); err != nil {
+		return fmt.Errorf("failed to move component > %w", err)
+	}
+
+	c.Term().Success().Printfln("Converted %s to %s, updated %d file(s)", c.Component, newName, len(c.result.Files))
+	return nil
+}
+
+// paths returns current component path and its path under the new kind, keeping the layout.
+func (c *ConvertKind) paths(parts []string) (string, string) {
+	layouts := [][2]string{
+		{filepath.Join(parts[0], parts[1], parts[2]), filepath.Join(parts[0], c.Kind, parts[2])},
+		{filepath.Join(parts[0], parts[1], "roles", parts[2]), filepath.Join(parts[0], c.Kind, "roles", parts[2])},
+	}
+
+	for _, l := range layouts {
+		if _, err := os.Stat(filepath.Join(c.Source, l[0], "meta", "plasma.yaml")); err == nil {
+			return l[0], l[1]
+		}
+	}
+
+	return "", ""
+}
+
+// referencingFiles returns YAML files of the source relative to it, skipping hidden directories.
+// Playbooks, dependencies and tasks including the component role are all YAML files.
+func (c *ConvertKind) referencingFiles() ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(c.Source, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if path != c.Source && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if ext := filepath.Ext(path); ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(c.Source, path)
+		files = append(files, rel)
+		return nil
+	})
+
+	sort.Strings(files)
+	return files, err
+}
+
+// rewrite replaces component name references in the file, printing changed lines.
+func (c *ConvertKind) rewrite(path string, re *regexp.Regexp, newName string) (int, error) {
+	fullPath := filepath.Join(c.Source, path)
+	data, err := os.ReadFile(filepath.Clean(fullPath))
+	if err != nil {
+		return 0, err
+	}
+
+	lines := strings.Split(string(data), "\n")
+	changes := 0
+	for i, line := range lines {
+		updated := re.ReplaceAllString(line, "${1}"+newName+"${2}")
+		if updated == line {
+			continue
+		}
+
+		if changes == 0 {
+			c.Term().Info().Printfln("--- %s", path)
+		}
+		c.Term().Printfln("- %s", line)
+		c.Term().Printfln("+ %s", updated)
+		lines[i] = updated
+		changes++
+	}
+
+	if changes == 0 || c.DryRun {
+		return changes, nil
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		return changes, err
+	}
+
+	return changes, os.WriteFile(fullPath, []byte(strings.Join(lines, "\n")), info.Mode())
+}
+
+// referenceRegexp matches the component name not being a part of a longer name.
+func referenceRegexp(name string) *regexp.Regexp {
+	return regexp.MustCompile(`(^|[^A-Za-z0-9_.\-])` + regexp.QuoteMeta(name) + `($|[^A-Za-z0-9_.\-])`)
+}
+
+func kinds(extra []string) []string {
+	result := make([]string, 0, len(sync.Kinds)+len(extra))
+	for k := range sync.Kinds {
+		result = append(result, k)
+	}
+	for _, k := range extra {
+		if !sync.IsUpdatableKind(k) {
+			result = append(result, k)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,30 @@
+package convertkind_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/plasmash/plasmactl-component/actions/convertkind"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+)
+
+func TestConvertKindExtraKinds(t *testing.T) {
+	testenv.NewPlatform(t)
+
+	if err := testenv.Run(t, &convertkind.ConvertKind{Component: testenv.Auth, Kind: "agents", Source: ".", DryRun: true}); err == nil {
+		t.Fatal("expected kind unknown without extra kinds")
+	}
+
+	err := testenv.Run(t, &convertkind.ConvertKind{Component: testenv.Auth, Kind: "unknown", Source: ".", ExtraKinds: []string{"agents"}})
+	if err == nil || !strings.Contains(err.Error(), "agents") {
+		t.Errorf("expected extra kinds listed as expected ones, got %v", err)
+	}
+
+	ck := &convertkind.ConvertKind{Component: testenv.Auth, Kind: "agents", Source: ".", DryRun: true, ExtraKinds: []string{"agents"}}
+	if err = testenv.Run(t, ck); err != nil {
+		t.Fatalf("convert to extra kind: %v", err)
+	}
+	if res := ck.Result().(*convertkind.ConvertKindResult); res.New != "foundation.agents.auth" {
+		t.Errorf("expected foundation.agents.auth, got %+v", res)
+	}
+}
@@ -0,0 +1,202 @@
+package create
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/launchrctl/launchr/pkg/action"
+)
+
+// TemplatesDir is the default directory of user-defined component templates.
+const TemplatesDir = ".plasmactl/templates"
+
+// defaultKind is the template directory used for kinds without own template.
+const defaultKind = "default"
+
+// templateExt marks files rendered with Go templates, other files are copied as is.
+const templateExt = ".tmpl"
+
+//go:embed all:templates
+var builtinTemplates embed.FS
+
+// TemplateData holds variables available in component templates.
+type TemplateData struct {
+	MRN   string
+	Layer string
+	Kind  string
+	Role  string
+	Owner string
+}
+
+// CreateResult is the structured result of component:create.
+type CreateResult struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Template string   `json:"template"`
+	Files    []string `json:"files"`
+	DryRun   bool     `json:"dry_run"`
+}
+
+// Create implements component:create command
+type Create struct {
+	action.WithLogger
+	action.WithTerm
+
+	Component    string
+	Owner        string
+	Source       string
+	TemplatesDir string
+	DryRun       bool
+	// Templates are contributed by other plugins by kind, they take precedence over built-in templates.
+	Templates map[string]fs.FS
+
+	result *CreateResult
+}
+
+// Result returns the structured result for JSON output.
+func (c *Create) Result() any {
+	return c.result
+}
+
+// Execute runs the create action
+func (c *Create) Execute() error {
+	parts := strings.Split(c.Component, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("invalid component name %q (expected: layer.kind.name)", c.Component)
+	}
+
+	data := TemplateData{MRN: c.Component, Layer: parts[0], Kind: parts[1], Role: parts[2], Owner: c.Owner}
+	componentDir := filepath.Join(c.Source, parts[0], parts[1], parts[2])
+	if _, err := os.Stat(componentDir); err == nil {
+		return fmt.Errorf("component %s already exists in %s", c.Component, componentDir)
+	}
+
+	tplFS, tplName, err := c.lookupTemplate(data.Kind)
+	if err != nil {
+		return err
+	}
+	c.Log().Debug("selected template", "name", tplName)
+
+	files, err := render(tplFS, data)
+	if err != nil {
+		return fmt.Errorf("failed to render template %s > %w", tplName, err)
+	}
+
+	c.result = &CreateResult{Name: c.Component, Path: componentDir, Template: tplName, DryRun: c.DryRun}
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		c.result.Files = append(c.result.Files, name)
+		c.Term().Printfln("+ %s", filepath.Join(componentDir, name))
+		if c.DryRun {
+			continue
+		}
+
+		target := filepath.Join(componentDir, name)
+		if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+		if err = os.WriteFile(target, files[name], 0600); err != nil {
+			return fmt.Errorf("failed to write %s > %w", target, err)
+		}
+	}
+
+	if c.DryRun {
+		c.Term().Info().Printfln("Dry-run, component %s from template %s not created", c.Component, tplName)
+		return nil
+	}
+
+	c.Term().Success().Printfln("Created component %s from template %s", c.Component, tplName)
+	return nil
+}
+
+// lookupTemplate returns template of the kind. User templates take precedence over contributed ones, contributed
+// ones over built-in ones, a kind template over the default one.
+func (c *Create) lookupTemplate(kind string) (fs.FS, string, error) {
+	if c.TemplatesDir != "" {
+		for _, name := range []string{kind, defaultKind} {
+			dir := filepath.Join(c.TemplatesDir, name)
+			if info, err := os.Stat(dir); err == nil && info.IsDir() {
+				return os.DirFS(dir), dir, nil
+			}
+		}
+	}
+
+	for _, name := range []string{kind, defaultKind} {
+		if tplFS, ok := c.Templates[name]; ok {
+			return tplFS, "extension/" + name, nil
+		}
+	}
+
+	for _, name := range []string{kind, defaultKind} {
+		sub, err := fs.Sub(builtinTemplates, path.Join("templates", name))
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err = fs.Stat(sub, "."); err == nil {
+			return sub, "builtin/" + name, nil
+		}
+	}
+
+	return nil, "", fmt.Errorf("no template found for kind %s", kind)
+}
+
+// render renders template files and paths. File contents are rendered only for files with .tmpl extension,
+// to keep Jinja templates of the component intact.
+func render(tplFS fs.FS, data TemplateData) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(tplFS, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		content, err := fs.ReadFile(tplFS, p)
+		if err != nil {
+			return err
+		}
+
+		name, err := execute(p, []byte(p), data)
+		if err != nil {
+			return err
+		}
+
+		if strings.HasSuffix(p, templateExt) {
+			name = bytes.TrimSuffix(name, []byte(templateExt))
+			content, err = execute(p, content, data)
+			if err != nil {
+				return err
+			}
+		}
+
+		files[filepath.FromSlash(string(name))] = content
+		return nil
+	})
+
+	return files, err
+}
+
+func execute(name string, text []byte, data TemplateData) ([]byte, error) {
+	tpl, err := template.New(name).Option("missingkey=error").Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	if err = tpl.Execute(&b, data); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
@@ -1,33 +1,54 @@
 package depend
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 
+	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-component/internal/architecture"
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
 	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 	"github.com/plasmash/plasmactl-platform/pkg/graph"
 	"gopkg.in/yaml.v3"
 )
 
 // DependOpResult represents the result of a single dependency operation.
 type DependOpResult struct {
-	Type    string `json:"type"`
-	Dep     string `json:"dep"`
-	NewDep  string `json:"new_dep,omitempty"`
-	Applied bool   `json:"applied"`
+	Target     string `json:"target,omitempty"`
+	Type       string `json:"type"`
+	Dep        string `json:"dep"`
+	NewDep     string `json:"new_dep,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	Applied    bool   `json:"applied"`
 }
 
 // DependResult is the structured result of component:depend.
 type DependResult struct {
-	Target     string           `json:"target"`
+	Target     string           `json:"target,omitempty"`
 	Mode       string           `json:"mode"`
 	Requires   []string         `json:"requires,omitempty"`
 	RequiredBy []string         `json:"required_by,omitempty"`
 	Operations []DependOpResult `json:"operations,omitempty"`
+	Edges      []DependencyEdge `json:"edges,omitempty"`
+	Added      []DependencyEdge `json:"added,omitempty"`
+	Removed    []DependencyEdge `json:"removed,omitempty"`
+
+	Violations []architecture.Violation `json:"violations,omitempty"`
+	Cycles     []sync.Cycle             `json:"cycles,omitempty"`
+	Other      string                   `json:"other,omitempty"`
+	Paths      [][]DependencyEdge       `json:"paths,omitempty"`
+	Origins    []Origin                 `json:"origins,omitempty"`
+	Statuses   []ComponentStatus        `json:"statuses,omitempty"`
+	Locations  []component.Location     `json:"locations,omitempty"`
+	Warnings   warning.List             `json:"warnings,omitempty"`
 }
 
 // Depend implements component:depend command
@@ -44,10 +65,53 @@ type Depend struct {
 	Path    bool // show paths instead of MRNs
 	Tree    bool // show tree-like output
 	Reverse bool // show reverse dependencies (requiredby)
-	Depth   int8 // recursion depth limit
+	Depth   int8 // recursion depth limit, negative for unlimited
 	Build   bool // include build dependencies (from main.yaml)
-
-	result *DependResult
+	Origin  bool // annotate components with the domain or package providing them
+	Status  bool // annotate components with their version and status markers
+	// ResolvePaths adds the source directories of the target and shown components to the result.
+	ResolvePaths bool
+	// Collapse summarizes subtrees of components reached several times with more components than this, in tree mode.
+	Collapse int
+	// Ref shows dependencies of domain components at a commit, tag or branch, read from the git objects without checkout.
+	Ref string
+	// Format prints the subgraph rooted at the target as dot, mermaid or json instead of a list.
+	Format string
+
+	// Origin options
+	DomainDir   string
+	PackagesDir string
+
+	// Snapshot options
+	Snapshot      string // write dependency graph snapshot to file
+	CheckSnapshot string // compare dependency graph against snapshot file
+
+	// Architecture options
+	Architecture      architecture.Matrix // allowed dependencies matrix
+	CheckArchitecture bool                // report existing violations of the matrix
+
+	// CheckCycles reports dependency cycles of the source components, with build dependencies if Build is set.
+	CheckCycles bool
+
+	// Why prints dependency paths between the target and this component.
+	Why string
+
+	// Force writes added dependencies closing a cycle or referring to unknown components, with a warning.
+	Force bool
+
+	// FromFile applies operations of targets declared in the YAML file, "-" to read it from Streams input.
+	FromFile string
+	// ReverseEdit applies operations to the dependents they name instead of the target: DEPENDENT adds the target
+	// to their dependencies, DEPENDENT- removes it, OLD/NEW moves it.
+	ReverseEdit bool
+	// RemoveFromAll removes the target from dependencies of all domain components depending on it.
+	RemoveFromAll bool
+	// Streams provide the operations file input, the process streams if nil.
+	Streams launchr.Streams
+
+	origins  map[string]*Origin
+	statuses map[string]*ComponentStatus
+	result   *DependResult
 }
 
 // Result returns the structured result for JSON output.
@@ -60,10 +124,71 @@ type DependOp struct {
 	Type   string // "add", "remove", "replace"
 	Dep    string
 	NewVal string // only for replace
+	// Constraint is the version constraint of the added dependency, set with DEP@CONSTRAINT. Constrained tells it
+	// was given, an empty constraint clears the existing one.
+	Constraint  sync.VersionConstraint
+	Constrained bool
+}
+
+// constraintSuffix returns the @CONSTRAINT suffix of the operation added dependency, empty without constraint.
+func (op DependOp) constraintSuffix() string {
+	if !op.Constrained {
+		return ""
+	}
+	return "@" + string(op.Constraint)
 }
 
 // Execute runs the depend action
 func (d *Depend) Execute() error {
+	if d.Ref != "" && (d.Snapshot != "" || d.CheckSnapshot != "" || d.CheckArchitecture || d.CheckCycles || d.ResolvePaths || len(d.Operations) > 0 || d.FromFile != "" || d.RemoveFromAll) {
+		return fmt.Errorf("--ref only shows dependencies, it can't be combined with operations, snapshots, --check-architecture, --check-cycles, --resolve-paths, --from-file or --remove-from-all")
+	}
+
+	if err := d.validateFormat(); err != nil {
+		return err
+	}
+
+	if d.FromFile != "" {
+		if d.Target != "" || len(d.Operations) > 0 {
+			return fmt.Errorf("--from-file declares targets and operations, they can't be given as arguments")
+		}
+		if d.ReverseEdit || d.RemoveFromAll {
+			return fmt.Errorf("--from-file can't be combined with --reverse-edit or --remove-from-all")
+		}
+		return d.executeFromFile()
+	}
+
+	if d.Snapshot != "" {
+		return d.executeSnapshot()
+	}
+	if d.CheckSnapshot != "" {
+		return d.executeCheckSnapshot()
+	}
+	if d.CheckArchitecture {
+		return d.executeCheckArchitecture()
+	}
+	if d.CheckCycles {
+		return d.executeCheckCycles()
+	}
+
+	if d.Target == "" {
+		return fmt.Errorf("target is required unless --snapshot, --check-snapshot, --check-architecture or --check-cycles is used")
+	}
+
+	if d.Why != "" && (len(d.Operations) > 0 || d.RemoveFromAll) {
+		return fmt.Errorf("--why only shows dependency paths, it can't be combined with operations")
+	}
+
+	if d.RemoveFromAll || d.ReverseEdit {
+		if d.ReverseEdit && len(d.Operations) == 0 {
+			return fmt.Errorf("--reverse-edit expects operations naming dependents of the target")
+		}
+		if d.RemoveFromAll && len(d.Operations) > 0 && !d.ReverseEdit {
+			return fmt.Errorf("--remove-from-all edits dependents of the target, operations require --reverse-edit")
+		}
+		return d.executeReverseOperations()
+	}
+
 	// No operations = show mode
 	if len(d.Operations) == 0 {
 		return d.executeShow()
@@ -91,39 +216,51 @@ func (d *Depend) depEdgeTypes() []string {
 
 // executeShow displays dependencies using the platform graph.
 func (d *Depend) executeShow() error {
+	if d.Ref != "" {
+		return d.executeShowRef()
+	}
+
 	g, err := graph.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load graph: %w", err)
 	}
 
-	// Resolve target to MRN
-	searchMrn := d.Target
-	if g.Node(searchMrn) == nil {
-		// Not found directly — try converting from path
-		c := sync.BuildComponentFromPath(d.Target, d.Source)
-		if c == nil {
-			return fmt.Errorf("not valid component %q", d.Target)
+	searchMrn, err := d.resolveGraphComponent(g, d.Target)
+	if err != nil {
+		return err
+	}
+
+	edgeTypes := d.depEdgeTypes()
+	if d.Why != "" {
+		other, errOther := d.resolveGraphComponent(g, d.Why)
+		if errOther != nil {
+			return errOther
 		}
-		searchMrn = c.GetName()
-		if g.Node(searchMrn) == nil {
-			return fmt.Errorf("component %q not found in graph", searchMrn)
+		if err = d.executeWhy(searchMrn, other, graphDeps{g, edgeTypes}); err == nil {
+			d.warnStaleGraph()
 		}
+		return err
 	}
 
-	edgeTypes := d.depEdgeTypes()
 	depth := int(d.Depth)
 
 	// Get parents (what depends on target) and children (what target depends on)
-	ancestors := g.Ancestors(searchMrn, depth, edgeTypes...)
-	descendants := g.Descendants(searchMrn, depth, edgeTypes...)
+	var parents, children map[string]bool
+	if depth < 0 {
+		parents = reachable(graphDeps{g, edgeTypes}, searchMrn, true, depth)
+		children = reachable(graphDeps{g, edgeTypes}, searchMrn, false, depth)
+	} else {
+		ancestors := g.Ancestors(searchMrn, depth, edgeTypes...)
+		descendants := g.Descendants(searchMrn, depth, edgeTypes...)
 
-	parents := make(map[string]bool, len(ancestors))
-	for _, n := range ancestors {
-		parents[n.Name] = true
-	}
-	children := make(map[string]bool, len(descendants))
-	for _, n := range descendants {
-		children[n.Name] = true
+		parents = make(map[string]bool, len(ancestors))
+		for _, n := range ancestors {
+			parents[n.Name] = true
+		}
+		children = make(map[string]bool, len(descendants))
+		for _, n := range descendants {
+			children[n.Name] = true
+		}
 	}
 
 	// Build sorted slices for the result
@@ -144,6 +281,79 @@ func (d *Depend) executeShow() error {
 		Requires:   requires,
 		RequiredBy: requiredBy,
 	}
+	d.warnStaleGraph()
+
+	if d.Origin {
+		shown := children
+		if d.Reverse {
+			shown = parents
+		}
+		d.result.Origins, err = d.annotateOrigins(g, searchMrn, shown, edgeTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.Status {
+		shown := children
+		if d.Reverse {
+			shown = parents
+		}
+		d.result.Statuses, err = d.annotateStatuses(g, searchMrn, shown)
+		if err != nil {
+			return err
+		}
+	}
+
+	if d.ResolvePaths {
+		shown := children
+		if d.Reverse {
+			shown = parents
+		}
+		d.result.Locations, err = d.locate(searchMrn, shown)
+		if err != nil {
+			return err
+		}
+	}
+
+	return d.printDependencies(searchMrn, graphDeps{g, edgeTypes}, parents, children)
+}
+
+// warnStaleGraph warns when components were modified since the platform graph was generated.
+func (d *Depend) warnStaleGraph() {
+	if msg := graphstate.Check(d.DomainDir); msg != "" {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.StaleGraph, "", "%s", msg))
+	}
+}
+
+// resolveGraphComponent resolves the component MRN or path to a component of the graph.
+func (d *Depend) resolveGraphComponent(g *graph.PlatformGraph, name string) (string, error) {
+	if g.Node(name) != nil {
+		return name, nil
+	}
+
+	// Not found directly — try converting from path
+	c := sync.BuildComponentFromPath(name, d.Source)
+	if c == nil {
+		return "", fmt.Errorf("not valid component %q", name)
+	}
+	if g.Node(c.GetName()) == nil {
+		return "", fmt.Errorf("component %q not found in graph", c.GetName())
+	}
+
+	return c.GetName(), nil
+}
+
+// printDependencies prints dependencies of the target, or its dependents in reverse mode, as a list, a tree
+// or a graph.
+func (d *Depend) printDependencies(target string, deps depGraph, parents, children map[string]bool) error {
+	if d.Format != "" {
+		shown := children
+		if d.Reverse {
+			shown = parents
+		}
+		return d.printGraph(target, deps, shown)
+	}
 
 	if len(parents) == 0 && len(children) == 0 {
 		d.Term().Info().Println("No dependencies found")
@@ -153,7 +363,7 @@ func (d *Depend) executeShow() error {
 	}
 
 	if d.Tree {
-		d.printTree(searchMrn, g, edgeTypes, d.Reverse, d.Path, d.Depth)
+		d.printTree(target, deps, d.Reverse, d.Path, d.Depth)
 	} else if d.Reverse {
 		if len(parents) > 0 {
 			d.printList(parents, d.Path, "requiredby")
@@ -167,27 +377,178 @@ func (d *Depend) executeShow() error {
 	return nil
 }
 
-// executeOperations applies kubectl-style operations
-func (d *Depend) executeOperations() error {
-	targetPath, err := d.resolveTargetPath()
+// executeCheckArchitecture reports existing dependencies violating the architecture matrix.
+func (d *Depend) executeCheckArchitecture() error {
+	if len(d.Architecture) == 0 {
+		d.result = &DependResult{Mode: "check-architecture"}
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Empty, "", "No architecture rules configured"))
+		return nil
+	}
+
+	g, err := graph.Load()
 	if err != nil {
+		return fmt.Errorf("failed to load graph: %w", err)
+	}
+
+	edgeTypes := d.depEdgeTypes()
+	deps := make(map[string][]string)
+	for _, n := range g.NodesByType("component") {
+		for _, e := range g.EdgesFrom(n.Name, edgeTypes...) {
+			deps[n.Name] = append(deps[n.Name], e.To().Name)
+		}
+	}
+
+	violations := d.Architecture.CheckAll(deps)
+	d.result = &DependResult{
+		Mode:       "check-architecture",
+		Violations: violations,
+	}
+
+	if len(violations) == 0 {
+		d.Term().Success().Println("No architecture violations found")
+		return nil
+	}
+
+	for _, v := range violations {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Architecture, v.From, "%s", v))
+	}
+
+	return fmt.Errorf("found %d architecture violation(s)", len(violations))
+}
+
+// executeCheckCycles reports dependency cycles of the source components with the files declaring their dependencies.
+func (d *Depend) executeCheckCycles() error {
+	inv, err := sync.NewInventory(d.Source, d.Log())
+	var cycleErr *sync.CycleError
+	if err != nil && !errors.As(err, &cycleErr) {
 		return err
 	}
 
-	depsFile := filepath.Join(targetPath, "tasks", "dependencies.yaml")
-	deps, err := d.loadDependencies(depsFile)
-	if err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to load dependencies: %w", err)
+	cycles := inv.FindCycles(d.Build)
+	d.result = &DependResult{
+		Mode:   "check-cycles",
+		Cycles: cycles,
+	}
+
+	if len(cycles) == 0 {
+		d.Term().Success().Println("No dependency cycles found")
+		return nil
 	}
 
-	// Parse operations
-	ops := d.parseOperations()
-	modified := false
+	for _, c := range cycles {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Cycle, c[0].From, "%s", c))
+		for _, e := range c {
+			location := e.File
+			if e.Line > 0 {
+				location = fmt.Sprintf("%s:%d", e.File, e.Line)
+			}
+			kind := "requires"
+			if e.Build {
+				kind = "builds"
+			}
+			d.Term().Printfln("  %s %s %s (%s)", e.From, kind, e.To, location)
+		}
+	}
+
+	return fmt.Errorf("found %d dependency cycle(s)", len(cycles))
+}
+
+// executeOperations applies kubectl-style operations
+func (d *Depend) executeOperations() error {
 	d.result = &DependResult{
 		Target: d.Target,
 		Mode:   "operations",
 	}
 
+	return d.applyOperations([]TargetOperations{{Target: d.Target, Operations: d.Operations}})
+}
+
+// applyOperations applies operations of all targets, then writes the modified dependencies files.
+// Nothing is written if an operation fails.
+func (d *Depend) applyOperations(entries []TargetOperations) error {
+	var edits []*dependencyEdit
+	byPath := make(map[string]*dependencyEdit)
+	edited := make(map[string]*[]string)
+
+	// Added dependencies are validated against the graph with the previous operations applied.
+	var pending *pendingDeps
+	validation := false
+
+	// Operations of several targets are printed under the target they apply to.
+	manyTargets := d.FromFile != "" || d.ReverseEdit || d.RemoveFromAll
+
+	for _, entry := range entries {
+		ops := d.parseOperations(entry.Operations)
+		if hasAdditions(ops) && !validation {
+			validation = true
+			if base := d.loadValidationDeps(); base != nil {
+				pending = &pendingDeps{knownDeps: base, edited: edited}
+			}
+		}
+
+		for _, target := range entry.targets() {
+			targetPath, err := d.resolveTargetPath(target)
+			if err != nil {
+				return err
+			}
+
+			edit, ok := byPath[targetPath]
+			if !ok {
+				if edit, err = d.loadEdit(target, targetPath); err != nil {
+					return err
+				}
+				byPath[targetPath] = edit
+				edited[edit.target] = &edit.deps
+				edits = append(edits, edit)
+			}
+
+			if manyTargets {
+				d.Term().Info().Println(edit.target)
+			}
+			if err = d.applyTargetOperations(edit, ops, pending); err != nil {
+				if manyTargets {
+					return fmt.Errorf("%s: %w, nothing was written", edit.target, err)
+				}
+				return err
+			}
+		}
+	}
+
+	return d.saveEdits(edits)
+}
+
+// loadEdit reads dependencies of the target to edit them.
+func (d *Depend) loadEdit(target, targetPath string) (*dependencyEdit, error) {
+	targetMrn, err := d.resolveDependencyMRN(target)
+	if err != nil {
+		return nil, err
+	}
+
+	edit := &dependencyEdit{target: targetMrn, file: filepath.Join(targetPath, "tasks", "dependencies.yaml")}
+	edit.original, err = os.ReadFile(edit.file)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to load dependencies: %w", err)
+	}
+	if err == nil {
+		deps, errLoad := d.loadDependencies(edit.file)
+		if errLoad != nil {
+			return nil, fmt.Errorf("failed to load dependencies: %w", errLoad)
+		}
+		edit.constraints = make(map[string]sync.VersionConstraint)
+		for _, dep := range deps {
+			edit.deps = append(edit.deps, dep.Name)
+			if dep.Constraint != "" {
+				edit.constraints[dep.Name] = dep.Constraint
+			}
+		}
+	}
+
+	return edit, nil
+}
+
+// applyTargetOperations applies operations to the edited dependencies of a target.
+func (d *Depend) applyTargetOperations(edit *dependencyEdit, ops []DependOp, pending *pendingDeps) error {
+	targetMrn := edit.target
 	for _, op := range ops {
 		depMrn, err := d.resolveDependencyMRN(op.Dep)
 		if err != nil {
@@ -196,73 +557,103 @@ func (d *Depend) executeOperations() error {
 
 		switch op.Type {
 		case "add":
-			applied := d.addDep(&deps, depMrn)
+			if v := d.Architecture.Check(targetMrn, depMrn); v != nil {
+				return fmt.Errorf("dependency is not allowed by architecture: %s", v)
+			}
+			if err = d.validateAddition(pending, targetMrn, depMrn); err != nil {
+				return err
+			}
+			if err = validateConstraint(op); err != nil {
+				return err
+			}
+			added := d.addDep(&edit.deps, depMrn)
+			constrained := edit.constrain(depMrn, op)
 			d.result.Operations = append(d.result.Operations, DependOpResult{
-				Type: "add", Dep: depMrn, Applied: applied,
+				Target: targetMrn, Type: "add", Dep: depMrn, Constraint: string(edit.constraints[depMrn]), Applied: added || constrained,
 			})
-			if applied {
-				d.Term().Success().Printfln("Added: %s", depMrn)
-				modified = true
-			} else {
-				d.Term().Warning().Printfln("Already exists: %s", depMrn)
+			switch {
+			case added:
+				d.Term().Success().Printfln("Added: %s%s", depMrn, constraintLabel(edit.constraints[depMrn]))
+				edit.modified = true
+			case constrained:
+				d.Term().Success().Printfln("Constrained: %s%s", depMrn, constraintLabel(edit.constraints[depMrn]))
+				edit.modified = true
+			default:
+				d.Term().Warning().Println(d.result.Warnings.Add(warning.Unchanged, depMrn, "Already exists: %s", depMrn))
 			}
 		case "remove":
-			applied := d.removeDep(&deps, depMrn)
+			applied := d.removeDep(&edit.deps, depMrn)
+			delete(edit.constraints, depMrn)
 			d.result.Operations = append(d.result.Operations, DependOpResult{
-				Type: "remove", Dep: depMrn, Applied: applied,
+				Target: targetMrn, Type: "remove", Dep: depMrn, Applied: applied,
 			})
 			if applied {
 				d.Term().Success().Printfln("Removed: %s", depMrn)
-				modified = true
+				edit.modified = true
 			} else {
-				d.Term().Warning().Printfln("Not found: %s", depMrn)
+				d.Term().Warning().Println(d.result.Warnings.Add(warning.Unchanged, depMrn, "Not found: %s", depMrn))
 			}
 		case "replace":
 			newMrn, err := d.resolveDependencyMRN(op.NewVal)
 			if err != nil {
 				return err
 			}
-			removed := d.removeDep(&deps, depMrn)
-			added := d.addDep(&deps, newMrn)
-			applied := removed || added
+			if v := d.Architecture.Check(targetMrn, newMrn); v != nil {
+				return fmt.Errorf("dependency is not allowed by architecture: %s", v)
+			}
+			if err = d.validateAddition(pending, targetMrn, newMrn); err != nil {
+				return err
+			}
+			if err = validateConstraint(op); err != nil {
+				return err
+			}
+			removed := d.removeDep(&edit.deps, depMrn)
+			if depMrn != newMrn {
+				delete(edit.constraints, depMrn)
+			}
+			added := d.addDep(&edit.deps, newMrn)
+			constrained := edit.constrain(newMrn, op)
+			applied := removed || added || constrained
 			d.result.Operations = append(d.result.Operations, DependOpResult{
-				Type: "replace", Dep: depMrn, NewDep: newMrn, Applied: applied,
+				Target: targetMrn, Type: "replace", Dep: depMrn, NewDep: newMrn, Constraint: string(edit.constraints[newMrn]), Applied: applied,
 			})
 			if applied {
-				d.Term().Success().Printfln("Replaced: %s → %s", depMrn, newMrn)
-				modified = true
+				d.Term().Success().Printfln("Replaced: %s → %s%s", depMrn, newMrn, constraintLabel(edit.constraints[newMrn]))
+				edit.modified = true
 			} else {
-				d.Term().Warning().Printfln("No change: %s → %s", depMrn, newMrn)
+				d.Term().Warning().Println(d.result.Warnings.Add(warning.Unchanged, depMrn, "No change: %s → %s", depMrn, newMrn))
 			}
 		}
 	}
 
-	if !modified {
-		return nil
-	}
-
-	sort.Strings(deps)
-
-	if err := d.saveDependencies(depsFile, deps); err != nil {
-		return fmt.Errorf("failed to save dependencies: %w", err)
-	}
-
 	return nil
 }
 
 // parseOperations parses kubectl-style operations
-func (d *Depend) parseOperations() []DependOp {
+func (d *Depend) parseOperations(args []string) []DependOp {
 	var ops []DependOp
 
-	for _, arg := range d.Operations {
+	for _, arg := range args {
 		switch {
 		case strings.Contains(arg, "/"):
-			// Replace: old/new
+			// Replace: old/new, old/new@constraint
 			parts := strings.SplitN(arg, "/", 2)
+			newVal, constraint, constrained := strings.Cut(parts[1], "@")
+			ops = append(ops, DependOp{
+				Type:        "replace",
+				Dep:         parts[0],
+				NewVal:      newVal,
+				Constraint:  sync.VersionConstraint(constraint),
+				Constrained: constrained,
+			})
+		case strings.Contains(arg, "@"):
+			// Add with version constraint: dep@constraint
+			dep, constraint, _ := strings.Cut(arg, "@")
 			ops = append(ops, DependOp{
-				Type:   "replace",
-				Dep:    parts[0],
-				NewVal: parts[1],
+				Type:        "add",
+				Dep:         dep,
+				Constraint:  sync.VersionConstraint(constraint),
+				Constrained: true,
 			})
 		case strings.HasSuffix(arg, "-"):
 			// Remove: dep-
@@ -304,18 +695,35 @@ func (d *Depend) removeDep(deps *[]string, dep string) bool {
 	return false
 }
 
+// validateConstraint checks the version constraint given by the operation, an empty one clears the constraint.
+func validateConstraint(op DependOp) error {
+	if op.Constraint == "" {
+		return nil
+	}
+	return op.Constraint.Validate()
+}
+
+// constraintLabel returns the constraint to print after a dependency, empty without constraint.
+func constraintLabel(c sync.VersionConstraint) string {
+	if c == "" {
+		return ""
+	}
+	return " (" + string(c) + ")"
+}
+
 // resolveTargetPath converts target to a filesystem path
-func (d *Depend) resolveTargetPath() (string, error) {
+func (d *Depend) resolveTargetPath(target string) (string, error) {
 	// Try as path first
-	if _, err := os.Stat(d.Target); err == nil {
-		return d.Target, nil
+	if _, err := os.Stat(target); err == nil {
+		return target, nil
 	}
 
 	// Try converting from MRN
-	path, err := sync.ConvertNameToPath(d.Target)
+	path, err := sync.ConvertNameToPath(target)
 	if err != nil {
-		return "", fmt.Errorf("cannot resolve target %q: %w", d.Target, err)
+		return "", fmt.Errorf("cannot resolve target %q: %w", target, err)
 	}
+	path = filepath.Join(d.DomainDir, path)
 
 	if _, err := os.Stat(path); err != nil {
 		return "", fmt.Errorf("target path does not exist: %s", path)
@@ -341,40 +749,26 @@ func (d *Depend) resolveDependencyMRN(dep string) (string, error) {
 }
 
 // loadDependencies reads the dependencies.yaml file
-func (d *Depend) loadDependencies(path string) ([]string, error) {
+func (d *Depend) loadDependencies(path string) ([]sync.Dependency, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return nil, err
 	}
 
-	var content struct {
-		Dependencies []string `yaml:"dependencies"`
-	}
-
-	if err := yaml.Unmarshal(data, &content); err != nil {
-		// Try as simple list
-		var deps []string
-		if err2 := yaml.Unmarshal(data, &deps); err2 != nil {
-			return nil, fmt.Errorf("failed to parse dependencies: %w", err)
-		}
-		return deps, nil
-	}
-
-	return content.Dependencies, nil
+	return sync.ParseDependencies(data)
 }
 
-// saveDependencies writes the dependencies.yaml file
-func (d *Depend) saveDependencies(path string, deps []string) error {
+// saveDependencies writes the dependencies.yaml file, with version constraints of dependencies
+func (d *Depend) saveDependencies(path string, deps []string, constraints map[string]sync.VersionConstraint) error {
 	// Ensure directory exists
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
-	content := struct {
-		Dependencies []string `yaml:"dependencies"`
-	}{
-		Dependencies: deps,
+	content := sync.DependenciesFile{Dependencies: make([]sync.Dependency, 0, len(deps))}
+	for _, dep := range deps {
+		content.Dependencies = append(content.Dependencies, sync.Dependency{Name: dep, Constraint: constraints[dep]})
 	}
 
 	data, err := yaml.Marshal(&content)
@@ -398,43 +792,72 @@ func (d *Depend) printList(items map[string]bool, toPath bool, prefix string) {
 			res, _ = sync.ConvertNameToPath(res)
 		}
 
-		d.Term().Printf("%s\t%s\n", prefix, res)
+		d.Term().Printf("%s\t%s%s%s\n", prefix, res, d.statusLabel(item), d.originLabel(item))
 	}
 }
 
-// printTree prints a dependency tree querying the graph dynamically.
-func (d *Depend) printTree(target string, g *graph.PlatformGraph, edgeTypes []string, reverse bool, toPath bool, depth int8) {
-	value := target
-	if toPath {
-		value, _ = sync.ConvertNameToPath(value)
-	}
-	d.Term().Printfln(value)
+// printTree prints a dependency tree querying the dependencies dynamically.
+// Negative depth prints the whole tree, components already printed are deduped and cycles are marked.
+func (d *Depend) printTree(target string, deps depGraph, reverse bool, toPath bool, depth int8) {
+	d.Term().Printfln(d.treeLabel(target, toPath))
+
+	t := &treeWalk{
+		deps:     deps,
+		reverse:  reverse,
+		toPath:   toPath,
+		maxDepth: depth,
+		seen:     map[string]bool{target: true},
+		path:     map[string]bool{target: true},
+		sizes:    make(map[string]int),
+	}
+	d.printTreeChildren(t, target, "", 0)
+}
 
-	seen := make(map[string]bool)
-	seen[target] = true
+// treeWalk holds the state of a dependency tree printing.
+type treeWalk struct {
+	deps     depGraph
+	reverse  bool
+	toPath   bool
+	maxDepth int8
+	// seen are components already printed, path are components of the branch being printed, to detect cycles.
+	seen map[string]bool
+	path map[string]bool
+	// sizes caches subtree sizes of components.
+	sizes map[string]int
+}
 
-	d.printTreeChildren(target, g, edgeTypes, reverse, "", toPath, 0, depth, seen)
+// children returns sorted components the component depends on, or its dependents in reverse mode.
+func (t *treeWalk) children(current string) []string {
+	childNames := neighbours(t.deps, current, t.reverse)
+	sort.Strings(childNames)
+	return childNames
 }
 
-// printTreeChildren recursively prints tree children querying the graph.
-func (d *Depend) printTreeChildren(current string, g *graph.PlatformGraph, edgeTypes []string, reverse bool, indent string, toPath bool, currentDepth, maxDepth int8, seen map[string]bool) {
-	if currentDepth >= maxDepth {
-		return
+// isRepeated tells if the component is reached from several components of the tree.
+func (t *treeWalk) isRepeated(name string) bool {
+	return len(neighbours(t.deps, name, !t.reverse)) > 1
+}
+
+// subtreeSize returns the number of distinct components below the component, cycles included once.
+func (t *treeWalk) subtreeSize(name string) int {
+	size, ok := t.sizes[name]
+	if !ok {
+		size = len(reachable(t.deps, name, t.reverse, -1))
+		t.sizes[name] = size
 	}
+	return size
+}
 
-	var childNames []string
-	if reverse {
-		for _, e := range g.EdgesTo(current, edgeTypes...) {
-			childNames = append(childNames, e.From().Name)
-		}
-	} else {
-		for _, e := range g.EdgesFrom(current, edgeTypes...) {
-			childNames = append(childNames, e.To().Name)
+// printTreeChildren recursively prints tree children querying the graph.
+func (d *Depend) printTreeChildren(t *treeWalk, current string, indent string, currentDepth int8) {
+	childNames := t.children(current)
+	if t.maxDepth >= 0 && currentDepth >= t.maxDepth {
+		if len(childNames) > 0 {
+			d.Term().Printfln(indent + "└── … " + strconv.Itoa(t.subtreeSize(current)) + " more")
 		}
+		return
 	}
 
-	sort.Strings(childNames)
-
 	for i, child := range childNames {
 		isLast := i == len(childNames)-1
 		edge := "├── "
@@ -444,17 +867,110 @@ func (d *Depend) printTreeChildren(current string, g *graph.PlatformGraph, edgeT
 			newIndent = indent + "    "
 		}
 
-		value := child
-		if toPath {
-			value, _ = sync.ConvertNameToPath(value)
+		label := d.treeLabel(child, t.toPath)
+		switch {
+		case t.path[child]:
+			d.Term().Printfln(indent + edge + label + " [cycle]")
+		case t.seen[child]:
+			d.Term().Printfln(indent + edge + label + " [deduped" + moreLabel(t.subtreeSize(child), ", ") + "]")
+		case d.Collapse > 0 && t.isRepeated(child) && t.subtreeSize(child) > d.Collapse:
+			t.seen[child] = true
+			d.Term().Printfln(indent + edge + label + " [collapsed" + moreLabel(t.subtreeSize(child), ", ") + "]")
+		default:
+			t.seen[child] = true
+			t.path[child] = true
+			d.Term().Printfln(indent + edge + label)
+			d.printTreeChildren(t, child, newIndent, currentDepth+1)
+			delete(t.path, child)
+		}
+	}
+}
+
+// treeLabel returns the component name or path with its origin.
+func (d *Depend) treeLabel(name string, toPath bool) string {
+	value := name
+	if toPath {
+		value, _ = sync.ConvertNameToPath(value)
+	}
+	return value + d.statusLabel(name) + d.originLabel(name)
+}
+
+// moreLabel returns the count of hidden components, empty if there are none.
+func moreLabel(count int, prefix string) string {
+	if count == 0 {
+		return ""
+	}
+	return prefix + "… " + strconv.Itoa(count) + " more"
+}
+
+// reachable returns components reached from the component through dependencies, or dependents in reverse mode,
+// up to the depth, unlimited if negative. Cycles are followed once.
+func reachable(deps depGraph, name string, reverse bool, depth int) map[string]bool {
+	found := make(map[string]bool)
+	level := []string{name}
+	for i := 0; len(level) > 0 && (depth < 0 || i < depth); i++ {
+		var next []string
+		for _, current := range level {
+			for _, n := range neighbours(deps, current, reverse) {
+				if n == name || found[n] {
+					continue
+				}
+				found[n] = true
+				next = append(next, n)
+			}
 		}
+		level = next
+	}
+
+	return found
+}
+
+// depGraph gives direct dependencies of components, of the platform graph or of an inventory.
+type depGraph interface {
+	// requires returns components the component depends on.
+	requires(name string) []string
+	// requiredBy returns components depending on the component.
+	requiredBy(name string) []string
+	// edges returns dependencies of the component with the edge types.
+	edges(name string) []DependencyEdge
+}
+
+// neighbours returns direct dependencies of the component, or its dependents in reverse mode.
+func neighbours(deps depGraph, name string, reverse bool) []string {
+	if reverse {
+		return deps.requiredBy(name)
+	}
+	return deps.requires(name)
+}
+
+// graphDeps are dependencies of the platform graph, of the edge types.
+type graphDeps struct {
+	g         *graph.PlatformGraph
+	edgeTypes []string
+}
+
+func (d graphDeps) requires(name string) []string {
+	var names []string
+	for _, e := range d.g.EdgesFrom(name, d.edgeTypes...) {
+		names = append(names, e.To().Name)
+	}
+	return names
+}
+
+func (d graphDeps) requiredBy(name string) []string {
+	var names []string
+	for _, e := range d.g.EdgesTo(name, d.edgeTypes...) {
+		names = append(names, e.From().Name)
+	}
+	return names
+}
 
-		if seen[child] {
-			d.Term().Printfln(indent + edge + value + " [deduped]")
-		} else {
-			seen[child] = true
-			d.Term().Printfln(indent + edge + value)
-			d.printTreeChildren(child, g, edgeTypes, reverse, newIndent, toPath, currentDepth+1, maxDepth, seen)
+func (d graphDeps) edges(name string) []DependencyEdge {
+	var edges []DependencyEdge
+	for _, t := range d.edgeTypes {
+		for _, e := range d.g.EdgesFrom(name, t) {
+			edges = append(edges, DependencyEdge{From: name, To: e.To().Name, Type: t})
 		}
 	}
+	return edges
 }
@@ -0,0 +1,141 @@
+package depend
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/plasmash/plasmactl-component/internal/strictyaml"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// TargetOperations are operations applied to one or several targets, as declared by an entry of an operations
+// file. An entry lists its targets with target, targets or both, and operations in the format of the command line.
+type TargetOperations struct {
+	Target     string   `yaml:"target"`
+	Targets    []string `yaml:"targets"`
+	Operations []string `yaml:"operations"`
+}
+
+func (t TargetOperations) targets() []string {
+	if t.Target == "" {
+		return t.Targets
+	}
+	return append([]string{t.Target}, t.Targets...)
+}
+
+// dependencyEdit is the dependencies file of a target being edited by operations.
+type dependencyEdit struct {
+	target      string
+	file        string
+	deps        []string
+	constraints map[string]sync.VersionConstraint
+	original    []byte // content before the edition, nil if the file doesn't exist
+	modified    bool
+}
+
+// constrain sets the version constraint of the dependency given by the operation, returns true if it changed.
+func (e *dependencyEdit) constrain(dep string, op DependOp) bool {
+	if !op.Constrained || e.constraints[dep] == op.Constraint {
+		return false
+	}
+
+	if e.constraints == nil {
+		e.constraints = make(map[string]sync.VersionConstraint)
+	}
+	if op.Constraint == "" {
+		delete(e.constraints, dep)
+	} else {
+		e.constraints[dep] = op.Constraint
+	}
+
+	return true
+}
+
+// executeFromFile applies operations of the file, or of stdin for "-", all at once: nothing is written if one fails.
+func (d *Depend) executeFromFile() error {
+	entries, err := d.readOperationsFile()
+	if err != nil {
+		return err
+	}
+
+	d.result = &DependResult{Mode: "operations"}
+	return d.applyOperations(entries)
+}
+
+// readOperationsFile reads and checks operations of the file given with --from-file.
+func (d *Depend) readOperationsFile() ([]TargetOperations, error) {
+	name := d.FromFile
+	var data []byte
+	var err error
+	if name == "-" {
+		name = "stdin"
+		var in io.Reader = os.Stdin
+		if d.Streams != nil {
+			in = d.Streams.In()
+		}
+		data, err = io.ReadAll(in)
+	} else {
+		data, err = os.ReadFile(filepath.Clean(name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operations file: %w", err)
+	}
+
+	var entries []TargetOperations
+	if err = strictyaml.Unmarshal(name, data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid operations file > %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no operations in %s", name)
+	}
+
+	for i, e := range entries {
+		if len(e.targets()) == 0 {
+			return nil, fmt.Errorf("%s: entry #%d has no target", name, i)
+		}
+		if len(e.Operations) == 0 {
+			return nil, fmt.Errorf("%s: entry #%d has no operations", name, i)
+		}
+	}
+
+	return entries, nil
+}
+
+// saveEdits writes modified dependencies files. If a file can't be written, files written before it are restored.
+func (d *Depend) saveEdits(edits []*dependencyEdit) error {
+	var written []*dependencyEdit
+	for _, edit := range edits {
+		if !edit.modified {
+			continue
+		}
+
+		sort.Strings(edit.deps)
+		if err := d.saveDependencies(edit.file, edit.deps, edit.constraints); err != nil {
+			if errRestore := restoreEdits(written); errRestore != nil {
+				return fmt.Errorf("failed to save dependencies: %w, and to restore written files: %w", err, errRestore)
+			}
+			return fmt.Errorf("failed to save dependencies: %w", err)
+		}
+		written = append(written, edit)
+	}
+
+	return nil
+}
+
+// restoreEdits restores the content of edited dependencies files.
+func restoreEdits(edits []*dependencyEdit) error {
+	var errs []error
+	for _, edit := range edits {
+		if edit.original == nil {
+			errs = append(errs, os.Remove(edit.file))
+			continue
+		}
+		errs = append(errs, os.WriteFile(edit.file, edit.original, 0644))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,109 @@
+package depend
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Graph formats of the show mode.
+const (
+	FormatDot     = "dot"
+	FormatMermaid = "mermaid"
+	FormatJSON    = "json"
+)
+
+// DependencyGraph is the subgraph rooted at the target printed with --format=json.
+type DependencyGraph struct {
+	Target string           `json:"target"`
+	Nodes  []string         `json:"nodes"`
+	Edges  []DependencyEdge `json:"edges"`
+}
+
+// validateFormat checks the graph format is known and only used to show dependencies.
+func (d *Depend) validateFormat() error {
+	switch d.Format {
+	case "":
+		return nil
+	case FormatDot, FormatMermaid, FormatJSON:
+	default:
+		return fmt.Errorf("unknown format %q (expected: %s, %s or %s)", d.Format, FormatDot, FormatMermaid, FormatJSON)
+	}
+
+	if d.Tree || d.Why != "" || len(d.Operations) > 0 || d.FromFile != "" || d.RemoveFromAll || d.Snapshot != "" ||
+		d.CheckSnapshot != "" || d.CheckArchitecture || d.CheckCycles {
+		return fmt.Errorf("--format only applies to showing dependencies, it can't be combined with --tree, --why, operations, snapshots, checks or --from-file")
+	}
+
+	return nil
+}
+
+// printGraph prints the subgraph made of the target and the shown components, with edges between them, in the
+// format. Edges are added to the result.
+func (d *Depend) printGraph(target string, deps depGraph, shown map[string]bool) error {
+	nodes := append([]string{target}, sortedNames(shown)...)
+
+	edges := []DependencyEdge{}
+	for _, n := range nodes {
+		for _, e := range deps.edges(n) {
+			if e.To == target || shown[e.To] {
+				edges = append(edges, e)
+			}
+		}
+	}
+	sortEdges(edges)
+	d.result.Edges = edges
+
+	switch d.Format {
+	case FormatDot:
+		d.Term().Print(d.dotGraph(nodes, edges))
+	case FormatMermaid:
+		d.Term().Print(d.mermaidGraph(nodes, edges))
+	default:
+		data, err := json.MarshalIndent(DependencyGraph{Target: target, Nodes: nodes, Edges: edges}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode graph: %w", err)
+		}
+		d.Term().Println(string(data))
+	}
+
+	return nil
+}
+
+// dotGraph renders the graph for Graphviz, the target in bold.
+func (d *Depend) dotGraph(nodes []string, edges []DependencyEdge) string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n  rankdir=LR;\n  node [shape=box];\n")
+	for i, n := range nodes {
+		attrs := "label=" + strconv.Quote(d.treeLabel(n, d.Path))
+		if i == 0 {
+			attrs += ", style=bold"
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", strconv.Quote(n), attrs)
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -> %s [label=%s];\n", strconv.Quote(e.From), strconv.Quote(e.To), strconv.Quote(e.Type))
+	}
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// mermaidGraph renders the graph as a Mermaid flowchart, the target with a thick border. Nodes get numbered ids,
+// component names aren't valid Mermaid ids.
+func (d *Depend) mermaidGraph(nodes []string, edges []DependencyEdge) string {
+	ids := make(map[string]string, len(nodes))
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, n := range nodes {
+		ids[n] = "n" + strconv.Itoa(i)
+		fmt.Fprintf(&b, "  %s[\"%s\"]\n", ids[n], strings.ReplaceAll(d.treeLabel(n, d.Path), `"`, "#quot;"))
+	}
+	for _, e := range edges {
+		fmt.Fprintf(&b, "  %s -->|%s| %s\n", ids[e.From], e.Type, ids[e.To])
+	}
+	fmt.Fprintf(&b, "  style %s stroke-width:3px\n", ids[nodes[0]])
+
+	return b.String()
+}
@@ -0,0 +1,168 @@
+package depend
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/compose/compose"
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+const domainNamespace = "domain"
+
+// Origin is the namespace providing a component to the build, the domain or a compose package,
+// with the component version there.
+type Origin struct {
+	Component string `json:"component"`
+	Namespace string `json:"namespace"`
+	Version   string `json:"version,omitempty"`
+	// Mismatch lists namespaces of related components resolving to another namespace:
+	// dependents of a dependency, or dependencies of a dependent with --reverse.
+	Mismatch []string `json:"mismatch,omitempty"`
+}
+
+// loadOrigins returns the namespace providing each component, the domain winning over packages
+// and later compose dependencies winning over earlier ones.
+func (d *Depend) loadOrigins() (map[string]*Origin, error) {
+	plasmaCompose, err := compose.Lookup(os.DirFS(d.DomainDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read compose file: %w", err)
+	}
+
+	type namespace struct{ name, path string }
+	namespaces := []namespace{{name: domainNamespace, path: d.DomainDir}}
+	for i := len(plasmaCompose.Dependencies) - 1; i >= 0; i-- {
+		dep := plasmaCompose.Dependencies[i]
+		pkg := dep.ToPackage(dep.Name)
+		namespaces = append(namespaces, namespace{name: dep.Name, path: filepath.Join(d.PackagesDir, pkg.GetName(), pkg.GetTarget())})
+	}
+
+	origins := make(map[string]*Origin)
+	for _, ns := range namespaces {
+		components, err := component.LoadFromPath(ns.path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load components of %s: %w", ns.name, err)
+		}
+
+		for _, c := range components {
+			if _, ok := origins[c.Name]; !ok {
+				origins[c.Name] = &Origin{Component: c.Name, Namespace: ns.name, Version: c.Version}
+			}
+		}
+	}
+
+	return origins, nil
+}
+
+// annotateOrigins returns origins of the shown components, flagging the ones related to a component
+// of the shown set provided by another namespace. Origins are kept for printing.
+func (d *Depend) annotateOrigins(g *graph.PlatformGraph, target string, shown map[string]bool, edgeTypes []string) ([]Origin, error) {
+	origins, err := d.loadOrigins()
+	if err != nil {
+		return nil, err
+	}
+	d.origins = origins
+
+	set := make(map[string]bool, len(shown)+1)
+	for name := range shown {
+		set[name] = true
+	}
+	set[target] = true
+
+	for name := range set {
+		from := origins[name]
+		for _, e := range g.EdgesFrom(name, edgeTypes...) {
+			to := origins[e.To().Name]
+			if !set[e.To().Name] || from == nil || to == nil || from.Namespace == to.Namespace {
+				continue
+			}
+
+			// Flag the listed side of the edge.
+			flagged, other := to, from
+			if d.Reverse {
+				flagged, other = from, to
+			}
+			flagged.Mismatch = appendUnique(flagged.Mismatch, other.Namespace)
+		}
+	}
+
+	result := make([]Origin, 0, len(shown))
+	for name := range shown {
+		o, ok := origins[name]
+		if !ok {
+			o = &Origin{Component: name}
+		}
+		sort.Strings(o.Mismatch)
+		result = append(result, *o)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Component < result[j].Component
+	})
+
+	return result, nil
+}
+
+// locate returns source locations of the target and shown components, sorted by component.
+// Components provided by no source are left out.
+func (d *Depend) locate(target string, shown map[string]bool) ([]component.Location, error) {
+	locator, err := component.NewLocator(d.DomainDir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := append([]string{target}, sortedNames(shown)...)
+	sort.Strings(names)
+	names = slices.Compact(names)
+
+	var locations []component.Location
+	for _, name := range names {
+		if l := locator.Locate(name); l != nil {
+			locations = append(locations, *l)
+		}
+	}
+
+	return locations, nil
+}
+
+// originLabel returns the annotation printed after a component with --origin.
+func (d *Depend) originLabel(name string) string {
+	if d.origins == nil {
+		return ""
+	}
+
+	o, ok := d.origins[name]
+	if !ok || o.Namespace == "" {
+		return " [unknown origin]"
+	}
+
+	label := fmt.Sprintf(" [%s", o.Namespace)
+	if o.Version != "" {
+		label += " " + o.Version
+	}
+	label += "]"
+
+	if len(o.Mismatch) > 0 {
+		relation := "dependents"
+		if d.Reverse {
+			relation = "dependencies"
+		}
+		label += fmt.Sprintf(" (!) %s from %s", relation, strings.Join(o.Mismatch, ", "))
+	}
+
+	return label
+}
+
+func appendUnique(slice []string, value string) []string {
+	for _, v := range slice {
+		if v == value {
+			return slice
+		}
+	}
+	return append(slice, value)
+}
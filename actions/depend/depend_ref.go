@@ -0,0 +1,134 @@
+package depend
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// executeShowRef displays dependencies of a domain component at the ref, read from the commit tree without checkout.
+// Origins and statuses describe the current build and packages, they aren't available at a ref.
+func (d *Depend) executeShowRef() error {
+	if d.Origin || d.Status {
+		return fmt.Errorf("--origin and --status can't be combined with --ref")
+	}
+
+	fsys, _, err := repository.RefFS(d.DomainDir, d.Ref)
+	if err != nil {
+		return err
+	}
+
+	inv, err := sync.NewInventoryFS(component.SourcesFS(fsys), d.Ref, d.Log())
+	if err != nil {
+		return fmt.Errorf("failed to read components at %s: %w", d.Ref, err)
+	}
+
+	target, err := d.resolveRefComponent(inv, d.Target)
+	if err != nil {
+		return err
+	}
+
+	deps := inventoryDeps{inv: inv, build: d.Build}
+	if d.Why != "" {
+		other, errOther := d.resolveRefComponent(inv, d.Why)
+		if errOther != nil {
+			return errOther
+		}
+		return d.executeWhy(target, other, deps)
+	}
+
+	parents := reachable(deps, target, true, int(d.Depth))
+	children := reachable(deps, target, false, int(d.Depth))
+
+	d.result = &DependResult{
+		Target:     target,
+		Mode:       "show",
+		Requires:   sortedNames(children),
+		RequiredBy: sortedNames(parents),
+	}
+
+	return d.printDependencies(target, deps, parents, children)
+}
+
+// resolveRefComponent resolves the component MRN or path to a component of the inventory read at the ref.
+func (d *Depend) resolveRefComponent(inv *sync.Inventory, name string) (string, error) {
+	if _, ok := inv.GetComponentsMap().Get(name); ok {
+		return name, nil
+	}
+
+	c := sync.BuildComponentFromPath(name, "")
+	if c == nil {
+		return "", fmt.Errorf("not valid component %q", name)
+	}
+	if _, ok := inv.GetComponentsMap().Get(c.GetName()); !ok {
+		return "", fmt.Errorf("component %q not found at %s", c.GetName(), d.Ref)
+	}
+
+	return c.GetName(), nil
+}
+
+// inventoryDeps are dependencies of an inventory, including build dependencies if build is set.
+type inventoryDeps struct {
+	inv   *sync.Inventory
+	build bool
+}
+
+func (d inventoryDeps) requires(name string) []string {
+	return d.names(name, d.inv.GetRequiresMap(), d.inv.GetBuildRequiresMap())
+}
+
+func (d inventoryDeps) requiredBy(name string) []string {
+	return d.names(name, d.inv.GetRequiredByMap(), d.inv.GetBuildRequiredByMap())
+}
+
+func (d inventoryDeps) names(name string, semantic, build map[string]*sync.OrderedMap[bool]) []string {
+	maps := []map[string]*sync.OrderedMap[bool]{semantic}
+	if d.build {
+		maps = append(maps, build)
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, m := range maps {
+		deps, ok := m[name]
+		if !ok {
+			continue
+		}
+		for _, n := range deps.Keys() {
+			if !seen[n] {
+				seen[n] = true
+				names = append(names, n)
+			}
+		}
+	}
+
+	return names
+}
+
+func (d inventoryDeps) edges(name string) []DependencyEdge {
+	var edges []DependencyEdge
+	if deps, ok := d.inv.GetRequiresMap()[name]; ok {
+		for _, n := range deps.Keys() {
+			edges = append(edges, DependencyEdge{From: name, To: n, Type: "requires"})
+		}
+	}
+	if deps, ok := d.inv.GetBuildRequiresMap()[name]; ok && d.build {
+		for _, n := range deps.Keys() {
+			edges = append(edges, DependencyEdge{From: name, To: n, Type: "builds"})
+		}
+	}
+	return edges
+}
+
+// sortedNames returns the names of the set sorted.
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,103 @@
+package depend
+
+import (
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// executeReverseOperations applies operations naming dependents of the target instead of its dependencies:
+// DEPENDENT adds the target to its dependencies, with a version constraint for DEPENDENT@CONSTRAINT, DEPENDENT- removes
+// it, OLD/NEW moves it from one to the other.
+// With --remove-from-all, the target is removed from all the domain components depending on it.
+func (d *Depend) executeReverseOperations() error {
+	lib, err := d.resolveDependencyMRN(d.Target)
+	if err != nil {
+		return err
+	}
+
+	d.result = &DependResult{
+		Target: lib,
+		Mode:   "operations",
+	}
+
+	var entries []TargetOperations
+	if d.RemoveFromAll {
+		dependents, errDependents := d.directDependents(lib)
+		if errDependents != nil {
+			return errDependents
+		}
+		if len(dependents) == 0 {
+			d.Term().Warning().Println(d.result.Warnings.Add(warning.Empty, lib, "No component depends on %s", lib))
+			return nil
+		}
+		entries = append(entries, TargetOperations{Targets: dependents, Operations: []string{lib + "-"}})
+	}
+
+	for _, op := range d.parseOperations(d.Operations) {
+		switch op.Type {
+		case "add":
+			entries = append(entries, TargetOperations{Target: op.Dep, Operations: []string{lib + op.constraintSuffix()}})
+		case "remove":
+			entries = append(entries, TargetOperations{Target: op.Dep, Operations: []string{lib + "-"}})
+		case "replace":
+			entries = append(entries,
+				TargetOperations{Target: op.Dep, Operations: []string{lib + "-"}},
+				TargetOperations{Target: op.NewVal, Operations: []string{lib + op.constraintSuffix()}},
+			)
+		}
+	}
+
+	return d.applyOperations(entries)
+}
+
+// directDependents returns the domain components listing the component in their dependencies file, read the way
+// operations edit them. Files which can't be read are skipped with a warning.
+func (d *Depend) directDependents(name string) ([]string, error) {
+	var dependents []string
+	err := filepath.WalkDir(d.DomainDir, func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.IsDir() {
+			if path != d.DomainDir && strings.HasPrefix(e.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if e.Name() != "dependencies.yaml" || filepath.Base(filepath.Dir(path)) != "tasks" {
+			return nil
+		}
+
+		rel, errRel := filepath.Rel(d.DomainDir, path)
+		if errRel != nil {
+			return errRel
+		}
+		c := sync.BuildComponentFromPath(rel, d.DomainDir)
+		if c == nil {
+			return nil
+		}
+
+		deps, errLoad := d.loadDependencies(path)
+		if errLoad != nil {
+			d.Term().Warning().Println(d.result.Warnings.Add(warning.Partial, c.GetName(), "Dependencies of %s can't be read, skipped: %s", c.GetName(), errLoad))
+			return nil
+		}
+		if slices.ContainsFunc(deps, func(dep sync.Dependency) bool { return dep.Name == name }) {
+			dependents = append(dependents, c.GetName())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to find dependents of %s: %w", name, err)
+	}
+
+	sort.Strings(dependents)
+	return slices.Compact(dependents), nil
+}
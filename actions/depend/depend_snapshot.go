@@ -0,0 +1,144 @@
+package depend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+)
+
+// DependencyEdge represents a single dependency edge between two components.
+type DependencyEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+func (e DependencyEdge) key() string {
+	return e.From + " " + e.Type + " " + e.To
+}
+
+// DependencySnapshot is the stored representation of the dependency graph.
+type DependencySnapshot struct {
+	Edges []DependencyEdge `json:"edges"`
+}
+
+// executeSnapshot writes the full dependency edge list to the snapshot file.
+func (d *Depend) executeSnapshot() error {
+	snapshot, err := d.buildSnapshot()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	if err = os.WriteFile(d.Snapshot, append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+
+	d.result = &DependResult{
+		Mode:  "snapshot",
+		Edges: snapshot.Edges,
+	}
+
+	d.Term().Success().Printfln("Snapshot with %d edge(s) written to %s", len(snapshot.Edges), d.Snapshot)
+	return nil
+}
+
+// executeCheckSnapshot compares the current dependency graph against the stored snapshot.
+func (d *Depend) executeCheckSnapshot() error {
+	data, err := os.ReadFile(d.CheckSnapshot)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+
+	var stored DependencySnapshot
+	if err = json.Unmarshal(data, &stored); err != nil {
+		return fmt.Errorf("failed to parse snapshot %s: %w", d.CheckSnapshot, err)
+	}
+
+	current, err := d.buildSnapshot()
+	if err != nil {
+		return err
+	}
+
+	added, removed := diffEdges(stored.Edges, current.Edges)
+	d.result = &DependResult{
+		Mode:    "check-snapshot",
+		Added:   added,
+		Removed: removed,
+	}
+
+	if len(added) == 0 && len(removed) == 0 {
+		d.Term().Success().Printfln("Dependency graph matches snapshot %s", d.CheckSnapshot)
+		return nil
+	}
+
+	for _, e := range added {
+		d.Term().Printfln("+ %s -[%s]-> %s", e.From, e.Type, e.To)
+	}
+	for _, e := range removed {
+		d.Term().Printfln("- %s -[%s]-> %s", e.From, e.Type, e.To)
+	}
+
+	return fmt.Errorf("dependency graph drifted from snapshot %s: %d added, %d removed edge(s)", d.CheckSnapshot, len(added), len(removed))
+}
+
+// buildSnapshot collects all dependency edges between components from the platform graph.
+func (d *Depend) buildSnapshot() (*DependencySnapshot, error) {
+	g, err := graph.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load graph: %w", err)
+	}
+
+	snapshot := &DependencySnapshot{Edges: []DependencyEdge{}}
+	for _, n := range g.NodesByType("component") {
+		for _, t := range graph.ComponentDependencyEdgeTypes() {
+			for _, e := range g.EdgesFrom(n.Name, t) {
+				snapshot.Edges = append(snapshot.Edges, DependencyEdge{From: n.Name, To: e.To().Name, Type: t})
+			}
+		}
+	}
+
+	sortEdges(snapshot.Edges)
+	return snapshot, nil
+}
+
+// diffEdges returns edges present only in current (added) and only in stored (removed).
+func diffEdges(stored, current []DependencyEdge) ([]DependencyEdge, []DependencyEdge) {
+	storedSet := make(map[string]bool, len(stored))
+	for _, e := range stored {
+		storedSet[e.key()] = true
+	}
+	currentSet := make(map[string]bool, len(current))
+	for _, e := range current {
+		currentSet[e.key()] = true
+	}
+
+	var added, removed []DependencyEdge
+	for _, e := range current {
+		if !storedSet[e.key()] {
+			added = append(added, e)
+		}
+	}
+	for _, e := range stored {
+		if !currentSet[e.key()] {
+			removed = append(removed, e)
+		}
+	}
+
+	sortEdges(added)
+	sortEdges(removed)
+	return added, removed
+}
+
+func sortEdges(edges []DependencyEdge) {
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].key() < edges[j].key()
+	})
+}
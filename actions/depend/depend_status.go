@@ -0,0 +1,129 @@
+package depend
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// Status markers of components.
+const (
+	markerBuildMismatch = "build-mismatch"
+	markerNotBuilt      = "not-built"
+	markerDeprecated    = "deprecated"
+	markerOrphan        = "orphan"
+)
+
+// ComponentStatus is the health of a component: its version in the domain, or in the build if the domain
+// doesn't provide it, and status markers.
+type ComponentStatus struct {
+	Component    string   `json:"component"`
+	Version      string   `json:"version,omitempty"`
+	BuildVersion string   `json:"build_version,omitempty"`
+	Deprecation  string   `json:"deprecation,omitempty"`
+	Markers      []string `json:"markers,omitempty"`
+}
+
+// annotateStatuses returns statuses of the target and shown components, comparing the domain to the build.
+// Components of the build attached to no chassis and required by no component are orphans.
+func (d *Depend) annotateStatuses(g *graph.PlatformGraph, target string, shown map[string]bool) ([]ComponentStatus, error) {
+	domain, err := component.LoadFromPath(d.DomainDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load domain components: %w", err)
+	}
+	build, err := component.LoadFromPath(d.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load build components: %w", err)
+	}
+	attachments, err := component.LoadAttachments(d.DomainDir, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+
+	attached := make(map[string]bool, len(attachments))
+	for _, a := range attachments {
+		attached[a.Component] = true
+	}
+
+	names := make([]string, 0, len(shown)+1)
+	names = append(names, target)
+	for name := range shown {
+		if name != target {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	d.statuses = make(map[string]*ComponentStatus, len(names))
+	result := make([]ComponentStatus, 0, len(names))
+	for _, name := range names {
+		s := &ComponentStatus{Component: name}
+		own, built := domain.Find(name), build.Find(name)
+		source := d.Source
+		switch {
+		case own != nil && built != nil:
+			s.Version, s.BuildVersion = own.Version, built.Version
+			source = d.DomainDir
+			if own.Version != built.Version {
+				s.Markers = append(s.Markers, markerBuildMismatch)
+			}
+		case own != nil:
+			s.Version = own.Version
+			source = d.DomainDir
+			s.Markers = append(s.Markers, markerNotBuilt)
+		case built != nil:
+			s.Version, s.BuildVersion = built.Version, built.Version
+		}
+
+		if c, errComponent := sync.NewComponent(name, source); errComponent == nil {
+			if deprecated, message, errMeta := c.GetDeprecation(); errMeta == nil && deprecated {
+				s.Deprecation = message
+				s.Markers = append(s.Markers, markerDeprecated)
+			}
+		}
+
+		if built != nil && !attached[name] && len(g.EdgesTo(name, graph.ComponentDependencyEdgeTypes()...)) == 0 {
+			s.Markers = append(s.Markers, markerOrphan)
+		}
+
+		d.statuses[name] = s
+		result = append(result, *s)
+	}
+
+	return result, nil
+}
+
+// statusLabel returns the version and status markers of the component, if statuses were annotated.
+func (d *Depend) statusLabel(name string) string {
+	if d.statuses == nil {
+		return ""
+	}
+
+	s, ok := d.statuses[name]
+	if !ok {
+		return ""
+	}
+
+	label := " " + component.FormatVersion(s.Version)
+	for _, m := range s.Markers {
+		switch m {
+		case markerBuildMismatch:
+			label += fmt.Sprintf(" (!) build %s", component.FormatVersion(s.BuildVersion))
+		case markerDeprecated:
+			if s.Deprecation != "" {
+				label += fmt.Sprintf(" [deprecated: %s]", s.Deprecation)
+				continue
+			}
+			fallthrough
+		default:
+			label += " [" + strings.ReplaceAll(m, "-", " ") + "]"
+		}
+	}
+
+	return label
+}
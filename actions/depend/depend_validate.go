@@ -0,0 +1,149 @@
+package depend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+)
+
+// hasAdditions tells if operations add dependencies, which are validated before being written.
+func hasAdditions(ops []DependOp) bool {
+	for _, op := range ops {
+		if op.Type == "add" || op.Type == "replace" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadValidationDeps returns the dependencies to validate operations against: the platform graph, or the components
+// of the source when the graph is unavailable. Nil is returned with a warning if neither can be read.
+func (d *Depend) loadValidationDeps() knownDeps {
+	g, err := graph.Load()
+	if err == nil {
+		return graphDeps{g, d.depEdgeTypes()}
+	}
+	d.Log().Warn(d.result.Warnings.Add(warning.Fallback, "", "platform graph is unavailable, loading components from filesystem"), "error", err)
+
+	source := d.Source
+	if _, errStat := os.Stat(source); source == "" || errStat != nil {
+		source = d.DomainDir
+	}
+
+	// Existing cycles are reported by the validation of the dependencies closing them.
+	inv, err := sync.NewInventory(source, d.Log())
+	var cycleErr *sync.CycleError
+	if err != nil && !errors.As(err, &cycleErr) {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Partial, "", "Added dependencies can't be validated: %s", err))
+		return nil
+	}
+
+	return inventoryDeps{inv: inv, build: d.Build}
+}
+
+// validateAddition checks that the dependency added to the target exists and doesn't close a cycle.
+// With --force, issues are reported as warnings and the dependency is written anyway.
+func (d *Depend) validateAddition(pending *pendingDeps, target, dep string) error {
+	if pending == nil || slices.Contains(*pending.edited[target], dep) {
+		return nil
+	}
+
+	if !pending.exists(dep) {
+		return d.rejectUnlessForced(warning.NotFound, dep, "dependency %s is not a known component", dep)
+	}
+
+	if dep == target {
+		return d.rejectUnlessForced(warning.Cycle, dep, "%s can't depend on itself", dep)
+	}
+
+	paths, _ := findPaths(pending, dep, target, 1)
+	if len(paths) == 0 {
+		return nil
+	}
+
+	chain := []string{target, dep}
+	for _, e := range paths[0] {
+		chain = append(chain, e.To)
+	}
+	return d.rejectUnlessForced(warning.Cycle, target, "adding %s to %s creates a dependency cycle: %s", dep, target, strings.Join(chain, " → "))
+}
+
+// rejectUnlessForced returns the validation issue as an error, or prints it as a warning with --force.
+func (d *Depend) rejectUnlessForced(code, subject, format string, a ...any) error {
+	if !d.Force {
+		return fmt.Errorf(format+" (use --force to write it anyway)", a...)
+	}
+
+	d.Term().Warning().Println(d.result.Warnings.Add(code, subject, format, a...))
+	return nil
+}
+
+// knownDeps are dependencies of a set of components, which tells whether a component belongs to it.
+type knownDeps interface {
+	depGraph
+	// exists tells if the component is known.
+	exists(name string) bool
+}
+
+// pendingDeps are dependencies with the dependencies of targets being edited, by MRN, in place of their stored ones.
+type pendingDeps struct {
+	knownDeps
+	edited map[string]*[]string
+}
+
+func (p *pendingDeps) edges(name string) []DependencyEdge {
+	deps, ok := p.edited[name]
+	if !ok {
+		return p.knownDeps.edges(name)
+	}
+
+	var edges []DependencyEdge
+	for _, e := range p.knownDeps.edges(name) {
+		if e.Type != "requires" {
+			edges = append(edges, e)
+		}
+	}
+	for _, dep := range *deps {
+		edges = append(edges, DependencyEdge{From: name, To: dep, Type: "requires"})
+	}
+	return edges
+}
+
+func (p *pendingDeps) requires(name string) []string {
+	var names []string
+	for _, e := range p.edges(name) {
+		names = append(names, e.To)
+	}
+	return names
+}
+
+func (p *pendingDeps) requiredBy(name string) []string {
+	var names []string
+	for _, n := range p.knownDeps.requiredBy(name) {
+		if _, ok := p.edited[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	for target := range p.edited {
+		if slices.Contains(p.requires(target), name) {
+			names = append(names, target)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (d graphDeps) exists(name string) bool {
+	return d.g.Node(name) != nil
+}
+
+func (d inventoryDeps) exists(name string) bool {
+	_, ok := d.inv.GetComponentsMap().Get(name)
+	return ok
+}
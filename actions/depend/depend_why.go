@@ -0,0 +1,119 @@
+package depend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// maxWhyPaths bounds the number of paths reported by --why, their count growing quickly in dense graphs.
+const maxWhyPaths = 100
+
+// executeWhy prints dependency paths from the target to the other component, shortest first, to explain why a
+// change of the other one propagates to the target. When the target isn't depending on the other component,
+// paths in the opposite direction are printed.
+func (d *Depend) executeWhy(target, other string, deps depGraph) error {
+	if target == other {
+		return fmt.Errorf("--why expects another component than the target")
+	}
+
+	d.result = &DependResult{
+		Target: target,
+		Mode:   "why",
+		Other:  other,
+	}
+
+	from, to := target, other
+	paths, truncated := findPaths(deps, from, to, maxWhyPaths)
+	if len(paths) == 0 {
+		from, to = other, target
+		paths, truncated = findPaths(deps, from, to, maxWhyPaths)
+	}
+	d.result.Paths = paths
+
+	if d.ResolvePaths {
+		shown := make(map[string]bool)
+		for _, path := range paths {
+			for _, e := range path {
+				shown[e.From], shown[e.To] = true, true
+			}
+		}
+		var err error
+		if d.result.Locations, err = d.locate(target, shown); err != nil {
+			return err
+		}
+	}
+
+	if len(paths) == 0 {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Empty, other, "No dependency path between %s and %s", target, other))
+		return nil
+	}
+
+	d.Term().Info().Printfln("%s depends on %s through %d path(s)", from, to, len(paths))
+	for _, path := range paths {
+		var b strings.Builder
+		b.WriteString(d.treeLabel(path[0].From, d.Path))
+		for _, e := range path {
+			b.WriteString(" -[" + e.Type + "]-> " + d.treeLabel(e.To, d.Path))
+		}
+		d.Term().Println(b.String())
+	}
+
+	if truncated {
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Partial, other, "Only the first %d paths are shown", maxWhyPaths))
+	}
+
+	return nil
+}
+
+// findPaths returns dependency paths without repeated components from one component to another, breadth-first so
+// shorter paths come first. It stops at the limit, telling if paths were left out.
+func findPaths(deps depGraph, from, to string, limit int) ([][]DependencyEdge, bool) {
+	// Only components leading to the destination are worth following.
+	leads := reachable(deps, to, true, -1)
+
+	var paths [][]DependencyEdge
+	queue := [][]DependencyEdge{nil}
+	for len(queue) > 0 {
+		path := queue[0]
+		queue = queue[1:]
+
+		current := from
+		if len(path) > 0 {
+			current = path[len(path)-1].To
+		}
+
+		edges := deps.edges(current)
+		sortEdges(edges)
+		for _, e := range edges {
+			next := append(path[:len(path):len(path)], e)
+			if e.To == to {
+				if len(paths) == limit {
+					return paths, true
+				}
+				paths = append(paths, next)
+				continue
+			}
+
+			if leads[e.To] && !visits(path, from, e.To) {
+				queue = append(queue, next)
+			}
+		}
+	}
+
+	return paths, false
+}
+
+// visits tells if the path starting at the component goes through the other one.
+func visits(path []DependencyEdge, start, name string) bool {
+	if start == name {
+		return true
+	}
+	for _, e := range path {
+		if e.To == name {
+			return true
+		}
+	}
+	return false
+}
@@ -5,13 +5,15 @@ import (
 
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 // DetachResult is the structured result of component:detach.
 type DetachResult struct {
-	Component string `json:"component"`
-	Chassis   string `json:"chassis"`
-	Detached  bool   `json:"detached"`
+	Component string       `json:"component"`
+	Chassis   string       `json:"chassis"`
+	Detached  bool         `json:"detached"`
+	Warnings  warning.List `json:"warnings,omitempty"`
 }
 
 // Detach implements component:detach command
@@ -51,7 +53,8 @@ func (d *Detach) Execute() error {
 	plays, detached := playbook.RemoveRole(plays, d.Component, d.Chassis)
 	if !detached {
 		d.result = &DetachResult{Component: d.Component, Chassis: d.Chassis, Detached: false}
-		d.Term().Warning().Printfln("Component %s not attached to %s", d.Component, d.Chassis)
+		d.Term().Warning().Println(d.result.Warnings.Add(warning.Unchanged, d.Component,
+			"Component %s not attached to %s", d.Component, d.Chassis))
 		return nil
 	}
 
@@ -0,0 +1,109 @@
+// Package doctor implements component:doctor, checking prerequisites of the plugin actions.
+package doctor
+
+import (
+	"fmt"
+
+	"github.com/launchrctl/keyring"
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+)
+
+// Check statuses.
+const (
+	StatusOK      = "ok"
+	StatusWarning = "warning"
+	StatusError   = "error"
+	StatusSkipped = "skipped"
+)
+
+// Check is the outcome of a prerequisite check, with the fix of a failed one.
+type Check struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	Fix     string `json:"fix,omitempty"`
+}
+
+// DoctorResult is the structured result of component:doctor.
+type DoctorResult struct {
+	Checks   []Check `json:"checks"`
+	Warnings int     `json:"warnings"`
+	Errors   int     `json:"errors"`
+}
+
+// Doctor implements component:doctor command, checking the environment actions depend on: git repository,
+// keyring, vault password, platform graph, composed build and packages.
+type Doctor struct {
+	action.WithLogger
+	action.WithTerm
+
+	Keyring keyring.Keyring
+
+	DomainDir   string
+	BuildDir    string
+	PackagesDir string
+
+	VaultPass         string
+	VaultPassProvider vaultpass.Provider
+	VersionFormat     sync.VersionFormat
+
+	result *DoctorResult
+}
+
+// Result returns the structured result for JSON output.
+func (d *Doctor) Result() any {
+	return d.result
+}
+
+// Execute runs the checks and fails if any of them failed, warnings don't fail.
+func (d *Doctor) Execute() error {
+	d.result = &DoctorResult{Checks: make([]Check, 0)}
+
+	for _, check := range []func() Check{
+		d.checkGit,
+		d.checkRepository,
+		d.checkKeyring,
+		d.checkVaultPass,
+		d.checkGraph,
+		d.checkBuild,
+		d.checkPackages,
+	} {
+		d.report(check())
+	}
+
+	if d.result.Errors > 0 {
+		return fmt.Errorf("%d check(s) failed, %d warning(s)", d.result.Errors, d.result.Warnings)
+	}
+
+	if d.result.Warnings > 0 {
+		d.Term().Warning().Printfln("All checks passed with %d warning(s)", d.result.Warnings)
+		return nil
+	}
+
+	d.Term().Success().Println("All checks passed")
+	return nil
+}
+
+func (d *Doctor) report(c Check) {
+	d.result.Checks = append(d.result.Checks, c)
+
+	switch c.Status {
+	case StatusOK:
+		d.Term().Success().Printfln("%s: %s", c.Name, c.Message)
+	case StatusSkipped:
+		d.Term().Info().Printfln("%s: %s", c.Name, c.Message)
+	case StatusWarning:
+		d.result.Warnings++
+		d.Term().Warning().Printfln("%s: %s", c.Name, c.Message)
+	default:
+		d.result.Errors++
+		d.Term().Error().Printfln("%s: %s", c.Name, c.Message)
+	}
+
+	if c.Fix != "" {
+		d.Term().Printfln("  fix: %s", c.Fix)
+	}
+}
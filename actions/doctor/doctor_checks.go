@@ -0,0 +1,394 @@
+package doctor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/launchrctl/compose/compose"
+	"github.com/launchrctl/keyring"
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+	vault "github.com/sosedoff/ansible-vault-go"
+
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+const (
+	vaultHeader = "$ANSIBLE_VAULT"
+	composeFile = "plasma-compose.yaml"
+	// maxListed is the number of components or packages named in a message, the rest are counted.
+	maxListed = 5
+)
+
+// checkGit ensures the git CLI is available, used to sign bump commits and by bump hooks.
+func (d *Doctor) checkGit() Check {
+	c := Check{Name: "git"}
+	path, err := exec.LookPath("git")
+	if err != nil {
+		c.Status = StatusWarning
+		c.Message = "git executable not found, signed bump commits and bump hooks relying on it fail"
+		c.Fix = "install git and add it to PATH"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = path
+	return c
+}
+
+// checkRepository ensures the domain is a git repository with complete history and no uncommitted changes,
+// which bump reads and sync refuses without --allow-override.
+func (d *Doctor) checkRepository() Check {
+	c := Check{Name: "repository"}
+	r, err := git.PlainOpenWithOptions(d.DomainDir, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("%s isn't a git repository: %v", d.DomainDir, err)
+		c.Fix = "run from the platform repository"
+		return c
+	}
+
+	if _, err = r.Head(); err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("no commit found: %v", err)
+		c.Fix = "commit the platform sources"
+		return c
+	}
+
+	boundary, err := repository.ShallowBoundary(r)
+	if err == nil && len(boundary) > 0 {
+		c.Status = StatusWarning
+		c.Message = "shallow clone, history read by bump and sync is truncated"
+		c.Fix = "run git fetch --unshallow, or use --unshallow of component:sync"
+		return c
+	}
+
+	w, err := r.Worktree()
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("no worktree: %v", err)
+		c.Fix = "run from a non-bare clone of the platform repository"
+		return c
+	}
+	status, err := w.Status()
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("failed to read worktree status: %v", err)
+		return c
+	}
+
+	var changed []string
+	for path, s := range status {
+		if s.Worktree != git.Untracked && (s.Worktree != git.Unmodified || s.Staging != git.Unmodified) {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		c.Status = StatusWarning
+		c.Message = fmt.Sprintf("%d uncommitted change(s): %s", len(changed), listed(changed))
+		c.Fix = "commit or stash changes, component:sync requires a clean tree unless --allow-override is used"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = "clean"
+	return c
+}
+
+// checkKeyring ensures the keyring can be read, actions storing the vault password in it fail otherwise.
+func (d *Doctor) checkKeyring() Check {
+	c := Check{Name: "keyring"}
+	if d.Keyring == nil {
+		c.Status = StatusSkipped
+		c.Message = "no keyring available"
+		return c
+	}
+
+	_, err := d.Keyring.GetForKey(vaultpass.KeyringKey)
+	switch {
+	case err == nil:
+		c.Status = StatusOK
+		c.Message = "vault password stored"
+	case errors.Is(err, keyring.ErrNotFound):
+		c.Status = StatusOK
+		c.Message = "accessible, no vault password stored, actions prompt for it"
+	case errors.Is(err, keyring.ErrEmptyPass):
+		c.Status = StatusError
+		c.Message = "empty keyring passphrase"
+		c.Fix = "provide the keyring passphrase"
+	default:
+		d.Log().Debug("keyring error", "error", err)
+		c.Status = StatusError
+		c.Message = "the keyring is malformed or wrong passphrase provided"
+		c.Fix = "check the keyring passphrase, or remove the keyring file to recreate it"
+	}
+
+	return c
+}
+
+// checkVaultPass ensures the vault password, of options or keyring, decrypts vault files.
+func (d *Doctor) checkVaultPass() Check {
+	c := Check{Name: "vault password"}
+	pass, source, err := d.vaultPass()
+	if err != nil {
+		c.Status = StatusError
+		c.Message = err.Error()
+		c.Fix = "set a single vault password source and ensure it's readable"
+		return c
+	}
+	if pass == "" {
+		c.Status = StatusSkipped
+		c.Message = "no vault password provided, actions prompt for it"
+		return c
+	}
+
+	file, err := d.vaultFile()
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("failed to find vault files: %v", err)
+		return c
+	}
+	if file == "" {
+		c.Status = StatusSkipped
+		c.Message = "no encrypted vault file found"
+		return c
+	}
+
+	if _, err = vault.DecryptFile(file, pass); err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("vault password of %s doesn't decrypt %s: %v", source, file, err)
+		c.Fix = "provide the current vault password, e.g. with --vault-pass-file"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("vault password of %s decrypts %s", source, file)
+	return c
+}
+
+// vaultPass returns the vault password and its source, from options, then the keyring.
+func (d *Doctor) vaultPass() (string, string, error) {
+	if d.VaultPass != "" {
+		return d.VaultPass, "--vault-pass", nil
+	}
+
+	pass, err := d.VaultPassProvider.Password()
+	if err != nil || pass != "" {
+		return pass, "the vault password provider", err
+	}
+
+	if d.Keyring != nil {
+		item, errGet := d.Keyring.GetForKey(vaultpass.KeyringKey)
+		if errGet == nil {
+			pass, _ = item.Value.(string)
+			return pass, "the keyring", nil
+		}
+	}
+
+	return "", "", nil
+}
+
+// vaultFile returns the first encrypted vault file of the domain sources or the build, empty if there is none.
+func (d *Doctor) vaultFile() (string, error) {
+	errFound := errors.New("found")
+	var found string
+	for _, dir := range []string{filepath.Join(d.DomainDir, "src"), d.BuildDir} {
+		if _, err := os.Stat(dir); err != nil {
+			continue
+		}
+
+		err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !sync.IsVaultFile(path) {
+				return err
+			}
+
+			data, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				return err
+			}
+			if bytes.HasPrefix(data, []byte(vaultHeader)) {
+				found = path
+				return errFound
+			}
+
+			return nil
+		})
+		if errors.Is(err, errFound) {
+			return found, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return "", nil
+}
+
+// checkGraph ensures the platform graph is available and its component versions match the build.
+func (d *Doctor) checkGraph() Check {
+	c := Check{Name: "platform graph"}
+	g, err := graph.Load()
+	if err != nil || g == nil {
+		c.Status = StatusWarning
+		c.Message = "platform graph can't be loaded, list, show and query fall back to scanning, depend and orphans fail"
+		if err != nil {
+			c.Message += fmt.Sprintf(": %v", err)
+		}
+		c.Fix = "generate the platform graph with the platform plugin"
+		return c
+	}
+
+	if msg := graphstate.Check(d.DomainDir); msg != "" {
+		c.Status = StatusWarning
+		c.Message = msg
+		c.Fix = fmt.Sprintf("regenerate the platform graph and remove %s, or use --graph-refresh rebuild", graphstate.File)
+		return c
+	}
+
+	build, err := component.LoadFromPath(d.BuildDir)
+	if err != nil || len(build) == 0 {
+		c.Status = StatusOK
+		c.Message = "loaded, no build to compare with"
+		return c
+	}
+
+	var stale []string
+	nodes := g.NodesByType("component")
+	for _, n := range nodes {
+		if b := build.Find(n.Name); b == nil || b.Version != n.Version {
+			stale = append(stale, n.Name)
+		}
+	}
+	seen := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		seen[n.Name] = true
+	}
+	for _, b := range build {
+		if !seen[b.Name] {
+			stale = append(stale, b.Name)
+		}
+	}
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		c.Status = StatusWarning
+		c.Message = fmt.Sprintf("graph is outdated for %d component(s): %s", len(stale), listed(stale))
+		c.Fix = "regenerate the platform graph after composing"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("%d component(s), matching the build", len(nodes))
+	return c
+}
+
+// checkBuild ensures the composed build exists and its versions match the domain, like sync requires.
+// Propagated versions are compared by their base part.
+func (d *Doctor) checkBuild() Check {
+	c := Check{Name: "build"}
+	if _, err := os.Stat(d.BuildDir); err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("no composed build in %s", d.BuildDir)
+		c.Fix = "run plasmactl model:compose"
+		return c
+	}
+
+	domain, err := component.LoadFromPath(d.DomainDir)
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("failed to load domain components: %v", err)
+		return c
+	}
+	build, err := component.LoadFromPath(d.BuildDir)
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("failed to load build components: %v", err)
+		return c
+	}
+
+	var stale []string
+	for _, own := range domain {
+		built := build.Find(own.Name)
+		if built == nil {
+			stale = append(stale, own.Name)
+			continue
+		}
+
+		ownBase, _ := d.VersionFormat.Split(own.Version)
+		builtBase, _ := d.VersionFormat.Split(built.Version)
+		if ownBase != builtBase {
+			stale = append(stale, own.Name)
+		}
+	}
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("build is stale for %d component(s): %s", len(stale), listed(stale))
+		c.Fix = "run plasmactl model:compose"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("%d component(s) in %s", len(build), d.BuildDir)
+	return c
+}
+
+// checkPackages ensures packages of the compose file are checked out in the packages directory.
+func (d *Doctor) checkPackages() Check {
+	c := Check{Name: "packages"}
+	if _, err := os.Stat(filepath.Join(d.DomainDir, composeFile)); err != nil {
+		c.Status = StatusSkipped
+		c.Message = fmt.Sprintf("no %s", composeFile)
+		return c
+	}
+
+	plasmaCompose, err := compose.Lookup(os.DirFS(d.DomainDir))
+	if err != nil {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("failed to read %s: %v", composeFile, err)
+		c.Fix = fmt.Sprintf("fix %s", composeFile)
+		return c
+	}
+
+	var missing []string
+	for _, dep := range plasmaCompose.Dependencies {
+		pkg := dep.ToPackage(dep.Name)
+		path := filepath.Join(d.PackagesDir, pkg.GetName(), pkg.GetTarget())
+		if _, err = os.Stat(path); err != nil {
+			missing = append(missing, fmt.Sprintf("%s (%s)", dep.Name, path))
+		}
+	}
+
+	if len(missing) > 0 {
+		c.Status = StatusError
+		c.Message = fmt.Sprintf("%d package(s) missing from disk: %s", len(missing), listed(missing))
+		c.Fix = "run plasmactl model:compose"
+		return c
+	}
+
+	c.Status = StatusOK
+	c.Message = fmt.Sprintf("%d package(s) checked out", len(plasmaCompose.Dependencies))
+	return c
+}
+
+// listed returns the first names joined, with the count of the others.
+func listed(names []string) string {
+	if len(names) <= maxListed {
+		return strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListed], ", "), len(names)-maxListed)
+}
@@ -0,0 +1,196 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/launchrctl/launchr/pkg/action"
+
+	syncaction "github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// ComponentVersion is the version a component had at the commit, with the domain commit the version was taken from.
+type ComponentVersion struct {
+	Name       string     `json:"name"`
+	Version    string     `json:"version"`
+	Propagated string     `json:"propagated,omitempty"`
+	Commit     string     `json:"commit,omitempty"`
+	Date       *time.Time `json:"date,omitempty"`
+	Message    string     `json:"message,omitempty"`
+	Bump       string     `json:"bump,omitempty"`
+}
+
+// HistoryResult is the structured result of component:history.
+type HistoryResult struct {
+	At         string             `json:"at"`
+	Commit     string             `json:"commit"`
+	Date       time.Time          `json:"date"`
+	Components []ComponentVersion `json:"components"`
+	Missing    []string           `json:"missing,omitempty"`
+	Warnings   warning.List       `json:"warnings,omitempty"`
+}
+
+// History implements component:history command
+type History struct {
+	action.WithLogger
+	action.WithTerm
+
+	// At is a date as YYYY-MM-DD, the domain is read at the last commit of the day, or a revision.
+	At         string
+	Components []string
+
+	BumpAuthors   []string
+	VersionFormat sync.VersionFormat
+
+	result *HistoryResult
+}
+
+// Result returns the structured result for JSON output.
+func (h *History) Result() any {
+	return h.result
+}
+
+// Execute runs the history action
+func (h *History) Execute() error {
+	if h.At == "" {
+		return errors.New("--at is required, a date as YYYY-MM-DD or a revision")
+	}
+
+	authors, err := repository.NewBumpAuthors(h.BumpAuthors)
+	if err != nil {
+		return err
+	}
+
+	ref, err := h.revision()
+	if err != nil {
+		return err
+	}
+
+	fsys, commit, err := repository.RefFS(".", ref)
+	if err != nil {
+		return err
+	}
+
+	components, err := component.LoadFS(context.Background(), component.SourcesFS(fsys), component.LoadOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to load components at %s: %w", h.At, err)
+	}
+
+	repo, err := git.PlainOpenWithOptions(".", &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	versions, err := syncaction.VersionCommits(repo, commit.Hash, authors)
+	if err != nil {
+		return err
+	}
+
+	h.result = &HistoryResult{
+		At:         h.At,
+		Commit:     commit.Hash.String(),
+		Date:       commit.Committer.When,
+		Components: []ComponentVersion{},
+	}
+
+	h.Term().Info().Printfln("Versions at %s (%s, %s)", h.At, commit.Hash.String()[:7], commit.Committer.When.Format(time.DateTime))
+
+	for _, c := range h.selectComponents(components) {
+		cv := h.componentVersion(repo, c, versions)
+		h.result.Components = append(h.result.Components, cv)
+		h.printVersion(cv)
+	}
+
+	return nil
+}
+
+// revision returns the revision to read the domain at, the last commit before the end of the day of a date.
+func (h *History) revision() (string, error) {
+	date, err := time.ParseInLocation(time.DateOnly, h.At, time.Local)
+	if err != nil {
+		return h.At, nil
+	}
+
+	commit, err := repository.CommitBefore(".", date.AddDate(0, 0, 1))
+	if err != nil {
+		return "", fmt.Errorf("failed to find commit at %s: %w", h.At, err)
+	}
+
+	return commit.Hash.String(), nil
+}
+
+// selectComponents returns the requested components sorted by name, all components without request. Requested
+// components which didn't exist at the commit are reported missing.
+func (h *History) selectComponents(components component.Components) component.Components {
+	byName := make(map[string]component.Component, len(components))
+	for _, c := range components {
+		byName[c.Name] = c
+	}
+
+	var selected component.Components
+	if len(h.Components) == 0 {
+		for _, c := range byName {
+			selected = append(selected, c)
+		}
+	}
+	for _, name := range h.Components {
+		c, ok := byName[name]
+		if !ok {
+			h.result.Missing = append(h.result.Missing, name)
+			h.Term().Warning().Println(h.result.Warnings.Add(warning.NotFound, name, "Component %s didn't exist at %s", name, h.At))
+			continue
+		}
+		selected = append(selected, c)
+	}
+
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+	return selected
+}
+
+// componentVersion maps the own part of the component version to the domain commit it was taken from. Versions
+// which aren't commit hashes of the domain, e.g. set manually, are reported without commit.
+func (h *History) componentVersion(repo *git.Repository, c component.Component, versions map[string]syncaction.VersionCommit) ComponentVersion {
+	cv := ComponentVersion{Name: c.Name, Version: c.Version}
+
+	base, propagated := h.VersionFormat.Split(c.Version)
+	cv.Propagated = propagated
+
+	vc, ok := versions[base]
+	if !ok {
+		return cv
+	}
+
+	cv.Commit = vc.Commit
+	cv.Bump = vc.Bump
+	if commit, err := repo.CommitObject(plumbing.NewHash(vc.Commit)); err == nil {
+		cv.Date = &commit.Author.When
+		cv.Message = subject(commit)
+	}
+
+	return cv
+}
+
+func (h *History) printVersion(cv ComponentVersion) {
+	if cv.Date == nil {
+		h.Term().Printfln("- %s", component.FormatDisplayName(cv.Name, cv.Version))
+		return
+	}
+
+	h.Term().Printfln("- %s: %s %s %s", component.FormatDisplayName(cv.Name, cv.Version), cv.Commit[:7], cv.Date.Format(time.DateOnly), cv.Message)
+}
+
+func subject(c *object.Commit) string {
+	s, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return s
+}
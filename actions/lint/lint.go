@@ -0,0 +1,548 @@
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+
+	"github.com/plasmash/plasmactl-component/internal/architecture"
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/strictyaml"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/extension"
+)
+
+const (
+	ruleManualVersions = "manual-versions"
+	ruleArchitecture   = "architecture"
+	ruleYAML           = "yaml"
+	ruleAttachments    = "attachments"
+	ruleDocs           = "docs"
+	ruleSecrets        = "secrets"
+	ruleTemplates      = "templates"
+	ruleTasks          = "tasks"
+	ruleVersions       = "versions"
+)
+
+// builtinRules are names of rules implemented by the action, contributed rules can't use them.
+var builtinRules = []string{
+	ruleManualVersions, ruleArchitecture, ruleYAML, ruleAttachments, ruleDocs, ruleSecrets, ruleTemplates, ruleTasks, ruleVersions,
+}
+
+// LintIssue represents a single finding reported by a lint rule.
+type LintIssue struct {
+	Rule    string `json:"rule"`
+	Subject string `json:"subject"`
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Commit  string `json:"commit,omitempty"`
+	Author  string `json:"author,omitempty"`
+	Message string `json:"message"`
+	Fixed   bool   `json:"fixed,omitempty"`
+	// Suppressed is set to inline or baseline for issues ignored by a comment or recorded in the baseline.
+	Suppressed string `json:"suppressed,omitempty"`
+}
+
+// LintResult is the structured result of component:lint.
+type LintResult struct {
+	Rules      []string    `json:"rules"`
+	Issues     []LintIssue `json:"issues"`
+	Fixed      int         `json:"fixed"`
+	Suppressed int         `json:"suppressed"`
+}
+
+// Lint implements component:lint command
+type Lint struct {
+	action.WithLogger
+	action.WithTerm
+
+	Source string
+
+	// Rule sets (all rules run when none selected)
+	ManualVersions bool
+	Architecture   bool
+	YAML           bool
+	Attachments    bool
+	Docs           bool
+	Secrets        bool
+	Templates      bool
+	Tasks          bool
+	Versions       bool
+	// ExtensionRules are names of contributed rules to run
+	ExtensionRules []string
+
+	// Rules contributed by other plugins, run with all rules or when selected
+	Extensions []extension.LintRule
+	// Matrix declares allowed dependencies for architecture rule
+	Matrix architecture.Matrix
+	// DocsRules declares documentation required by component kind for docs rule
+	DocsRules DocsRules
+	// TemplateFilters are filters known in addition to Jinja2 and ansible-core ones for templates rule
+	TemplateFilters []string
+	// DeprecatedModules are modules reported by tasks rule, strictyaml.DeprecatedModules if empty
+	DeprecatedModules []string
+	// VersionFormat is the propagated version format checked by versions rule
+	VersionFormat sync.VersionFormat
+
+	// Modifiers
+	Fix bool
+	// Baseline is the file of known issues, which don't fail the lint.
+	Baseline string
+	// WriteBaseline records current issues in the baseline file instead of failing on them.
+	WriteBaseline bool
+
+	// BumpAuthors are names or emails, or /regexp/, of past bump commit authors besides the bumper, whose version
+	// changes aren't manual.
+	BumpAuthors []string
+
+	result *LintResult
+}
+
+// Result returns the structured result for JSON output.
+func (l *Lint) Result() any {
+	return l.result
+}
+
+// Execute runs the lint action
+func (l *Lint) Execute() error {
+	all := !l.ManualVersions && !l.Architecture && !l.YAML && !l.Attachments && !l.Docs && !l.Secrets && !l.Templates && !l.Tasks && !l.Versions &&
+		len(l.ExtensionRules) == 0
+	l.result = &LintResult{}
+
+	extensions, err := l.selectExtensions(all)
+	if err != nil {
+		return err
+	}
+
+	if all || l.ManualVersions {
+		l.result.Rules = append(l.result.Rules, ruleManualVersions)
+		if err := l.checkManualVersions(); err != nil {
+			return fmt.Errorf("%s > %w", ruleManualVersions, err)
+		}
+	}
+
+	if all || l.Architecture {
+		l.result.Rules = append(l.result.Rules, ruleArchitecture)
+		if err := l.checkArchitecture(); err != nil {
+			return fmt.Errorf("%s > %w", ruleArchitecture, err)
+		}
+	}
+
+	if all || l.YAML {
+		l.result.Rules = append(l.result.Rules, ruleYAML)
+		if err := l.checkYAML(); err != nil {
+			return fmt.Errorf("%s > %w", ruleYAML, err)
+		}
+	}
+
+	if all || l.Attachments {
+		l.result.Rules = append(l.result.Rules, ruleAttachments)
+		if err := l.checkAttachments(); err != nil {
+			return fmt.Errorf("%s > %w", ruleAttachments, err)
+		}
+	}
+
+	if all || l.Docs {
+		l.result.Rules = append(l.result.Rules, ruleDocs)
+		if err := l.checkDocs(); err != nil {
+			return fmt.Errorf("%s > %w", ruleDocs, err)
+		}
+	}
+
+	if all || l.Secrets {
+		l.result.Rules = append(l.result.Rules, ruleSecrets)
+		if err := l.checkSecrets(); err != nil {
+			return fmt.Errorf("%s > %w", ruleSecrets, err)
+		}
+	}
+
+	if all || l.Templates {
+		l.result.Rules = append(l.result.Rules, ruleTemplates)
+		if err := l.checkTemplates(); err != nil {
+			return fmt.Errorf("%s > %w", ruleTemplates, err)
+		}
+	}
+
+	if all || l.Tasks {
+		l.result.Rules = append(l.result.Rules, ruleTasks)
+		if err := l.checkTasks(); err != nil {
+			return fmt.Errorf("%s > %w", ruleTasks, err)
+		}
+	}
+
+	if all || l.Versions {
+		l.result.Rules = append(l.result.Rules, ruleVersions)
+		if err := l.checkVersions(); err != nil {
+			return fmt.Errorf("%s > %w", ruleVersions, err)
+		}
+	}
+
+	for _, rule := range extensions {
+		l.result.Rules = append(l.result.Rules, rule.Name)
+		if err = l.checkExtension(rule); err != nil {
+			return fmt.Errorf("%s > %w", rule.Name, err)
+		}
+	}
+
+	l.suppressInline()
+	if l.WriteBaseline {
+		if l.Baseline == "" {
+			return fmt.Errorf("baseline file is required to write the baseline")
+		}
+		if err = l.writeBaseline(); err != nil {
+			return err
+		}
+	}
+	if l.Baseline != "" {
+		if err = l.applyBaseline(); err != nil {
+			return err
+		}
+	}
+
+	return l.report()
+}
+
+// report prints collected issues and fails if unfixed issues remain.
+func (l *Lint) report() error {
+	if len(l.result.Issues) == 0 {
+		l.Term().Success().Println("No issues found")
+		return nil
+	}
+
+	unfixed := 0
+	suppressed := map[string]int{}
+	for _, issue := range l.result.Issues {
+		if issue.Suppressed != "" {
+			suppressed[issue.Suppressed]++
+			l.result.Suppressed++
+			continue
+		}
+		if issue.Fixed {
+			l.Term().Success().Printfln("[%s] %s: %s (fixed)", issue.Rule, issue.Subject, issue.Message)
+			continue
+		}
+
+		unfixed++
+		l.Term().Warning().Printfln("[%s] %s: %s", issue.Rule, issue.Subject, issue.Message)
+	}
+
+	if l.result.Suppressed > 0 {
+		l.Term().Info().Printfln("%d issue(s) suppressed: %d inline, %d in baseline",
+			l.result.Suppressed, suppressed[suppressedInline], suppressed[suppressedBaseline])
+	}
+
+	if unfixed > 0 {
+		return fmt.Errorf("lint found %d issue(s)", unfixed)
+	}
+
+	return nil
+}
+
+// checkManualVersions flags components whose version was changed outside of bump commits.
+func (l *Lint) checkManualVersions() error {
+	inv, err := sync.NewInventory(l.Source, l.Log())
+	if err != nil {
+		return err
+	}
+
+	components := inv.GetComponentsMap()
+	metaPaths := make(map[string]string, components.Len())
+	for _, name := range components.Keys() {
+		c, _ := components.Get(name)
+		metaPaths[name] = c.BuildMetaPath()
+	}
+
+	repo, err := git.PlainOpenWithOptions(l.Source, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("%s - %w", l.Source, err)
+	}
+
+	authors, err := repository.NewBumpAuthors(l.BumpAuthors)
+	if err != nil {
+		return err
+	}
+
+	edits, err := repository.FindManualVersionEdits(repo, metaPaths, authors)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(edits))
+	for name := range edits {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fixed := make(map[string]bool)
+	if l.Fix && len(names) > 0 {
+		fixed, err = l.rebump(components, edits, names)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range names {
+		for _, e := range edits[name] {
+			l.result.Issues = append(l.result.Issues, LintIssue{
+				Rule:    ruleManualVersions,
+				Subject: name,
+				File:    metaPaths[name],
+				Commit:  e.Commit,
+				Author:  fmt.Sprintf("%s <%s>", e.Author, e.Email),
+				Message: fmt.Sprintf("version changed from %s to %s in non-bump commit %s by %s (%s)",
+					component.FormatVersion(e.OldVersion), component.FormatVersion(e.NewVersion), e.Commit[:13], e.Author, e.Date.Format("2006-01-02")),
+				Fixed: fixed[name],
+			})
+		}
+	}
+
+	return nil
+}
+
+// checkArchitecture flags dependencies not permitted by the architecture matrix.
+func (l *Lint) checkArchitecture() error {
+	if len(l.Matrix) == 0 {
+		l.Log().Debug("no architecture rules configured, skipping")
+		return nil
+	}
+
+	inv, err := sync.NewInventory(l.Source, l.Log())
+	if err != nil {
+		return err
+	}
+
+	deps := make(map[string][]string)
+	for name, requires := range inv.GetRequiresMap() {
+		deps[name] = requires.Keys()
+	}
+
+	for _, v := range l.Matrix.CheckAll(deps) {
+		path, _ := sync.ConvertNameToPath(v.From)
+		l.result.Issues = append(l.result.Issues, LintIssue{
+			Rule:    ruleArchitecture,
+			Subject: v.From,
+			File:    filepath.Join(path, "tasks", "dependencies.yaml"),
+			Message: fmt.Sprintf("depends on %s: %s (rule %q)", v.To, v.Reason, v.Rule),
+		})
+	}
+
+	return nil
+}
+
+// checkAttachments flags playbook attachments to chassis paths missing from the chassis model, and components
+// attached to the same chassis path several times. With Fix, duplicates are removed keeping the canonical one.
+func (l *Lint) checkAttachments() error {
+	attachments, err := component.LoadAttachments(l.Source, "")
+	if err != nil {
+		return err
+	}
+
+	if err = l.checkDuplicateAttachments(attachments); err != nil {
+		return err
+	}
+
+	if _, err = os.Stat(filepath.Join(l.Source, "chassis.yaml")); os.IsNotExist(err) {
+		l.Log().Debug("no chassis.yaml found, skipping chassis sections check")
+		return nil
+	}
+
+	c, err := chassis.Load(l.Source)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range component.InvalidAttachments(attachments, c) {
+		message := fmt.Sprintf("attached to unknown chassis section %s", a.Chassis)
+		if a.Suggestion != "" {
+			message += fmt.Sprintf(" (did you mean %s?)", a.Suggestion)
+		}
+
+		l.result.Issues = append(l.result.Issues, LintIssue{
+			Rule:    ruleAttachments,
+			Subject: a.Component,
+			File:    a.Playbook,
+			Line:    a.Line,
+			Column:  a.Column,
+			Message: message,
+		})
+	}
+
+	return nil
+}
+
+// checkDuplicateAttachments flags components attached to the same chassis path by several plays or layer
+// playbooks, which Ansible runs once per occurrence.
+func (l *Lint) checkDuplicateAttachments(attachments []component.Attachment) error {
+	duplicates := component.DuplicateAttachments(attachments)
+
+	fixed := make(map[string]bool)
+	if l.Fix && len(duplicates) > 0 {
+		positions := make(map[string][]playbook.Position)
+		var playbooks []string
+		for _, d := range duplicates {
+			if _, ok := positions[d.Playbook]; !ok {
+				playbooks = append(playbooks, d.Playbook)
+			}
+			positions[d.Playbook] = append(positions[d.Playbook], playbook.Position{Line: d.Line, Column: d.Column})
+		}
+
+		for _, path := range playbooks {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			updated, removed, err := playbook.RemoveRolesAt(data, positions[path])
+			if err != nil {
+				return fmt.Errorf("%s > %w", path, err)
+			}
+			if removed == 0 {
+				continue
+			}
+			if err = os.WriteFile(path, updated, 0644); err != nil {
+				return fmt.Errorf("failed to write playbook: %w", err)
+			}
+			fixed[path] = true
+		}
+	}
+
+	for _, d := range duplicates {
+		l.result.Issues = append(l.result.Issues, LintIssue{
+			Rule:    ruleAttachments,
+			Subject: d.Component,
+			File:    d.Playbook,
+			Line:    d.Line,
+			Column:  d.Column,
+			Message: fmt.Sprintf("attached to %s again, already attached at %s:%d:%d, the role would run twice",
+				d.Chassis, d.Canonical.Playbook, d.Canonical.Line, d.Canonical.Column),
+			Fixed: fixed[d.Playbook],
+		})
+	}
+
+	return nil
+}
+
+// checkYAML strictly parses component meta, dependencies and layer playbooks, reporting malformed files
+// and unknown fields with their positions.
+func (l *Lint) checkYAML() error {
+	checks := []struct {
+		patterns []string
+		check    func(string) error
+		playbook bool
+	}{
+		{[]string{"*/*/*/meta/plasma.yaml", "*/*/roles/*/meta/plasma.yaml"}, strictyaml.CheckMeta, false},
+		{[]string{"*/*/*/tasks/dependencies.yaml", "*/*/roles/*/tasks/dependencies.yaml"}, strictyaml.CheckDependencies, false},
+		{[]string{"*/*.yaml", "src/*/*.yaml"}, strictyaml.CheckPlaybook, true},
+	}
+
+	for _, c := range checks {
+		for _, pattern := range c.patterns {
+			paths, err := filepath.Glob(filepath.Join(l.Source, pattern))
+			if err != nil {
+				return err
+			}
+
+			for _, path := range paths {
+				rel, _ := filepath.Rel(l.Source, path)
+				if strings.HasPrefix(rel, ".") {
+					continue
+				}
+
+				subject := yamlSubject(rel)
+				// Playbooks are named after their layer directory.
+				if c.playbook {
+					layer := filepath.Base(filepath.Dir(rel))
+					if filepath.Base(rel) != layer+".yaml" {
+						continue
+					}
+					subject = layer
+				}
+
+				l.addYAMLIssues(ruleYAML, subject, rel, c.check(path))
+			}
+		}
+	}
+
+	return nil
+}
+
+// addYAMLIssues reports positioned errors of the file under the rule.
+func (l *Lint) addYAMLIssues(rule, subject, file string, err error) {
+	if err == nil {
+		return
+	}
+
+	positioned := strictyaml.Errors(err)
+	if len(positioned) == 0 {
+		l.result.Issues = append(l.result.Issues, LintIssue{Rule: rule, Subject: subject, File: file, Message: err.Error()})
+		return
+	}
+
+	for _, e := range positioned {
+		l.result.Issues = append(l.result.Issues, LintIssue{
+			Rule:    rule,
+			Subject: subject,
+			File:    file,
+			Line:    e.Line,
+			Column:  e.Column,
+			Message: e.Error(),
+		})
+	}
+}
+
+// yamlSubject returns component name of the file path in the root or roles layout.
+func yamlSubject(path string) string {
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	if len(parts) > 3 && parts[2] == "roles" {
+		return sync.PrepareComponentName(parts[0], parts[1], parts[3])
+	}
+	if len(parts) > 2 {
+		return sync.PrepareComponentName(parts[0], parts[1], parts[2])
+	}
+
+	return path
+}
+
+// rebump sets versions of manually edited components to their latest edit commit and creates bump commit.
+func (l *Lint) rebump(components *sync.OrderedMap[*sync.Component], edits map[string][]repository.VersionEdit, names []string) (map[string]bool, error) {
+	bumper, err := repository.NewBumper()
+	if err != nil {
+		return nil, err
+	}
+
+	fixed := make(map[string]bool)
+	for _, name := range names {
+		c, ok := components.Get(name)
+		if !ok || len(edits[name]) == 0 {
+			continue
+		}
+
+		// Edits are ordered from newest to oldest.
+		version := edits[name][0].Commit[:13]
+		debug, errUpdate := c.UpdateVersion(version)
+		for _, d := range debug {
+			l.Log().Debug("error", "message", d)
+		}
+		if errUpdate != nil {
+			return nil, errUpdate
+		}
+
+		l.Term().Printfln("- %s re-bumped to %s", name, version)
+		fixed[name] = true
+		l.result.Fixed++
+	}
+
+	if len(fixed) == 0 {
+		return fixed, nil
+	}
+
+	return fixed, bumper.Commit()
+}
@@ -0,0 +1,247 @@
+package lint
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Suppression sources of issues.
+const (
+	suppressedInline   = "inline"
+	suppressedBaseline = "baseline"
+)
+
+const (
+	// ignoreDirective suppresses issues of the rules on the same or the next line, all rules without names.
+	ignoreDirective = "lint:ignore"
+	// ignoreFileDirective suppresses issues of the rules in the whole file, all rules without names.
+	ignoreFileDirective = "lint:ignore-file"
+)
+
+// Baseline is the recorded set of known issues, only new issues fail the lint.
+// Issues are identified without positions, so edits moving them around don't make them new.
+type Baseline struct {
+	Issues []BaselineIssue `json:"issues"`
+}
+
+// BaselineIssue identifies a known issue.
+type BaselineIssue struct {
+	Rule    string `json:"rule"`
+	Subject string `json:"subject"`
+	File    string `json:"file,omitempty"`
+	Message string `json:"message"`
+}
+
+func baselineIssue(issue LintIssue) BaselineIssue {
+	return BaselineIssue{Rule: issue.Rule, Subject: issue.Subject, File: filepath.ToSlash(issue.File), Message: issue.Message}
+}
+
+// suppressInline marks issues suppressed by `# lint:ignore` comments of their files.
+func (l *Lint) suppressInline() {
+	directives := make(map[string]*fileDirectives)
+	for i := range l.result.Issues {
+		issue := &l.result.Issues[i]
+		if issue.File == "" || issue.Fixed {
+			continue
+		}
+
+		d, ok := directives[issue.File]
+		if !ok {
+			d = readDirectives(filepath.Join(l.Source, issue.File))
+			directives[issue.File] = d
+		}
+		if d.suppresses(issue.Rule, issue.Line) {
+			issue.Suppressed = suppressedInline
+		}
+	}
+}
+
+// applyBaseline marks issues recorded in the baseline file, each recorded issue matching a single one.
+func (l *Lint) applyBaseline() error {
+	data, err := os.ReadFile(l.Baseline)
+	if os.IsNotExist(err) {
+		l.Log().Debug("no baseline file found, every issue is new", "file", l.Baseline)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var baseline Baseline
+	if err = json.Unmarshal(data, &baseline); err != nil {
+		return fmt.Errorf("invalid baseline %s > %w", l.Baseline, err)
+	}
+
+	known := make(map[BaselineIssue]int, len(baseline.Issues))
+	for _, b := range baseline.Issues {
+		known[b]++
+	}
+
+	for i := range l.result.Issues {
+		issue := &l.result.Issues[i]
+		if issue.Fixed || issue.Suppressed != "" {
+			continue
+		}
+
+		key := baselineIssue(*issue)
+		if known[key] > 0 {
+			known[key]--
+			issue.Suppressed = suppressedBaseline
+		}
+	}
+
+	return nil
+}
+
+// writeBaseline records unfixed issues not suppressed inline as the baseline.
+func (l *Lint) writeBaseline() error {
+	baseline := Baseline{Issues: make([]BaselineIssue, 0)}
+	for _, issue := range l.result.Issues {
+		if issue.Fixed || issue.Suppressed == suppressedInline {
+			continue
+		}
+		baseline.Issues = append(baseline.Issues, baselineIssue(issue))
+	}
+
+	sort.Slice(baseline.Issues, func(i, j int) bool {
+		a, b := baseline.Issues[i], baseline.Issues[j]
+		if a.Rule != b.Rule {
+			return a.Rule < b.Rule
+		}
+		if a.Subject != b.Subject {
+			return a.Subject < b.Subject
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Message < b.Message
+	})
+
+	data, err := json.MarshalIndent(baseline, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.WriteFile(l.Baseline, append(data, '\n'), 0644); err != nil { //nolint:gosec
+		return fmt.Errorf("failed to write baseline %s > %w", l.Baseline, err)
+	}
+
+	l.Term().Info().Printfln("Recorded %d issue(s) in baseline %s", len(baseline.Issues), l.Baseline)
+	return nil
+}
+
+// fileDirectives are suppression comments of a file: rules ignored by line and in the whole file.
+// Empty rule list ignores all rules.
+type fileDirectives struct {
+	lines map[int][]string
+	file  [][]string
+}
+
+// readDirectives collects suppression comments of the file, none if it can't be read.
+func readDirectives(path string) *fileDirectives {
+	d := &fileDirectives{lines: make(map[int][]string)}
+	f, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return d
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+
+	for i, line := range lines {
+		idx := strings.Index(line, "#")
+		if idx < 0 {
+			continue
+		}
+
+		comment := strings.TrimSpace(line[idx+1:])
+		if rest, ok := strings.CutPrefix(comment, ignoreFileDirective); ok && isDirectiveEnd(rest) {
+			d.file = append(d.file, directiveRules(rest))
+			continue
+		}
+		rest, ok := strings.CutPrefix(comment, ignoreDirective)
+		if !ok || !isDirectiveEnd(rest) {
+			continue
+		}
+
+		// A trailing comment applies to its own line, a comment alone on its line to the next node,
+		// i.e. the next line and lines indented deeper, like the keys of a list item.
+		rules := directiveRules(rest)
+		if strings.TrimSpace(line[:idx]) != "" {
+			d.lines[i+1] = rules
+			continue
+		}
+		for _, n := range nextBlock(lines, i+1) {
+			d.lines[n+1] = rules
+		}
+	}
+
+	return d
+}
+
+// nextBlock returns indexes of the first content line from start and the following lines indented deeper.
+func nextBlock(lines []string, start int) []int {
+	var block []int
+	indent := -1
+	for n := start; n < len(lines); n++ {
+		trimmed := strings.TrimSpace(lines[n])
+		if trimmed == "" || (indent < 0 && strings.HasPrefix(trimmed, "#")) {
+			continue
+		}
+
+		lineIndent := len(lines[n]) - len(strings.TrimLeft(lines[n], " \t"))
+		if indent >= 0 && lineIndent <= indent {
+			break
+		}
+		if indent < 0 {
+			indent = lineIndent
+		}
+		block = append(block, n)
+	}
+
+	return block
+}
+
+// suppresses tells if the issue of the rule at the line, 0 if unknown, is ignored.
+func (d *fileDirectives) suppresses(rule string, line int) bool {
+	for _, rules := range d.file {
+		if matchesRule(rules, rule) {
+			return true
+		}
+	}
+
+	rules, ok := d.lines[line]
+	return line > 0 && ok && matchesRule(rules, rule)
+}
+
+func isDirectiveEnd(rest string) bool {
+	return rest == "" || rest[0] == ' ' || rest[0] == '\t'
+}
+
+// directiveRules returns comma or space separated rule names of the directive, stopping at a `--` explanation.
+func directiveRules(rest string) []string {
+	rest, _, _ = strings.Cut(rest, "--")
+	return strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t'
+	})
+}
+
+func matchesRule(rules []string, rule string) bool {
+	if len(rules) == 0 {
+		return true
+	}
+	for _, r := range rules {
+		if r == rule {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const readmeFile = "README.md"
+
+// DocsRule declares documentation required for components of a kind.
+type DocsRule struct {
+	// README requires a README.md starting with a title heading.
+	README bool `yaml:"readme"`
+	// Sections are second level headings required in README.md, matched case-insensitively.
+	Sections []string `yaml:"sections"`
+	// Description requires a non-empty plasma.description in meta/plasma.yaml.
+	Description bool `yaml:"description"`
+}
+
+// DocsRules are documentation rules by component kind, "*" applies to kinds without own rule.
+type DocsRules map[string]DocsRule
+
+// forKind returns the rule of the kind and whether any applies.
+func (r DocsRules) forKind(kind string) (DocsRule, bool) {
+	if rule, ok := r[kind]; ok {
+		return rule, true
+	}
+	rule, ok := r["*"]
+	return rule, ok
+}
+
+// checkDocs flags components missing README or meta description required for their kind.
+func (l *Lint) checkDocs() error {
+	if len(l.DocsRules) == 0 {
+		l.Log().Debug("no docs rules configured, skipping")
+		return nil
+	}
+
+	for _, pattern := range []string{"*/*/*/meta/plasma.yaml", "*/*/roles/*/meta/plasma.yaml"} {
+		paths, err := filepath.Glob(filepath.Join(l.Source, pattern))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			rel, _ := filepath.Rel(l.Source, path)
+			if strings.HasPrefix(rel, ".") {
+				continue
+			}
+
+			kind := strings.Split(filepath.ToSlash(rel), "/")[1]
+			rule, ok := l.DocsRules.forKind(kind)
+			if !ok {
+				continue
+			}
+
+			for _, issue := range l.docsIssues(rule, filepath.Dir(filepath.Dir(rel))) {
+				issue.Rule = ruleDocs
+				issue.Subject = yamlSubject(rel)
+				l.result.Issues = append(l.result.Issues, issue)
+			}
+		}
+	}
+
+	return nil
+}
+
+// docsIssues returns documentation missing from the component directory relative to the source.
+func (l *Lint) docsIssues(rule DocsRule, dir string) []LintIssue {
+	var issues []LintIssue
+
+	if rule.README || len(rule.Sections) > 0 {
+		readme := filepath.Join(dir, readmeFile)
+		data, err := os.ReadFile(filepath.Join(l.Source, readme))
+		switch {
+		case os.IsNotExist(err):
+			issues = append(issues, LintIssue{File: readme, Message: "missing " + readmeFile})
+		case err != nil:
+			issues = append(issues, LintIssue{File: readme, Message: err.Error()})
+		default:
+			for _, message := range readmeIssues(data, rule.Sections) {
+				issues = append(issues, LintIssue{File: readme, Message: message})
+			}
+		}
+	}
+
+	if rule.Description {
+		metaPath := filepath.Join(dir, "meta", "plasma.yaml")
+		data, err := os.ReadFile(filepath.Join(l.Source, metaPath))
+		if err != nil {
+			return append(issues, LintIssue{File: metaPath, Message: err.Error()})
+		}
+
+		var meta struct {
+			Plasma struct {
+				Description string `yaml:"description"`
+			} `yaml:"plasma"`
+		}
+		// Malformed meta is reported by the yaml rule.
+		_ = yaml.Unmarshal(data, &meta)
+		if strings.TrimSpace(meta.Plasma.Description) == "" {
+			issues = append(issues, LintIssue{File: metaPath, Message: "missing plasma.description in meta"})
+		}
+	}
+
+	return issues
+}
+
+// readmeIssues checks README starts with a title heading and has the required sections.
+func readmeIssues(data []byte, sections []string) []string {
+	var issues []string
+	var lines int
+	headings := make(map[string]bool)
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		lines++
+		if lines == 1 && !strings.HasPrefix(line, "# ") {
+			issues = append(issues, readmeFile+" must start with a title heading")
+		}
+
+		if heading, ok := strings.CutPrefix(line, "## "); ok {
+			headings[strings.ToLower(strings.TrimSpace(heading))] = true
+		}
+	}
+
+	if lines == 0 {
+		return []string{readmeFile + " is empty"}
+	}
+
+	for _, section := range sections {
+		if !headings[strings.ToLower(section)] {
+			issues = append(issues, fmt.Sprintf("%s has no %q section", readmeFile, section))
+		}
+	}
+
+	return issues
+}
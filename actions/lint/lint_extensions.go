@@ -0,0 +1,61 @@
+package lint
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/pkg/extension"
+)
+
+// selectExtensions returns contributed rules to run: all of them with all rules, the selected ones otherwise.
+func (l *Lint) selectExtensions(all bool) ([]extension.LintRule, error) {
+	known := make(map[string]extension.LintRule, len(l.Extensions))
+	for _, rule := range l.Extensions {
+		if slices.Contains(builtinRules, rule.Name) {
+			return nil, fmt.Errorf("contributed lint rule %s conflicts with the built-in rule", rule.Name)
+		}
+		known[rule.Name] = rule
+	}
+
+	if all {
+		return l.Extensions, nil
+	}
+
+	var selected []extension.LintRule
+	var unknown []string
+	for _, name := range l.ExtensionRules {
+		rule, ok := known[name]
+		if !ok {
+			unknown = append(unknown, name)
+			continue
+		}
+		selected = append(selected, rule)
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown lint rule(s): %s", strings.Join(unknown, ", "))
+	}
+
+	return selected, nil
+}
+
+// checkExtension runs a rule contributed by another plugin.
+func (l *Lint) checkExtension(rule extension.LintRule) error {
+	issues, err := rule.Check(l.Source)
+	if err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		l.result.Issues = append(l.result.Issues, LintIssue{
+			Rule:    rule.Name,
+			Subject: issue.Subject,
+			File:    issue.File,
+			Line:    issue.Line,
+			Column:  issue.Column,
+			Message: issue.Message,
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package lint
+
+import (
+	"bufio"
+	"bytes"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// secretsIgnore marks a line as reviewed, it isn't reported by the secrets rule.
+const secretsIgnore = "lint:ignore-secrets"
+
+// secretsDirs are component directories scanned for secrets.
+var secretsDirs = []string{"tasks", "templates", "defaults", "vars", "handlers", "files"}
+
+var (
+	rePrivateKey = regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)
+	reAWSKey     = regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)
+	// Credential keys assigned to a literal value, in YAML or INI/properties style.
+	reCredential = regexp.MustCompile(`(?i)^\s*-?\s*["']?([a-z0-9_.-]*(password|passwd|secret|token|api_?key|access_?key|private_?key))["']?\s*[:=]\s*(.+)$`)
+	reToken      = regexp.MustCompile(`[A-Za-z0-9+/=_-]{32,}`)
+)
+
+// checkSecrets flags private keys, cloud credentials, literal passwords and high-entropy strings
+// in component sources outside of vault files.
+func (l *Lint) checkSecrets() error {
+	for _, pattern := range []string{"*/*/*/meta/plasma.yaml", "*/*/roles/*/meta/plasma.yaml"} {
+		paths, err := filepath.Glob(filepath.Join(l.Source, pattern))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			rel, _ := filepath.Rel(l.Source, path)
+			if strings.HasPrefix(rel, ".") {
+				continue
+			}
+
+			dir := filepath.Dir(filepath.Dir(rel))
+			for _, sub := range secretsDirs {
+				if err = l.scanSecretsDir(yamlSubject(rel), filepath.Join(dir, sub)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// scanSecretsDir scans files of the directory relative to the source.
+func (l *Lint) scanSecretsDir(subject, dir string) error {
+	root := filepath.Join(l.Source, dir)
+	if _, err := os.Stat(root); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		// Vault files are encrypted, binary files aren't sources.
+		if d.Name() == "vault.yaml" || bytes.HasPrefix(data, []byte("$ANSIBLE_VAULT")) || bytes.IndexByte(data, 0) >= 0 {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(l.Source, path)
+		for _, f := range findSecrets(data) {
+			l.result.Issues = append(l.result.Issues, LintIssue{
+				Rule:    ruleSecrets,
+				Subject: subject,
+				File:    rel,
+				Line:    f.line,
+				Message: f.message,
+			})
+		}
+
+		return nil
+	})
+}
+
+type secretFinding struct {
+	line    int
+	message string
+}
+
+// findSecrets returns lines looking like secrets, at most one finding per line.
+func findSecrets(data []byte) []secretFinding {
+	var findings []secretFinding
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if strings.Contains(line, secretsIgnore) {
+			continue
+		}
+
+		if message := secretMessage(line); message != "" {
+			findings = append(findings, secretFinding{line: n, message: message})
+		}
+	}
+
+	return findings
+}
+
+func secretMessage(line string) string {
+	switch {
+	case rePrivateKey.MatchString(line):
+		return "private key"
+	case reAWSKey.MatchString(line):
+		return "AWS access key"
+	}
+
+	if m := reCredential.FindStringSubmatch(line); m != nil && isLiteralSecret(m[3]) {
+		return "unencrypted credential in " + m[1] + ", use vault"
+	}
+
+	for _, token := range reToken.FindAllString(line, -1) {
+		if isHighEntropy(token) {
+			return "high-entropy string, possible secret"
+		}
+	}
+
+	return ""
+}
+
+// isLiteralSecret reports whether the assigned value is a literal, not a variable, lookup or vault reference.
+func isLiteralSecret(value string) bool {
+	value = strings.TrimSpace(value)
+	if i := strings.Index(value, " #"); i >= 0 {
+		value = strings.TrimSpace(value[:i])
+	}
+	value = strings.Trim(value, `"'`)
+
+	switch {
+	case value == "", value == "~", value == "null", value == "|", value == ">":
+		return false
+	case strings.Contains(value, "{{"), strings.Contains(value, "{%"), strings.HasPrefix(value, "!vault"), strings.HasPrefix(value, "$"):
+		return false
+	}
+
+	return len(value) >= 4
+}
+
+// isHighEntropy reports whether the token looks random enough to be a key or token.
+// Hex strings like commit hashes are common in sources and need a longer length.
+func isHighEntropy(token string) bool {
+	isHex := strings.Trim(strings.ToLower(token), "0123456789abcdef") == ""
+	if isHex {
+		return len(token) >= 40 && entropy(token) > 3.5 && !isCommitLike(token)
+	}
+
+	hasDigit := strings.ContainsAny(token, "0123456789")
+	hasUpper := strings.ToLower(token) != token
+	hasLower := strings.ToUpper(token) != token
+	return hasDigit && hasUpper && hasLower && entropy(token) > 4.0
+}
+
+// isCommitLike reports hex strings of git object hash length.
+func isCommitLike(token string) bool {
+	return len(token) == 40 || len(token) == 64
+}
+
+// entropy returns Shannon entropy of the string in bits per character.
+func entropy(s string) float64 {
+	counts := make(map[rune]int)
+	for _, r := range s {
+		counts[r]++
+	}
+
+	var result float64
+	length := float64(len(s))
+	for _, c := range counts {
+		p := float64(c) / length
+		result -= p * math.Log2(p)
+	}
+
+	return result
+}
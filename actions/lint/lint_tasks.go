@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/strictyaml"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// checkTasks validates component tasks files are lists of task maps, included roles are existing components
+// named by MRN and no deprecated module is used.
+func (l *Lint) checkTasks() error {
+	// Roles may be provided by compose packages of the domain.
+	loaded, err := component.LoadComposed(context.Background(), l.Source, component.LoadOptions{})
+	if err != nil {
+		return err
+	}
+
+	components := make(map[string]bool, len(loaded))
+	for _, c := range loaded {
+		components[c.Name] = true
+	}
+
+	deprecated := l.DeprecatedModules
+	if len(deprecated) == 0 {
+		deprecated = strictyaml.DeprecatedModules
+	}
+
+	for _, pattern := range []string{"*/*/*/meta/plasma.yaml", "*/*/roles/*/meta/plasma.yaml"} {
+		paths, err := filepath.Glob(filepath.Join(l.Source, pattern))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			rel, _ := filepath.Rel(l.Source, path)
+			if strings.HasPrefix(rel, ".") {
+				continue
+			}
+
+			tasksDir := filepath.Join(filepath.Dir(filepath.Dir(path)), "tasks")
+			for _, ext := range []string{"*.yaml", "*.yml"} {
+				tasks, err := filepath.Glob(filepath.Join(tasksDir, ext))
+				if err != nil {
+					return err
+				}
+
+				for _, task := range tasks {
+					file, _ := filepath.Rel(l.Source, task)
+					l.addYAMLIssues(ruleTasks, yamlSubject(rel), file, strictyaml.CheckTasks(task, components, deprecated))
+				}
+			}
+		}
+	}
+
+	return nil
+}
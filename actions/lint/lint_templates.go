@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/jinja"
+)
+
+// checkTemplates parses Jinja2 templates of components, reporting syntax errors and unknown filters.
+func (l *Lint) checkTemplates() error {
+	filters := jinja.DefaultFilters().With(l.TemplateFilters...)
+
+	for _, pattern := range []string{"*/*/*/meta/plasma.yaml", "*/*/roles/*/meta/plasma.yaml"} {
+		paths, err := filepath.Glob(filepath.Join(l.Source, pattern))
+		if err != nil {
+			return err
+		}
+
+		for _, path := range paths {
+			rel, _ := filepath.Rel(l.Source, path)
+			if strings.HasPrefix(rel, ".") {
+				continue
+			}
+
+			root := filepath.Join(l.Source, filepath.Dir(filepath.Dir(rel)))
+			err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+				if err != nil || d.IsDir() || filepath.Ext(path) != ".j2" {
+					return err
+				}
+
+				data, err := os.ReadFile(filepath.Clean(path))
+				if err != nil {
+					return err
+				}
+
+				file, _ := filepath.Rel(l.Source, path)
+				for _, e := range jinja.Check(string(data), filters) {
+					l.result.Issues = append(l.result.Issues, LintIssue{
+						Rule:    ruleTemplates,
+						Subject: yamlSubject(rel),
+						File:    file,
+						Line:    e.Line,
+						Column:  e.Column,
+						Message: e.Message,
+					})
+				}
+
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package lint_test
+
+import (
+	"testing"
+
+	"github.com/plasmash/plasmactl-component/actions/bump"
+	"github.com/plasmash/plasmactl-component/actions/lint"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+)
+
+func TestLintManualVersionsBumpAuthors(t *testing.T) {
+	p := testenv.NewPlatform(t)
+	if err := testenv.Run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	p.SetVersion(testenv.Auth, "bot0000000000")
+	p.Commit("versions bump", "ci-bot")
+
+	l := &lint.Lint{Source: ".", ManualVersions: true}
+	if err := testenv.Run(t, l); err == nil {
+		t.Fatal("expected version set by an undeclared author reported")
+	}
+
+	for _, authors := range [][]string{{"ci-bot"}, {testenv.DeveloperEmail}, {"/^ci-/"}} {
+		l = &lint.Lint{Source: ".", ManualVersions: true, BumpAuthors: authors}
+		if err := testenv.Run(t, l); err != nil {
+			t.Errorf("expected version set by bump author %v accepted, got %v", authors, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package lint
+
+import (
+	"fmt"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// checkVersions flags component versions not conforming to the propagated version format, which sync would
+// mis-compare. With Fix, versions are normalized and committed as a bump.
+func (l *Lint) checkVersions() error {
+	if err := l.VersionFormat.Validate(); err != nil {
+		return err
+	}
+
+	inv, err := sync.NewInventory(l.Source, l.Log())
+	if err != nil {
+		return err
+	}
+
+	components := inv.GetComponentsMap()
+	components.SortKeysAlphabetically()
+
+	var bumper *repository.Bumper
+	for _, name := range components.Keys() {
+		c, _ := components.Get(name)
+		version, debug, err := c.GetVersion()
+		for _, d := range debug {
+			l.Log().Debug("error", "message", d)
+		}
+		if err != nil {
+			return err
+		}
+
+		// Components are created without version until their first bump.
+		if version == "" {
+			continue
+		}
+
+		fixed, reason := l.VersionFormat.Normalize(version)
+		if reason == "" {
+			continue
+		}
+
+		issue := LintIssue{
+			Rule:    ruleVersions,
+			Subject: name,
+			File:    c.BuildMetaPath(),
+			Message: fmt.Sprintf("version %q %s", version, reason),
+		}
+		if fixed != "" {
+			issue.Message += fmt.Sprintf(", expected %q", fixed)
+		}
+
+		if l.Fix && fixed != "" {
+			if bumper == nil {
+				bumper, err = repository.NewBumper()
+				if err != nil {
+					return err
+				}
+			}
+
+			debug, err = c.UpdateVersion(fixed)
+			for _, d := range debug {
+				l.Log().Debug("error", "message", d)
+			}
+			if err != nil {
+				return err
+			}
+
+			issue.Fixed = true
+			l.result.Fixed++
+		}
+
+		l.result.Issues = append(l.result.Issues, issue)
+	}
+
+	if bumper == nil {
+		return nil
+	}
+
+	return bumper.Commit()
+}
@@ -1,11 +1,18 @@
 package list
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"sort"
 
 	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
+	"github.com/plasmash/plasmactl-component/internal/repository"
 	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 	"github.com/plasmash/plasmactl-platform/pkg/graph"
 )
 
@@ -16,11 +23,17 @@ type ComponentListItem struct {
 	Layer   string `json:"layer"`
 	Kind    string `json:"kind"`
 	Chassis string `json:"chassis,omitempty"`
+	// ChangedIn is the latest commit touching the component, with --changed-since.
+	ChangedIn string `json:"changed_in,omitempty"`
+	// Location is the source directory of the component, with --resolve-paths.
+	Location *component.Location `json:"location,omitempty"`
 }
 
 // ListResult is the structured output for component:list
 type ListResult struct {
-	Components []ComponentListItem `json:"components"`
+	Components         []ComponentListItem           `json:"components"`
+	InvalidAttachments []component.InvalidAttachment `json:"invalid_attachments,omitempty"`
+	Warnings           warning.List                  `json:"warnings,omitempty"`
 }
 
 // List implements the component:list command
@@ -28,24 +41,57 @@ type List struct {
 	action.WithLogger
 	action.WithTerm
 
-	Tree    bool
-	Kind    string
-	All     bool
-	Orphans bool
-
-	result *ListResult
+	Tree      bool
+	Kind      string
+	All       bool
+	Orphans   bool
+	Chassis   string
+	NoInherit bool
+	// ChangedSince keeps components touched by commits since the revision or date (YYYY-MM-DD).
+	ChangedSince string
+	// Ref lists domain components of a commit, tag or branch, read from the git objects without checkout.
+	Ref string
+	// ResolvePaths adds the source directory of listed components to the result.
+	ResolvePaths bool
+
+	InvalidAttachments bool
+
+	// Context and Progress are used when components are loaded from filesystem.
+	Context  context.Context
+	Progress component.Progress
+
+	warnings warning.List
+	result   *ListResult
 }
 
-// Result returns the structured result for JSON output
+// Result returns the structured result for JSON output, with warnings of the run.
 func (l *List) Result() any {
+	if l.result != nil {
+		l.result.Warnings = l.warnings
+	}
 	return l.result
 }
 
 // Execute runs the component:list action
 func (l *List) Execute() error {
+	if l.Ref != "" {
+		if l.InvalidAttachments || l.ChangedSince != "" || l.ResolvePaths {
+			return errors.New("--ref can't be combined with --invalid-attachments, --changed-since or --resolve-paths")
+		}
+		return l.listFromFilesystem()
+	}
+
+	if l.InvalidAttachments {
+		return l.listInvalidAttachments()
+	}
+
 	g, err := graph.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load graph: %w", err)
+		l.Log().Warn(l.warnings.Add(warning.Fallback, "", "platform graph is unavailable, loading components from filesystem"), "error", err)
+		return l.listFromFilesystem()
+	}
+	if msg := graphstate.Check("."); msg != "" {
+		l.Term().Warning().Println(l.warnings.Add(warning.StaleGraph, "", "%s", msg))
 	}
 
 	allNodes := g.NodesByType("component")
@@ -59,6 +105,20 @@ func (l *List) Execute() error {
 			chassis = attachEdges[0].From().Name
 		}
 
+		// Filter by chassis, keeping the matching attachment
+		if l.Chassis != "" {
+			chassis = ""
+			for _, e := range attachEdges {
+				if component.MatchesChassis(e.From().Name, l.Chassis, !l.NoInherit) {
+					chassis = e.From().Name
+					break
+				}
+			}
+			if chassis == "" {
+				continue
+			}
+		}
+
 		// Filter: attached only (default) vs all
 		if !l.All && chassis == "" {
 			continue
@@ -83,15 +143,94 @@ func (l *List) Execute() error {
 		items = l.filterOrphans(items, g)
 	}
 
+	return l.print(items, g)
+}
+
+// listFromFilesystem lists components of the composed output, or of the domain at the ref, with their playbook
+// attachments. Orphans and nodes of the tree require the platform graph and are not available.
+func (l *List) listFromFilesystem() error {
+	if l.Orphans {
+		return errors.New("--orphans requires the platform graph")
+	}
+
+	ctx := l.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var components component.Components
+	var err error
+	if l.Ref != "" {
+		var fsys fs.FS
+		if fsys, _, err = repository.RefFS(".", l.Ref); err != nil {
+			return err
+		}
+		components, err = component.LoadAttachedFS(ctx, fsys, component.LoadOptions{Progress: l.Progress})
+	} else {
+		components, err = component.LoadAttached(ctx, ".", component.LoadOptions{Progress: l.Progress})
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load components: %w", err)
+	}
+
+	var items []ComponentListItem
+	listed := make(map[string]bool)
+	for _, c := range components {
+		if listed[c.Name] {
+			continue
+		}
+		if l.Chassis != "" && !component.MatchesChassis(c.Chassis, l.Chassis, !l.NoInherit) {
+			continue
+		}
+		if !l.All && c.Chassis == "" {
+			continue
+		}
+		if l.Kind != "" && c.Kind != l.Kind {
+			continue
+		}
+
+		listed[c.Name] = true
+		items = append(items, ComponentListItem{
+			Name:    c.Name,
+			Version: c.Version,
+			Layer:   c.Layer,
+			Kind:    c.Kind,
+			Chassis: c.Chassis,
+		})
+	}
+
+	return l.print(items, nil)
+}
+
+// print sorts and prints listed components, the graph is optional.
+func (l *List) print(items []ComponentListItem, g *graph.PlatformGraph) error {
+	if l.ChangedSince != "" {
+		var err error
+		items, err = l.filterChanged(items)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Sort by name
 	sort.Slice(items, func(i, j int) bool {
 		return items[i].Name < items[j].Name
 	})
 
+	if l.ResolvePaths {
+		locator, err := component.NewLocator(".")
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			items[i].Location = locator.Locate(items[i].Name)
+		}
+	}
+
 	l.result = &ListResult{Components: items}
 
 	if len(items) == 0 {
-		l.Term().Warning().Println("No components found")
+		l.Term().Warning().Println(l.warnings.Add(warning.Empty, "", "No components found"))
 		return nil
 	}
 
@@ -101,19 +240,57 @@ func (l *List) Execute() error {
 
 	// Flat output - one per line, scriptable
 	for _, item := range items {
+		if item.ChangedIn != "" {
+			l.Term().Printfln("%s\t%s", component.FormatDisplayName(item.Name, item.Version), item.ChangedIn[:13])
+			continue
+		}
 		l.Term().Printfln("%s", component.FormatDisplayName(item.Name, item.Version))
 	}
 
 	return nil
 }
 
+// listInvalidAttachments lists playbook attachments to chassis paths missing from the chassis model
+func (l *List) listInvalidAttachments() error {
+	c, err := chassis.Load(".")
+	if err != nil {
+		return err
+	}
+
+	attachments, err := component.LoadAttachments(".", "")
+	if err != nil {
+		return fmt.Errorf("failed to load attachments: %w", err)
+	}
+
+	invalid := component.InvalidAttachments(attachments, c)
+	l.result = &ListResult{InvalidAttachments: invalid}
+	if len(invalid) == 0 {
+		l.Term().Success().Printfln("All %d attachment(s) refer to existing chassis sections", len(attachments))
+		return nil
+	}
+
+	for _, a := range invalid {
+		location := fmt.Sprintf("%s:%d:%d", a.Playbook, a.Line, a.Column)
+		if a.Suggestion != "" {
+			l.Term().Printfln("%s\t%s\t%s (did you mean %s?)", a.Component, a.Chassis, location, a.Suggestion)
+			continue
+		}
+		l.Term().Printfln("%s\t%s\t%s", a.Component, a.Chassis, location)
+	}
+
+	return nil
+}
+
 // printTree prints components as a tree with chassis paths and nodes
 func (l *List) printTree(items []ComponentListItem, g *graph.PlatformGraph) error {
 	// Build chassis path to nodes map from graph
+	// Nodes aren't known without graph.
 	chassisToNodes := make(map[string][]string)
-	for _, n := range g.NodesByType("node") {
-		for _, e := range g.EdgesFrom(n.Name, "allocates") {
-			chassisToNodes[e.To().Name] = append(chassisToNodes[e.To().Name], n.Name)
+	if g != nil {
+		for _, n := range g.NodesByType("node") {
+			for _, e := range g.EdgesFrom(n.Name, "allocates") {
+				chassisToNodes[e.To().Name] = append(chassisToNodes[e.To().Name], n.Name)
+			}
 		}
 	}
 
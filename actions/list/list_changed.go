@@ -0,0 +1,70 @@
+package list
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// changeRange returns the commit range of the changed since value, a date as YYYY-MM-DD or a revision.
+func (l *List) changeRange() repository.CommitRange {
+	if date, err := time.ParseInLocation(time.DateOnly, l.ChangedSince, time.Local); err == nil {
+		return repository.CommitRange{SinceDate: date}
+	}
+
+	return repository.CommitRange{Since: l.ChangedSince}
+}
+
+// changedComponents returns components touched by commits since the revision or date, with the hash of the latest
+// commit touching them. Bump commits are skipped, like bump does in range mode, the repository isn't modified.
+func (l *List) changedComponents() (map[string]string, error) {
+	repo, err := repository.NewBumper()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository: %w", err)
+	}
+
+	commits, err := repo.GetCommitsInRange(l.changeRange())
+	if err != nil {
+		return nil, err
+	}
+
+	changed := make(map[string]string)
+	for _, c := range commits {
+		for _, path := range c.Files {
+			platform, kind, role, err := sync.ProcessComponentPath(path)
+			if err != nil || platform == "" || kind == "" || role == "" {
+				continue
+			}
+
+			name := sync.PrepareComponentName(platform, kind, role)
+			if _, ok := changed[name]; !ok {
+				changed[name] = c.Hash
+			}
+		}
+	}
+
+	return changed, nil
+}
+
+// filterChanged keeps components touched since the revision or date, marked with the latest commit touching them.
+func (l *List) filterChanged(items []ComponentListItem) ([]ComponentListItem, error) {
+	changed, err := l.changedComponents()
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]ComponentListItem, 0, len(items))
+	for _, item := range items {
+		hash, ok := changed[item.Name]
+		if !ok {
+			continue
+		}
+
+		item.ChangedIn = hash
+		filtered = append(filtered, item)
+	}
+
+	return filtered, nil
+}
@@ -0,0 +1,371 @@
+package manifest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/launchrctl/compose/compose"
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/release"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+const domainPackage = "domain"
+
+// ChangedComponent represents a component which version or chassis differ between manifests.
+type ChangedComponent struct {
+	Name       string   `json:"name"`
+	OldVersion string   `json:"old_version"`
+	NewVersion string   `json:"new_version"`
+	OldChassis []string `json:"old_chassis,omitempty"`
+	NewChassis []string `json:"new_chassis,omitempty"`
+}
+
+// Diff is the difference between two manifests.
+type Diff struct {
+	Added   []release.Component `json:"added"`
+	Removed []release.Component `json:"removed"`
+	Changed []ChangedComponent  `json:"changed"`
+}
+
+// ManifestResult is the structured result of component:release-manifest.
+type ManifestResult struct {
+	Manifest *release.Manifest `json:"manifest,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Diff     *Diff             `json:"diff,omitempty"`
+	Warnings warning.List      `json:"warnings,omitempty"`
+}
+
+// ReleaseManifest implements component:release-manifest command
+type ReleaseManifest struct {
+	action.WithLogger
+	action.WithTerm
+
+	// Arguments
+	Manifests []string
+
+	BuildDir    string
+	PackagesDir string
+	DomainDir   string
+	Ref         string
+	Output      string
+	Diff        bool
+	// VersionFormat tells how the base version is read from propagated versions.
+	VersionFormat sync.VersionFormat
+
+	warnings warning.List
+	result   *ManifestResult
+}
+
+// Result returns the structured result for JSON output, with warnings of the run.
+func (r *ReleaseManifest) Result() any {
+	if r.result != nil {
+		r.result.Warnings = r.warnings
+	}
+	return r.result
+}
+
+// Execute runs the release-manifest action
+func (r *ReleaseManifest) Execute() error {
+	if r.Diff {
+		if len(r.Manifests) != 2 {
+			return fmt.Errorf("usage: component:release-manifest --diff OLD NEW")
+		}
+		return r.executeDiff(r.Manifests[0], r.Manifests[1])
+	}
+
+	if len(r.Manifests) > 0 {
+		return fmt.Errorf("manifest files are only accepted with --diff")
+	}
+
+	m, err := r.build()
+	if err != nil {
+		return err
+	}
+
+	r.result = &ManifestResult{Manifest: m, Output: r.Output}
+	if r.Output != "" {
+		if err = release.Save(r.Output, m); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range m.Components {
+		r.Term().Printfln("%s %s (%s)", component.FormatDisplayName(c.Name, c.Version), strings.Join(c.Chassis, ", "), c.Package)
+	}
+
+	r.Term().Success().Printfln("Manifest of %d component(s) for %s (%s), checksum %s", len(m.Components), m.Ref, m.Commit, m.Checksum)
+	return nil
+}
+
+// build assembles manifest of the components attached in the build directory.
+func (r *ReleaseManifest) build() (*release.Manifest, error) {
+	repo, err := git.PlainOpenWithOptions(r.DomainDir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("%s - %w", r.DomainDir, err)
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(r.Ref))
+	if err != nil {
+		return nil, fmt.Errorf("can't resolve ref %s > %w", r.Ref, err)
+	}
+
+	refCommit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("can't get commit object %s > %w", hash, err)
+	}
+
+	// Components are read from the build, composed from the worktree, which must be a clean checkout of the ref.
+	if err = checkCheckout(repo, refCommit, r.Ref); err != nil {
+		return nil, err
+	}
+
+	// Build directory is the src directory of composed platform.
+	attachments, err := component.LoadAttachments(filepath.Dir(r.BuildDir), "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attachments: %w", err)
+	}
+
+	components, err := component.LoadFromPath(r.BuildDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load components: %w", err)
+	}
+
+	packages, err := r.packages()
+	if err != nil {
+		return nil, err
+	}
+
+	chassis := make(map[string][]string)
+	for _, a := range attachments {
+		if !slices.Contains(chassis[a.Component], a.Chassis) {
+			chassis[a.Component] = append(chassis[a.Component], a.Chassis)
+		}
+	}
+
+	repos := make(map[string]*git.Repository)
+	m := &release.Manifest{Ref: r.Ref, Commit: refCommit.Hash.String(), Date: refCommit.Author.When.UTC()}
+	for name, paths := range chassis {
+		sort.Strings(paths)
+		c := release.Component{Name: name, Chassis: paths}
+		if bc := components.Find(name); bc != nil {
+			c.Version = bc.Version
+		} else {
+			r.Term().Warning().Println(r.warnings.Add(warning.NotFound, name, "Component %s is attached but not found in build", name))
+		}
+
+		var pkgPath string
+		c.Package, pkgPath = findPackage(name, packages)
+		if c.Package != "" && c.Version != "" {
+			r.resolveCommit(&c, pkgPath, repos)
+		}
+
+		m.Components = append(m.Components, c)
+	}
+
+	sort.Slice(m.Components, func(i, j int) bool {
+		return m.Components[i].Name < m.Components[j].Name
+	})
+
+	m.Checksum, err = release.Checksum(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// checkCheckout ensures HEAD is the commit of the ref and tracked files aren't changed, so the build matches the ref.
+func checkCheckout(repo *git.Repository, commit *object.Commit, ref string) error {
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("can't resolve HEAD > %w", err)
+	}
+	if head.Hash() != commit.Hash {
+		return fmt.Errorf("ref %s (%s) isn't checked out, HEAD is %s: checkout the ref and compose the build again",
+			ref, commit.Hash.String()[:7], head.Hash().String()[:7])
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return fmt.Errorf("failed to read worktree status > %w", err)
+	}
+
+	var changed []string
+	for path, st := range status {
+		if st.Worktree != git.Untracked && (st.Worktree != git.Unmodified || st.Staging != git.Unmodified) {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		return fmt.Errorf("%d uncommitted change(s) aren't part of ref %s: %s", len(changed), ref, strings.Join(changed, ", "))
+	}
+
+	return nil
+}
+
+type packagePath struct {
+	name string
+	path string
+}
+
+// packages returns domain and composed packages paths, from the highest priority to the lowest.
+func (r *ReleaseManifest) packages() ([]packagePath, error) {
+	plasmaCompose, err := compose.Lookup(os.DirFS(r.DomainDir))
+	if err != nil {
+		return nil, err
+	}
+
+	result := []packagePath{{name: domainPackage, path: r.DomainDir}}
+	for i := len(plasmaCompose.Dependencies) - 1; i >= 0; i-- {
+		dep := plasmaCompose.Dependencies[i]
+		pkg := dep.ToPackage(dep.Name)
+		result = append(result, packagePath{name: dep.Name, path: filepath.Join(r.PackagesDir, pkg.GetName(), pkg.GetTarget())})
+	}
+
+	return result, nil
+}
+
+// findPackage returns the package providing component to the build.
+func findPackage(name string, packages []packagePath) (string, string) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return "", ""
+	}
+
+	for _, p := range packages {
+		for _, dir := range []string{
+			filepath.Join(p.path, parts[0], parts[1], parts[2]),
+			filepath.Join(p.path, parts[0], parts[1], "roles", parts[2]),
+		} {
+			if _, err := os.Stat(filepath.Join(dir, "meta", "plasma.yaml")); err == nil {
+				return p.name, p.path
+			}
+		}
+	}
+
+	return "", ""
+}
+
+// resolveCommit sets commit and date of the component version from the package repository.
+func (r *ReleaseManifest) resolveCommit(c *release.Component, path string, repos map[string]*git.Repository) {
+	repo, ok := repos[path]
+	if !ok {
+		var err error
+		repo, err = git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			r.Log().Debug("package is not a git repository", "path", path, "error", err)
+		}
+		repos[path] = repo
+	}
+
+	if repo == nil {
+		return
+	}
+
+	// Base of propagated versions is the component own commit.
+	base, _ := r.VersionFormat.Split(c.Version)
+	hash, err := repo.ResolveRevision(plumbing.Revision(base))
+	if err != nil {
+		r.Log().Debug("can't resolve component version", "component", c.Name, "version", c.Version, "error", err)
+		return
+	}
+
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return
+	}
+
+	date := commit.Author.When.UTC()
+	c.Commit = commit.Hash.String()
+	c.Date = &date
+}
+
+// executeDiff compares two manifest files after checksum verification.
+func (r *ReleaseManifest) executeDiff(oldPath, newPath string) error {
+	oldManifest, err := release.Load(oldPath)
+	if err != nil {
+		return err
+	}
+
+	newManifest, err := release.Load(newPath)
+	if err != nil {
+		return err
+	}
+
+	d := diff(oldManifest, newManifest)
+	r.result = &ManifestResult{Diff: d}
+
+	if len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 {
+		r.Term().Success().Println("Manifests are identical")
+		return nil
+	}
+
+	for _, c := range d.Added {
+		r.Term().Printfln("+ %s", component.FormatDisplayName(c.Name, c.Version))
+	}
+	for _, c := range d.Removed {
+		r.Term().Printfln("- %s", component.FormatDisplayName(c.Name, c.Version))
+	}
+	for _, c := range d.Changed {
+		r.Term().Printfln("~ %s: %s -> %s", c.Name, component.FormatVersion(c.OldVersion), component.FormatVersion(c.NewVersion))
+		if c.OldChassis != nil || c.NewChassis != nil {
+			r.Term().Printfln("    chassis: %s -> %s", strings.Join(c.OldChassis, ", "), strings.Join(c.NewChassis, ", "))
+		}
+	}
+
+	return nil
+}
+
+func diff(oldManifest, newManifest *release.Manifest) *Diff {
+	d := &Diff{}
+	oldComponents := make(map[string]release.Component, len(oldManifest.Components))
+	for _, c := range oldManifest.Components {
+		oldComponents[c.Name] = c
+	}
+
+	newComponents := make(map[string]bool, len(newManifest.Components))
+	for _, c := range newManifest.Components {
+		newComponents[c.Name] = true
+		prev, ok := oldComponents[c.Name]
+		if !ok {
+			d.Added = append(d.Added, c)
+			continue
+		}
+
+		sameChassis := strings.Join(prev.Chassis, ",") == strings.Join(c.Chassis, ",")
+		if prev.Version == c.Version && sameChassis {
+			continue
+		}
+
+		changed := ChangedComponent{Name: c.Name, OldVersion: prev.Version, NewVersion: c.Version}
+		if !sameChassis {
+			changed.OldChassis = prev.Chassis
+			changed.NewChassis = c.Chassis
+		}
+		d.Changed = append(d.Changed, changed)
+	}
+
+	for _, c := range oldManifest.Components {
+		if !newComponents[c.Name] {
+			d.Removed = append(d.Removed, c)
+		}
+	}
+
+	return d
+}
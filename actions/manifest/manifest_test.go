@@ -0,0 +1,49 @@
+package manifest_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/plasmash/plasmactl-model/pkg/model"
+
+	"github.com/plasmash/plasmactl-component/actions/manifest"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+)
+
+func TestReleaseManifest(t *testing.T) {
+	p := testenv.NewPlatform(t)
+	p.WriteFile("plasma-compose.yaml", "name: platform\n")
+	p.WriteFile(".gitignore", ".plasma/\n")
+	p.WriteFile(filepath.Join("interaction", "interaction.yaml"), "- hosts: "+testenv.Chassis+"\n  roles:\n    - "+testenv.Dashboards+"\n")
+	initial := p.Commit("attach dashboards", testenv.DeveloperName)
+	p.Compose()
+
+	build := func(ref string) (*manifest.ReleaseManifest, error) {
+		rm := &manifest.ReleaseManifest{BuildDir: model.MergedSrcDir, PackagesDir: model.PackagesDir, DomainDir: ".", Ref: ref}
+		return rm, testenv.Run(t, rm)
+	}
+
+	rm, err := build("HEAD")
+	if err != nil {
+		t.Fatalf("manifest: %v", err)
+	}
+	m := rm.Result().(*manifest.ManifestResult).Manifest
+	if m.Commit != initial || len(m.Components) != 1 || m.Components[0].Name != testenv.Dashboards || m.Components[0].Version != "aaa1111111111" {
+		t.Errorf("expected manifest of attached dashboards at %s, got %+v", initial, m)
+	}
+
+	p.SetVersion(testenv.Dashboards, "bbb2222222222")
+	if _, err = build("HEAD"); err == nil || !strings.Contains(err.Error(), "uncommitted change") {
+		t.Errorf("expected uncommitted changes to be refused, got %v", err)
+	}
+
+	p.Commit("change dashboards", testenv.DeveloperName)
+	p.Compose()
+	if _, err = build(initial); err == nil || !strings.Contains(err.Error(), "isn't checked out") {
+		t.Errorf("expected ref other than HEAD to be refused, got %v", err)
+	}
+	if rm, err = build("HEAD"); err != nil || rm.Result().(*manifest.ManifestResult).Manifest.Components[0].Version != "bbb2222222222" {
+		t.Errorf("expected manifest of the new version, got %v", err)
+	}
+}
@@ -0,0 +1,136 @@
+package query
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+	"github.com/plasmash/plasmactl-platform/pkg/graph"
+)
+
+// ChassisUsage is the utilization of a chassis path.
+type ChassisUsage struct {
+	Chassis    string         `json:"chassis"`
+	Components map[string]int `json:"components,omitempty"` // Count of attached components by kind
+	Nodes      []string       `json:"nodes,omitempty"`      // Nodes allocated to the chassis path
+	Unattached []string       `json:"unattached,omitempty"` // Descendants without attached components
+}
+
+// chassisReport reports components by kind and allocated nodes of every chassis path, with descendants having
+// no component attached. Paths are taken from chassis.yaml, or from attachments and allocations if it's missing.
+// The report is scoped to the identifier subtree, if given. Without graph, nodes are unknown.
+func (q *Query) chassisReport(g *graph.PlatformGraph) error {
+	attached := make(map[string]map[string]int)
+	nodes := make(map[string][]string)
+	paths := make(map[string]bool)
+
+	if g != nil {
+		for _, n := range g.NodesByType("component") {
+			for _, e := range g.EdgesTo(n.Name, "distributes") {
+				addKind(attached, e.From().Name, n.Kind)
+			}
+		}
+		for _, n := range g.NodesByType("node") {
+			for _, e := range g.EdgesFrom(n.Name, "allocates") {
+				nodes[e.To().Name] = append(nodes[e.To().Name], n.Name)
+				paths[e.To().Name] = true
+			}
+		}
+	} else {
+		ctx := q.Context
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		components, err := component.LoadAttached(ctx, ".", component.LoadOptions{Progress: q.Progress})
+		if err != nil {
+			return fmt.Errorf("failed to load components: %w", err)
+		}
+		for _, c := range components {
+			if c.Chassis != "" {
+				addKind(attached, c.Chassis, c.Kind)
+			}
+		}
+	}
+
+	for p := range attached {
+		paths[p] = true
+	}
+
+	if c, err := chassis.Load("."); err == nil {
+		for _, p := range c.Flatten() {
+			paths[p] = true
+		}
+	} else {
+		q.Log().Debug("chassis model is unavailable, reporting known chassis paths only", "error", err)
+	}
+
+	var sorted []string
+	for p := range paths {
+		if q.Identifier == "" || component.MatchesChassis(p, q.Identifier, true) {
+			sorted = append(sorted, p)
+		}
+	}
+	sort.Strings(sorted)
+
+	q.result.Components = []ComponentMatch{}
+	for _, p := range sorted {
+		usage := ChassisUsage{Chassis: p, Components: attached[p], Nodes: nodes[p]}
+		sort.Strings(usage.Nodes)
+		for _, d := range sorted {
+			if chassis.IsDescendantOf(d, p) && len(attached[d]) == 0 {
+				usage.Unattached = append(usage.Unattached, d)
+			}
+		}
+
+		q.result.Chassis = append(q.result.Chassis, usage)
+	}
+
+	if len(q.result.Chassis) == 0 {
+		q.Term().Warning().Println(q.result.Warnings.Add(warning.Empty, "", "No chassis paths found"))
+		return nil
+	}
+
+	for _, usage := range q.result.Chassis {
+		q.Term().Printfln("%s\t%s\t%s", usage.Chassis, formatKinds(usage.Components), orDash(strings.Join(usage.Nodes, ", ")))
+		if len(usage.Unattached) > 0 {
+			q.Term().Printfln("  unattached: %s", strings.Join(usage.Unattached, ", "))
+		}
+	}
+
+	return nil
+}
+
+func addKind(attached map[string]map[string]int, chassisPath, kind string) {
+	if attached[chassisPath] == nil {
+		attached[chassisPath] = make(map[string]int)
+	}
+	attached[chassisPath][kind]++
+}
+
+// formatKinds formats component counts by kind (e.g. "applications: 2, services: 1").
+func formatKinds(kinds map[string]int) string {
+	names := make([]string, 0, len(kinds))
+	for k := range kinds {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, k := range names {
+		parts = append(parts, fmt.Sprintf("%s: %d", k, kinds[k]))
+	}
+
+	return orDash(strings.Join(parts, ", "))
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -1,12 +1,15 @@
 package query
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"sort"
-	"strings"
 
 	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
 	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 	"github.com/plasmash/plasmactl-platform/pkg/graph"
 )
 
@@ -16,11 +19,15 @@ type ComponentMatch struct {
 	Version string `json:"version"`
 	Kind    string `json:"kind"`
 	Chassis string `json:"chassis"`
+	// Location is the source directory of the component, with --resolve-paths.
+	Location *component.Location `json:"location,omitempty"`
 }
 
 // QueryResult is the structured output for component:query
 type QueryResult struct {
 	Components []ComponentMatch `json:"components"`
+	Chassis    []ChassisUsage   `json:"chassis,omitempty"`
+	Warnings   warning.List     `json:"warnings,omitempty"`
 }
 
 // Query implements the component:query command
@@ -30,15 +37,38 @@ type Query struct {
 
 	Identifier string
 	Kind       string // "chassis" or "node" to skip auto-detection
+	NoInherit  bool   // match exact chassis paths only, not their descendants
+	Report     bool   // report utilization of chassis paths instead of matching components
+	// ResolvePaths adds the source directory of matched components to the result.
+	ResolvePaths bool
+
+	// Context and Progress are used when components are loaded from filesystem.
+	Context  context.Context
+	Progress component.Progress
 
 	result QueryResult
 }
 
 // Execute runs the query action
 func (q *Query) Execute() error {
+	if q.Identifier == "" && !q.Report {
+		return errors.New("identifier is required, unless --chassis-report is set")
+	}
+
 	g, err := graph.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load graph: %w", err)
+		q.Log().Warn(q.result.Warnings.Add(warning.Fallback, "", "platform graph is unavailable, loading components from filesystem"), "error", err)
+		if q.Report {
+			return q.chassisReport(nil)
+		}
+		return q.queryFromFilesystem()
+	}
+	if msg := graphstate.Check("."); msg != "" {
+		q.Term().Warning().Println(q.result.Warnings.Add(warning.StaleGraph, "", "%s", msg))
+	}
+
+	if q.Report {
+		return q.chassisReport(g)
 	}
 
 	var matches []componentMatch
@@ -51,7 +81,7 @@ func (q *Query) Execute() error {
 		for _, n := range g.NodesByType("component") {
 			for _, e := range g.EdgesTo(n.Name, "distributes") {
 				chassis := e.From().Name
-				if chassis == q.Identifier || strings.HasPrefix(chassis, q.Identifier+".") {
+				if component.MatchesChassis(chassis, q.Identifier, !q.NoInherit) {
 					matches = append(matches, componentMatch{
 						name:    n.Name,
 						version: n.Version,
@@ -76,7 +106,7 @@ func (q *Query) Execute() error {
 			// Find components attached to those chassis paths (attaches: chassis → component)
 			for _, n := range g.NodesByType("component") {
 				for _, e := range g.EdgesTo(n.Name, "distributes") {
-					if chassisSet[e.From().Name] {
+					if q.servedBy(e.From().Name, chassisSet) {
 						matches = append(matches, componentMatch{
 							name:    n.Name,
 							version: n.Version,
@@ -89,8 +119,45 @@ func (q *Query) Execute() error {
 		}
 	}
 
+	return q.print(matches)
+}
+
+// queryFromFilesystem queries components of the composed output by chassis path of their playbook attachments.
+// Node allocations are only known by the platform graph.
+func (q *Query) queryFromFilesystem() error {
+	if q.Kind == "node" {
+		return errors.New("node queries require the platform graph")
+	}
+
+	ctx := q.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	components, err := component.LoadAttached(ctx, ".", component.LoadOptions{Progress: q.Progress})
+	if err != nil {
+		return fmt.Errorf("failed to load components: %w", err)
+	}
+
+	var matches []componentMatch
+	for _, c := range components {
+		if c.Chassis != "" && component.MatchesChassis(c.Chassis, q.Identifier, !q.NoInherit) {
+			matches = append(matches, componentMatch{
+				name:    c.Name,
+				version: c.Version,
+				kind:    c.Kind,
+				chassis: c.Chassis,
+			})
+		}
+	}
+
+	return q.print(matches)
+}
+
+// print sorts, stores and prints matched components
+func (q *Query) print(matches []componentMatch) error {
 	if len(matches) == 0 {
-		q.Term().Warning().Printfln("No components found for %q", q.Identifier)
+		q.Term().Warning().Println(q.result.Warnings.Add(warning.Empty, q.Identifier, "No components found for %q", q.Identifier))
 		return nil
 	}
 
@@ -102,14 +169,26 @@ func (q *Query) Execute() error {
 		return matches[i].name < matches[j].name
 	})
 
+	var locator *component.Locator
+	if q.ResolvePaths {
+		var err error
+		if locator, err = component.NewLocator("."); err != nil {
+			return err
+		}
+	}
+
 	// Build result
 	for _, m := range matches {
-		q.result.Components = append(q.result.Components, ComponentMatch{
+		match := ComponentMatch{
 			Name:    m.name,
 			Version: m.version,
 			Kind:    m.kind,
 			Chassis: m.chassis,
-		})
+		}
+		if locator != nil {
+			match.Location = locator.Locate(m.name)
+		}
+		q.result.Components = append(q.result.Components, match)
 	}
 
 	// Output
@@ -120,6 +199,16 @@ func (q *Query) Execute() error {
 	return nil
 }
 
+// servedBy reports whether the chassis path is one of the allocated paths, or inherits from one of them.
+func (q *Query) servedBy(chassis string, allocated map[string]bool) bool {
+	for a := range allocated {
+		if component.MatchesChassis(chassis, a, !q.NoInherit) {
+			return true
+		}
+	}
+	return false
+}
+
 // Result returns the structured result for JSON output
 func (q *Query) Result() any {
 	return q.result
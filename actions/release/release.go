@@ -0,0 +1,257 @@
+package release
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/launchrctl/keyring"
+	"github.com/launchrctl/launchr"
+	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/pterm/pterm"
+
+	"github.com/plasmash/plasmactl-component/actions/bump"
+	syncaction "github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/internal/freeze"
+	"github.com/plasmash/plasmactl-component/internal/notify"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// ReleaseResult is the structured result of component:release.
+type ReleaseResult struct {
+	Bumped []bump.BumpedComponent       `json:"bumped"`
+	Synced []syncaction.SyncedComponent `json:"synced"`
+	// Warnings lists the warnings of the release, including the ones of bump and sync.
+	Warnings warning.List `json:"warnings,omitempty"`
+	DryRun   bool         `json:"dry_run"`
+}
+
+// Changes returns bumped and propagated components as changes to notify their owners of.
+func (r *ReleaseResult) Changes() []notify.Change {
+	bumped := &bump.BumpResult{Components: r.Bumped}
+	synced := &syncaction.SyncResult{Components: r.Synced}
+	return append(bumped.Changes(), synced.Changes()...)
+}
+
+// Release implements component:release command: bump followed by sync propagation.
+type Release struct {
+	action.WithLogger
+	action.WithTerm
+
+	// services.
+	Keyring keyring.Keyring
+	Streams launchr.Streams
+
+	// target dirs.
+	BuildDir    string
+	PackagesDir string
+	DomainDir   string
+	Domains     []syncaction.Domain
+
+	// options.
+	Last                   bool
+	DryRun                 bool
+	Yes                    bool
+	AllowOverride          bool
+	SkipMissingPackages    bool
+	SkipBuildCheck         bool
+	SkipConstraints        bool
+	Unshallow              bool
+	VersionFormat          sync.VersionFormat
+	BumpAuthors            []string
+	SignKey                string
+	BumpCommit             repository.BumpCommit
+	Hooks                  bump.Hooks
+	FreezeWindows          freeze.Windows
+	FilterByComponentUsage bool
+	TimeDepth              string
+	VaultPass              string
+	VaultPassProvider      vaultpass.Provider
+	ShowProgress           bool
+	// ExtraKinds are component kinds registered by plugins, released as the built-in ones.
+	ExtraKinds []string
+
+	result *ReleaseResult
+}
+
+// Result returns the structured result for JSON output.
+func (r *Release) Result() any {
+	return r.result
+}
+
+// Execute plans bump and propagation, asks for confirmation, then bumps, commits and propagates.
+func (r *Release) Execute() error {
+	r.result = &ReleaseResult{DryRun: r.DryRun}
+
+	b := &bump.Bump{
+		Streams:       r.Streams,
+		Last:          r.Last,
+		DryRun:        true,
+		VersionFormat: r.VersionFormat,
+		SignKey:       r.SignKey,
+		CommitMessage: r.BumpCommit,
+		Hooks:         r.Hooks,
+		ExtraKinds:    r.ExtraKinds,
+	}
+	b.SetLogger(r.Log())
+	b.SetTerm(r.Term())
+
+	components, err := b.Collect()
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, group := range components {
+		for name := range group {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		r.Term().Info().Println("No component to release")
+		return nil
+	}
+
+	// Plan: versions to bump and estimated propagation of bumped components.
+	if err = b.Update(components); err != nil {
+		return err
+	}
+	bumped := b.Result().(*bump.BumpResult)
+	r.result.Bumped = bumped.Components
+	r.result.Warnings = append(r.result.Warnings, bumped.Warnings...)
+
+	s := r.newSync()
+	s.Simulate = r.inBuild(names)
+	if len(s.Simulate) > 0 {
+		if err = s.Execute(); err != nil {
+			return fmt.Errorf("planning propagation > %w", err)
+		}
+	}
+
+	if r.DryRun {
+		if err = b.CommitWithHooks(); err != nil {
+			return err
+		}
+		if res, ok := s.Result().(*syncaction.SyncResult); ok && res != nil {
+			r.result.Synced = res.Components
+			r.result.Warnings = append(r.result.Warnings, res.Warnings...)
+		}
+		return nil
+	}
+
+	if !r.Yes {
+		confirmed, errConfirm := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).Show("Proceed with the release?")
+		if errConfirm != nil {
+			return errConfirm
+		}
+		if !confirmed {
+			return errors.New("release aborted")
+		}
+	}
+
+	b.DryRun = false
+	if err = b.Update(components); err != nil {
+		return err
+	}
+
+	// Bumped versions are written to the build as well, so sync sees them without composing again.
+	if err = r.updateBuild(components); err != nil {
+		return err
+	}
+
+	if err = b.CommitWithHooks(); err != nil {
+		return err
+	}
+
+	s.Simulate = nil
+	s.DryRun = false
+	if err = s.Execute(); err != nil {
+		return err
+	}
+
+	synced := s.Result().(*syncaction.SyncResult)
+	r.result.Synced = synced.Components
+	r.result.Warnings = append(r.result.Warnings, synced.Warnings...)
+	r.Term().Success().Printfln("Released %d component(s), propagated to %d component(s)", len(r.result.Bumped), len(r.result.Synced))
+	return nil
+}
+
+// newSync returns sync action sharing release services and options.
+// The same action is executed for planning and propagation, so the build inventory is loaded once.
+func (r *Release) newSync() *syncaction.Sync {
+	s := &syncaction.Sync{
+		Keyring: r.Keyring,
+		Streams: r.Streams,
+
+		DomainDir:   r.DomainDir,
+		Domains:     r.Domains,
+		BuildDir:    r.BuildDir,
+		PackagesDir: r.PackagesDir,
+
+		FilterByComponentUsage: r.FilterByComponentUsage,
+		TimeDepth:              r.TimeDepth,
+		AllowOverride:          r.AllowOverride,
+		SkipMissingPackages:    r.SkipMissingPackages,
+		SkipBuildCheck:         r.SkipBuildCheck,
+		SkipConstraints:        r.SkipConstraints,
+		Unshallow:              r.Unshallow,
+		VersionFormat:          r.VersionFormat,
+		BumpAuthors:            r.BumpAuthors,
+		FreezeWindows:          r.FreezeWindows,
+		VaultPass:              r.VaultPass,
+		VaultPassProvider:      r.VaultPassProvider,
+		ShowProgress:           r.ShowProgress,
+		ExtraKinds:             r.ExtraKinds,
+	}
+	s.SetLogger(r.Log())
+	s.SetTerm(r.Term())
+
+	return s
+}
+
+// inBuild filters components present in the build directory.
+func (r *Release) inBuild(names []string) []string {
+	var result []string
+	for _, name := range names {
+		c, err := sync.NewComponent(name, r.BuildDir)
+		if err != nil || !c.IsValidComponent() {
+			r.Term().Warning().Println(r.result.Warnings.Add(warning.NotFound, name, "Component %s is not in build, compose to include it into propagation", name))
+			continue
+		}
+		result = append(result, name)
+	}
+
+	sort.Strings(result)
+	return result
+}
+
+// updateBuild sets bumped versions to the components of the build directory.
+func (r *Release) updateBuild(components map[string]map[string]*sync.Component) error {
+	for version, group := range components {
+		for name := range group {
+			c, err := sync.NewComponent(name, r.BuildDir)
+			if err != nil {
+				return err
+			}
+
+			if !c.IsValidComponent() {
+				r.Log().Debug("component is not in build, skipping", "component", name)
+				continue
+			}
+
+			debug, err := c.UpdateVersion(version)
+			for _, d := range debug {
+				r.Log().Debug("error", "message", d)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
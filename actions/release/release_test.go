@@ -0,0 +1,78 @@
+package release_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/plasmash/plasmactl-component/actions/bump"
+	"github.com/plasmash/plasmactl-component/actions/release"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+)
+
+func TestRelease(t *testing.T) {
+	p := testenv.NewPlatform(t)
+	p.WriteFile("plasma-compose.yaml", "name: platform\n")
+	initial := p.Commit("add compose", testenv.DeveloperName)[:13]
+	for _, name := range []string{testenv.Postgres, testenv.Auth, testenv.Dashboards} {
+		p.SetVersion(name, initial)
+	}
+	p.Commit("versions bump", repository.Author)
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	change := p.Commit("change postgres", testenv.DeveloperName)[:13]
+	buildDir := p.Compose()
+	post := filepath.Join(t.TempDir(), "post.txt")
+	hooks := bump.Hooks{
+		Pre:  []bump.Hook{{Run: `echo '{{ join .Names " " }}' > BUMPED`, Add: []string{"BUMPED"}}},
+		Post: []bump.Hook{{Run: "echo {{ .Commit }} > " + post}},
+	}
+
+	execute := func(dryRun bool) (*release.ReleaseResult, error) {
+		r := &release.Release{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, DryRun: dryRun, Yes: true, Hooks: hooks}
+		err := testenv.Run(t, r)
+		res, _ := r.Result().(*release.ReleaseResult)
+		return res, err
+	}
+
+	res, err := execute(true)
+	if err != nil {
+		t.Fatalf("dry-run release: %v", err)
+	}
+	if len(res.Bumped) != 1 || res.Bumped[0].Name != testenv.Postgres || len(res.Synced) != 2 {
+		t.Errorf("expected %s bump planned with propagation to 2 components, got %+v", testenv.Postgres, res)
+	}
+	if head := p.HeadCommit(); head.Author.Name != testenv.DeveloperName {
+		t.Errorf("expected no bump commit on dry-run, got %q", head.Message)
+	}
+	if v := testenv.BuildVersions(t, buildDir, testenv.Postgres, testenv.Auth, testenv.Dashboards); !slices.Equal(v, []string{initial, initial, initial}) {
+		t.Errorf("expected build untouched by dry-run, got %v", v)
+	}
+	if _, err = os.Stat(post); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected hooks only printed on dry-run, got %v", err)
+	}
+
+	if res, err = execute(false); err != nil {
+		t.Fatalf("release: %v", err)
+	}
+	head := p.HeadCommit()
+	if head.Author.Name != repository.Author {
+		t.Errorf("expected bump commit, got %q by %s", head.Message, head.Author.Name)
+	}
+	if _, err = head.File("BUMPED"); err != nil {
+		t.Errorf("expected pre hook output in the bump commit: %v", err)
+	}
+	if data, errRead := os.ReadFile(post); errRead != nil || strings.TrimSpace(string(data)) != head.Hash.String() {
+		t.Errorf("expected bump commit %s in post hook, got %q (%v)", head.Hash, data, errRead)
+	}
+	versions := testenv.BuildVersions(t, buildDir, testenv.Postgres, testenv.Auth, testenv.Dashboards)
+	if versions[0] != change || versions[1] != initial+"-"+change || versions[2] != initial+"-"+change {
+		t.Errorf("expected %s bumped to %s and propagated in build, got %v", testenv.Postgres, change, versions)
+	}
+	if len(res.Synced) != 2 {
+		t.Errorf("expected propagation to 2 components, got %+v", res.Synced)
+	}
+}
@@ -0,0 +1,180 @@
+package setversion
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// VersionChange is a component which version is set.
+type VersionChange struct {
+	Name       string `json:"name"`
+	File       string `json:"file"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// SetVersionResult is the structured result of component:set-version.
+type SetVersionResult struct {
+	Version    string          `json:"version"`
+	DryRun     bool            `json:"dry_run"`
+	Components []VersionChange `json:"components"`
+	// Unchanged lists selected components which already have the version.
+	Unchanged []string `json:"unchanged,omitempty"`
+}
+
+// SetVersion implements component:set-version command
+type SetVersion struct {
+	action.WithLogger
+	action.WithTerm
+
+	Version string
+
+	// Selection, components matching all given selectors are updated
+	Components []string
+	Kind       string
+	Layer      string
+
+	Source        string
+	DryRun        bool
+	VersionFormat sync.VersionFormat
+
+	result *SetVersionResult
+}
+
+// Result returns the structured result for JSON output.
+func (s *SetVersion) Result() any {
+	return s.result
+}
+
+// Execute runs the set-version action
+func (s *SetVersion) Execute() error {
+	if _, reason := s.VersionFormat.Normalize(s.Version); reason != "" {
+		return fmt.Errorf("version %q %s", s.Version, reason)
+	}
+
+	selected, err := s.selectComponents()
+	if err != nil {
+		return err
+	}
+
+	s.result = &SetVersionResult{Version: s.Version, DryRun: s.DryRun, Components: []VersionChange{}}
+	for _, c := range selected {
+		if c.Version == s.Version {
+			s.result.Unchanged = append(s.result.Unchanged, c.Name)
+			continue
+		}
+
+		file, err := s.metaPath(c.Name)
+		if err != nil {
+			return err
+		}
+
+		if !s.DryRun {
+			if err = setMetaVersion(filepath.Join(s.Source, file), s.Version); err != nil {
+				return fmt.Errorf("failed to set version of %s > %w", c.Name, err)
+			}
+		}
+
+		s.result.Components = append(s.result.Components, VersionChange{Name: c.Name, File: file, OldVersion: c.Version, NewVersion: s.Version})
+		s.Term().Printfln("- %s: %s -> %s", c.Name, component.FormatVersion(c.Version), s.Version)
+	}
+
+	for _, name := range s.result.Unchanged {
+		s.Term().Info().Printfln("- skip %s (already %s)", name, s.Version)
+	}
+
+	switch {
+	case len(s.result.Components) == 0:
+		s.Term().Printfln("No version change")
+	case s.DryRun:
+		s.Term().Info().Printfln("Dry-run: %d component(s) would be updated", len(s.result.Components))
+	default:
+		s.Term().Success().Printfln("Set version of %d component(s)", len(s.result.Components))
+	}
+
+	return nil
+}
+
+// selectComponents returns source components matching the selectors, sorted by name.
+func (s *SetVersion) selectComponents() (component.Components, error) {
+	if len(s.Components) == 0 && s.Kind == "" && s.Layer == "" {
+		return nil, errors.New("select components with --components, --kind or --layer")
+	}
+
+	components, err := component.LoadFromPath(s.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Components) > 0 {
+		var unknown []string
+		names := make(map[string]bool, len(s.Components))
+		for _, name := range s.Components {
+			if components.Find(name) == nil {
+				unknown = append(unknown, name)
+			}
+			names[name] = true
+		}
+		if len(unknown) > 0 {
+			return nil, fmt.Errorf("unknown component(s): %s", strings.Join(unknown, ", "))
+		}
+
+		components = components.Filter(func(c component.Component) bool { return names[c.Name] })
+	}
+	if s.Kind != "" {
+		components = components.ByKind(s.Kind)
+	}
+	if s.Layer != "" {
+		components = components.ByLayer(s.Layer)
+	}
+
+	if len(components) == 0 {
+		return nil, errors.New("no component matches the selection")
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i].Name < components[j].Name })
+	return components, nil
+}
+
+// metaPath returns meta file of the component relative to the source, in the root or roles layout.
+func (s *SetVersion) metaPath(name string) (string, error) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid component MRN %q", name)
+	}
+
+	for _, dir := range []string{
+		filepath.Join(parts[0], parts[1], parts[2]),
+		filepath.Join(parts[0], parts[1], "roles", parts[2]),
+	} {
+		file := filepath.Join(dir, "meta", "plasma.yaml")
+		if _, err := os.Stat(filepath.Join(s.Source, file)); err == nil {
+			return file, nil
+		}
+	}
+
+	return "", fmt.Errorf("meta file of %s not found", name)
+}
+
+func setMetaVersion(path, version string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	data, err = sync.SetMetaVersion(data, version)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
@@ -1,11 +1,17 @@
 package show
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"sort"
 
 	"github.com/launchrctl/launchr/pkg/action"
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
+	"github.com/plasmash/plasmactl-component/internal/repository"
 	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 	"github.com/plasmash/plasmactl-platform/pkg/graph"
 )
 
@@ -18,6 +24,8 @@ type ComponentInfo struct {
 	Package     string   `json:"package,omitempty"`
 	Attachment  string   `json:"attachment,omitempty"`
 	Allocations []string `json:"allocations,omitempty"`
+	// Location is the source directory of the component, with --resolve-paths.
+	Location *component.Location `json:"location,omitempty"`
 }
 
 // OverviewResult is the structured output for component:show (no args)
@@ -32,6 +40,7 @@ type OverviewResult struct {
 type ShowResult struct {
 	Component *ComponentInfo  `json:"component,omitempty"`
 	Overview  *OverviewResult `json:"overview,omitempty"`
+	Warnings  warning.List    `json:"warnings,omitempty"`
 }
 
 // Show implements the component:show command
@@ -40,12 +49,26 @@ type Show struct {
 	action.WithTerm
 
 	Component string
+	// DomainDir is the platform root components are loaded from.
+	DomainDir string
+	// Ref shows domain components of a commit, tag or branch, read from the git objects without checkout.
+	Ref string
+	// ResolvePaths adds the source directory of the component to the result.
+	ResolvePaths bool
 
-	result *ShowResult
+	// Context and Progress are used when components are loaded from filesystem.
+	Context  context.Context
+	Progress component.Progress
+
+	warnings warning.List
+	result   *ShowResult
 }
 
-// Result returns the structured result for JSON output
+// Result returns the structured result for JSON output, with warnings of the run.
 func (s *Show) Result() any {
+	if s.result != nil {
+		s.result.Warnings = s.warnings
+	}
 	return s.result
 }
 
@@ -55,11 +78,19 @@ func (s *Show) Execute() error {
 	if s.Component == "" {
 		return s.showOverview()
 	}
+	if s.Ref != "" {
+		if s.ResolvePaths {
+			return errors.New("--ref can't be combined with --resolve-paths")
+		}
+		return s.showFromFilesystem()
+	}
 
 	g, err := graph.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load graph: %w", err)
+		s.Log().Warn(s.warnings.Add(warning.Fallback, "", "platform graph is unavailable, loading components from filesystem"), "error", err)
+		return s.showFromFilesystem()
 	}
+	s.warnStaleGraph()
 
 	n := g.Node(s.Component)
 	if n == nil || n.Type != "component" {
@@ -106,18 +137,21 @@ func (s *Show) Execute() error {
 		},
 	}
 
-	// Print human-readable output
-	s.printComponent(s.result.Component)
-
-	return nil
+	return s.printComponent(s.result.Component)
 }
 
 // showOverview displays component statistics grouped by layer and kind
 func (s *Show) showOverview() error {
+	if s.Ref != "" {
+		return s.showOverviewFromFilesystem()
+	}
+
 	g, err := graph.Load()
 	if err != nil {
-		return fmt.Errorf("failed to load graph: %w", err)
+		s.Log().Warn(s.warnings.Add(warning.Fallback, "", "platform graph is unavailable, loading components from filesystem"), "error", err)
+		return s.showOverviewFromFilesystem()
 	}
+	s.warnStaleGraph()
 
 	allComponents := g.NodesByType("component")
 
@@ -137,18 +171,114 @@ func (s *Show) showOverview() error {
 		}
 	}
 
+	s.printOverview(byLayer, byKind, attachedByKind, attachedCount, len(allComponents))
+	return nil
+}
+
+// loadFromFilesystem loads components of the composed output, or of the domain at the ref, with their playbook
+// attachments.
+func (s *Show) loadFromFilesystem() (component.Components, error) {
+	ctx := s.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	var components component.Components
+	var err error
+	if s.Ref != "" {
+		var fsys fs.FS
+		if fsys, _, err = repository.RefFS(s.DomainDir, s.Ref); err != nil {
+			return nil, err
+		}
+		components, err = component.LoadAttachedFS(ctx, fsys, component.LoadOptions{Progress: s.Progress})
+	} else {
+		components, err = component.LoadAttached(ctx, s.DomainDir, component.LoadOptions{Progress: s.Progress})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load components: %w", err)
+	}
+
+	return components, nil
+}
+
+// warnStaleGraph warns when components were modified since the platform graph was generated.
+func (s *Show) warnStaleGraph() {
+	if msg := graphstate.Check(s.DomainDir); msg != "" {
+		s.Term().Warning().Println(s.warnings.Add(warning.StaleGraph, "", "%s", msg))
+	}
+}
+
+// showFromFilesystem shows component details without package and allocations, which require the platform graph.
+func (s *Show) showFromFilesystem() error {
+	components, err := s.loadFromFilesystem()
+	if err != nil {
+		return err
+	}
+
+	c := components.Find(s.Component)
+	if c == nil {
+		s.Term().Error().Printfln("Component %q not found", s.Component)
+		return nil
+	}
+
+	s.result = &ShowResult{
+		Component: &ComponentInfo{
+			Name:       c.Name,
+			Version:    c.Version,
+			Layer:      c.Layer,
+			Kind:       c.Kind,
+			Attachment: c.Chassis,
+		},
+	}
+
+	return s.printComponent(s.result.Component)
+}
+
+// showOverviewFromFilesystem displays component statistics of the composed output.
+func (s *Show) showOverviewFromFilesystem() error {
+	components, err := s.loadFromFilesystem()
+	if err != nil {
+		return err
+	}
+
+	byLayer := make(map[string]int)
+	byKind := make(map[string]int)
+	attachedByKind := make(map[string]int)
+	attachedCount := 0
+
+	seen := make(map[string]bool)
+	for _, c := range components {
+		if seen[c.Name] {
+			continue
+		}
+		seen[c.Name] = true
+
+		byLayer[c.Layer]++
+		byKind[c.Kind]++
+		if c.Chassis != "" {
+			attachedCount++
+			attachedByKind[c.Kind]++
+		}
+	}
+
+	s.printOverview(byLayer, byKind, attachedByKind, attachedCount, len(seen))
+	return nil
+}
+
+// printOverview stores and prints component statistics
+func (s *Show) printOverview(byLayer, byKind, attachedByKind map[string]int, attachedCount, total int) {
 	// Build result
 	s.result = &ShowResult{
 		Overview: &OverviewResult{
 			ByLayer:  byLayer,
 			ByKind:   byKind,
 			Attached: attachedCount,
-			Total:    len(allComponents),
+			Total:    total,
 		},
 	}
 
 	// Print by layer
-	s.Term().Info().Printfln("By Layer (%d total)", len(allComponents))
+	s.Term().Info().Printfln("By Layer (%d total)", total)
 	layers := sortedKeys(byLayer)
 	for _, layer := range layers {
 		s.Term().Printfln("  %s\t%d", layer, byLayer[layer])
@@ -168,9 +298,7 @@ func (s *Show) showOverview() error {
 
 	// Print summary
 	s.Term().Info().Printfln("Attached: %d components", attachedCount)
-	s.Term().Info().Printfln("Total: %d components", len(allComponents))
-
-	return nil
+	s.Term().Info().Printfln("Total: %d components", total)
 }
 
 // sortedKeys returns map keys sorted alphabetically
@@ -183,8 +311,16 @@ func sortedKeys(m map[string]int) []string {
 	return keys
 }
 
-// printComponent outputs human-readable component details
-func (s *Show) printComponent(comp *ComponentInfo) {
+// printComponent locates the component with --resolve-paths and outputs human-readable component details
+func (s *Show) printComponent(comp *ComponentInfo) error {
+	if s.ResolvePaths {
+		locator, err := component.NewLocator(s.DomainDir)
+		if err != nil {
+			return err
+		}
+		comp.Location = locator.Locate(comp.Name)
+	}
+
 	s.Term().Printfln("component\t%s", comp.Name)
 	s.Term().Printfln("version\t%s", component.FormatVersion(comp.Version))
 	s.Term().Printfln("layer\t%s", comp.Layer)
@@ -198,10 +334,16 @@ func (s *Show) printComponent(comp *ComponentInfo) {
 		s.Term().Printfln("attachment\t(not attached)")
 	}
 
+	if comp.Location != nil {
+		s.Term().Printfln("path\t%s (%s)", comp.Location.Path, comp.Location.Source)
+	}
+
 	if len(comp.Allocations) > 0 {
 		s.Term().Info().Printfln("Allocations (%d)", len(comp.Allocations))
 		for _, n := range comp.Allocations {
 			s.Term().Printfln("%s", n)
 		}
 	}
+
+	return nil
 }
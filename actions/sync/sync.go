@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -13,13 +14,20 @@ import (
 	async "sync"
 	"time"
 
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/launchrctl/compose/compose"
 	"github.com/launchrctl/keyring"
 	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/pterm/pterm"
 
+	"github.com/plasmash/plasmactl-component/internal/freeze"
+	"github.com/plasmash/plasmactl-component/internal/notify"
+	"github.com/plasmash/plasmactl-component/internal/repository"
 	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 var (
@@ -27,22 +35,69 @@ var (
 )
 
 const (
-	vaultpassKey    = "vaultpass"
 	domainNamespace = "domain"
 	buildHackAuthor = "override"
+
+	overrideTypeComponent = "component"
+	overrideTypeVariable  = "variable"
 )
 
 // SyncedComponent represents a single component version change during sync.
 type SyncedComponent struct {
+	Name       string   `json:"name"`
+	OldVersion string   `json:"old_version"`
+	NewVersion string   `json:"new_version"`
+	Commit     string   `json:"commit,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Changes    []string `json:"changes,omitempty"`
+	Via        []string `json:"via,omitempty"`
+}
+
+// OverriddenResource represents a component or variable which build value bypassed HEAD commit verification.
+type OverriddenResource struct {
 	Name       string `json:"name"`
-	OldVersion string `json:"old_version"`
-	NewVersion string `json:"new_version"`
+	Type       string `json:"type"`
+	BuildValue string `json:"build_value,omitempty"`
+	HeadValue  string `json:"head_value,omitempty"`
 }
 
 // SyncResult is the structured result of component:sync.
 type SyncResult struct {
-	Components []SyncedComponent `json:"components"`
-	DryRun     bool              `json:"dry_run"`
+	Components []SyncedComponent    `json:"components"`
+	Overridden []OverriddenResource `json:"overridden"`
+	// MissingPackages lists compose dependencies skipped because their checkout is absent.
+	MissingPackages []string `json:"missing_packages,omitempty"`
+	// Stale lists build components which version doesn't match their sources.
+	Stale []StaleComponent `json:"stale,omitempty"`
+	// Metrics is the timing breakdown of sync stages and counters of processed items.
+	Metrics *SyncMetrics `json:"metrics,omitempty"`
+	// VersionMismatches lists components which version read back after apply doesn't match the applied one.
+	VersionMismatches []VersionMismatch `json:"version_mismatches,omitempty"`
+	// VersionConflicts lists components propagated different versions by component and variable changes.
+	VersionConflicts []VersionConflict `json:"version_conflicts,omitempty"`
+	// ConstraintViolations lists dependencies of propagated components not satisfying their version constraints.
+	ConstraintViolations []ConstraintViolation `json:"constraint_violations,omitempty"`
+	// PlanDiff compares the plan with the latest applied one, set with --diff-last.
+	PlanDiff *PlanDiff `json:"plan_diff,omitempty"`
+	// Plan is the computed propagation plan, set when a report is requested.
+	Plan *PropagationPlan `json:"plan,omitempty"`
+	// Branch is the branch versions were propagated on, set with --branch.
+	Branch string `json:"branch,omitempty"`
+	// BranchCommit is the commit of propagated versions on the branch, empty if nothing changed.
+	BranchCommit string `json:"branch_commit,omitempty"`
+	// Warnings lists the warnings of the run, printed to the terminal or logged.
+	Warnings warning.List `json:"warnings,omitempty"`
+	DryRun   bool         `json:"dry_run"`
+}
+
+// Changes returns propagated components as changes to notify their owners of.
+func (r *SyncResult) Changes() []notify.Change {
+	changes := make([]notify.Change, 0, len(r.Components))
+	for _, c := range r.Components {
+		changes = append(changes, notify.Change{Component: c.Name, OldVersion: c.OldVersion, NewVersion: c.NewVersion, Action: notify.ActionPropagated})
+	}
+
+	return changes
 }
 
 // Sync is a type representing a components version synchronization action.
@@ -58,22 +113,84 @@ type Sync struct {
 	BuildDir    string
 	PackagesDir string
 	DomainDir   string
+	Domains     []Domain
 
 	// internal.
-	saveKeyring bool
-	timeline    []sync.TimelineItem
+	saveKeyring   bool
+	timeline      []sync.TimelineItem
+	propagatedBy  map[string]sync.TimelineItem
+	versionClaims map[string][]sync.TimelineItem
+	inventory     *sync.Inventory
+	skipped       []SkippedComponent
+	conflicts     []PlanConflict
+	cache         *timelineCache
+	applied       []JournalEntry
+	plan          *PropagationPlan
+	bumpAuthors   *repository.BumpAuthors
+	owners        map[string]sourceNamespace
+	shallow       map[string][]plumbing.Hash
+	shallowMx     async.Mutex
+	patch         strings.Builder
+	metrics       *SyncMetrics
+	metricsMx     async.Mutex
+	traceCtx      context.Context
+	state         *applyState
+	stateMx       async.Mutex
+	warningsMx    async.Mutex
+	freezeWindows freeze.Windows
+	attachedTo    map[string][]string
+	// stateDir is the domain directory holding sync state, it stays the original domain in branch runs.
+	stateDir string
+	branch   string
 
 	// options.
 	DryRun                 bool
 	AllowOverride          bool
+	ConfirmOverrides       bool
 	FilterByComponentUsage bool
 	TimeDepth              string
 	VaultPass              string
+	VaultPassProvider      vaultpass.Provider
 	ShowProgress           bool
+	Simulate               []string
+	Only                   []string
+	FromManifest           string
+	SkipMissingPackages    bool
+	Report                 string
+	ReportFile             string
+	NoCache                bool
+	Undo                   bool
+	Interactive            bool
+	SkipBuildCheck         bool
+	FromSources            bool
+	Concurrency            int
+	OnlyVars               bool
+	OnlyComponents         bool
+	DiffLast               bool
+	BumpAuthors            []string
+	Unshallow              bool
+	VersionFormat          sync.VersionFormat
+	Patch                  string
+	ConflictStrategy       string
+	Resume                 bool
+	NoVerify               bool
+	FreezeWindows          freeze.Windows
+	OverrideFreeze         bool
+	Branch                 string
+	SkipConstraints        bool
+	// ExtraKinds are component kinds registered by plugins, propagated as the built-in ones.
+	ExtraKinds []string
 
 	result *SyncResult
 }
 
+// Domain represents an additional domain-level repository contributing components and variables.
+type Domain struct {
+	Name     string `yaml:"name"`
+	Path     string `yaml:"path"`
+	Priority int    `yaml:"priority"`
+}
+
 // Result returns the structured result for JSON output.
 func (s *Sync) Result() any {
 	return s.result
@@ -83,24 +200,144 @@ type hashStruct struct {
 	hash     string
 	hashTime time.Time
 	author   string
+	email    string
+	message  string
+}
+
+// validateOptions rejects options which can't be combined, before the mode of the run is selected.
+func (s *Sync) validateOptions() error {
+	if s.ConflictStrategy != "" && !slices.Contains(conflictStrategies, s.ConflictStrategy) {
+		return fmt.Errorf("unknown conflict strategy %q, expected one of %s", s.ConflictStrategy, strings.Join(conflictStrategies, ", "))
+	}
+
+	if s.Undo && (len(s.Simulate) > 0 || s.FromManifest != "" || len(s.Only) > 0) {
+		return fmt.Errorf("--undo can't be combined with --simulate, --from-manifest or --only")
+	}
+
+	if s.Resume && (len(s.Simulate) > 0 || s.FromManifest != "" || len(s.Only) > 0) {
+		return fmt.Errorf("--resume can't be combined with --simulate, --from-manifest or --only")
+	}
+
+	if s.FromManifest != "" && len(s.Simulate) > 0 {
+		return fmt.Errorf("--from-manifest can't be combined with --simulate")
+	}
+
+	if s.FromSources && s.FromManifest != "" {
+		return fmt.Errorf("--from-sources can't be combined with --from-manifest")
+	}
+
+	if s.OnlyVars && (s.OnlyComponents || len(s.Only) > 0) {
+		return fmt.Errorf("--only-vars can't be combined with --only-components or --only")
+	}
+
+	if len(s.Only) > 0 && (len(s.Simulate) > 0 || s.FromManifest != "") {
+		return fmt.Errorf("--only can't be combined with --simulate or --from-manifest")
+	}
+
+	if s.Branch != "" {
+		if s.FromManifest != "" {
+			return fmt.Errorf("--branch can't be combined with --from-manifest")
+		}
+		if len(s.Domains) > 0 {
+			return fmt.Errorf("--branch can't propagate to additional domains, their checkouts don't follow the branch")
+		}
+	}
+
+	return nil
 }
 
 // Execute the sync action to propagate resources' versions.
 func (s *Sync) Execute() error {
+	// Simulation and comparison with previous run never touch files.
+	if len(s.Simulate) > 0 || s.DiffLast {
+		s.DryRun = true
+	}
+
+	if s.Patch != "" && !s.DryRun {
+		return fmt.Errorf("--patch requires --dry-run")
+	}
+
+	if err := s.validateOptions(); err != nil {
+		return err
+	}
+
+	if s.stateDir == "" {
+		s.stateDir = s.DomainDir
+	}
+	if s.Branch != "" {
+		return s.executeOnBranch()
+	}
+
 	s.result = &SyncResult{DryRun: s.DryRun}
+	if s.Undo {
+		return s.undo()
+	}
+
+	defer func() {
+		if errJournal := s.writeJournal(); errJournal != nil {
+			s.Term().Warning().Println(s.warn(warning.State, "", "Applied changes can't be undone: %s", errJournal))
+		}
+	}()
+
+	if s.Resume {
+		if err := s.resume(); err != nil {
+			return err
+		}
+		return s.verifyApplied()
+	}
+
+	if s.FromManifest != "" {
+		if err := s.restoreFromManifest(); err != nil {
+			return err
+		}
+		return s.verifyApplied()
+	}
+
+	var err error
+	s.bumpAuthors, err = repository.NewBumpAuthors(s.BumpAuthors)
+	if err != nil {
+		return err
+	}
+
+	if s.Report != "" && s.Report != ReportJSON && s.Report != ReportYAML {
+		return fmt.Errorf("unknown report format %q (expected: %s, %s)", s.Report, ReportJSON, ReportYAML)
+	}
+
+	s.warnInterrupted()
 	s.Term().Info().Println("Processing propagation...")
 
-	err := s.ensureVaultpassExists()
+	err = s.validateDomains()
 	if err != nil {
 		return err
 	}
 
+	err = s.resolveVaultPass()
+	if err != nil {
+		return err
+	}
+
+	if s.FromSources {
+		s.Log().Info("Composing domains and packages sources")
+		cleanup, errCompose := s.composeSources()
+		if errCompose != nil {
+			return errCompose
+		}
+		defer cleanup()
+	}
+
+	defer s.startTrace()()
+
 	err = s.propagate()
 	if err != nil {
 		return err
 	}
 	s.Term().Info().Println("Propagation has been finished")
 
+	err = s.verifyApplied()
+	if err != nil {
+		return err
+	}
+
 	if s.saveKeyring {
 		err = s.Keyring.Save()
 	}
@@ -108,8 +345,30 @@ func (s *Sync) Execute() error {
 	return err
 }
 
+// resolveVaultPass reads vault password from the configured provider, so sync runs unattended,
+// or from keyring, prompting for it if missing.
+func (s *Sync) resolveVaultPass() error {
+	if s.VaultPass == "" {
+		pass, err := s.VaultPassProvider.Password()
+		if err != nil {
+			return err
+		}
+		if pass != "" {
+			s.VaultPass = pass
+			return nil
+		}
+	}
+
+	// Keyring is optional when sync is embedded, vault password is then used as is.
+	if s.Keyring == nil {
+		return nil
+	}
+
+	return s.ensureVaultpassExists()
+}
+
 func (s *Sync) ensureVaultpassExists() error {
-	keyValueItem, errGet := s.Keyring.GetForKey(vaultpassKey)
+	keyValueItem, errGet := s.Keyring.GetForKey(vaultpass.KeyringKey)
 	if errGet != nil {
 		if errors.Is(errGet, keyring.ErrEmptyPass) {
 			return errGet
@@ -118,7 +377,7 @@ func (s *Sync) ensureVaultpassExists() error {
 			return errMalformedKeyring
 		}
 
-		keyValueItem.Key = vaultpassKey
+		keyValueItem.Key = vaultpass.KeyringKey
 		keyValueItem.Value = s.VaultPass
 
 		if keyValueItem.Value == "" {
@@ -144,73 +403,325 @@ func (s *Sync) ensureVaultpassExists() error {
 func (s *Sync) propagate() error {
 	s.timeline = sync.CreateTimeline()
 
+	inv, err := s.buildInventory()
+	if err != nil {
+		return err
+	}
+
+	err = s.validateSelection(inv)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Simulate) > 0 {
+		err = s.simulateTimeline(inv)
+		if err != nil {
+			return fmt.Errorf("simulating timeline > %w", err)
+		}
+	} else {
+		err = s.buildTimeline(inv)
+		if err != nil {
+			return fmt.Errorf("building timeline > %w", err)
+		}
+
+		err = s.confirmOverrides()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(s.timeline) == 0 {
+		s.Term().Warning().Println(s.warn(warning.Empty, "", "No components were found for propagation"))
+		return s.report(sync.NewOrderedMap[*sync.Component](), nil)
+	}
+
+	err = s.resolveFreezeWindows()
+	if err != nil {
+		return err
+	}
+
+	end := s.stage(stagePropagationMap, "")
+	toSync, componentVersionMap, err := s.buildPropagationMap(inv, s.timeline)
+	end()
+	if err != nil {
+		return fmt.Errorf("building propagation map > %w", err)
+	}
+	s.count(counterTimelineItems, len(s.timeline))
+	s.count(counterPropagated, toSync.Len())
+
+	err = s.resolveVersionConflicts(componentVersionMap)
+	if err != nil {
+		return err
+	}
+
+	err = s.checkConstraints(inv, componentVersionMap)
+	if err != nil {
+		return err
+	}
+
+	err = s.report(toSync, componentVersionMap)
+	if err != nil {
+		return err
+	}
+
+	err = s.updateComponents(componentVersionMap, toSync)
+	if err != nil {
+		return fmt.Errorf("propagate > %w", err)
+	}
+
+	if s.keepsPlan() && len(s.result.Components) > 0 {
+		if errPlan := s.saveLastPlan(s.plan); errPlan != nil {
+			s.Term().Warning().Println(s.warn(warning.State, "", "Applied propagation plan can't be stored: %s", errPlan))
+		}
+	}
+
+	if len(s.Simulate) > 0 {
+		s.reportSimulation(inv)
+	}
+
+	return nil
+}
+
+// report writes propagation plan before components are updated, if requested, and compares it with the latest
+// applied plan with --diff-last. The plan is kept to be stored once applied.
+func (s *Sync) report(toSync *sync.OrderedMap[*sync.Component], componentVersionMap map[string]string) error {
+	if s.Report == "" && !s.DiffLast && !s.keepsPlan() {
+		return nil
+	}
+
+	plan, err := s.buildPlan(toSync, componentVersionMap)
+	if err != nil {
+		return fmt.Errorf("building propagation plan > %w", err)
+	}
+	s.plan = plan
+
+	if s.DiffLast {
+		if err = s.diffLastPlan(plan); err != nil {
+			return err
+		}
+	}
+
+	if s.Report == "" {
+		return nil
+	}
+
+	s.result.Plan = plan
+	return s.writeReport(plan)
+}
+
+// buildInventory initializes build inventory with calculated usage.
+// Inventory is kept, so consecutive executions of the same action don't scan the build again.
+func (s *Sync) buildInventory() (*sync.Inventory, error) {
+	if s.inventory != nil {
+		return s.inventory, nil
+	}
+
 	s.Log().Info("Initializing build inventory")
+	end := s.stage(stageInventory, "")
 	inv, err := sync.NewInventory(s.BuildDir, s.Log())
+	end()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	s.count(counterComponents, inv.GetComponentsMap().Len())
 
 	if s.FilterByComponentUsage {
 		s.Log().Info("Calculating components usage")
+		end = s.stage(stageComponentsUsage, "")
 		err = inv.CalculateComponentsUsage()
+		end()
 		if err != nil {
-			return fmt.Errorf("calculate components usage > %w", err)
+			return nil, fmt.Errorf("calculate components usage > %w", err)
 		}
 	}
 
 	s.Log().Info("Calculating variables usage")
+	end = s.stage(stageVariablesUsage, "")
 	err = inv.CalculateVariablesUsage(s.VaultPass)
+	end()
 	if err != nil {
-		return fmt.Errorf("calculate variables usage > %w", err)
+		return nil, fmt.Errorf("calculate variables usage > %w", err)
 	}
 
-	err = s.buildTimeline(inv)
-	if err != nil {
-		return fmt.Errorf("building timeline > %w", err)
+	s.inventory = inv
+	return inv, nil
+}
+
+func (s *Sync) validateDomains() error {
+	for i, d := range s.Domains {
+		if d.Name == "" || d.Path == "" {
+			return fmt.Errorf("domain #%d must have both name and path", i)
+		}
+
+		if _, err := os.Stat(d.Path); err != nil {
+			return fmt.Errorf("domain %s > %w", d.Name, err)
+		}
 	}
 
-	if len(s.timeline) == 0 {
-		s.Term().Warning().Println("No components were found for propagation")
+	return nil
+}
+
+// domainsByPriority returns main domain together with additional domains, sorted from the lowest priority to the highest.
+// On equal priority, main domain wins over additional ones.
+func (s *Sync) domainsByPriority() []Domain {
+	domains := make([]Domain, 0, len(s.Domains)+1)
+	domains = append(domains, s.Domains...)
+	domains = append(domains, Domain{Name: domainNamespace, Path: s.DomainDir})
+
+	sort.SliceStable(domains, func(i, j int) bool {
+		return domains[i].Priority < domains[j].Priority
+	})
+
+	return domains
+}
+
+// confirmOverrides lists resources overridden by build values and asks user to continue.
+func (s *Sync) confirmOverrides() error {
+	if len(s.result.Overridden) == 0 {
 		return nil
 	}
 
-	toSync, componentVersionMap, err := s.buildPropagationMap(inv, s.timeline)
-	if err != nil {
-		return fmt.Errorf("building propagation map > %w", err)
+	sort.Slice(s.result.Overridden, func(i, j int) bool {
+		if s.result.Overridden[i].Type != s.result.Overridden[j].Type {
+			return s.result.Overridden[i].Type < s.result.Overridden[j].Type
+		}
+		return s.result.Overridden[i].Name < s.result.Overridden[j].Name
+	})
+
+	s.Term().Warning().Printfln("%d resource(s) don't match HEAD commit and will be overridden by build values:", len(s.result.Overridden))
+	for _, o := range s.result.Overridden {
+		if o.Type == overrideTypeComponent {
+			s.Term().Printfln("- %s %s: %s (HEAD: %s)", o.Type, o.Name, o.BuildValue, o.HeadValue)
+			continue
+		}
+		s.Term().Printfln("- %s %s", o.Type, o.Name)
 	}
 
-	err = s.updateComponents(componentVersionMap, toSync)
+	if !s.ConfirmOverrides {
+		return nil
+	}
+
+	confirmed, err := pterm.DefaultInteractiveConfirm.WithDefaultValue(false).Show("Continue with overridden resources?")
 	if err != nil {
-		return fmt.Errorf("propagate > %w", err)
+		return fmt.Errorf("confirm overrides > %w", err)
+	}
+
+	if !confirmed {
+		return errors.New("sync aborted, overrides were not confirmed")
 	}
 
 	return nil
 }
 
+// approveUpdates lets the operator select proposed version changes to apply.
+// Denied components are reported as skipped and keep their version.
+func (s *Sync) approveUpdates(sortList []string, updateMap map[string]map[string]string) ([]string, error) {
+	if !s.Interactive || s.DryRun {
+		return sortList, nil
+	}
+
+	options := make([]string, 0, len(sortList))
+	byOption := make(map[string]string, len(sortList))
+	for _, key := range sortList {
+		option := fmt.Sprintf("%s: %s -> %s", key, updateMap[key]["current"], updateMap[key]["new"])
+		options = append(options, option)
+		byOption[option] = key
+	}
+
+	selected, err := pterm.DefaultInteractiveMultiselect.
+		WithOptions(options).
+		WithDefaultOptions(options).
+		WithMaxHeight(min(len(options), 20)).
+		Show("Select version changes to apply")
+	if err != nil {
+		return nil, fmt.Errorf("select version changes > %w", err)
+	}
+
+	approved := make(map[string]bool, len(selected))
+	for _, option := range selected {
+		approved[byOption[option]] = true
+	}
+
+	var result []string
+	for _, key := range sortList {
+		if !approved[key] {
+			s.skip(key, "denied by operator")
+			s.Term().Warning().Println(s.warn(warning.Skipped, key, "- skip %s (denied)", key))
+			continue
+		}
+		result = append(result, key)
+	}
+
+	return result, nil
+}
+
 func (s *Sync) buildTimeline(buildInv *sync.Inventory) error {
+	if !s.OnlyVars {
+		err := s.buildComponentsTimeline(buildInv)
+		if err != nil {
+			return err
+		}
+	} else {
+		s.Log().Info("Skipping components, only variables are propagated")
+	}
+
+	if len(s.Only) > 0 || s.OnlyComponents {
+		s.Log().Info("Skipping variables, only components are propagated")
+		return nil
+	}
+
+	s.Log().Info("Populate timeline with variables")
+	err := s.populateTimelineVars(buildInv)
+	if err != nil {
+		return fmt.Errorf("iteraring variables > %w", err)
+	}
+
+	return nil
+}
+
+// buildComponentsTimeline populates timeline with version changes of domains and packages components.
+func (s *Sync) buildComponentsTimeline(buildInv *sync.Inventory) error {
 	s.Log().Info("Gathering domain and packages components")
+	end := s.stage(stageComponentsMap, "")
 	componentsMap, packagePathMap, err := s.getComponentsMaps(buildInv)
+	end()
 	if err != nil {
 		return fmt.Errorf("build component map > %w", err)
 	}
 
+	// Composed sources match domains and packages by construction.
+	if !s.FromSources {
+		s.Log().Info("Checking build is up to date")
+		end = s.stage(stageBuildCheck, "")
+		err = s.checkBuild(componentsMap)
+		end()
+		if err != nil {
+			return err
+		}
+	}
+
+	if !s.NoCache {
+		s.cache, err = loadTimelineCache(s.stateDirFile(cacheFile))
+		if err != nil {
+			s.Log().Warn("timeline cache is ignored", "error", err)
+		}
+	}
+
 	s.Log().Info("Populate timeline with components")
 	err = s.populateTimelineComponents(componentsMap, packagePathMap)
 	if err != nil {
 		return fmt.Errorf("iterating components > %w", err)
 	}
 
-	s.Log().Info("Populate timeline with variables")
-	err = s.populateTimelineVars(buildInv)
-	if err != nil {
-		return fmt.Errorf("iteraring variables > %w", err)
+	if err = s.cache.save(); err != nil {
+		s.Log().Warn("failed to save timeline cache", "error", err)
 	}
 
 	return nil
 }
 
-func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.OrderedMap[*sync.Component], map[string]string, error) {
-	componentsMap := make(map[string]*sync.OrderedMap[*sync.Component])
+// namespaces returns names of packages and domains ordered from the lowest priority to the highest, with their paths.
+func (s *Sync) namespaces() ([]string, map[string]string, error) {
 	packagePathMap := make(map[string]string)
 
 	plasmaCompose, err := compose.Lookup(os.DirFS(s.DomainDir))
@@ -219,15 +730,52 @@ func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.Ord
 	}
 
 	var priorityOrder []string
+	var missing, missingPaths []string
 	for _, dep := range plasmaCompose.Dependencies {
 		pkg := dep.ToPackage(dep.Name)
-		packagePathMap[dep.Name] = filepath.Join(s.PackagesDir, pkg.GetName(), pkg.GetTarget())
+		path := filepath.Join(s.PackagesDir, pkg.GetName(), pkg.GetTarget())
+		if _, errStat := os.Stat(path); errStat != nil {
+			missing = append(missing, dep.Name)
+			missingPaths = append(missingPaths, fmt.Sprintf("%s (%s)", dep.Name, path))
+			continue
+		}
+
+		packagePathMap[dep.Name] = path
 		priorityOrder = append(priorityOrder, dep.Name)
 	}
 
-	packagePathMap[domainNamespace] = s.DomainDir
+	if len(missing) > 0 {
+		if !s.SkipMissingPackages {
+			return nil, nil, fmt.Errorf("packages missing from disk: %s, run model:compose to fetch them or use --skip-missing-packages", strings.Join(missingPaths, ", "))
+		}
+
+		if s.result.MissingPackages == nil {
+			s.Term().Warning().Println(s.warn(warning.Partial, "", "Skipping packages missing from disk, propagation is partial: %s", strings.Join(missingPaths, ", ")))
+		}
+		s.result.MissingPackages = missing
+	}
+
+	// Domains always take precedence over packages, ordered between each other by priority.
+	for _, d := range s.domainsByPriority() {
+		if _, ok := packagePathMap[d.Name]; ok {
+			return nil, nil, fmt.Errorf("domain name %q is already used by another package or domain", d.Name)
+		}
+
+		packagePathMap[d.Name] = d.Path
+		priorityOrder = append(priorityOrder, d.Name)
+	}
+
+	return priorityOrder, packagePathMap, nil
+}
+
+func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.OrderedMap[*sync.Component], map[string]string, error) {
+	componentsMap := make(map[string]*sync.OrderedMap[*sync.Component])
+	s.conflicts = nil
 
-	priorityOrder = append(priorityOrder, domainNamespace)
+	priorityOrder, packagePathMap, err := s.namespaces()
+	if err != nil {
+		return nil, nil, err
+	}
 
 	var wg async.WaitGroup
 	var mx async.Mutex
@@ -305,6 +853,8 @@ func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.Ord
 		}
 	}
 
+	s.filterSelection(componentsMap)
+
 	buildComponents := buildInv.GetComponentsMap()
 	for _, componentName := range buildComponents.Keys() {
 		conflicts := make(map[string]string)
@@ -332,12 +882,10 @@ func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.Ord
 
 		var sameVersionNamespaces []string
 		for conflictingNamespace := range conflicts {
-			conflictEntity, err := sync.NewComponent(componentName, packagePathMap[conflictingNamespace])
-			if err != nil {
-				return nil, nil, err
-			}
+			// Component of the namespace map, as files of bare packages are only readable through it.
+			conflictEntity, _ := componentsMap[conflictingNamespace].Get(componentName)
 
-			baseVersion, _, debug, err := conflictEntity.GetBaseVersion()
+			baseVersion, _, debug, err := conflictEntity.GetBaseVersion(s.VersionFormat)
 			for _, d := range debug {
 				s.Log().Debug("error", "message", d)
 			}
@@ -373,13 +921,33 @@ func (s *Sync) getComponentsMaps(buildInv *sync.Inventory) (map[string]*sync.Ord
 				}
 			}
 		}
+
+		s.recordConflict(componentName, conflicts, componentsMap)
 	}
 
 	return componentsMap, packagePathMap, nil
 }
 
+// recordConflict keeps component provided by several namespaces and namespaces providing it after resolution.
+func (s *Sync) recordConflict(name string, namespaces map[string]string, componentsMap map[string]*sync.OrderedMap[*sync.Component]) {
+	conflict := PlanConflict{Component: name}
+	for ns := range namespaces {
+		conflict.Namespaces = append(conflict.Namespaces, ns)
+		if _, ok := componentsMap[ns].Get(name); ok {
+			conflict.Kept = append(conflict.Kept, ns)
+		}
+	}
+	sort.Strings(conflict.Namespaces)
+	sort.Strings(conflict.Kept)
+
+	s.conflicts = append(s.conflicts, conflict)
+}
+
 func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.TimelineItem) (*sync.OrderedMap[*sync.Component], map[string]string, error) {
 	componentVersionMap := make(map[string]string)
+	s.skipped = nil
+	s.propagatedBy = make(map[string]sync.TimelineItem)
+	s.versionClaims = make(map[string][]sync.TimelineItem)
 	toSync := sync.NewOrderedMap[*sync.Component]()
 	componentsMap := buildInv.GetComponentsMap()
 	processed := make(map[string]bool)
@@ -408,8 +976,9 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 
 				c, _ := components.Get(key)
 
-				if !sync.IsUpdatableKind(c.GetKind()) {
-					s.Log().Warn(fmt.Sprintf("%s is not allowed to propagate", key))
+				if !sync.IsUpdatableKind(c.GetKind(), s.ExtraKinds...) {
+					s.Log().Warn(s.warn(warning.Skipped, key, "%s is not allowed to propagate", key))
+					s.skip(key, "kind not allowed to propagate")
 					continue
 				}
 
@@ -445,18 +1014,24 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 
 					// Skip component if it was processed by previous timeline item or previous component (via deps).
 					if processed[dep] {
+						s.recordVersionClaim(dep, item)
 						continue
 					}
 
 					processed[dep] = true
 
-					if !sync.IsUpdatableKind(depComponent.GetKind()) {
-						s.Log().Warn(fmt.Sprintf("%s is not allowed to propagate", dep))
+					if !sync.IsUpdatableKind(depComponent.GetKind(), s.ExtraKinds...) {
+						s.Log().Warn(s.warn(warning.Skipped, dep, "%s is not allowed to propagate", dep))
+						s.skip(dep, "kind not allowed to propagate")
+						continue
+					}
+					if s.skipFrozen(dep, depComponent) {
 						continue
 					}
 
 					toSync.Set(dep, depComponent)
 					componentVersionMap[dep] = i.GetVersion()
+					s.propagatedBy[dep] = item
 
 					if _, okD := components.Get(dep); !okD {
 						dependenciesLog.Set(dep, true)
@@ -468,6 +1043,7 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 				// Ensure new version removes previous propagation for that component.
 				toSync.Unset(key)
 				delete(componentVersionMap, key)
+				delete(s.propagatedBy, key)
 			}
 
 			if dependenciesLog.Len() > 0 {
@@ -487,6 +1063,18 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 			for _, v := range variables.Keys() {
 				variable, _ := variables.Get(v)
 				vc := buildInv.GetVariableComponents(variable.GetName(), variable.GetPlatform())
+				for _, u := range buildInv.GetVariableUsages(variable.GetName(), variable.GetPlatform()) {
+					if len(usedComponents) > 0 && !usedComponents[u.Component] {
+						continue
+					}
+					s.Log().Debug("variable usage",
+						slog.String("variable", variable.GetName()),
+						slog.String("component", u.Component),
+						slog.String("referenced", u.Variable),
+						slog.String("file", fmt.Sprintf("%s:%d", u.File, u.Line)),
+						slog.String("source", u.Source),
+					)
+				}
 
 				if len(usedComponents) == 0 {
 					components = append(components, vc...)
@@ -507,6 +1095,7 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 			var toProcess []string
 			for _, key := range components {
 				if processed[key] {
+					s.recordVersionClaim(key, item)
 					continue
 				}
 				toProcess = append(toProcess, key)
@@ -520,15 +1109,17 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 				// First set version for main component.
 				mainComponent, okM := componentsMap.Get(c)
 				if !okM {
-					s.Log().Warn(fmt.Sprintf("skipping not valid component %s (direct vars dependency)", c))
+					s.Log().Warn(s.warn(warning.NotFound, c, "skipping not valid component %s (direct vars dependency)", c))
+					s.skip(c, "not found in build")
 					continue
 				}
 
 				processed[c] = true
 
-				if sync.IsUpdatableKind(mainComponent.GetKind()) {
+				if sync.IsUpdatableKind(mainComponent.GetKind(), s.ExtraKinds...) && !s.skipFrozen(c, mainComponent) {
 					toSync.Set(c, mainComponent)
 					componentVersionMap[c] = i.GetVersion()
+					s.propagatedBy[c] = item
 					dependenciesLog.Set(c, true)
 				}
 
@@ -537,24 +1128,31 @@ func (s *Sync) buildPropagationMap(buildInv *sync.Inventory, timeline []sync.Tim
 				for dep := range dependentComponents {
 					depComponent, okC := componentsMap.Get(dep)
 					if !okC {
-						s.Log().Warn(fmt.Sprintf("skipping not valid component %s (dependency of %s)", dep, c))
+						s.Log().Warn(s.warn(warning.NotFound, dep, "skipping not valid component %s (dependency of %s)", dep, c))
+						s.skip(dep, "not found in build")
 						continue
 					}
 
 					// Skip component if it was processed by previous timeline item or previous component (via deps).
 					if processed[dep] {
+						s.recordVersionClaim(dep, item)
 						continue
 					}
 
 					processed[dep] = true
 
-					if !sync.IsUpdatableKind(depComponent.GetKind()) {
-						s.Log().Warn(fmt.Sprintf("%s is not allowed to propagate", dep))
+					if !sync.IsUpdatableKind(depComponent.GetKind(), s.ExtraKinds...) {
+						s.Log().Warn(s.warn(warning.Skipped, dep, "%s is not allowed to propagate", dep))
+						s.skip(dep, "kind not allowed to propagate")
+						continue
+					}
+					if s.skipFrozen(dep, depComponent) {
 						continue
 					}
 
 					toSync.Set(dep, depComponent)
 					componentVersionMap[dep] = i.GetVersion()
+					s.propagatedBy[dep] = item
 
 					dependenciesLog.Set(dep, true)
 				}
@@ -582,7 +1180,7 @@ func (s *Sync) updateComponents(componentVersionMap map[string]string, toSync *s
 	s.Log().Info("Sorting components before update")
 	for _, key := range toSync.Keys() {
 		c, _ := toSync.Get(key)
-		baseVersion, currentVersion, debug, errVersion := c.GetBaseVersion()
+		baseVersion, currentVersion, debug, errVersion := c.GetBaseVersion(s.VersionFormat)
 		for _, d := range debug {
 			s.Log().Debug("error", "message", d)
 		}
@@ -591,15 +1189,15 @@ func (s *Sync) updateComponents(componentVersionMap map[string]string, toSync *s
 		}
 
 		if currentVersion == "" {
-			s.Term().Warning().Printfln("component %s has no version", c.GetName())
+			s.Term().Warning().Println(s.warn(warning.NotFound, c.GetName(), "component %s has no version", c.GetName()))
 			stopPropagation = true
 		}
 
-		newVersion := composeVersion(currentVersion, componentVersionMap[c.GetName()])
+		newVersion := composeVersion(s.VersionFormat, currentVersion, componentVersionMap[c.GetName()])
 		if baseVersion == componentVersionMap[c.GetName()] {
 			s.Log().Debug("skip identical",
 				"baseVersion", baseVersion, "currentVersion", currentVersion, "propagateVersion", componentVersionMap[c.GetName()], "newVersion", newVersion)
-			s.Term().Warning().Printfln("- skip %s (identical versions)", c.GetName())
+			s.Term().Warning().Println(s.warn(warning.Unchanged, c.GetName(), "- skip %s (identical versions)", c.GetName()))
 			continue
 		}
 
@@ -622,17 +1220,21 @@ func (s *Sync) updateComponents(componentVersionMap map[string]string, toSync *s
 	}
 
 	sort.Strings(sortList)
-	s.Log().Info("Propagating versions")
+	sortList, err := s.approveUpdates(sortList, updateMap)
+	if err != nil {
+		return err
+	}
 
-	var p *pterm.ProgressbarPrinter
-	if s.ShowProgress {
-		p, _ = pterm.DefaultProgressbar.WithWriter(s.Term()).WithTotal(len(sortList)).WithTitle("Updating components").Start()
+	if len(sortList) == 0 {
+		s.Term().Printfln("No version change approved")
+		return nil
 	}
-	for _, key := range sortList {
-		if p != nil {
-			p.Increment()
-		}
 
+	s.Log().Info("Propagating versions")
+
+	// Changes are logged in order first, files are then written concurrently.
+	var writes []versionWrite
+	for _, key := range sortList {
 		val := updateMap[key]
 
 		c, ok := toSync.Get(key)
@@ -642,47 +1244,91 @@ func (s *Sync) updateComponents(componentVersionMap map[string]string, toSync *s
 			return fmt.Errorf("unidentified component found during update %s", key)
 		}
 
-		s.result.Components = append(s.result.Components, SyncedComponent{
+		synced := SyncedComponent{
 			Name:       c.GetName(),
 			OldVersion: currentVersion,
 			NewVersion: newVersion,
-		})
+		}
+		if item, okItem := s.propagatedBy[key]; okItem {
+			synced.Commit = item.GetCommit()
+			synced.Message = item.GetMessage()
+			synced.Changes = item.GetChanges()
+		}
+
+		s.result.Components = append(s.result.Components, synced)
 		s.Log().Info(fmt.Sprintf("%s from %s to %s", c.GetName(), currentVersion, newVersion))
-		if s.DryRun {
-			continue
+		if s.DryRun && len(s.Simulate) == 0 {
+			s.printChangelog(synced)
 		}
 
-		debug, err := c.UpdateVersion(newVersion)
-		for _, d := range debug {
-			s.Log().Debug("error", "message", d)
+		target := c
+		if s.FromSources {
+			target, err = s.sourceComponent(c)
+			if err != nil {
+				return err
+			}
+			if target == nil {
+				s.Term().Warning().Println(s.warn(warning.Skipped, key, "- %s is provided by package %s, new version is only reported", key, s.owners[key].name))
+				continue
+			}
 		}
-		if err != nil {
-			return err
+
+		if s.DryRun {
+			if err = s.diffVersion(target, newVersion, len(s.Simulate) == 0); err != nil {
+				return err
+			}
+			continue
 		}
+
+		writes = append(writes, versionWrite{component: target, oldVersion: currentVersion, newVersion: newVersion})
 	}
 
-	return nil
+	if s.DryRun {
+		return s.writePatch()
+	}
+
+	return s.applyVersions(writes)
 }
 
-func composeVersion(oldVersion string, newVersion string) string {
-	var version string
-	if len(strings.Split(newVersion, "-")) > 1 {
-		version = newVersion
-	} else {
-		split := strings.Split(oldVersion, "-")
-		if len(split) == 1 {
-			version = fmt.Sprintf("%s-%s", oldVersion, newVersion)
-		} else if len(split) > 1 {
-			version = fmt.Sprintf("%s-%s", split[0], newVersion)
-		} else {
-			version = newVersion
-		}
+// composeVersion returns the version propagated to a component: base of the current version and the new version,
+// or the new version itself if it's already composed.
+func composeVersion(format sync.VersionFormat, oldVersion string, newVersion string) string {
+	if format.IsComposed(newVersion) {
+		return newVersion
 	}
 
-	return version
+	base, _ := format.Split(oldVersion)
+	return format.Compose(base, newVersion)
+}
+
+// printChangelog outputs propagated version together with commit subjects which caused it.
+func (s *Sync) printChangelog(c SyncedComponent) {
+	s.Term().Printfln("- %s: %s -> %s", c.Name, c.OldVersion, c.NewVersion)
+	if c.Message != "" {
+		s.Term().Printfln("    %s", c.Message)
+	}
+	for _, change := range c.Changes {
+		s.Term().Printfln("    * %s", change)
+	}
 }
 
 func (s *Sync) getComponentsMapFrom(dir string) (*sync.OrderedMap[*sync.Component], error) {
+	// Bare package checkouts have no files on disk, components are read from HEAD tree.
+	if repository.IsBareDir(dir) {
+		repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			return nil, fmt.Errorf("%s - %w", dir, err)
+		}
+
+		paths, readFile, err := repository.HeadFiles(repo)
+		if err != nil {
+			return nil, fmt.Errorf("%s - %w", dir, err)
+		}
+
+		s.Log().Debug("reading components of bare repository", "path", dir)
+		return sync.NewComponentsMapFromFiles(paths, dir, readFile), nil
+	}
+
 	inv, err := sync.NewInventory(dir, s.Log())
 	if err != nil {
 		return nil, err
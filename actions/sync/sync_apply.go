@@ -0,0 +1,120 @@
+package sync
+
+import (
+	"runtime"
+	async "sync"
+
+	"github.com/pterm/pterm"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// versionWrite is a component version update to write to its meta file.
+type versionWrite struct {
+	component  *sync.Component
+	oldVersion string
+	newVersion string
+}
+
+// fileLocks serializes writes to the same file.
+type fileLocks struct {
+	mx    async.Mutex
+	files map[string]*async.Mutex
+}
+
+func (l *fileLocks) lock(path string) func() {
+	l.mx.Lock()
+	if l.files == nil {
+		l.files = make(map[string]*async.Mutex)
+	}
+	m, ok := l.files[path]
+	if !ok {
+		m = &async.Mutex{}
+		l.files[path] = m
+	}
+	l.mx.Unlock()
+
+	m.Lock()
+	return m.Unlock
+}
+
+// applyVersions writes component versions with a pool of Concurrency workers, runtime.NumCPU() if not positive.
+// Successful writes are journaled in the order of writes, the first failed write in that order is returned.
+// Progress is persisted to the state file until all versions are written, see resume.
+func (s *Sync) applyVersions(writes []versionWrite) error {
+	if len(writes) == 0 {
+		return nil
+	}
+
+	defer s.stage(stageApply, "")()
+
+	if err := s.startApplyState(writes); err != nil {
+		return err
+	}
+
+	workers := s.Concurrency
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	var p *pterm.ProgressbarPrinter
+	if s.ShowProgress {
+		p, _ = pterm.DefaultProgressbar.WithWriter(s.Term()).WithTotal(len(writes)).WithTitle("Updating components").Start()
+	}
+
+	errs := make([]error, len(writes))
+	debugs := make([][]string, len(writes))
+	jobs := make(chan int)
+	locks := &fileLocks{}
+	var wg async.WaitGroup
+	var mx async.Mutex
+
+	for w := 0; w < min(workers, len(writes)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				unlock := locks.lock(writes[i].component.MetaPath())
+				debugs[i], errs[i] = writes[i].component.UpdateVersion(writes[i].newVersion)
+				unlock()
+				if errs[i] == nil {
+					s.markWritten(writes[i].component.GetName())
+				}
+
+				if p != nil {
+					mx.Lock()
+					p.Increment()
+					mx.Unlock()
+				}
+			}
+		}()
+	}
+
+	for i := range writes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	var err error
+	for i, write := range writes {
+		for _, d := range debugs[i] {
+			s.Log().Debug("error", "message", d)
+		}
+
+		if errs[i] != nil {
+			if err == nil {
+				err = errs[i]
+			}
+			continue
+		}
+		s.journalChange(write.component, write.oldVersion, write.newVersion)
+		s.count(counterWritten, 1)
+	}
+
+	if errState := s.finishApplyState(); errState != nil && err == nil {
+		err = errState
+	}
+
+	return err
+}
@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+)
+
+// executeOnBranch propagates versions on another branch of the domain, e.g. a release branch receiving a hotfix.
+// The branch is checked out in a temporary linked worktree, its components are composed from sources since the
+// current build doesn't reflect it, and propagated versions are committed to the branch. Sync state of the branch is
+// kept in the original domain, apart from the state of the current checkout, to undo, resume or compare branch runs.
+func (s *Sync) executeOnBranch() error {
+	branch := s.Branch
+	wt, err := repository.AddWorktree(s.DomainDir, branch)
+	if err != nil {
+		return fmt.Errorf("failed to check out branch %s > %w", branch, err)
+	}
+	defer func() {
+		if errRemove := wt.Remove(); errRemove != nil {
+			s.Log().Warn("failed to remove branch worktree", "path", wt.Dir, "error", errRemove)
+		}
+	}()
+
+	s.Term().Info().Printfln("Propagating versions on branch %s", branch)
+	s.Log().Debug("branch checked out", "branch", branch, "path", wt.Dir)
+
+	s.Branch = ""
+	s.branch = branch
+	s.DomainDir = wt.Dir
+	s.FromSources = true
+
+	err = s.Execute()
+	if s.result != nil {
+		s.result.Branch = branch
+	}
+	if err != nil || s.DryRun {
+		return err
+	}
+
+	hash, err := wt.Commit(repository.SyncMessage)
+	if err != nil {
+		return fmt.Errorf("failed to commit propagated versions to %s > %w", branch, err)
+	}
+	if hash.IsZero() {
+		s.Term().Info().Printfln("Nothing to commit to %s", branch)
+		return nil
+	}
+
+	s.result.BranchCommit = hash.String()
+	s.Term().Info().Printfln("Committed propagated versions to %s (%s)", branch, hash.String()[:7])
+
+	return nil
+}
+
+// stateDirFile returns location of the sync state file, given relative to the domain directory. Files of branch runs are
+// stored per branch, e.g. .plasmactl/branches/release/1.4/sync-journal.json.
+func (s *Sync) stateDirFile(file string) string {
+	if s.branch == "" {
+		return filepath.Join(s.stateDir, file)
+	}
+
+	return filepath.Join(s.stateDir, filepath.Dir(file), "branches", s.branch, filepath.Base(file))
+}
+
+// recordedPath returns the meta file path stored in sync state. Paths of branch runs are relative to the branch
+// worktree, which is checked out in a different temporary directory by each run.
+func (s *Sync) recordedPath(path string) string {
+	if s.branch == "" {
+		return path
+	}
+
+	rel, err := filepath.Rel(s.DomainDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+
+	return rel
+}
+
+// recordedPrefix returns directory of the component meta file stored in sync state, see journalPrefix.
+func (s *Sync) recordedPrefix(change JournalEntry) string {
+	prefix := journalPrefix(change, s.BuildDir)
+	if s.branch == "" || filepath.IsAbs(prefix) {
+		return prefix
+	}
+
+	return filepath.Join(s.DomainDir, prefix)
+}
+
+// cacheKey returns the path the repository timeline is cached by, the branch worktree is cached as the original domain.
+func (s *Sync) cacheKey(path string) string {
+	if s.branch != "" && path == s.DomainDir {
+		return s.stateDir
+	}
+
+	return path
+}
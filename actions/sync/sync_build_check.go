@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// StaleComponent is a build component which version doesn't match any composed domain or package.
+type StaleComponent struct {
+	Name         string   `json:"name"`
+	BuildVersion string   `json:"build_version,omitempty"`
+	Sources      []string `json:"sources"`
+}
+
+// checkBuild ensures build base versions match the version of one of domains or packages providing components,
+// as versions read from a build composed before sources changed lead to wrong propagation decisions.
+// Propagated versions (base-propagated) are compared by their base part.
+func (s *Sync) checkBuild(componentsMap map[string]*sync.OrderedMap[*sync.Component]) error {
+	sources := make(map[string][]string)
+	for namespace, components := range componentsMap {
+		for _, name := range components.Keys() {
+			sources[name] = append(sources[name], namespace)
+		}
+	}
+
+	var stale []StaleComponent
+	for name, namespaces := range sources {
+		sort.Strings(namespaces)
+		build, err := sync.NewComponent(name, s.BuildDir)
+		if err != nil {
+			return err
+		}
+
+		// Missing from build means compose wasn't re-run after the component was added.
+		buildVersion := ""
+		if build.IsValidComponent() {
+			buildVersion, _, _, err = build.GetBaseVersion(s.VersionFormat)
+			if err != nil {
+				return err
+			}
+		}
+
+		matched := false
+		var versions []string
+		for _, namespace := range namespaces {
+			c, _ := componentsMap[namespace].Get(name)
+			version, _, _, errVersion := c.GetBaseVersion(s.VersionFormat)
+			if errVersion != nil {
+				return errVersion
+			}
+
+			versions = append(versions, fmt.Sprintf("%s@%s", namespace, version))
+			if version == buildVersion && buildVersion != "" {
+				matched = true
+				break
+			}
+		}
+
+		if !matched {
+			stale = append(stale, StaleComponent{Name: name, BuildVersion: buildVersion, Sources: versions})
+		}
+	}
+
+	if len(stale) == 0 {
+		return nil
+	}
+
+	sort.Slice(stale, func(i, j int) bool {
+		return stale[i].Name < stale[j].Name
+	})
+	s.result.Stale = stale
+
+	names := make([]string, 0, len(stale))
+	for _, c := range stale {
+		names = append(names, c.Name)
+		build := c.BuildVersion
+		if build == "" {
+			build = "missing"
+		}
+		s.Log().Warn(s.warn(warning.StaleBuild, c.Name, "build version of %s doesn't match sources", c.Name), "component", c.Name, "build", build, "sources", strings.Join(c.Sources, ", "))
+	}
+
+	if !s.SkipBuildCheck {
+		return fmt.Errorf("build is stale for %d component(s): %s, run model:compose to update it or use --skip-build-check", len(stale), strings.Join(names, ", "))
+	}
+
+	s.Term().Warning().Printfln("Build is stale for %d component(s), propagation may be wrong: %s", len(stale), strings.Join(names, ", "))
+	return nil
+}
@@ -0,0 +1,226 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	async "sync"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// cacheFile is the timeline cache location relative to the domain directory.
+var cacheFile = filepath.Join(".plasmactl", "sync-cache.json")
+
+// timelineCache persists commits of component versions resolved from git history, per repository.
+// A repository cache stays valid while its HEAD only moves forward, so resolution is repeated only for components
+// which version or meta file changed since the cached HEAD.
+type timelineCache struct {
+	Repositories map[string]*repositoryCache `json:"repositories"`
+
+	path string
+	mx   async.Mutex
+}
+
+type repositoryCache struct {
+	Head        string                      `json:"head"`
+	TimeDepth   string                      `json:"time_depth,omitempty"`
+	BumpAuthors string                      `json:"bump_authors,omitempty"`
+	Components  map[string]*cachedComponent `json:"components"`
+	// History is walked history of the cached HEAD, latest first, so only later commits are walked on cache miss.
+	History []historyCommit `json:"history,omitempty"`
+
+	mx async.Mutex
+}
+
+type cachedComponent struct {
+	Version  string    `json:"version"`
+	MetaHash string    `json:"meta_hash"`
+	Commit   string    `json:"commit"`
+	Date     time.Time `json:"date"`
+	Author   string    `json:"author"`
+	Email    string    `json:"email,omitempty"`
+	Message  string    `json:"message,omitempty"`
+	Changes  []string  `json:"changes,omitempty"`
+}
+
+// loadTimelineCache reads cache file, an unreadable cache is discarded.
+func loadTimelineCache(path string) (*timelineCache, error) {
+	cache := &timelineCache{Repositories: make(map[string]*repositoryCache), path: path}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return cache, nil
+		}
+		return cache, err
+	}
+
+	if err = json.Unmarshal(data, cache); err != nil || cache.Repositories == nil {
+		cache.Repositories = make(map[string]*repositoryCache)
+		return cache, fmt.Errorf("discarding malformed cache %s > %w", path, err)
+	}
+
+	return cache, nil
+}
+
+// repository returns cache of the repository at the head commit.
+// Cache is reset if the cached HEAD isn't an ancestor of the head, e.g. after history rewrite,
+// or if time depth or bump authors differ, as history is grouped differently.
+func (t *timelineCache) repository(path, timeDepth, bumpAuthors string, repo *git.Repository, head *object.Commit) *repositoryCache {
+	if t == nil {
+		return nil
+	}
+
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	rc, ok := t.Repositories[key]
+	if ok && rc.TimeDepth == timeDepth && rc.Head != head.Hash.String() {
+		ok = false
+		if cached, errCommit := repo.CommitObject(plumbing.NewHash(rc.Head)); errCommit == nil {
+			ok, _ = cached.IsAncestor(head)
+		}
+	}
+
+	if !ok || rc.TimeDepth != timeDepth || rc.BumpAuthors != bumpAuthors {
+		rc = &repositoryCache{TimeDepth: timeDepth, BumpAuthors: bumpAuthors, Components: make(map[string]*cachedComponent)}
+		t.Repositories[key] = rc
+	}
+	rc.Head = head.Hash.String()
+
+	return rc
+}
+
+// save writes cache file.
+func (t *timelineCache) save() error {
+	if t == nil {
+		return nil
+	}
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(t.path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(t.path, data, 0600)
+}
+
+// get returns cached commit of the component version, if the version and meta file are unchanged.
+func (rc *repositoryCache) get(name, version, metaHash string) (*cachedComponent, bool) {
+	if rc == nil {
+		return nil, false
+	}
+
+	rc.mx.Lock()
+	defer rc.mx.Unlock()
+
+	c, ok := rc.Components[name]
+	if !ok || c.Version != version || c.MetaHash != metaHash {
+		return nil, false
+	}
+
+	return c, true
+}
+
+func (rc *repositoryCache) set(name string, c *cachedComponent) {
+	if rc == nil {
+		return
+	}
+
+	rc.mx.Lock()
+	defer rc.mx.Unlock()
+	rc.Components[name] = c
+}
+
+// history returns walked history of the cached HEAD.
+func (rc *repositoryCache) history() []historyCommit {
+	if rc == nil {
+		return nil
+	}
+
+	rc.mx.Lock()
+	defer rc.mx.Unlock()
+	return rc.History
+}
+
+func (rc *repositoryCache) setHistory(history []historyCommit) {
+	if rc == nil {
+		return
+	}
+
+	rc.mx.Lock()
+	defer rc.mx.Unlock()
+	rc.History = history
+}
+
+// commitsHistory collects commits groups of repository on first use, as cached components don't need them.
+type commitsHistory struct {
+	repo      *git.Repository
+	timeDepth string
+	authors   *repository.BumpAuthors
+	// shallow are parents of shallow clone boundary, missing from the repository.
+	shallow []plumbing.Hash
+	// cache holds history walked by previous runs, it's extended with commits since the cached HEAD.
+	cache *repositoryCache
+
+	once    async.Once
+	groups  *sync.OrderedMap[*CommitsGroup]
+	commits map[string]map[string]string
+	err     error
+}
+
+func (h *commitsHistory) load() (*sync.OrderedMap[*CommitsGroup], map[string]map[string]string, error) {
+	h.once.Do(func() {
+		h.groups, h.commits, h.err = h.collect()
+		if h.err != nil {
+			h.err = fmt.Errorf("collect components commits > %w", h.err)
+		}
+	})
+
+	return h.groups, h.commits, h.err
+}
+
+// collect groups commits of history from HEAD, walking only commits since the cached history.
+func (h *commitsHistory) collect() (*sync.OrderedMap[*CommitsGroup], map[string]map[string]string, error) {
+	ref, err := h.repo.Head()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	cached := h.cache.history()
+	known := make(map[plumbing.Hash]bool, len(cached))
+	for _, c := range cached {
+		known[plumbing.NewHash(c.Hash)] = true
+	}
+
+	walked, err := walkHistory(h.repo, ref.Hash(), h.timeDepth, h.authors, h.shallow, known)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	history := append(walked, cached...)
+	h.cache.setHistory(history)
+
+	groups, hashes := groupHistory(history, ref.Hash().String())
+	return groups, hashes, nil
+}
@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"path/filepath"
 	"runtime"
+	"strings"
 	async "sync"
 	"time"
 
@@ -18,6 +20,7 @@ import (
 
 	"github.com/plasmash/plasmactl-component/internal/repository"
 	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 var errRunBruteProcess = fmt.Errorf("run brute")
@@ -32,6 +35,10 @@ type CommitsGroup struct {
 	commit string
 	items  []string
 	date   time.Time
+
+	// changes caches subjects of group commits per component directory.
+	changes map[string][]string
+	mx      async.Mutex
 }
 
 func (s *Sync) populateTimelineComponents(components map[string]*sync.OrderedMap[*sync.Component], packagePathMap map[string]string) error {
@@ -63,7 +70,10 @@ func (s *Sync) populateTimelineComponents(components map[string]*sync.OrderedMap
 					path := domain["path"].(string)
 					pb := domain["pb"].(*pterm.ProgressbarPrinter)
 
-					if err := s.findComponentsChangeTime(ctx, components[name], path, &mx, pb); err != nil {
+					end := s.stage(stageTimelineComponents, name)
+					err := s.findComponentsChangeTime(ctx, components[name], path, &mx, pb)
+					end()
+					if err != nil {
 						select {
 						case errorChan <- fmt.Errorf("worker %d error processing %s: %w", workerID, name, err):
 							cancel()
@@ -125,22 +135,32 @@ func (s *Sync) populateTimelineComponents(components map[string]*sync.OrderedMap
 	return nil
 }
 
-func collectComponentsCommits(r *git.Repository, beforeDate string) (*sync.OrderedMap[*CommitsGroup], map[string]map[string]string, error) {
-	ref, err := r.Head()
+// collectCommitsFrom groups commits of history from the commit by bump commits, and maps version hashes of commits
+// to the commit and the group they belong to.
+func collectCommitsFrom(r *git.Repository, from plumbing.Hash, beforeDate string, authors *repository.BumpAuthors, shallow []plumbing.Hash) (*sync.OrderedMap[*CommitsGroup], map[string]map[string]string, error) {
+	history, err := walkHistory(r, from, beforeDate, authors, shallow, nil)
 	if err != nil {
-		return nil, nil, fmt.Errorf("can't get HEAD ref > %w", err)
+		return nil, nil, err
 	}
 
-	hashes := make(map[string]map[string]string)
-	var commits []string
-	var section string
-	var sectionName string
-	var sectionDate time.Time
+	groups, hashes := groupHistory(history, from.String())
+	return groups, hashes, nil
+}
+
+// historyCommit is a commit of walked history, bump commits start commits groups.
+type historyCommit struct {
+	Hash string    `json:"hash"`
+	Date time.Time `json:"date"`
+	Bump bool      `json:"bump,omitempty"`
+}
 
+// walkHistory returns commits of history from the commit, latest first, until the date. Known commits and their
+// history aren't walked.
+func walkHistory(r *git.Repository, from plumbing.Hash, beforeDate string, authors *repository.BumpAuthors, shallow []plumbing.Hash, known map[plumbing.Hash]bool) ([]historyCommit, error) {
 	// start from the latest commit and iterate to the past
-	cIter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	cIter, err := historyLog(r, from, shallow, known)
 	if err != nil {
-		return nil, nil, fmt.Errorf("git log error > %w", err)
+		return nil, fmt.Errorf("git log error > %w", err)
 	}
 
 	var before time.Time
@@ -148,46 +168,67 @@ func collectComponentsCommits(r *git.Repository, beforeDate string) (*sync.Order
 	if beforeDate != "" {
 		before, err = time.Parse(time.DateOnly, beforeDate)
 		if err != nil {
-			return nil, nil, fmt.Errorf("can't parse date %s, format should be %s > %w", beforeDate, time.DateOnly, err)
+			return nil, fmt.Errorf("can't parse date %s, format should be %s > %w", beforeDate, time.DateOnly, err)
 		}
 	}
 
-	groups := sync.NewOrderedMap[*CommitsGroup]()
-
+	var history []historyCommit
 	_ = cIter.ForEach(func(c *object.Commit) error {
 		if c.Author.When.Before(before) {
 			return storer.ErrStop
 		}
 
-		hash := c.Hash.String()
-		hash = hash[:13]
-		if _, ok := hashes[hash]; !ok {
-			hashes[hash] = make(map[string]string)
-			hashes[hash]["original"] = c.Hash.String()
-			hashes[hash]["section"] = ""
-		} else {
-			return fmt.Errorf("duplicate version hash %s during commits iteration", hash)
+		history = append(history, historyCommit{
+			Hash: c.Hash.String(),
+			Date: c.Author.When,
+			Bump: authors.Match(c.Author.Name, c.Author.Email),
+		})
+		return nil
+	})
+
+	return history, nil
+}
+
+// groupHistory groups commits of history from the commit by bump commits, and maps version hashes of commits
+// to the commit and the group they belong to.
+func groupHistory(history []historyCommit, from string) (*sync.OrderedMap[*CommitsGroup], map[string]map[string]string) {
+	hashes := make(map[string]map[string]string)
+	var commits []string
+	var section string
+	var sectionName string
+	var sectionDate time.Time
+
+	groups := sync.NewOrderedMap[*CommitsGroup]()
+
+	for _, c := range history {
+		hash := c.Hash[:13]
+		if _, ok := hashes[hash]; ok {
+			// Duplicate version hash, commits can't be told apart beyond it.
+			break
 		}
+		hashes[hash] = make(map[string]string)
+		hashes[hash]["original"] = c.Hash
+		hashes[hash]["section"] = ""
 
-		if ref.Hash() == c.Hash {
+		if from == c.Hash {
 			commits = []string{}
-			sectionDate = c.Author.When
-			if c.Author.Name == repository.Author {
-				section = c.Hash.String()
+			sectionDate = c.Date
+			if c.Bump {
+				section = c.Hash
 				sectionName = section
 				hashes[hash]["section"] = sectionName
 			} else {
-				section = ref.Hash().String()
+				section = from
 				sectionName = headGroupName
 				hashes[hash]["section"] = sectionName
-				commits = append(commits, c.Hash.String())
+				commits = append(commits, c.Hash)
 			}
 
-			return nil
+			continue
 		}
 
 		// create new group when bump commits appears and store previous one.
-		if c.Author.Name == repository.Author {
+		if c.Bump {
 			group := &CommitsGroup{
 				name:   sectionName,
 				commit: section,
@@ -197,17 +238,15 @@ func collectComponentsCommits(r *git.Repository, beforeDate string) (*sync.Order
 
 			groups.Set(section, group)
 
-			section = c.Hash.String()
-			sectionName = c.Hash.String()
-			sectionDate = c.Author.When
+			section = c.Hash
+			sectionName = c.Hash
+			sectionDate = c.Date
 			commits = []string{}
 		} else {
 			hashes[hash]["section"] = section
-			commits = append(commits, c.Hash.String())
+			commits = append(commits, c.Hash)
 		}
-
-		return nil
-	})
+	}
 
 	if _, ok := groups.Get(section); !ok {
 		group := &CommitsGroup{
@@ -220,7 +259,7 @@ func collectComponentsCommits(r *git.Repository, beforeDate string) (*sync.Order
 		groups.Set(section, group)
 	}
 
-	return groups, hashes, nil
+	return groups, hashes
 }
 
 func (s *Sync) findComponentsChangeTime(ctx context.Context, namespaceComponents *sync.OrderedMap[*sync.Component], gitPath string, mx *async.Mutex, p *pterm.ProgressbarPrinter) error {
@@ -229,11 +268,24 @@ func (s *Sync) findComponentsChangeTime(ctx context.Context, namespaceComponents
 		return fmt.Errorf("%s - %w", gitPath, err)
 	}
 
-	groups, commitsMap, err := collectComponentsCommits(repo, s.TimeDepth)
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	headCommit, err := repo.CommitObject(head.Hash())
 	if err != nil {
-		return fmt.Errorf("collect components commits > %w", err)
+		return fmt.Errorf("can't get HEAD commit object > %w", err)
 	}
 
+	shallow, err := s.prepareShallow(repo, gitPath)
+	if err != nil {
+		return err
+	}
+
+	cache := s.cache.repository(s.cacheKey(gitPath), s.TimeDepth, s.bumpAuthors.String(), repo, headCommit)
+	history := &commitsHistory{repo: repo, timeDepth: s.TimeDepth, authors: s.bumpAuthors, shallow: shallow, cache: cache}
+
 	var wg async.WaitGroup
 	errorChan := make(chan error, 1)
 	//maxWorkers := 3
@@ -250,7 +302,7 @@ func (s *Sync) findComponentsChangeTime(ctx context.Context, namespaceComponents
 					if !ok {
 						return
 					}
-					if err = s.processComponent(c, groups, commitsMap, repo, mx); err != nil {
+					if err = s.processComponent(c, history, cache, repo, mx); err != nil {
 						if p != nil {
 							_, _ = p.Stop()
 						}
@@ -295,7 +347,7 @@ func (s *Sync) findComponentsChangeTime(ctx context.Context, namespaceComponents
 	return nil
 }
 
-func (s *Sync) processComponent(component *sync.Component, commitsGroups *sync.OrderedMap[*CommitsGroup], commitsMap map[string]map[string]string, repo *git.Repository, mx *async.Mutex) error {
+func (s *Sync) processComponent(component *sync.Component, history *commitsHistory, cache *repositoryCache, repo *git.Repository, mx *async.Mutex) error {
 	buildComponent, err := sync.NewComponent(component.GetName(), s.BuildDir)
 	if err != nil {
 		return err
@@ -351,27 +403,49 @@ func (s *Sync) processComponent(component *sync.Component, commitsGroups *sync.O
 			return errors.New(msg)
 		}
 
-		s.Log().Warn(msg)
+		s.Log().Warn(s.warn(warning.Overridden, component.GetName(), "%s", msg))
 		overridden = true
 	} else {
 		versionHash.hash = headCommit.Hash.String()
 		versionHash.hashTime = headCommit.Author.When
 		versionHash.author = headCommit.Author.Name
+		versionHash.email = headCommit.Author.Email
+		versionHash.message = commitSubject(headCommit)
 	}
 
-	if !overridden {
+	var changes []string
+	if cached, ok := cache.get(component.GetName(), currentVersion, currentMetaHash); ok && !overridden {
+		s.Log().Debug("component version commit found in cache", "mrn", component.GetName(), "commit", cached.Commit)
+		versionHash.hash = cached.Commit
+		versionHash.hashTime = cached.Date
+		versionHash.author = cached.Author
+		versionHash.email = cached.Email
+		versionHash.message = cached.Message
+		changes = cached.Changes
+	} else if !overridden {
+		commitsGroups, commitsMap, errHistory := history.load()
+		if errHistory != nil {
+			return errHistory
+		}
+
 		// @todo rewrite to concurrent map ?
 		//mx.Lock()
 		item, ok := commitsMap[currentVersion]
 		//mx.Unlock()
-		if !ok {
-			s.Log().Warn(fmt.Sprintf("Latest version of `%s` doesn't match any existing commit", component.GetName()))
+		shallow := !ok && len(history.shallow) > 0
+		switch {
+		case shallow:
+			s.Log().Warn(s.warn(warning.Partial, component.GetName(), "Version commit of `%s` is beyond the shallow clone boundary, resolving it from meta file", component.GetName()))
+		case !ok:
+			s.Log().Warn(s.warn(warning.NotFound, component.GetName(), "Latest version of `%s` doesn't match any existing commit", component.GetName()))
 		}
 
 		var commit *object.Commit
 		var errProcess error
 
-		if len(item) == 0 {
+		if shallow {
+			commit, errProcess = resolveShallowVersion(repo, headCommit, componentMetaPath, currentVersion)
+		} else if len(item) == 0 {
 			commit, errProcess = s.processUnknownSection(commitsGroups, componentMetaPath, currentVersion, repo, currentMetaHash)
 		} else {
 			group, okSection := commitsGroups.Get(item["section"])
@@ -397,6 +471,29 @@ func (s *Sync) processComponent(component *sync.Component, commitsGroups *sync.O
 		versionHash.hash = commit.Hash.String()
 		versionHash.hashTime = commit.Author.When
 		versionHash.author = commit.Author.Name
+		versionHash.email = commit.Author.Email
+		versionHash.message = commitSubject(commit)
+
+		if group, ok := commitsGroups.Get(versionHash.hash); ok && group.name != headGroupName {
+			changes, err = group.componentChanges(repo, filepath.Dir(filepath.Dir(componentMetaPath)))
+			if err != nil {
+				return fmt.Errorf("collect changes of %s > %w", component.GetName(), err)
+			}
+		}
+
+		// Resolution from meta file is approximate, it is repeated once complete history is available.
+		if !shallow {
+			cache.set(component.GetName(), &cachedComponent{
+				Version:  currentVersion,
+				MetaHash: currentMetaHash,
+				Commit:   versionHash.hash,
+				Date:     versionHash.hashTime,
+				Author:   versionHash.author,
+				Email:    versionHash.email,
+				Message:  versionHash.message,
+				Changes:  changes,
+			})
+		}
 	}
 
 	mx.Lock()
@@ -409,11 +506,21 @@ func (s *Sync) processComponent(component *sync.Component, commitsGroups *sync.O
 		slog.Time("date", versionHash.hashTime),
 	)
 
-	if versionHash.author != repository.Author && versionHash.author != buildHackAuthor {
-		s.Log().Warn(fmt.Sprintf("Latest commit of %s is not a bump commit", component.GetName()))
+	if versionHash.author != buildHackAuthor && !s.bumpAuthors.Match(versionHash.author, versionHash.email) {
+		s.Log().Warn(s.warn(warning.ManualVersion, component.GetName(), "Latest commit of %s is not a bump commit, run component:lint --manual-versions for details", component.GetName()))
+	}
+
+	if overridden {
+		s.result.Overridden = append(s.result.Overridden, OverriddenResource{
+			Name:       component.GetName(),
+			Type:       overrideTypeComponent,
+			BuildValue: currentVersion,
+			HeadValue:  headVersion,
+		})
 	}
 
 	tci := sync.NewTimelineComponentsItem(currentVersion, versionHash.hash, versionHash.hashTime, s.Term())
+	tci.SetMessages(versionHash.message, changes)
 	tci.AddComponent(component)
 
 	s.timeline = sync.AddToTimeline(s.timeline, tci)
@@ -649,6 +756,81 @@ func (s *Sync) processBumpSection(group *CommitsGroup, componentMetaPath, curren
 	return sectionCommit, nil
 }
 
+// componentChanges returns subjects of group commits which modified files in component directory.
+func (g *CommitsGroup) componentChanges(repo *git.Repository, componentDir string) ([]string, error) {
+	g.mx.Lock()
+	defer g.mx.Unlock()
+
+	if changes, ok := g.changes[componentDir]; ok {
+		return changes, nil
+	}
+
+	changes := make([]string, 0)
+	for _, item := range g.items {
+		c, err := repo.CommitObject(plumbing.NewHash(item))
+		if err != nil {
+			return nil, fmt.Errorf("can't get commit object %s > %w", item, err)
+		}
+
+		modified, err := isDirModified(c, componentDir)
+		if err != nil {
+			return nil, err
+		}
+
+		if modified {
+			changes = append(changes, commitSubject(c))
+		}
+	}
+
+	if g.changes == nil {
+		g.changes = make(map[string][]string)
+	}
+	g.changes[componentDir] = changes
+
+	return changes, nil
+}
+
+// isDirModified checks if commit changed files in directory comparing to its first parent.
+func isDirModified(c *object.Commit, dir string) (bool, error) {
+	tree, err := c.Tree()
+	if err != nil {
+		return false, fmt.Errorf("can't get tree of commit %s > %w", c.Hash, err)
+	}
+
+	parentTree := &object.Tree{}
+	if c.NumParents() > 0 {
+		parent, errParent := c.Parent(0)
+		if errParent != nil {
+			return false, fmt.Errorf("can't get parent of commit %s > %w", c.Hash, errParent)
+		}
+
+		parentTree, err = parent.Tree()
+		if err != nil {
+			return false, fmt.Errorf("can't get tree of commit %s > %w", parent.Hash, err)
+		}
+	}
+
+	diff, err := object.DiffTree(parentTree, tree)
+	if err != nil {
+		return false, fmt.Errorf("can't diff commit %s > %w", c.Hash, err)
+	}
+
+	prefix := filepath.ToSlash(dir) + "/"
+	for _, ch := range diff {
+		if strings.HasPrefix(ch.From.Name, prefix) || strings.HasPrefix(ch.To.Name, prefix) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// commitSubject returns the first line of commit message.
+func commitSubject(c *object.Commit) string {
+	subject, _, _ := strings.Cut(strings.TrimSpace(c.Message), "\n")
+	return strings.TrimSpace(subject)
+}
+
 func getFileHashFromCommit(c *object.Commit, path string) (string, *object.File, error) {
 	file, err := c.File(path)
 	if err != nil {
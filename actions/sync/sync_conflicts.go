@@ -0,0 +1,151 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pterm/pterm"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// Conflict strategies, applied when component and variable changes propagate different versions to a component.
+const (
+	// ConflictNewest keeps the version of the newest change and reports the conflict.
+	ConflictNewest = "newest"
+	// ConflictError fails the sync.
+	ConflictError = "error"
+	// ConflictInteractive asks the operator to choose the version.
+	ConflictInteractive = "interactive"
+)
+
+var conflictStrategies = []string{ConflictNewest, ConflictError, ConflictInteractive}
+
+// VersionClaim is a version propagated to a component by a timeline item.
+type VersionClaim struct {
+	Type    string    `json:"type" yaml:"type"`
+	Version string    `json:"version" yaml:"version"`
+	Commit  string    `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Date    time.Time `json:"date" yaml:"date"`
+}
+
+// VersionConflict is a component propagated different versions by component and variable changes, with the
+// claims from the newest to the oldest and the version chosen by the conflict strategy.
+type VersionConflict struct {
+	Component string         `json:"component" yaml:"component"`
+	Claims    []VersionClaim `json:"claims" yaml:"claims"`
+	Chosen    string         `json:"chosen" yaml:"chosen"`
+}
+
+// recordVersionClaim keeps the timeline item which would propagate to a component already propagated by a newer
+// item of the other type, with a different version.
+func (s *Sync) recordVersionClaim(name string, item sync.TimelineItem) {
+	newest, ok := s.propagatedBy[name]
+	if !ok || timelineType(newest) == timelineType(item) || newest.GetVersion() == item.GetVersion() {
+		return
+	}
+
+	for _, claim := range s.versionClaims[name] {
+		if claim.GetVersion() == item.GetVersion() {
+			return
+		}
+	}
+
+	s.versionClaims[name] = append(s.versionClaims[name], item)
+}
+
+// resolveVersionConflicts applies the conflict strategy to the recorded claims and reports conflicts in the result.
+func (s *Sync) resolveVersionConflicts(componentVersionMap map[string]string) error {
+	names := make([]string, 0, len(s.versionClaims))
+	for name := range s.versionClaims {
+		// Propagation may be removed by a change of the component itself.
+		if _, ok := s.propagatedBy[name]; ok {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	s.Term().Warning().Printfln("Components propagated different versions by component and variable changes:")
+	for _, name := range names {
+		items := append([]sync.TimelineItem{s.propagatedBy[name]}, s.versionClaims[name]...)
+		conflict := VersionConflict{Component: name, Chosen: items[0].GetVersion()}
+		for _, item := range items {
+			conflict.Claims = append(conflict.Claims, VersionClaim{
+				Type:    timelineType(item),
+				Version: item.GetVersion(),
+				Commit:  item.GetCommit(),
+				Date:    item.GetDate(),
+			})
+		}
+
+		if s.ConflictStrategy == ConflictInteractive {
+			chosen, err := s.chooseClaim(conflict)
+			if err != nil {
+				return err
+			}
+			conflict.Chosen = items[chosen].GetVersion()
+			componentVersionMap[name] = items[chosen].GetVersion()
+			s.propagatedBy[name] = items[chosen]
+		}
+
+		s.result.VersionConflicts = append(s.result.VersionConflicts, conflict)
+		s.Term().Println(s.warn(warning.Conflict, name, "- %s: %s, %s chosen", name, formatClaims(conflict.Claims), conflict.Chosen))
+	}
+
+	if s.ConflictStrategy == ConflictError {
+		return fmt.Errorf("%d component(s) propagated conflicting versions, see --conflict-strategy", len(names))
+	}
+
+	return nil
+}
+
+// chooseClaim asks the operator which claim of the conflict to propagate and returns its index.
+func (s *Sync) chooseClaim(conflict VersionConflict) (int, error) {
+	options := make([]string, 0, len(conflict.Claims))
+	for _, claim := range conflict.Claims {
+		options = append(options, formatClaim(claim))
+	}
+
+	selected, err := pterm.DefaultInteractiveSelect.
+		WithOptions(options).
+		WithDefaultOption(options[0]).
+		Show(fmt.Sprintf("Select version to propagate to %s", conflict.Component))
+	if err != nil {
+		return 0, fmt.Errorf("select version of %s > %w", conflict.Component, err)
+	}
+
+	for i, option := range options {
+		if option == selected {
+			return i, nil
+		}
+	}
+
+	return 0, nil
+}
+
+func formatClaim(claim VersionClaim) string {
+	return fmt.Sprintf("%s (%s, %s)", claim.Version, claim.Type, claim.Date.Format(time.DateOnly))
+}
+
+func formatClaims(claims []VersionClaim) string {
+	formatted := make([]string, 0, len(claims))
+	for _, claim := range claims {
+		formatted = append(formatted, formatClaim(claim))
+	}
+
+	return strings.Join(formatted, " vs ")
+}
+
+func timelineType(item sync.TimelineItem) string {
+	if _, ok := item.(*sync.TimelineVariablesItem); ok {
+		return timelineTypeVariables
+	}
+
+	return timelineTypeComponents
+}
@@ -0,0 +1,170 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// ConstraintViolation is a dependency of a propagated component which version doesn't satisfy the version
+// constraint declared by the component, or can't be compared with it.
+type ConstraintViolation struct {
+	Component  string `json:"component"`
+	Dependency string `json:"dependency"`
+	Constraint string `json:"constraint"`
+	Version    string `json:"version"`
+	Message    string `json:"message,omitempty"`
+}
+
+// checkConstraints verifies dependencies of propagated components satisfy the version constraints of their
+// dependencies files. Dependencies are checked with their base version in the build, hashes are ordered by history
+// of the domains and packages. Violations fail the sync, unless SkipConstraints reports them as warnings.
+func (s *Sync) checkConstraints(buildInv *sync.Inventory, componentVersionMap map[string]string) error {
+	names := make([]string, 0, len(componentVersionMap))
+	for name := range componentVersionMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ancestor := s.versionAncestry()
+	for _, name := range names {
+		constraints := buildInv.GetConstraints(name)
+		deps := make([]string, 0, len(constraints))
+		for dep := range constraints {
+			deps = append(deps, dep)
+		}
+		sort.Strings(deps)
+
+		for _, dep := range deps {
+			violation := ConstraintViolation{Component: name, Dependency: dep, Constraint: string(constraints[dep])}
+			satisfied, err := s.satisfiesConstraint(&violation, ancestor)
+			if err != nil {
+				violation.Message = err.Error()
+			} else if satisfied {
+				continue
+			}
+			s.result.ConstraintViolations = append(s.result.ConstraintViolations, violation)
+		}
+	}
+
+	violations := s.result.ConstraintViolations
+	if len(violations) == 0 {
+		return nil
+	}
+
+	for _, v := range violations {
+		msg := fmt.Sprintf("- %s requires %s %s, build has %s", v.Component, v.Dependency, v.Constraint, component.FormatVersion(v.Version))
+		if v.Message != "" {
+			msg += ": " + v.Message
+		}
+		if s.SkipConstraints {
+			s.Term().Warning().Println(s.warn(warning.Constraint, v.Component, "%s", msg))
+		} else {
+			s.Term().Error().Println(msg)
+		}
+	}
+	if s.SkipConstraints {
+		return nil
+	}
+
+	return fmt.Errorf("%d dependency version(s) don't satisfy constraints of propagated components, use --skip-constraints to propagate anyway", len(violations))
+}
+
+// satisfiesConstraint reads the build base version of the dependency of the violation and checks it against the
+// constraint.
+func (s *Sync) satisfiesConstraint(v *ConstraintViolation, ancestor sync.AncestorFunc) (bool, error) {
+	c, err := sync.NewComponent(v.Dependency, s.BuildDir)
+	if err != nil {
+		return false, err
+	}
+
+	version, _, debug, err := c.GetBaseVersion(s.VersionFormat)
+	for _, d := range debug {
+		s.Log().Debug("error", "message", d)
+	}
+	if err != nil {
+		return false, err
+	}
+	v.Version = version
+	if version == "" {
+		return false, fmt.Errorf("%s has no version", v.Dependency)
+	}
+
+	return sync.VersionConstraint(v.Constraint).Satisfied(version, ancestor)
+}
+
+// versionAncestry orders hashes by history of the domains and packages: a version precedes another one when its
+// commit is an ancestor of the other one in a repository having both. Repositories are opened on first use.
+func (s *Sync) versionAncestry() sync.AncestorFunc {
+	var repos []*git.Repository
+	opened := false
+
+	return func(version, other string) (bool, error) {
+		if !opened {
+			opened = true
+			repos = s.namespaceRepositories()
+		}
+
+		for _, r := range repos {
+			a, b := resolveCommit(r, version), resolveCommit(r, other)
+			if a == nil || b == nil {
+				continue
+			}
+			return a.IsAncestor(b)
+		}
+
+		return false, fmt.Errorf("commits of %s and %s aren't both found in the domain or packages history", version, other)
+	}
+}
+
+// namespaceRepositories opens repositories of the domain and of the packages and domains providing components.
+func (s *Sync) namespaceRepositories() []*git.Repository {
+	paths := []string{s.DomainDir}
+	if _, packagePathMap, err := s.namespaces(); err == nil {
+		for _, path := range packagePathMap {
+			paths = append(paths, path)
+		}
+	}
+
+	var repos []*git.Repository
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil || seen[abs] {
+			continue
+		}
+		seen[abs] = true
+
+		r, err := git.PlainOpenWithOptions(path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+		if err != nil {
+			s.Log().Debug("repository can't order versions", "path", path, "error", err)
+			continue
+		}
+		repos = append(repos, r)
+	}
+
+	return repos
+}
+
+// resolveCommit returns the commit of the hash, which may be abbreviated, nil if the repository doesn't have it.
+func resolveCommit(r *git.Repository, hash string) *object.Commit {
+	h, err := r.ResolveRevision(plumbing.Revision(hash))
+	if err != nil {
+		return nil
+	}
+
+	c, err := r.CommitObject(*h)
+	if err != nil {
+		return nil
+	}
+
+	return c
+}
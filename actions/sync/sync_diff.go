@@ -0,0 +1,126 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// lastPlanFile is the location of the latest applied propagation plan relative to the domain directory.
+var lastPlanFile = filepath.Join(".plasmactl", "sync-last-plan.json")
+
+// PlanVersion is a component with the version propagated to it.
+type PlanVersion struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// PlanVersionChange is a component propagated to a different version than in the previous run.
+type PlanVersionChange struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// PlanDiff compares propagation plan with the latest applied one.
+type PlanDiff struct {
+	Added   []PlanVersion       `json:"added"`
+	Removed []PlanVersion       `json:"removed"`
+	Changed []PlanVersionChange `json:"changed"`
+}
+
+// Empty reports whether plans propagate the same versions.
+func (d *PlanDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+func (s *Sync) lastPlanPath() string {
+	return s.stateDirFile(lastPlanFile)
+}
+
+// keepsPlan reports whether the plan is stored as the latest applied one.
+func (s *Sync) keepsPlan() bool {
+	return !s.DryRun && len(s.Simulate) == 0
+}
+
+// saveLastPlan stores the applied propagation plan, to be compared with --diff-last.
+func (s *Sync) saveLastPlan(plan *PropagationPlan) error {
+	data, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := s.lastPlanPath()
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// diffLastPlan compares versions propagated by the plan with the latest applied plan and prints the difference.
+func (s *Sync) diffLastPlan(plan *PropagationPlan) error {
+	path := s.lastPlanPath()
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("no applied propagation plan in %s, run component:sync first", path)
+		}
+		return err
+	}
+
+	var last PropagationPlan
+	if err = json.Unmarshal(data, &last); err != nil {
+		return fmt.Errorf("malformed propagation plan %s > %w", path, err)
+	}
+
+	diff := comparePlans(&last, plan)
+	s.result.PlanDiff = diff
+
+	if diff.Empty() {
+		s.Term().Success().Println("Propagation is the same as in the previous run")
+		return nil
+	}
+
+	s.Term().Info().Printfln("Propagation differs from the previous run:")
+	for _, c := range diff.Added {
+		s.Term().Printfln("+ %s: %s", c.Name, c.Version)
+	}
+	for _, c := range diff.Removed {
+		s.Term().Printfln("- %s: %s", c.Name, c.Version)
+	}
+	for _, c := range diff.Changed {
+		s.Term().Printfln("~ %s: %s -> %s", c.Name, c.OldVersion, c.NewVersion)
+	}
+
+	return nil
+}
+
+// comparePlans returns components propagated by only one of plans or to different versions, sorted by name.
+func comparePlans(last, current *PropagationPlan) *PlanDiff {
+	diff := &PlanDiff{Added: []PlanVersion{}, Removed: []PlanVersion{}, Changed: []PlanVersionChange{}}
+	for name, version := range current.Versions {
+		lastVersion, ok := last.Versions[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, PlanVersion{Name: name, Version: version})
+		case lastVersion != version:
+			diff.Changed = append(diff.Changed, PlanVersionChange{Name: name, OldVersion: lastVersion, NewVersion: version})
+		}
+	}
+
+	for name, version := range last.Versions {
+		if _, ok := current.Versions[name]; !ok {
+			diff.Removed = append(diff.Removed, PlanVersion{Name: name, Version: version})
+		}
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].Name < diff.Added[j].Name })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].Name < diff.Removed[j].Name })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].Name < diff.Changed[j].Name })
+
+	return diff
+}
@@ -0,0 +1,35 @@
+package sync
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// resolveFreezeWindows keeps freeze windows active at the moment and the chassis sections of build components,
+// so propagation skips components under a window.
+func (s *Sync) resolveFreezeWindows() error {
+	s.freezeWindows = s.FreezeWindows.Active(time.Now())
+	s.attachedTo = nil
+	if len(s.freezeWindows) == 0 {
+		return nil
+	}
+
+	attachments, err := component.LoadAttachments(filepath.Dir(s.BuildDir), "")
+	if err != nil {
+		return fmt.Errorf("failed to load attachments > %w", err)
+	}
+
+	s.attachedTo = make(map[string][]string, len(attachments))
+	for _, a := range attachments {
+		s.attachedTo[a.Component] = append(s.attachedTo[a.Component], a.Chassis)
+	}
+
+	for _, w := range s.freezeWindows {
+		s.Log().Info("freeze window is active", "window", w.String(), "chassis", w.Chassis)
+	}
+
+	return nil
+}
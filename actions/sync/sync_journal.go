@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// journalFile is the sync journal location relative to the domain directory.
+var journalFile = filepath.Join(".plasmactl", "sync-journal.json")
+
+// maxJournalRuns is the number of sync runs kept in journal, older runs can't be undone.
+const maxJournalRuns = 20
+
+// JournalEntry is a component version change applied by sync.
+type JournalEntry struct {
+	Component  string `json:"component"`
+	MetaPath   string `json:"meta_path"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// JournalRun holds version changes applied by a sync run.
+type JournalRun struct {
+	Date    time.Time      `json:"date"`
+	Changes []JournalEntry `json:"changes"`
+}
+
+// journal is the list of recorded sync runs, latest last.
+type journal struct {
+	Runs []JournalRun `json:"runs"`
+}
+
+func (s *Sync) journalPath() string {
+	return s.stateDirFile(journalFile)
+}
+
+func loadJournal(path string) (*journal, error) {
+	j := &journal{}
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return j, nil
+		}
+		return nil, err
+	}
+
+	if err = json.Unmarshal(data, j); err != nil {
+		return nil, fmt.Errorf("malformed sync journal %s > %w", path, err)
+	}
+
+	return j, nil
+}
+
+func (j *journal) save(path string) error {
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// journalChange records component version change applied to build, or to domain with --from-sources.
+func (s *Sync) journalChange(c *sync.Component, oldVersion, newVersion string) {
+	s.applied = append(s.applied, JournalEntry{
+		Component:  c.GetName(),
+		MetaPath:   s.recordedPath(c.MetaPath()),
+		OldVersion: oldVersion,
+		NewVersion: newVersion,
+	})
+}
+
+// writeJournal appends version changes applied by the run to journal, so they can be undone.
+// It is called even if propagation failed midway, to undo changes applied before the failure.
+func (s *Sync) writeJournal() error {
+	if len(s.applied) == 0 {
+		return nil
+	}
+
+	path := s.journalPath()
+	j, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	j.Runs = append(j.Runs, JournalRun{Date: time.Now().UTC(), Changes: s.applied})
+	if len(j.Runs) > maxJournalRuns {
+		j.Runs = j.Runs[len(j.Runs)-maxJournalRuns:]
+	}
+
+	if err = j.save(path); err != nil {
+		return fmt.Errorf("failed to write sync journal > %w", err)
+	}
+
+	s.applied = nil
+	return nil
+}
+
+// undo restores versions changed by the latest journaled run and removes it from journal.
+// Components which version was changed since the run are left untouched.
+func (s *Sync) undo() error {
+	path := s.journalPath()
+	j, err := loadJournal(path)
+	if err != nil {
+		return err
+	}
+
+	if len(j.Runs) == 0 {
+		s.Term().Warning().Println(s.warn(warning.Empty, "", "No sync run to undo in %s", path))
+		return nil
+	}
+
+	run := j.Runs[len(j.Runs)-1]
+	s.Term().Info().Printfln("Undoing sync run of %s (%d change(s))", run.Date.Format(time.RFC3339), len(run.Changes))
+
+	var modified []string
+	for i := len(run.Changes) - 1; i >= 0; i-- {
+		change := run.Changes[i]
+		c, errComponent := sync.NewComponent(change.Component, s.recordedPrefix(change))
+		if errComponent != nil {
+			return errComponent
+		}
+
+		currentVersion, debug, errVersion := c.GetVersion()
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if errVersion != nil {
+			return errVersion
+		}
+
+		if currentVersion != change.NewVersion {
+			modified = append(modified, change.Component)
+			s.Term().Warning().Println(s.warn(warning.Skipped, change.Component, "- skip %s (version %s was changed to %s since sync)", change.Component, change.NewVersion, currentVersion))
+			continue
+		}
+
+		s.result.Components = append(s.result.Components, SyncedComponent{
+			Name:       change.Component,
+			OldVersion: currentVersion,
+			NewVersion: change.OldVersion,
+		})
+		s.Term().Printfln("- %s: %s -> %s", change.Component, currentVersion, change.OldVersion)
+		if s.DryRun {
+			if err = s.diffVersion(c, change.OldVersion, true); err != nil {
+				return err
+			}
+			continue
+		}
+
+		debug, errUpdate := c.UpdateVersion(change.OldVersion)
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if errUpdate != nil {
+			return errUpdate
+		}
+	}
+
+	if s.DryRun {
+		s.Term().Info().Printfln("Dry-run: %d component(s) would be restored", len(s.result.Components))
+		return s.writePatch()
+	}
+
+	j.Runs = j.Runs[:len(j.Runs)-1]
+	if err = j.save(path); err != nil {
+		return fmt.Errorf("failed to write sync journal > %w", err)
+	}
+
+	if len(modified) > 0 {
+		return fmt.Errorf("restored %d component(s), %d component(s) modified since sync were skipped", len(s.result.Components), len(modified))
+	}
+
+	s.Term().Success().Printfln("Restored %d component(s)", len(s.result.Components))
+	return nil
+}
+
+// journalPrefix returns directory of the journaled component meta file, which is either build or domain.
+func journalPrefix(change JournalEntry, buildDir string) string {
+	parts := strings.Split(change.Component, ".")
+	if len(parts) != 3 {
+		return buildDir
+	}
+
+	for _, layout := range sync.Layouts {
+		meta := filepath.Join(layout.Dir(parts[0], parts[1], parts[2]), "meta", "plasma.yaml")
+		if strings.HasSuffix(change.MetaPath, meta) {
+			return filepath.Clean(strings.TrimSuffix(change.MetaPath, meta))
+		}
+	}
+
+	return buildDir
+}
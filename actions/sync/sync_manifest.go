@@ -0,0 +1,93 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/plasmash/plasmactl-component/internal/release"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// restoreFromManifest sets versions of build components to values recorded in release manifest.
+func (s *Sync) restoreFromManifest() error {
+	m, err := release.Load(s.FromManifest)
+	if err != nil {
+		return err
+	}
+
+	s.Term().Info().Printfln("Restoring versions from manifest %s (%s)", m.Ref, m.Commit)
+
+	s.Log().Info("Initializing build inventory")
+	inv, err := sync.NewInventory(s.BuildDir, s.Log())
+	if err != nil {
+		return err
+	}
+
+	componentsMap := inv.GetComponentsMap()
+	var missing []string
+	for _, mc := range m.Components {
+		c, ok := componentsMap.Get(mc.Name)
+		if !ok {
+			missing = append(missing, mc.Name)
+			continue
+		}
+
+		currentVersion, debug, errVersion := c.GetVersion()
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if errVersion != nil {
+			return errVersion
+		}
+
+		if currentVersion == mc.Version {
+			continue
+		}
+
+		s.result.Components = append(s.result.Components, SyncedComponent{
+			Name:       mc.Name,
+			OldVersion: currentVersion,
+			NewVersion: mc.Version,
+			Commit:     mc.Commit,
+		})
+		s.Term().Printfln("- %s: %s -> %s", mc.Name, currentVersion, mc.Version)
+		if s.DryRun {
+			if err = s.diffVersion(c, mc.Version, true); err != nil {
+				return err
+			}
+			continue
+		}
+
+		debug, errUpdate := c.UpdateVersion(mc.Version)
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if errUpdate != nil {
+			return errUpdate
+		}
+		s.journalChange(c, currentVersion, mc.Version)
+	}
+
+	sort.Strings(missing)
+	for _, name := range missing {
+		s.Term().Warning().Println(s.warn(warning.NotFound, name, "Component %s from manifest not found in build", name))
+	}
+
+	if len(s.result.Components) == 0 {
+		s.Term().Printfln("All components already match the manifest")
+		return nil
+	}
+
+	if s.DryRun {
+		s.Term().Info().Printfln("Dry-run: %d component(s) would be restored", len(s.result.Components))
+		return s.writePatch()
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("restored %d component(s), %d component(s) from manifest are missing in build", len(s.result.Components), len(missing))
+	}
+
+	s.Term().Success().Printfln("Restored %d component(s)", len(s.result.Components))
+	return nil
+}
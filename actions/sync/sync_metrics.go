@@ -0,0 +1,108 @@
+package sync
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of sync spans.
+const tracerName = "github.com/plasmash/plasmactl-component/actions/sync"
+
+// Sync stages.
+const (
+	stageSync               = "sync"
+	stageInventory          = "inventory"
+	stageComponentsUsage    = "components-usage"
+	stageVariablesUsage     = "variables-usage"
+	stageComponentsMap      = "components-map"
+	stageBuildCheck         = "build-check"
+	stageTimelineComponents = "timeline-components"
+	stageTimelineVariables  = "timeline-variables"
+	stagePropagationMap     = "propagation-map"
+	stageApply              = "apply"
+	stageVerify             = "verify"
+)
+
+// Sync counters.
+const (
+	counterComponents     = "components"
+	counterTimelineItems  = "timeline_items"
+	counterVariablesFiles = "variables_files"
+	counterPropagated     = "propagated"
+	counterWritten        = "written"
+)
+
+// StageMetric is the duration of a sync stage, per package or domain for timeline stages. Stages are listed
+// in order of completion.
+type StageMetric struct {
+	Name       string `json:"name"`
+	Source     string `json:"source,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+// SyncMetrics is the timing breakdown of sync stages and counters of processed items.
+type SyncMetrics struct {
+	DurationMs int64          `json:"duration_ms"`
+	Stages     []StageMetric  `json:"stages"`
+	Counters   map[string]int `json:"counters"`
+}
+
+// startTrace starts the root span of the sync, spans are recorded by the OpenTelemetry tracer provider
+// registered globally, if any.
+func (s *Sync) startTrace() func() {
+	start := time.Now()
+	s.metrics = &SyncMetrics{Stages: []StageMetric{}, Counters: make(map[string]int)}
+	s.result.Metrics = s.metrics
+
+	var span trace.Span
+	s.traceCtx, span = otel.Tracer(tracerName).Start(context.Background(), stageSync)
+	return func() {
+		s.metricsMx.Lock()
+		defer s.metricsMx.Unlock()
+
+		s.metrics.DurationMs = time.Since(start).Milliseconds()
+		for name, value := range s.metrics.Counters {
+			span.SetAttributes(attribute.Int(name, value))
+		}
+		span.End()
+	}
+}
+
+// stage measures a sync stage until the returned function is called. Source is the package or domain
+// processed by the stage, if any.
+func (s *Sync) stage(name, source string) func() {
+	if s.metrics == nil {
+		return func() {}
+	}
+
+	start := time.Now()
+	_, span := otel.Tracer(tracerName).Start(s.traceCtx, name)
+	if source != "" {
+		span.SetAttributes(attribute.String("source", source))
+	}
+
+	return func() {
+		duration := time.Since(start)
+		span.End()
+		s.Log().Debug("sync stage finished", "stage", name, "source", source, "duration", duration)
+
+		s.metricsMx.Lock()
+		defer s.metricsMx.Unlock()
+		s.metrics.Stages = append(s.metrics.Stages, StageMetric{Name: name, Source: source, DurationMs: duration.Milliseconds()})
+	}
+}
+
+// count adds n to the sync counter.
+func (s *Sync) count(name string, n int) {
+	if s.metrics == nil {
+		return
+	}
+
+	s.metricsMx.Lock()
+	defer s.metricsMx.Unlock()
+	s.metrics.Counters[name] += n
+}
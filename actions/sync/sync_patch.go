@@ -0,0 +1,78 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/unidiff"
+)
+
+// diffVersion prints the unified diff of component meta file updated to the version, in dry-run mode, and keeps
+// it for the patch file. Paths are relative to the domain directory, so the patch is applied there with `git apply`.
+func (s *Sync) diffVersion(c *sync.Component, version string, show bool) error {
+	before, after, debug, err := c.RenderVersion(version)
+	for _, d := range debug {
+		s.Log().Debug("error", "message", d)
+	}
+	if err != nil {
+		return err
+	}
+
+	path, err := s.patchPath(c.MetaPath())
+	if err != nil {
+		return err
+	}
+
+	diff := unidiff.Diff(path, before, after)
+	if diff == "" {
+		return nil
+	}
+
+	if show {
+		for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+			s.Term().Printfln("    %s", line)
+		}
+	}
+	s.patch.WriteString(diff)
+
+	return nil
+}
+
+func (s *Sync) patchPath(path string) (string, error) {
+	domain, err := filepath.Abs(s.DomainDir)
+	if err != nil {
+		return "", err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	rel, err := filepath.Rel(domain, abs)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.ToSlash(rel), nil
+}
+
+// writePatch writes the diffs of the dry-run to the patch file if requested.
+func (s *Sync) writePatch() error {
+	if s.Patch == "" {
+		return nil
+	}
+
+	if s.patch.Len() == 0 {
+		s.Term().Info().Printfln("No change to write to %s", s.Patch)
+		return nil
+	}
+
+	if err := os.WriteFile(s.Patch, []byte(s.patch.String()), 0600); err != nil {
+		return fmt.Errorf("failed to write patch > %w", err)
+	}
+
+	s.Term().Info().Printfln("Patch written to %s, apply it with: git apply %s", s.Patch, s.Patch)
+	return nil
+}
@@ -0,0 +1,192 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// Report formats.
+const (
+	ReportJSON = "json"
+	ReportYAML = "yaml"
+)
+
+const (
+	timelineTypeComponents = "components"
+	timelineTypeVariables  = "variables"
+)
+
+// PlanItem is a timeline item of the propagation plan.
+type PlanItem struct {
+	Type       string    `json:"type" yaml:"type"`
+	Version    string    `json:"version" yaml:"version"`
+	Commit     string    `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Date       time.Time `json:"date" yaml:"date"`
+	Message    string    `json:"message,omitempty" yaml:"message,omitempty"`
+	Components []string  `json:"components,omitempty" yaml:"components,omitempty"`
+	Variables  []string  `json:"variables,omitempty" yaml:"variables,omitempty"`
+}
+
+// SkippedComponent is a component excluded from propagation.
+type SkippedComponent struct {
+	Name   string `json:"name" yaml:"name"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// PlanConflict is a component provided by several packages or domains, with namespaces kept after resolution.
+type PlanConflict struct {
+	Component  string   `json:"component" yaml:"component"`
+	Namespaces []string `json:"namespaces" yaml:"namespaces"`
+	Kept       []string `json:"kept" yaml:"kept"`
+}
+
+// PropagationPlan is the propagation computed by sync before components are updated.
+type PropagationPlan struct {
+	Timeline        []PlanItem         `json:"timeline" yaml:"timeline"`
+	Versions        map[string]string  `json:"versions" yaml:"versions"`
+	Skipped         []SkippedComponent `json:"skipped" yaml:"skipped"`
+	Conflicts       []PlanConflict     `json:"conflicts" yaml:"conflicts"`
+	MissingPackages []string           `json:"missing_packages,omitempty" yaml:"missing_packages,omitempty"`
+}
+
+// skip records component excluded from propagation.
+func (s *Sync) skip(name, reason string) {
+	s.skipped = append(s.skipped, SkippedComponent{Name: name, Reason: reason})
+}
+
+// warn records the warning in the result and returns its message. Safe for concurrent use.
+func (s *Sync) warn(code, subject, format string, a ...any) string {
+	s.warningsMx.Lock()
+	defer s.warningsMx.Unlock()
+	return s.result.Warnings.Add(code, subject, format, a...)
+}
+
+// skipFrozen skips the component with a warning if it's frozen, manually propagated, opted out of propagation or under
+// an active freeze window, and tells if it did.
+func (s *Sync) skipFrozen(name string, c *sync.Component) bool {
+	frozen, reason, err := c.GetFrozen()
+	if err == nil && !frozen {
+		if disabled, errDisabled := c.GetPropagationDisabled(); errDisabled == nil && disabled {
+			frozen, reason = true, "propagation disabled"
+		}
+	}
+	if err != nil || !frozen {
+		w, ok := s.freezeWindows.Match(s.attachedTo[name])
+		if !ok {
+			return false
+		}
+		if s.OverrideFreeze {
+			s.Term().Warning().Println(s.warn(warning.Freeze, name, "Overriding freeze window %s for component %s", w, name))
+			return false
+		}
+		reason = fmt.Sprintf("freeze window %s", w)
+	}
+
+	s.Term().Warning().Println(s.warn(warning.Skipped, name, "Skipping component %s (%s)", name, reason))
+	s.skip(name, reason)
+	return true
+}
+
+// buildPlan assembles propagation plan from timeline and computed versions.
+// Components which version is already propagated are reported as skipped, as update would skip them.
+func (s *Sync) buildPlan(toSync *sync.OrderedMap[*sync.Component], componentVersionMap map[string]string) (*PropagationPlan, error) {
+	plan := &PropagationPlan{
+		Timeline:        make([]PlanItem, 0, len(s.timeline)),
+		Versions:        make(map[string]string, len(componentVersionMap)),
+		Skipped:         append([]SkippedComponent{}, s.skipped...),
+		Conflicts:       append([]PlanConflict{}, s.conflicts...),
+		MissingPackages: s.result.MissingPackages,
+	}
+
+	for _, item := range s.timeline {
+		p := PlanItem{
+			Version: item.GetVersion(),
+			Commit:  item.GetCommit(),
+			Date:    item.GetDate(),
+			Message: item.GetMessage(),
+		}
+
+		switch i := item.(type) {
+		case *sync.TimelineComponentsItem:
+			p.Type = timelineTypeComponents
+			p.Components = i.GetComponents().Keys()
+			sort.Strings(p.Components)
+		case *sync.TimelineVariablesItem:
+			p.Type = timelineTypeVariables
+			p.Variables = i.GetVariables().Keys()
+			sort.Strings(p.Variables)
+		}
+
+		plan.Timeline = append(plan.Timeline, p)
+	}
+
+	for _, key := range toSync.Keys() {
+		c, _ := toSync.Get(key)
+		baseVersion, _, debug, err := c.GetBaseVersion(s.VersionFormat)
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if baseVersion == componentVersionMap[key] {
+			plan.Skipped = append(plan.Skipped, SkippedComponent{Name: key, Reason: "identical version"})
+			continue
+		}
+
+		plan.Versions[key] = componentVersionMap[key]
+	}
+
+	sort.Slice(plan.Skipped, func(i, j int) bool {
+		return plan.Skipped[i].Name < plan.Skipped[j].Name
+	})
+	sort.Slice(plan.Conflicts, func(i, j int) bool {
+		return plan.Conflicts[i].Component < plan.Conflicts[j].Component
+	})
+
+	return plan, nil
+}
+
+// writeReport writes propagation plan in the report format to the report file, or to stdout if no file is set.
+func (s *Sync) writeReport(plan *PropagationPlan) error {
+	var data []byte
+	var err error
+	switch s.Report {
+	case ReportJSON:
+		data, err = json.MarshalIndent(plan, "", "  ")
+		data = append(data, '\n')
+	case ReportYAML:
+		data, err = yaml.Marshal(plan)
+	default:
+		return fmt.Errorf("unknown report format %q (expected: %s, %s)", s.Report, ReportJSON, ReportYAML)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal propagation plan > %w", err)
+	}
+
+	if s.ReportFile == "" {
+		var out io.Writer = os.Stdout
+		if s.Streams != nil {
+			out = s.Streams.Out()
+		}
+		_, err = out.Write(data)
+		return err
+	}
+
+	if err = os.WriteFile(s.ReportFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to write report > %w", err)
+	}
+
+	s.Term().Info().Printfln("Propagation plan written to %s", s.ReportFile)
+	return nil
+}
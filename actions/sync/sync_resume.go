@@ -0,0 +1,232 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// stateFile is the location of the apply progress of a sync run relative to the domain directory.
+// It exists while versions are written and is kept if the run is interrupted, to be resumed.
+var stateFile = filepath.Join(".plasmactl", "sync-state.json")
+
+// stateSaveInterval throttles saving of the apply progress, resume checks meta files anyway.
+const stateSaveInterval = 500 * time.Millisecond
+
+// StateEntry is a component version write of the apply progress.
+type StateEntry struct {
+	JournalEntry
+	Written bool `json:"written"`
+}
+
+// applyState is the progress of version writes of a sync run.
+type applyState struct {
+	Date    time.Time    `json:"date"`
+	Changes []StateEntry `json:"changes"`
+
+	index map[string]int
+	saved time.Time
+}
+
+func (s *Sync) statePath() string {
+	return s.stateDirFile(stateFile)
+}
+
+func loadApplyState(path string) (*applyState, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	st := &applyState{}
+	if err = json.Unmarshal(data, st); err != nil {
+		return nil, fmt.Errorf("malformed sync state %s > %w", path, err)
+	}
+	st.reindex()
+
+	return st, nil
+}
+
+func (st *applyState) reindex() {
+	st.index = make(map[string]int, len(st.Changes))
+	for i, change := range st.Changes {
+		st.index[change.Component] = i
+	}
+}
+
+func (st *applyState) save(path string) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	st.saved = time.Now()
+	return os.WriteFile(path, data, 0600)
+}
+
+// startApplyState stores the writes about to be applied, unless a resumed state is already in progress.
+func (s *Sync) startApplyState(writes []versionWrite) error {
+	if s.state != nil {
+		return nil
+	}
+
+	s.state = &applyState{Date: time.Now().UTC()}
+	for _, w := range writes {
+		s.state.Changes = append(s.state.Changes, StateEntry{JournalEntry: JournalEntry{
+			Component:  w.component.GetName(),
+			MetaPath:   s.recordedPath(w.component.MetaPath()),
+			OldVersion: w.oldVersion,
+			NewVersion: w.newVersion,
+		}})
+	}
+	s.state.reindex()
+
+	if err := s.state.save(s.statePath()); err != nil {
+		return fmt.Errorf("failed to write sync state > %w", err)
+	}
+
+	return nil
+}
+
+// markWritten records the component version as written, saving progress at most every stateSaveInterval.
+func (s *Sync) markWritten(name string) {
+	s.stateMx.Lock()
+	defer s.stateMx.Unlock()
+
+	i, ok := s.state.index[name]
+	if !ok {
+		return
+	}
+	s.state.Changes[i].Written = true
+
+	if time.Since(s.state.saved) < stateSaveInterval {
+		return
+	}
+	if err := s.state.save(s.statePath()); err != nil {
+		s.Log().Warn("failed to save sync state", "error", err)
+	}
+}
+
+// finishApplyState removes the state once all versions are written, or saves progress to be resumed.
+func (s *Sync) finishApplyState() error {
+	for _, change := range s.state.Changes {
+		if !change.Written {
+			if err := s.state.save(s.statePath()); err != nil {
+				return fmt.Errorf("failed to write sync state > %w", err)
+			}
+			s.Term().Warning().Println(s.warn(warning.State, "", "Sync didn't write all versions, continue it with --resume"))
+			return nil
+		}
+	}
+
+	if err := os.Remove(s.statePath()); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove sync state > %w", err)
+	}
+
+	return nil
+}
+
+// warnInterrupted reports a previous run which didn't write all versions.
+func (s *Sync) warnInterrupted() {
+	if _, err := os.Stat(s.statePath()); err == nil {
+		s.Term().Warning().Println(s.warn(warning.State, "", "Previous sync was interrupted, run with --resume to continue it instead of starting over"))
+	}
+}
+
+// resume continues writing versions of the interrupted sync run recorded in the state file.
+// Versions are checked in meta files: components already having the new version are considered written and
+// components changed since the run are skipped.
+func (s *Sync) resume() error {
+	st, err := loadApplyState(s.statePath())
+	if err != nil {
+		return err
+	}
+	if st == nil {
+		s.Term().Warning().Println(s.warn(warning.Empty, "", "No interrupted sync to resume in %s", s.statePath()))
+		return nil
+	}
+
+	s.Term().Info().Printfln("Resuming sync run of %s (%d change(s))", st.Date.Format(time.RFC3339), len(st.Changes))
+
+	var writes []versionWrite
+	var modified []string
+	for i, change := range st.Changes {
+		if change.Written {
+			continue
+		}
+
+		c, errComponent := sync.NewComponent(change.Component, s.recordedPrefix(change.JournalEntry))
+		if errComponent != nil {
+			return errComponent
+		}
+
+		currentVersion, debug, errVersion := c.GetVersion()
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if errVersion != nil {
+			return errVersion
+		}
+
+		switch currentVersion {
+		case change.NewVersion:
+			st.Changes[i].Written = true
+			continue
+		case change.OldVersion:
+		default:
+			modified = append(modified, change.Component)
+			s.Term().Warning().Println(s.warn(warning.Skipped, change.Component, "- skip %s (version %s was changed to %s since sync)", change.Component, change.OldVersion, currentVersion))
+			st.Changes[i].Written = true
+			continue
+		}
+
+		s.result.Components = append(s.result.Components, SyncedComponent{
+			Name:       change.Component,
+			OldVersion: change.OldVersion,
+			NewVersion: change.NewVersion,
+		})
+		s.Term().Printfln("- %s: %s -> %s", change.Component, change.OldVersion, change.NewVersion)
+		if s.DryRun {
+			if err = s.diffVersion(c, change.NewVersion, true); err != nil {
+				return err
+			}
+			continue
+		}
+		writes = append(writes, versionWrite{component: c, oldVersion: change.OldVersion, newVersion: change.NewVersion})
+	}
+
+	if s.DryRun {
+		s.Term().Info().Printfln("Dry-run: %d component(s) would be written", len(s.result.Components))
+		return s.writePatch()
+	}
+
+	s.state = st
+	if len(writes) == 0 {
+		err = s.finishApplyState()
+	} else {
+		err = s.applyVersions(writes)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(modified) > 0 {
+		return fmt.Errorf("resumed %d component(s), %d component(s) modified since sync were skipped", len(writes), len(modified))
+	}
+
+	s.Term().Success().Printfln("Resumed %d component(s)", len(writes))
+	return nil
+}
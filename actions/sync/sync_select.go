@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// validateSelection ensures each selected MRN or pattern is valid and matches a component of the build.
+func (s *Sync) validateSelection(buildInv *sync.Inventory) error {
+	names := buildInv.GetComponentsMap().Keys()
+	for _, pattern := range s.Only {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid component pattern %q > %w", pattern, err)
+		}
+
+		found := false
+		for _, name := range names {
+			if ok, _ := path.Match(pattern, name); ok {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			return fmt.Errorf("component pattern %q doesn't match any component in build", pattern)
+		}
+	}
+
+	return nil
+}
+
+// isSelected reports whether the component is selected for propagation.
+// All components are selected if no selection is set.
+func (s *Sync) isSelected(name string) bool {
+	if len(s.Only) == 0 {
+		return true
+	}
+
+	for _, pattern := range s.Only {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// filterSelection removes components not selected for propagation from packages maps,
+// so their history isn't scanned. Dependents of selected components are still propagated.
+func (s *Sync) filterSelection(componentsMap map[string]*sync.OrderedMap[*sync.Component]) {
+	if len(s.Only) == 0 {
+		return
+	}
+
+	for _, components := range componentsMap {
+		for _, k := range components.Keys() {
+			if !s.isSelected(k) {
+				components.Unset(k)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// prepareShallow handles a shallow clone at the path, as CI runners often check out with limited depth.
+// With Unshallow, the complete history is fetched from the origin remote. Otherwise, parents beyond the shallow
+// boundary are returned to be skipped when walking history, versions committed before the boundary are then
+// resolved from meta files. Repositories are checked once per run.
+func (s *Sync) prepareShallow(repo *git.Repository, path string) ([]plumbing.Hash, error) {
+	key, err := filepath.Abs(path)
+	if err != nil {
+		key = path
+	}
+
+	s.shallowMx.Lock()
+	defer s.shallowMx.Unlock()
+
+	if skip, ok := s.shallow[key]; ok {
+		return skip, nil
+	}
+
+	boundary, err := repository.ShallowBoundary(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	var skip []plumbing.Hash
+	switch {
+	case len(boundary) == 0:
+	case s.Unshallow:
+		s.Log().Info(fmt.Sprintf("Fetching complete history of shallow clone %s", path))
+		if err = repository.Unshallow(repo, git.DefaultRemoteName); err != nil {
+			return nil, fmt.Errorf("unshallow %s > %w", path, err)
+		}
+	default:
+		s.Log().Warn(s.warn(warning.Partial, "", "%s is a shallow clone, versions committed before its history are resolved from meta files, use --unshallow to fetch complete history", path))
+		skip, err = repository.ShallowParents(repo, boundary)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if s.shallow == nil {
+		s.shallow = make(map[string][]plumbing.Hash)
+	}
+	s.shallow[key] = skip
+
+	return skip, nil
+}
+
+// historyLog iterates commits from the hash to the past, skipping the given parents of shallow commits and known
+// commits with their history.
+func historyLog(r *git.Repository, from plumbing.Hash, skip []plumbing.Hash, known map[plumbing.Hash]bool) (object.CommitIter, error) {
+	if len(skip) == 0 && len(known) == 0 {
+		return r.Log(&git.LogOptions{From: from})
+	}
+
+	c, err := r.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+
+	return object.NewCommitPreorderIter(c, known, skip), nil
+}
+
+// resolveShallowVersion returns the oldest commit of available history keeping the component version, following
+// first parents from the head. It approximates the version commit when the version was set before the shallow boundary.
+func resolveShallowVersion(r *git.Repository, head *object.Commit, componentMetaPath, currentVersion string) (*object.Commit, error) {
+	commit := head
+	for commit.NumParents() > 0 {
+		parent, err := r.CommitObject(commit.ParentHashes[0])
+		if err != nil {
+			if errors.Is(err, plumbing.ErrObjectNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("can't get parent of commit %s > %w", commit.Hash, err)
+		}
+
+		file, err := parent.File(componentMetaPath)
+		if err != nil {
+			if errors.Is(err, object.ErrFileNotFound) {
+				break
+			}
+			return nil, fmt.Errorf("opening file %s in commit %s > %w", componentMetaPath, parent.Hash, err)
+		}
+
+		metaFile, err := loadYamlFileFromBytes(file, componentMetaPath)
+		if err != nil {
+			return nil, fmt.Errorf("YAML load commit %s > %w", parent.Hash, err)
+		}
+
+		if sync.GetMetaVersion(metaFile) != currentVersion {
+			break
+		}
+		commit = parent
+	}
+
+	return commit, nil
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+const simulatedMessage = "simulated change"
+
+// simulateTimeline replaces timeline with a single item pretending simulated components received new version at HEAD.
+func (s *Sync) simulateTimeline(buildInv *sync.Inventory) error {
+	repo, err := git.PlainOpenWithOptions(s.DomainDir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("%s - %w", s.DomainDir, err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	commit := head.Hash().String()
+	item := sync.NewTimelineComponentsItem(commit[:13], commit, time.Now(), s.Term())
+	item.SetMessages(simulatedMessage, s.Simulate)
+
+	componentsMap := buildInv.GetComponentsMap()
+	for _, name := range s.Simulate {
+		c, ok := componentsMap.Get(name)
+		if !ok {
+			return fmt.Errorf("component %s not found in build", name)
+		}
+
+		item.AddComponent(c)
+	}
+
+	s.timeline = []sync.TimelineItem{item}
+	return nil
+}
+
+// reportSimulation links propagated components to simulated ones they depend on and prints the blast radius.
+func (s *Sync) reportSimulation(buildInv *sync.Inventory) {
+	for i, c := range s.result.Components {
+		deps := buildInv.GetRequiresComponents(c.Name, -1)
+		for _, name := range s.Simulate {
+			if deps[name] {
+				s.result.Components[i].Via = append(s.result.Components[i].Via, name)
+			}
+		}
+		sort.Strings(s.result.Components[i].Via)
+	}
+
+	s.Term().Info().Printfln("Simulated change of %s would propagate to %d component(s)", strings.Join(s.Simulate, ", "), len(s.result.Components))
+	for _, c := range s.result.Components {
+		s.Term().Printfln("- %s: %s -> %s (via %s)", c.Name, c.OldVersion, c.NewVersion, strings.Join(c.Via, ", "))
+	}
+}
@@ -0,0 +1,183 @@
+package sync
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// sourceNamespace is a domain or package providing a component of the composed sources.
+type sourceNamespace struct {
+	name string
+	path string
+	bare bool
+}
+
+// composeSources builds the effective component set from domains and packages into a temporary directory,
+// used as build dir for the rest of sync. Namespaces are applied from the lowest priority to the highest,
+// so files of the higher priority namespace win, as done by compose.
+// The returned function removes the temporary directory.
+func (s *Sync) composeSources() (func(), error) {
+	priorityOrder, packagePathMap, err := s.namespaces()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := os.MkdirTemp("", "plasmactl-sync-")
+	if err != nil {
+		return nil, err
+	}
+	cleanup := func() {
+		if errRemove := os.RemoveAll(dir); errRemove != nil {
+			s.Log().Warn("failed to remove composed sources", "path", dir, "error", errRemove)
+		}
+	}
+
+	s.owners = make(map[string]sourceNamespace)
+	for _, name := range priorityOrder {
+		ns := sourceNamespace{name: name, path: packagePathMap[name], bare: repository.IsBareDir(packagePathMap[name])}
+		s.Log().Debug("composing sources of namespace", "namespace", name, "path", ns.path)
+
+		if ns.bare {
+			err = s.composeBare(ns, dir)
+		} else {
+			err = s.composeDir(ns, dir)
+		}
+		if err != nil {
+			cleanup()
+			return nil, fmt.Errorf("compose sources of %s > %w", name, err)
+		}
+	}
+
+	s.BuildDir = dir
+	return cleanup, nil
+}
+
+// composeDir copies files of layers of the namespace directory.
+func (s *Sync) composeDir(ns sourceNamespace, dst string) error {
+	return filepath.WalkDir(ns.path, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(ns.path, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if !d.Type().IsRegular() {
+			return nil
+		}
+
+		data, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return err
+		}
+
+		return s.composeFile(ns, dst, filepath.ToSlash(rel), data)
+	})
+}
+
+// composeBare copies files of layers of the bare repository HEAD tree.
+func (s *Sync) composeBare(ns sourceNamespace, dst string) error {
+	repo, err := git.PlainOpenWithOptions(ns.path, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return err
+	}
+
+	paths, readFile, err := repository.HeadFiles(repo)
+	if err != nil {
+		return err
+	}
+
+	for _, rel := range paths {
+		if isHiddenPath(rel) {
+			continue
+		}
+
+		data, errRead := readFile(rel)
+		if errRead != nil {
+			return errRead
+		}
+
+		if err = s.composeFile(ns, dst, rel, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// composeFile writes file of the namespace to the composed sources and records owner of component meta files.
+// Only files inside layers are composed.
+func (s *Sync) composeFile(ns sourceNamespace, dst, rel string, data []byte) error {
+	if !strings.Contains(rel, "/") {
+		return nil
+	}
+
+	path := filepath.Join(dst, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+
+	if strings.HasSuffix(rel, "/meta/plasma.yaml") {
+		if c := sync.BuildComponentFromPath(rel, dst); c != nil {
+			s.owners[c.GetName()] = ns
+		}
+	}
+
+	return nil
+}
+
+func isHiddenPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sourceComponent returns component of the domain providing it to composed sources, to persist its new version.
+// Components provided by packages have no writable source and nil is returned.
+func (s *Sync) sourceComponent(c *sync.Component) (*sync.Component, error) {
+	ns, ok := s.owners[c.GetName()]
+	if !ok {
+		return nil, fmt.Errorf("unknown source of component %s", c.GetName())
+	}
+
+	if ns.bare || !s.isDomain(ns.name) {
+		return nil, nil
+	}
+
+	return sync.NewComponent(c.GetName(), ns.path)
+}
+
+func (s *Sync) isDomain(name string) bool {
+	for _, d := range s.domainsByPriority() {
+		if d.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,318 @@
+package sync_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/internal/release"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// newSyncPlatform creates a platform bumped at its initial commit, then with a bumped change of postgres and a change
+// of a variable used by dashboards. It returns the platform with its composed build and the initial version.
+func newSyncPlatform(t *testing.T) (*testenv.Platform, string, string) {
+	t.Helper()
+	p := testenv.NewPlatform(t)
+	varsPath := filepath.Join("platform", "group_vars", "platform", "vars.yaml")
+	p.WriteFile("plasma-compose.yaml", "name: platform\n")
+	p.WriteFile(varsPath, "grafana_port: 3000\n")
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "templates", "grafana.ini.j2"), "port = {{ grafana_port }}\n")
+	initial := p.Commit("add grafana port", testenv.DeveloperName)[:13]
+	for _, name := range []string{testenv.Postgres, testenv.Auth, testenv.Dashboards} {
+		p.SetVersion(name, initial)
+	}
+	p.Commit("versions bump", repository.Author)
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.SetVersion(testenv.Postgres, p.Commit("change postgres", testenv.DeveloperName)[:13])
+	p.Commit("versions bump", repository.Author)
+	p.WriteFile(varsPath, "grafana_port: 3001\n")
+	p.Commit("change grafana port", testenv.DeveloperName)
+
+	return p, p.Compose(), initial
+}
+
+func TestSyncSimulate(t *testing.T) {
+	_, buildDir, initial := newSyncPlatform(t)
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, Simulate: []string{testenv.Auth}}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("simulate: %v", err)
+	}
+
+	res := s.Result().(*sync.SyncResult)
+	if !res.DryRun || len(res.Components) != 1 || res.Components[0].Name != testenv.Dashboards || !slices.Equal(res.Components[0].Via, []string{testenv.Auth}) {
+		t.Errorf("expected dry-run propagation to %s via %s, got %+v", testenv.Dashboards, testenv.Auth, res)
+	}
+	if v := testenv.BuildVersions(t, buildDir, testenv.Auth, testenv.Dashboards); !slices.Equal(v, []string{initial, initial}) {
+		t.Errorf("expected build untouched by simulation, got %v", v)
+	}
+
+	if err := testenv.Run(t, &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, Simulate: []string{"foundation.services.unknown"}}); err == nil {
+		t.Error("expected simulation of a component not in build to fail")
+	}
+}
+
+func TestSyncFromManifest(t *testing.T) {
+	_, buildDir, initial := newSyncPlatform(t)
+	m := &release.Manifest{Ref: "v1.0.0", Commit: strings.Repeat("a", 40), Components: []release.Component{
+		{Name: testenv.Auth, Version: "bbb2222222222"},
+		{Name: testenv.Dashboards, Version: initial},
+		{Name: "foundation.services.unknown", Version: "ccc3333333333"},
+	}}
+	var err error
+	if m.Checksum, err = release.Checksum(m); err != nil {
+		t.Fatalf("checksum: %v", err)
+	}
+	if err = release.Save("release.json", m); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, FromManifest: "release.json", DryRun: true}
+	if err = testenv.Run(t, s); err != nil {
+		t.Fatalf("dry-run sync from manifest: %v", err)
+	}
+
+	res := s.Result().(*sync.SyncResult)
+	if len(res.Components) != 1 || res.Components[0].Name != testenv.Auth || res.Components[0].OldVersion != initial {
+		t.Errorf("expected only %s restored, got %+v", testenv.Auth, res.Components)
+	}
+	if !slices.ContainsFunc(res.Warnings, func(w warning.Warning) bool { return w.Code == warning.NotFound }) {
+		t.Errorf("expected warning for component missing in build, got %+v", res.Warnings)
+	}
+	if v := testenv.BuildVersions(t, buildDir, testenv.Auth); v[0] != initial {
+		t.Errorf("expected build untouched by dry-run, got %v", v)
+	}
+
+	err = testenv.Run(t, &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, FromManifest: "release.json"})
+	if err == nil || !strings.Contains(err.Error(), "missing in build") {
+		t.Errorf("expected missing component reported as error, got %v", err)
+	}
+	if v := testenv.BuildVersions(t, buildDir, testenv.Auth, testenv.Dashboards); !slices.Equal(v, []string{"bbb2222222222", initial}) {
+		t.Errorf("expected manifest versions in build, got %v", v)
+	}
+
+	m.Components[0].Version = "ddd4444444444"
+	if err = release.Save("release.json", m); err != nil {
+		t.Fatalf("save manifest: %v", err)
+	}
+	if err = testenv.Run(t, &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, FromManifest: "release.json"}); err == nil {
+		t.Error("expected manifest with wrong checksum to be refused")
+	}
+}
+
+func TestSyncCache(t *testing.T) {
+	p, buildDir, _ := newSyncPlatform(t)
+	dryRun := func(noCache bool) *sync.SyncResult {
+		t.Helper()
+		s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, DryRun: true, NoCache: noCache}
+		if err := testenv.Run(t, s); err != nil {
+			t.Fatalf("sync: %v", err)
+		}
+		return s.Result().(*sync.SyncResult)
+	}
+
+	uncached := dryRun(true)
+	if _, err := os.Stat(filepath.Join(p.Dir, ".plasmactl", "sync-cache.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected no cache written with --no-cache, got %v", err)
+	}
+
+	first := dryRun(false)
+	cache := p.ReadFile(".plasmactl/sync-cache.json")
+	if !strings.Contains(cache, p.HeadCommit().Hash.String()) || !strings.Contains(cache, testenv.Postgres) {
+		t.Errorf("expected cache of resolved versions at HEAD, got:\n%s", cache)
+	}
+
+	cached := dryRun(false)
+	p.WriteFile(".plasmactl/sync-cache.json", "{")
+	discarded := dryRun(false)
+	for _, res := range []*sync.SyncResult{first, cached, discarded} {
+		if !slices.EqualFunc(res.Components, uncached.Components, func(a, b sync.SyncedComponent) bool {
+			return a.Name == b.Name && a.NewVersion == b.NewVersion && a.Commit == b.Commit
+		}) {
+			t.Errorf("expected the same propagation with cache, got %+v, expected %+v", res.Components, uncached.Components)
+		}
+	}
+	if !strings.HasPrefix(p.ReadFile(".plasmactl/sync-cache.json"), "{\"repositories\"") {
+		t.Error("expected malformed cache replaced")
+	}
+
+	// Commits since the cached HEAD are walked and grouped with the cached history.
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change auth", testenv.DeveloperName)
+	extended, uncached := dryRun(false), dryRun(true)
+	if !slices.EqualFunc(extended.Components, uncached.Components, func(a, b sync.SyncedComponent) bool {
+		return a.Name == b.Name && a.NewVersion == b.NewVersion && a.Commit == b.Commit
+	}) {
+		t.Errorf("expected the same propagation with extended cache, got %+v, expected %+v", extended.Components, uncached.Components)
+	}
+
+	// History before the cached HEAD isn't walked again, a commit dropped from it stays missing.
+	var stored struct {
+		Repositories map[string]map[string]any `json:"repositories"`
+	}
+	if err := json.Unmarshal([]byte(p.ReadFile(".plasmactl/sync-cache.json")), &stored); err != nil {
+		t.Fatal(err)
+	}
+	var dropped string
+	for _, rc := range stored.Repositories {
+		history := rc["history"].([]any)
+		dropped = history[len(history)-1].(map[string]any)["hash"].(string)
+		rc["history"] = history[:len(history)-1]
+	}
+	data, err := json.Marshal(stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.WriteFile(".plasmactl/sync-cache.json", string(data))
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {msg: auth}\n")
+	p.Commit("change auth again", testenv.DeveloperName)
+	dryRun(false)
+	if history := p.ReadFile(".plasmactl/sync-cache.json"); !strings.Contains(history, p.HeadCommit().Hash.String()) || strings.Contains(history, `"hash":"`+dropped+`"`) {
+		t.Errorf("expected only commits since the cached HEAD walked, got:\n%s", history)
+	}
+}
+
+func TestSyncConcurrency(t *testing.T) {
+	_, buildDir, initial := newSyncPlatform(t)
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, Concurrency: 4}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+
+	res := s.Result().(*sync.SyncResult)
+	if len(res.Components) != 2 || res.Metrics.Counters["written"] != 2 {
+		t.Fatalf("expected 2 versions written, got %+v, counters %v", res.Components, res.Metrics.Counters)
+	}
+	versions := testenv.BuildVersions(t, buildDir, res.Components[0].Name, res.Components[1].Name)
+	for i, c := range res.Components {
+		if !strings.HasPrefix(c.NewVersion, initial+"-") || versions[i] != c.NewVersion {
+			t.Errorf("expected %s propagated version %s in build, got %s", c.Name, c.NewVersion, versions[i])
+		}
+	}
+}
+
+func TestSyncVersionConflicts(t *testing.T) {
+	p, buildDir, initial := newSyncPlatform(t)
+	variableChange := p.HeadCommit().Hash.String()[:13]
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, ConflictStrategy: sync.ConflictError}
+	if err := testenv.Run(t, s); err == nil {
+		t.Fatal("expected conflict to fail sync with error strategy")
+	}
+	if v := testenv.BuildVersions(t, buildDir, testenv.Auth, testenv.Dashboards); !slices.Equal(v, []string{initial, initial}) {
+		t.Errorf("expected build untouched on conflict, got %v", v)
+	}
+
+	s = &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, DryRun: true}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	conflicts := s.Result().(*sync.SyncResult).VersionConflicts
+	if len(conflicts) != 1 || conflicts[0].Component != testenv.Dashboards || conflicts[0].Chosen != variableChange ||
+		len(conflicts[0].Claims) != 2 || conflicts[0].Claims[0].Type != "variables" || conflicts[0].Claims[1].Type != "components" {
+		t.Errorf("expected %s conflict resolved to the newest variable change, got %+v", testenv.Dashboards, conflicts)
+	}
+}
+
+func TestSyncVerify(t *testing.T) {
+	_, buildDir, _ := newSyncPlatform(t)
+	stages := func(res *sync.SyncResult) []string {
+		var names []string
+		for _, st := range res.Metrics.Stages {
+			names = append(names, st.Name)
+		}
+		return names
+	}
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, NoVerify: true}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if slices.Contains(stages(s.Result().(*sync.SyncResult)), "verify") {
+		t.Error("expected no verification with --no-verify")
+	}
+
+	_, buildDir, _ = newSyncPlatform(t)
+	s = &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	res := s.Result().(*sync.SyncResult)
+	if !slices.Contains(stages(res), "verify") || len(res.VersionMismatches) != 0 {
+		t.Errorf("expected applied versions verified without mismatch, got stages %v, mismatches %+v", stages(res), res.VersionMismatches)
+	}
+}
+
+func TestSyncBranchState(t *testing.T) {
+	p, buildDir, _ := newSyncPlatform(t)
+	branch := "release/1.0"
+	if err := p.Repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName(branch), p.HeadCommit().Hash)); err != nil {
+		t.Fatal(err)
+	}
+
+	s := &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, Branch: branch}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("sync on branch: %v", err)
+	}
+	if res := s.Result().(*sync.SyncResult); len(res.Components) == 0 || res.BranchCommit == "" {
+		t.Fatalf("expected versions committed to %s, got %+v", branch, res)
+	}
+
+	state := filepath.Join(".plasmactl", "branches", branch)
+	for _, file := range []string{"sync-journal.json", "sync-last-plan.json", "sync-cache.json"} {
+		if _, err := os.Stat(filepath.Join(p.Dir, state, file)); err != nil {
+			t.Errorf("expected %s of the branch kept in the domain: %v", file, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(p.Dir, ".plasmactl", "sync-journal.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected branch run apart from the journal of the current checkout")
+	}
+	if journal := p.ReadFile(filepath.Join(state, "sync-journal.json")); strings.Contains(journal, os.TempDir()) {
+		t.Errorf("expected meta paths relative to the branch worktree, got:\n%s", journal)
+	}
+
+	s = &sync.Sync{Streams: testenv.QuietStreams(), DomainDir: ".", BuildDir: buildDir, Branch: branch, Undo: true}
+	if err := testenv.Run(t, s); err != nil {
+		t.Fatalf("undo on branch: %v", err)
+	}
+	if res := s.Result().(*sync.SyncResult); len(res.Components) == 0 || res.BranchCommit == "" {
+		t.Errorf("expected restored versions committed to %s, got %+v", branch, res)
+	}
+	if !strings.Contains(p.ReadFile(filepath.Join(state, "sync-journal.json")), `"runs": []`) {
+		t.Error("expected undone run removed from the branch journal")
+	}
+}
+
+func TestSyncOptions(t *testing.T) {
+	tests := []struct {
+		name     string
+		sync     *sync.Sync
+		expected string
+	}{
+		{"from-sources with from-manifest", &sync.Sync{FromSources: true, FromManifest: "release.json"}, "--from-sources can't be combined"},
+		{"simulate with from-manifest", &sync.Sync{Simulate: []string{testenv.Auth}, FromManifest: "release.json"}, "--from-manifest can't be combined"},
+		{"only with from-manifest", &sync.Sync{Only: []string{testenv.Auth}, FromManifest: "release.json"}, "--only can't be combined"},
+		{"undo with simulate", &sync.Sync{Undo: true, Simulate: []string{testenv.Auth}}, "--undo can't be combined"},
+		{"branch with from-manifest", &sync.Sync{Branch: "release", FromManifest: "release.json"}, "--branch can't be combined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := testenv.Run(t, tt.sync); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("expected %q error, got %v", tt.expected, err)
+			}
+		})
+	}
+}
@@ -14,11 +14,13 @@ import (
 
 	"github.com/cespare/xxhash/v2"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/pterm/pterm"
 
 	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
 )
 
 func (s *Sync) populateTimelineVars(buildInv *sync.Inventory) error {
@@ -30,20 +32,54 @@ func (s *Sync) populateTimelineVars(buildInv *sync.Inventory) error {
 		}
 	}
 
-	filesCrawler := sync.NewFilesCrawler(s.DomainDir)
-	groupedFiles, err := filesCrawler.FindVarsFiles("")
-	if err != nil {
-		return fmt.Errorf("can't get vars files > %w", err)
+	// Iterate domains from the highest priority, so vars file is taken from the domain which wins composition.
+	domains := s.domainsByPriority()
+	processed := make(map[string]struct{})
+	for i := len(domains) - 1; i >= 0; i-- {
+		filesCrawler := sync.NewFilesCrawler(domains[i].Path)
+		groupedFiles, err := filesCrawler.FindVarsFiles("")
+		if err != nil {
+			return fmt.Errorf("can't get vars files > %w", err)
+		}
+
+		var varsFiles []string
+		for _, paths := range groupedFiles {
+			for _, path := range paths {
+				if _, ok := processed[path]; ok {
+					s.Log().Debug("skipping vars file overridden by higher priority domain", "path", path, "domain", domains[i].Name)
+					continue
+				}
+
+				processed[path] = struct{}{}
+				varsFiles = append(varsFiles, path)
+			}
+		}
+
+		if len(varsFiles) == 0 {
+			continue
+		}
+
+		s.count(counterVariablesFiles, len(varsFiles))
+		end := s.stage(stageTimelineVariables, domains[i].Name)
+		err = s.populateDomainVars(buildInv, domains[i].Path, varsFiles)
+		end()
+		if err != nil {
+			return fmt.Errorf("domain %s > %w", domains[i].Name, err)
+		}
 	}
 
-	var varsFiles []string
-	for _, paths := range groupedFiles {
-		varsFiles = append(varsFiles, paths...)
+	return nil
+}
+
+func (s *Sync) populateDomainVars(buildInv *sync.Inventory, domainDir string, varsFiles []string) error {
+	repo, err := git.PlainOpenWithOptions(domainDir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return fmt.Errorf("%s - %w", domainDir, err)
 	}
 
-	repo, err := git.PlainOpenWithOptions(s.DomainDir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	shallow, err := s.prepareShallow(repo, domainDir)
 	if err != nil {
-		return fmt.Errorf("%s - %w", s.DomainDir, err)
+		return err
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -71,7 +107,7 @@ func (s *Sync) populateTimelineVars(buildInv *sync.Inventory) error {
 					if !ok {
 						return
 					}
-					if err = s.findVariableUpdateTime(varsFile, buildInv, repo, &mx); err != nil {
+					if err = s.findVariableUpdateTime(varsFile, buildInv, repo, shallow, &mx); err != nil {
 						if p != nil {
 							_, _ = p.Stop()
 						}
@@ -114,7 +150,7 @@ func (s *Sync) populateTimelineVars(buildInv *sync.Inventory) error {
 	return nil
 }
 
-func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo *git.Repository, mx *async.Mutex) error {
+func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo *git.Repository, shallow []plumbing.Hash, mx *async.Mutex) error {
 	ref, err := repo.Head()
 	if err != nil {
 		return fmt.Errorf("can't get HEAD ref > %w", err)
@@ -160,7 +196,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 	var danglingCommit *object.Commit
 
 	toIterate := variablesMap.ToDict()
-	cIter, err := repo.Log(&git.LogOptions{From: ref.Hash()})
+	cIter, err := historyLog(repo, ref.Hash(), shallow, nil)
 	if err != nil {
 		return err
 	}
@@ -193,6 +229,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 				hashesMap[k].hash = danglingCommit.Hash.String()
 				hashesMap[k].hashTime = danglingCommit.Author.When
 				hashesMap[k].author = danglingCommit.Author.Name
+				hashesMap[k].message = commitSubject(danglingCommit)
 			}
 
 			danglingCommit = nil
@@ -206,7 +243,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 			if strings.Contains(errIt.Error(), "did not find expected key") ||
 				strings.Contains(errIt.Error(), "did not find expected comment or line break") ||
 				strings.Contains(errIt.Error(), "could not find expected") {
-				s.Log().Warn("Bad YAML structured detected",
+				s.Log().Warn(s.warn(warning.Partial, varsFile, "Bad YAML structured detected"),
 					slog.String("file", varsFile),
 					slog.String("commit", c.Hash.String()),
 					slog.String("error", errIt.Error()),
@@ -216,7 +253,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 			}
 
 			if strings.Contains(errIt.Error(), "invalid password for vault") {
-				s.Log().Warn("Invalid password for vault",
+				s.Log().Warn(s.warn(warning.Partial, varsFile, "Invalid password for vault"),
 					slog.String("file", varsFile),
 					slog.String("commit", c.Hash.String()),
 				)
@@ -225,7 +262,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 			}
 
 			if strings.Contains(errIt.Error(), "invalid secret format") {
-				s.Log().Warn("invalid secret format for vault",
+				s.Log().Warn(s.warn(warning.Partial, varsFile, "invalid secret format for vault"),
 					slog.String("file", varsFile),
 					slog.String("commit", c.Hash.String()),
 				)
@@ -255,6 +292,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 			hashesMap[k].hash = c.Hash.String()
 			hashesMap[k].hashTime = c.Author.When
 			hashesMap[k].author = c.Author.Name
+			hashesMap[k].message = commitSubject(c)
 		}
 
 		return nil
@@ -269,6 +307,7 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 			hashesMap[k].hash = danglingCommit.Hash.String()
 			hashesMap[k].hashTime = danglingCommit.Author.When
 			hashesMap[k].author = danglingCommit.Author.Name
+			hashesMap[k].message = commitSubject(danglingCommit)
 		}
 
 		danglingCommit = nil
@@ -293,10 +332,15 @@ func (s *Sync) findVariableUpdateTime(varsFile string, inv *sync.Inventory, repo
 				return errors.New(msg)
 			}
 
-			s.Log().Warn(msg)
+			s.Log().Warn(s.warn(warning.Overridden, n, "%s", msg))
+			s.result.Overridden = append(s.result.Overridden, OverriddenResource{
+				Name: n,
+				Type: overrideTypeVariable,
+			})
 		}
 
 		tri := sync.NewTimelineVariablesItem(version, hm.hash, hm.hashTime, s.Term())
+		tri.SetMessages(hm.message, nil)
 		tri.AddVariable(v)
 
 		s.timeline = sync.AddToTimeline(s.timeline, tri)
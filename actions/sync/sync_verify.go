@@ -0,0 +1,72 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// VersionMismatch is a component which meta version doesn't match the version applied by sync,
+// e.g. edited concurrently.
+type VersionMismatch struct {
+	Component string `json:"component"`
+	File      string `json:"file"`
+	Expected  string `json:"expected"`
+	Actual    string `json:"actual"`
+}
+
+// verifyApplied re-reads meta files of versions applied by the run and reports the ones not matching the plan.
+// A missing or unreadable meta file is a mismatch with an empty actual version.
+func (s *Sync) verifyApplied() error {
+	if s.NoVerify || s.DryRun || len(s.applied) == 0 {
+		return nil
+	}
+
+	defer s.stage(stageVerify, "")()
+
+	// The latest change of a component is the expected version.
+	var order []string
+	expected := make(map[string]JournalEntry)
+	for _, change := range s.applied {
+		if _, ok := expected[change.Component]; !ok {
+			order = append(order, change.Component)
+		}
+		expected[change.Component] = change
+	}
+
+	for _, name := range order {
+		change := expected[name]
+		c, err := sync.NewComponent(change.Component, s.recordedPrefix(change))
+		if err != nil {
+			return err
+		}
+
+		actual, debug, err := c.GetVersion()
+		for _, d := range debug {
+			s.Log().Debug("error", "message", d)
+		}
+		if err != nil {
+			s.Log().Debug("can't read version after sync", "component", name, "error", err)
+		}
+
+		if err == nil && actual == change.NewVersion {
+			continue
+		}
+
+		s.result.VersionMismatches = append(s.result.VersionMismatches, VersionMismatch{
+			Component: name,
+			File:      change.MetaPath,
+			Expected:  change.NewVersion,
+			Actual:    actual,
+		})
+		s.Term().Warning().Println(s.warn(warning.Mismatch, name, "- %s: expected %s after sync, found %s", name, change.NewVersion, actual))
+	}
+
+	if len(s.result.VersionMismatches) > 0 {
+		return fmt.Errorf("%d component(s) don't have the synced version, edited concurrently? (skip the check with --no-verify)", len(s.result.VersionMismatches))
+	}
+
+	s.Log().Info("Synced versions verified", "components", len(order))
+	return nil
+}
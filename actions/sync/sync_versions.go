@@ -0,0 +1,45 @@
+package sync
+
+import (
+	"fmt"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+
+	"github.com/plasmash/plasmactl-component/internal/repository"
+)
+
+// VersionCommit is the commit a version hash was taken from, and the bump commit which set the version.
+type VersionCommit struct {
+	Commit string
+	Bump   string
+}
+
+// VersionCommits maps version hashes to their commits, grouping history from the commit by bump commits the way
+// sync does to find when components changed. Bump is empty for commits not bumped yet at the commit.
+func VersionCommits(r *git.Repository, from plumbing.Hash, authors *repository.BumpAuthors) (map[string]VersionCommit, error) {
+	boundary, err := repository.ShallowBoundary(r)
+	if err != nil {
+		return nil, err
+	}
+	shallow, err := repository.ShallowParents(r, boundary)
+	if err != nil {
+		return nil, err
+	}
+
+	_, hashes, err := collectCommitsFrom(r, from, "", authors, shallow)
+	if err != nil {
+		return nil, fmt.Errorf("collect commits of %s > %w", from, err)
+	}
+
+	versions := make(map[string]VersionCommit, len(hashes))
+	for version, h := range hashes {
+		vc := VersionCommit{Commit: h["original"]}
+		if section := h["section"]; section != headGroupName && section != h["original"] {
+			vc.Bump = section
+		}
+		versions[version] = vc
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,202 @@
+package variables
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/launchrctl/keyring"
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// VariableItem represents a variable with its definitions and consumers.
+type VariableItem struct {
+	Name       string   `json:"name"`
+	Platform   string   `json:"platform"`
+	Vault      bool     `json:"vault"`
+	Files      []string `json:"files"`
+	Components []string `json:"components,omitempty"`
+	Variables  []string `json:"variables,omitempty"`
+	// Usages are the references making components consume the variable, with their file and line.
+	Usages []sync.VariableUsage `json:"usages,omitempty"`
+}
+
+// VariablesResult is the structured result of component:variables.
+type VariablesResult struct {
+	Variables []VariableItem `json:"variables,omitempty"`
+	Rename    *RenameResult  `json:"rename,omitempty"`
+	Warnings  warning.List   `json:"warnings,omitempty"`
+}
+
+// Variables implements component:variables command
+type Variables struct {
+	action.WithLogger
+	action.WithTerm
+
+	Keyring keyring.Keyring
+
+	// Arguments
+	Command []string
+
+	Source            string
+	VaultPass         string
+	VaultPassProvider vaultpass.Provider
+	Filter            string
+	Unused            bool
+	DryRun            bool
+
+	result *VariablesResult
+}
+
+// Result returns the structured result for JSON output.
+func (v *Variables) Result() any {
+	return v.result
+}
+
+// Execute runs the variables action
+func (v *Variables) Execute() error {
+	if len(v.Command) > 0 {
+		switch v.Command[0] {
+		case "rename":
+			if len(v.Command) != 3 {
+				return fmt.Errorf("usage: component:variables rename OLD NEW")
+			}
+			return v.rename(v.Command[1], v.Command[2])
+		default:
+			return fmt.Errorf("unknown command %q", v.Command[0])
+		}
+	}
+
+	inv, err := v.loadInventory()
+	if err != nil {
+		return err
+	}
+
+	v.result = &VariablesResult{Variables: v.collect(inv)}
+	if len(v.result.Variables) == 0 {
+		v.Term().Warning().Println(v.result.Warnings.Add(warning.Empty, "", "No variables found"))
+		return nil
+	}
+
+	v.print()
+	return nil
+}
+
+// loadInventory builds inventory and calculates variables usage.
+func (v *Variables) loadInventory() (*sync.Inventory, error) {
+	pass, err := vaultpass.Resolve(v.VaultPass, v.VaultPassProvider, v.Keyring, v.Term(), "Ansible vault password")
+	if err != nil {
+		return nil, err
+	}
+
+	inv, err := sync.NewInventory(v.Source, v.Log())
+	if err != nil {
+		return nil, err
+	}
+
+	if err = inv.CalculateVariablesUsage(pass); err != nil {
+		return nil, fmt.Errorf("calculate variables usage > %w", err)
+	}
+
+	return inv, nil
+}
+
+// collect builds sorted list of variables from inventory maps.
+func (v *Variables) collect(inv *sync.Inventory) []VariableItem {
+	variablesMap := inv.GetVariableVariablesDependencyMap()
+
+	var items []VariableItem
+	for name, platforms := range inv.GetVariableFilesMap() {
+		if v.Filter != "" && !strings.Contains(name, v.Filter) {
+			continue
+		}
+
+		for platform, files := range platforms {
+			item := VariableItem{
+				Name:       name,
+				Platform:   platform,
+				Files:      uniqueSorted(files),
+				Components: uniqueSorted(inv.GetVariableComponents(name, platform)),
+				Usages:     inv.GetVariableUsages(name, platform),
+			}
+
+			for _, f := range files {
+				if sync.IsVaultFile(f) {
+					item.Vault = true
+				}
+			}
+
+			// Variables referencing this one directly.
+			if dep, ok := variablesMap[name][platform]; ok {
+				for dependent, dependentPlatforms := range dep.Dependent {
+					for p := range dependentPlatforms {
+						item.Variables = append(item.Variables, fmt.Sprintf("%s (%s)", dependent, p))
+					}
+				}
+				item.Variables = uniqueSorted(item.Variables)
+			}
+
+			if v.Unused && len(item.Components) > 0 {
+				continue
+			}
+
+			items = append(items, item)
+		}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].Name != items[j].Name {
+			return items[i].Name < items[j].Name
+		}
+		return items[i].Platform < items[j].Platform
+	})
+
+	return items
+}
+
+func (v *Variables) print() {
+	for _, item := range v.result.Variables {
+		name := fmt.Sprintf("%s (%s)", item.Name, item.Platform)
+		if item.Vault {
+			name += " [vault]"
+		}
+
+		v.Term().Info().Println(name)
+		v.Term().Printfln("  files: %s", strings.Join(item.Files, ", "))
+		if len(item.Components) > 0 {
+			v.Term().Printfln("  components: %s", strings.Join(item.Components, ", "))
+		}
+		for _, u := range item.Usages {
+			source := u.Source
+			if u.Variable != item.Name {
+				source += ", via " + u.Variable
+			}
+			v.Term().Printfln("    %s: %s:%d (%s)", u.Component, u.File, u.Line, source)
+		}
+		if len(item.Variables) > 0 {
+			v.Term().Printfln("  variables: %s", strings.Join(item.Variables, ", "))
+		}
+	}
+}
+
+func uniqueSorted(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+
+	seen := make(map[string]bool, len(list))
+	result := make([]string, 0, len(list))
+	for _, s := range list {
+		if !seen[s] {
+			seen[s] = true
+			result = append(result, s)
+		}
+	}
+
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,294 @@
+package variables
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	vault "github.com/sosedoff/ansible-vault-go"
+
+	"github.com/plasmash/plasmactl-component/internal/jinja"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+var variableRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RenamedFile represents a file changed by variable rename.
+type RenamedFile struct {
+	Path    string `json:"path"`
+	Changes int    `json:"changes"`
+	Vault   bool   `json:"vault,omitempty"`
+}
+
+// RenameResult is the structured result of variable rename.
+type RenameResult struct {
+	Old    string        `json:"old"`
+	New    string        `json:"new"`
+	Files  []RenamedFile `json:"files"`
+	DryRun bool          `json:"dry_run"`
+}
+
+// rename renames variable across variables files, component defaults and Jinja templates.
+func (v *Variables) rename(oldName, newName string) error {
+	for _, n := range []string{oldName, newName} {
+		if !variableRe.MatchString(n) {
+			return fmt.Errorf("invalid variable name %q", n)
+		}
+	}
+	if oldName == newName {
+		return fmt.Errorf("old and new variable names are the same")
+	}
+
+	pass, err := vaultpass.Resolve(v.VaultPass, v.VaultPassProvider, v.Keyring, v.Term(), "Ansible vault password")
+	if err != nil {
+		return err
+	}
+
+	inv, err := sync.NewInventory(v.Source, v.Log())
+	if err != nil {
+		return err
+	}
+
+	if err = inv.CalculateVariablesUsage(pass); err != nil {
+		return fmt.Errorf("calculate variables usage > %w", err)
+	}
+
+	filesMap := inv.GetVariableFilesMap()
+	if _, ok := filesMap[oldName]; !ok {
+		return fmt.Errorf("variable %q not found", oldName)
+	}
+	if _, ok := filesMap[newName]; ok {
+		return fmt.Errorf("variable %q already exists", newName)
+	}
+
+	// Every file is renamed, and vaults decrypted and encrypted again, before writing any, so a failure leaves
+	// files untouched.
+	files := v.affectedFiles(inv, oldName)
+	result := &RenameResult{Old: oldName, New: newName, DryRun: v.DryRun}
+	updated := make(map[string][]byte, len(files))
+	for _, path := range files {
+		changed, content, errFile := v.renameInFile(path, oldName, newName, pass)
+		if errFile != nil {
+			return errFile
+		}
+		if changed.Changes > 0 {
+			result.Files = append(result.Files, changed)
+			updated[path] = content
+		}
+	}
+
+	v.result = &VariablesResult{Rename: result}
+	if len(result.Files) == 0 {
+		v.Term().Warning().Println(v.result.Warnings.Add(warning.Empty, oldName, "No occurrences of %s found", oldName))
+		return nil
+	}
+
+	if v.DryRun {
+		v.Term().Info().Printfln("Dry-run: %s would be renamed to %s in %d file(s)", oldName, newName, len(result.Files))
+		return nil
+	}
+
+	for i, f := range result.Files {
+		if err = writeFile(filepath.Join(v.Source, f.Path), updated[f.Path]); err != nil {
+			v.Term().Error().Printfln("Renamed %s in %d of %d file(s), the rest still use it", oldName, i, len(result.Files))
+			return err
+		}
+	}
+
+	v.Term().Success().Printfln("Renamed %s to %s in %d file(s)", oldName, newName, len(result.Files))
+	return nil
+}
+
+// affectedFiles collects files defining the variable, files of dependent variables and files of consuming components.
+func (v *Variables) affectedFiles(inv *sync.Inventory, name string) []string {
+	unique := make(map[string]bool)
+	components := make(map[string]bool)
+
+	for platform, files := range inv.GetVariableFilesMap()[name] {
+		for _, f := range files {
+			unique[f] = true
+		}
+
+		for _, c := range inv.GetVariableComponents(name, platform) {
+			components[c] = true
+		}
+
+		// Variables referencing renamed variable in their values.
+		dependent := make(map[string]map[string]bool)
+		if dep, ok := inv.GetVariableVariablesDependencyMap()[name][platform]; ok {
+			sync.GatherDependentKeys(dep, dependent)
+		}
+		for dn, platforms := range dependent {
+			for dp := range platforms {
+				for _, f := range inv.GetVariableFiles(dn, dp) {
+					unique[f] = true
+				}
+			}
+		}
+	}
+
+	for c := range components {
+		for _, f := range v.componentFiles(c) {
+			unique[f] = true
+		}
+	}
+
+	result := make([]string, 0, len(unique))
+	for f := range unique {
+		result = append(result, f)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+// componentFiles returns defaults, vars, tasks and templates files of the component relative to source.
+func (v *Variables) componentFiles(name string) []string {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	var result []string
+	candidates := []string{
+		filepath.Join(parts[0], parts[1], parts[2]),
+		filepath.Join(parts[0], parts[1], "roles", parts[2]),
+	}
+	for _, dir := range candidates {
+		_ = filepath.Walk(filepath.Join(v.Source, dir), func(path string, info os.FileInfo, err error) error {
+			if err != nil || info.IsDir() {
+				return nil
+			}
+
+			rel, _ := filepath.Rel(v.Source, path)
+			section := strings.Split(strings.TrimPrefix(rel, dir+string(filepath.Separator)), string(filepath.Separator))[0]
+			ext := filepath.Ext(path)
+			switch section {
+			case "defaults", "vars", "tasks":
+				if ext == ".yaml" || ext == ".yml" {
+					result = append(result, rel)
+				}
+			case "templates":
+				if ext == ".j2" {
+					result = append(result, rel)
+				}
+			}
+
+			return nil
+		})
+	}
+
+	return result
+}
+
+// renameInFile replaces variable definition and Jinja references in file, printing diff of changed lines.
+// It returns the content to write, encrypted again for vault files.
+func (v *Variables) renameInFile(path, oldName, newName, pass string) (RenamedFile, []byte, error) {
+	file := RenamedFile{Path: path, Vault: sync.IsVaultFile(path)}
+	fullPath := filepath.Join(v.Source, path)
+
+	var content string
+	if file.Vault {
+		decrypted, err := vault.DecryptFile(fullPath, pass)
+		if err != nil {
+			return file, nil, fmt.Errorf("decrypt %s > %w", path, err)
+		}
+		content = decrypted
+	} else {
+		data, err := os.ReadFile(filepath.Clean(fullPath))
+		if err != nil {
+			return file, nil, err
+		}
+		content = string(data)
+	}
+
+	isVars := filepath.Ext(path) != ".j2" && !strings.Contains(path, string(filepath.Separator)+"tasks"+string(filepath.Separator))
+	updated := renameInContent(content, oldName, newName, isVars)
+
+	// Names never span lines, renamed content keeps lines of the original.
+	oldLines := strings.Split(content, "\n")
+	newLines := strings.Split(updated, "\n")
+	for i := range oldLines {
+		if newLines[i] != oldLines[i] {
+			file.Changes++
+		}
+	}
+
+	if file.Changes == 0 {
+		return file, nil, nil
+	}
+
+	v.Term().Info().Printfln("--- %s", path)
+	if file.Vault {
+		v.Term().Printfln("  (%d line(s) changed in vault, content hidden)", file.Changes)
+	} else {
+		for i := range oldLines {
+			if oldLines[i] != newLines[i] {
+				v.Term().Printfln("- %s", oldLines[i])
+				v.Term().Printfln("+ %s", newLines[i])
+			}
+		}
+	}
+
+	if !file.Vault {
+		return file, []byte(updated), nil
+	}
+
+	encrypted, err := vault.Encrypt(updated, pass)
+	if err != nil {
+		return file, nil, fmt.Errorf("encrypt %s > %w", path, err)
+	}
+
+	return file, []byte(encrypted), nil
+}
+
+// writeFile overwrites the file keeping its permissions.
+func writeFile(path string, content []byte) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, info.Mode())
+}
+
+// renameInContent renames top-level key definitions (for variables files) and references inside Jinja tags.
+func renameInContent(content, oldName, newName string, isVars bool) string {
+	if isVars {
+		lines := strings.Split(content, "\n")
+		for i, line := range lines {
+			lines[i] = renameDefinition(line, oldName, newName)
+		}
+		content = strings.Join(lines, "\n")
+	}
+
+	return replaceIdentifier(content, oldName, newName)
+}
+
+// renameDefinition renames the top-level key defined by the line.
+func renameDefinition(line, oldName, newName string) string {
+	rest, ok := strings.CutPrefix(line, oldName)
+	if ok && strings.HasPrefix(strings.TrimLeft(rest, " "), ":") {
+		return newName + rest
+	}
+
+	return line
+}
+
+// replaceIdentifier replaces variable references inside Jinja tags of the content. Attributes, filters, longer names
+// and text outside tags or in strings are kept.
+func replaceIdentifier(content, oldName, newName string) string {
+	names := jinja.Names(content)
+	for i := len(names) - 1; i >= 0; i-- {
+		if n := names[i]; n.Name == oldName {
+			content = content[:n.Pos] + newName + content[n.Pos+len(oldName):]
+		}
+	}
+
+	return content
+}
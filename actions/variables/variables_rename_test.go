@@ -0,0 +1,51 @@
+package variables
+
+import "testing"
+
+func TestReplaceIdentifier(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{"expression", "{{ foo }}", "{{ bar }}"},
+		{"prefix collision", "{{ foo_bar }} {{ foo }} {{ my_foo }}", "{{ foo_bar }} {{ bar }} {{ my_foo }}"},
+		{"attribute and filter", "{{ cfg.foo | foo }} {{ foo.port }}", "{{ cfg.foo | foo }} {{ bar.port }}"},
+		{"outside tags", "foo: {{ foo }} # foo", "foo: {{ bar }} # foo"},
+		{"strings and comments", "{{ 'foo' ~ foo }}{# foo #}", "{{ 'foo' ~ bar }}{# foo #}"},
+		{"statements", "{% if foo is defined %}{% for x in foo %}{{ x }}{% endfor %}{% endif %}",
+			"{% if bar is defined %}{% for x in bar %}{{ x }}{% endfor %}{% endif %}"},
+		{"tag over lines", "{{ [\n  foo,\n  foo_bar,\n] }}", "{{ [\n  bar,\n  foo_bar,\n] }}"},
+		{"whitespace control", "{{- foo -}}{%- set y = foo -%}", "{{- bar -}}{%- set y = bar -%}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replaceIdentifier(tt.src, "foo", "bar"); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestRenameInContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		isVars   bool
+		expected string
+	}{
+		{"definition", "foo: 1\nfoo_bar: 2\nfoo : 3\n", true, "bar: 1\nfoo_bar: 2\nbar : 3\n"},
+		{"nested keys kept", "config:\n  foo: 1\n", true, "config:\n  foo: 1\n"},
+		{"reference in value", "foo_url: \"{{ foo }}/path\"\n", true, "foo_url: \"{{ bar }}/path\"\n"},
+		{"no definition outside variables files", "foo: \"{{ foo }}\"\n", false, "foo: \"{{ bar }}\"\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := renameInContent(tt.src, "foo", "bar", tt.isVars); got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
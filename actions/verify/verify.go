@@ -0,0 +1,191 @@
+package verify
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/launchrctl/launchr/pkg/action"
+
+	"github.com/plasmash/plasmactl-component/internal/provenance"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// Verification statuses.
+const (
+	StatusValid            = "valid"
+	StatusUnsigned         = "unsigned"
+	StatusTampered         = "tampered"
+	StatusInvalidSignature = "invalid-signature"
+	StatusError            = "error"
+)
+
+// VerifiedComponent is the provenance verification of a component.
+type VerifiedComponent struct {
+	Name    string `json:"name"`
+	Status  string `json:"status"`
+	KeyID   string `json:"key_id,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// VerifyResult is the structured result of component:verify.
+type VerifyResult struct {
+	Components []VerifiedComponent `json:"components"`
+	Valid      int                 `json:"valid"`
+	Unsigned   int                 `json:"unsigned"`
+	Failed     int                 `json:"failed"`
+	Warnings   warning.List        `json:"warnings,omitempty"`
+}
+
+// Verify implements component:verify command
+type Verify struct {
+	action.WithLogger
+	action.WithTerm
+
+	Provenance       bool
+	PublicKeys       []string
+	RequireSignature bool
+
+	Components []string
+	Source     string
+
+	result *VerifyResult
+}
+
+// Result returns the structured result for JSON output.
+func (v *Verify) Result() any {
+	return v.result
+}
+
+// Execute runs the verify action
+func (v *Verify) Execute() error {
+	if !v.Provenance {
+		return errors.New("nothing to verify, use --provenance")
+	}
+
+	if len(v.PublicKeys) == 0 {
+		return errors.New("no trusted public key, set --public-key or component.provenance.public_keys")
+	}
+
+	keys, err := provenance.LoadPublicKeys(v.PublicKeys...)
+	if err != nil {
+		return err
+	}
+
+	components, err := v.selectComponents()
+	if err != nil {
+		return err
+	}
+
+	v.result = &VerifyResult{Components: []VerifiedComponent{}}
+	for _, c := range components {
+		verified := v.verify(c.Name, keys)
+		v.result.Components = append(v.result.Components, verified)
+
+		switch verified.Status {
+		case StatusValid:
+			v.result.Valid++
+			v.Term().Printfln("- %s: %s (%s)", verified.Name, verified.Status, verified.KeyID)
+		case StatusUnsigned:
+			v.result.Unsigned++
+			v.Term().Warning().Println(v.result.Warnings.Add(warning.Unsigned, verified.Name, "- %s: %s", verified.Name, verified.Status))
+		default:
+			v.result.Failed++
+			v.Term().Error().Printfln("- %s: %s (%s)", verified.Name, verified.Status, verified.Message)
+		}
+	}
+
+	v.Term().Info().Printfln("%d valid, %d unsigned, %d failed", v.result.Valid, v.result.Unsigned, v.result.Failed)
+
+	if v.result.Failed > 0 {
+		return fmt.Errorf("provenance verification failed for %d component(s)", v.result.Failed)
+	}
+	if v.RequireSignature && v.result.Unsigned > 0 {
+		return fmt.Errorf("%d component(s) aren't signed", v.result.Unsigned)
+	}
+
+	return nil
+}
+
+func (v *Verify) verify(name string, keys []ed25519.PublicKey) VerifiedComponent {
+	verified := VerifiedComponent{Name: name}
+
+	dir, err := v.componentDir(name)
+	if err != nil {
+		verified.Status = StatusError
+		verified.Message = err.Error()
+		return verified
+	}
+
+	sig, err := provenance.Verify(dir, keys)
+	if sig != nil {
+		verified.KeyID = sig.KeyID
+	}
+
+	switch {
+	case err == nil:
+		verified.Status = StatusValid
+	case errors.Is(err, provenance.ErrUnsigned):
+		verified.Status = StatusUnsigned
+	case errors.Is(err, provenance.ErrTampered):
+		verified.Status = StatusTampered
+		verified.Message = err.Error()
+	case errors.Is(err, provenance.ErrInvalidSignature):
+		verified.Status = StatusInvalidSignature
+		verified.Message = err.Error()
+	default:
+		verified.Status = StatusError
+		verified.Message = err.Error()
+	}
+
+	return verified
+}
+
+// selectComponents returns source components, only the given ones if any.
+func (v *Verify) selectComponents() (component.Components, error) {
+	components, err := component.LoadFromPath(v.Source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(v.Components) == 0 {
+		return components, nil
+	}
+
+	var unknown []string
+	names := make(map[string]bool, len(v.Components))
+	for _, name := range v.Components {
+		if components.Find(name) == nil {
+			unknown = append(unknown, name)
+		}
+		names[name] = true
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown component(s): %s", strings.Join(unknown, ", "))
+	}
+
+	return components.Filter(func(c component.Component) bool { return names[c.Name] }), nil
+}
+
+// componentDir returns the component directory in the source, in the root or roles layout.
+func (v *Verify) componentDir(name string) (string, error) {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("invalid component MRN %q", name)
+	}
+
+	for _, dir := range []string{
+		filepath.Join(v.Source, parts[0], parts[1], parts[2]),
+		filepath.Join(v.Source, parts[0], parts[1], "roles", parts[2]),
+	} {
+		if _, err := os.Stat(filepath.Join(dir, "meta", "plasma.yaml")); err == nil {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("meta file of %s not found", name)
+}
@@ -0,0 +1,168 @@
+// Package architecture validates component dependencies against allowed layer/kind combinations.
+package architecture
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Wildcard matches any layer or kind.
+const Wildcard = "*"
+
+// Rule declares which dependencies are allowed for components matching From.
+// Selectors use "layer" or "layer.kind" notation, "*" matches anything.
+type Rule struct {
+	From  string   `yaml:"from"`
+	Allow []string `yaml:"allow"`
+	Deny  []string `yaml:"deny"`
+}
+
+// Matrix is a set of dependency rules. The most specific rule matching a component applies.
+type Matrix []Rule
+
+// Violation describes a dependency not permitted by the matrix.
+type Violation struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Rule   string `json:"rule"`
+	Reason string `json:"reason"`
+}
+
+// String implements [fmt.Stringer] interface.
+func (v Violation) String() string {
+	return fmt.Sprintf("%s -> %s: %s (rule %q)", v.From, v.To, v.Reason, v.Rule)
+}
+
+// Validate checks that all rule selectors are well-formed.
+func (m Matrix) Validate() error {
+	seen := make(map[string]bool, len(m))
+	for i, r := range m {
+		if err := validateSelector(r.From); err != nil {
+			return fmt.Errorf("rule #%d from > %w", i, err)
+		}
+		if seen[r.From] {
+			return fmt.Errorf("rule #%d: duplicated rule for %q", i, r.From)
+		}
+		seen[r.From] = true
+
+		for _, s := range append(append([]string{}, r.Allow...), r.Deny...) {
+			if err := validateSelector(s); err != nil {
+				return fmt.Errorf("rule %q > %w", r.From, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Check returns a violation if dependency from component "from" to component "to" is not allowed.
+// Components are given in MRN format (layer.kind.name). Nil is returned when the dependency is allowed.
+func (m Matrix) Check(from, to string) *Violation {
+	rule := m.ruleFor(from)
+	if rule == nil {
+		return nil
+	}
+
+	for _, s := range rule.Deny {
+		if matches(s, to) {
+			return &Violation{From: from, To: to, Rule: rule.From, Reason: fmt.Sprintf("%s is denied", s)}
+		}
+	}
+
+	if len(rule.Allow) == 0 {
+		return nil
+	}
+
+	for _, s := range rule.Allow {
+		if matches(s, to) {
+			return nil
+		}
+	}
+
+	return &Violation{From: from, To: to, Rule: rule.From, Reason: "not in allowed list"}
+}
+
+// CheckAll checks dependencies map (component to its dependencies) and returns sorted violations.
+func (m Matrix) CheckAll(deps map[string][]string) []Violation {
+	var violations []Violation
+	for from, list := range deps {
+		for _, to := range list {
+			if v := m.Check(from, to); v != nil {
+				violations = append(violations, *v)
+			}
+		}
+	}
+
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].From != violations[j].From {
+			return violations[i].From < violations[j].From
+		}
+		return violations[i].To < violations[j].To
+	})
+
+	return violations
+}
+
+// ruleFor returns the most specific rule matching the component.
+func (m Matrix) ruleFor(name string) *Rule {
+	var found *Rule
+	best := -1
+	for i := range m {
+		if !matches(m[i].From, name) {
+			continue
+		}
+
+		if spec := specificity(m[i].From); spec > best {
+			best = spec
+			found = &m[i]
+		}
+	}
+
+	return found
+}
+
+// matches checks if the component MRN matches the selector.
+func matches(selector, name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return false
+	}
+
+	sel := strings.Split(selector, ".")
+	for i, s := range sel {
+		if s != Wildcard && s != parts[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// specificity ranks selectors: non-wildcard segments count as more specific.
+func specificity(selector string) int {
+	spec := 0
+	for _, s := range strings.Split(selector, ".") {
+		spec *= 2
+		if s != Wildcard {
+			spec++
+		}
+	}
+
+	return spec
+}
+
+func validateSelector(selector string) error {
+	sel := strings.Split(selector, ".")
+	if selector == "" || len(sel) > 2 {
+		return fmt.Errorf("invalid selector %q (expected: layer or layer.kind)", selector)
+	}
+
+	for _, s := range sel {
+		if s == "" {
+			return fmt.Errorf("invalid selector %q (expected: layer or layer.kind)", selector)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package architecture
+
+import "testing"
+
+func TestMatrixCheck(t *testing.T) {
+	m := Matrix{
+		{From: "*", Deny: []string{"interaction"}},
+		{From: "foundation", Allow: []string{"foundation"}},
+		{From: "foundation.applications", Allow: []string{"foundation.services", "interaction.softwares"}},
+		{From: "*.softwares", Allow: []string{"*.softwares"}},
+		{From: "interaction.*", Allow: []string{"*"}, Deny: []string{"cognition.skills"}},
+	}
+	if err := m.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		from, to string
+		rule     string // empty when the dependency is allowed
+	}{
+		// Wildcard rule applies to components without more specific rule.
+		{"cognition.skills.analyzer", "foundation.services.postgres", ""},
+		{"cognition.skills.analyzer", "interaction.applications.dashboards", "*"},
+		// Layer rule wins over the wildcard one.
+		{"foundation.services.postgres", "foundation.softwares.pgbouncer", ""},
+		{"foundation.services.postgres", "cognition.skills.analyzer", "foundation"},
+		// Layer and kind rule wins over the layer one.
+		{"foundation.applications.auth", "foundation.services.postgres", ""},
+		{"foundation.applications.auth", "interaction.softwares.grafana", ""},
+		{"foundation.applications.auth", "foundation.softwares.pgbouncer", "foundation.applications"},
+		// Layer wildcard and kind rule ranks as a layer rule, the first one declared applies.
+		{"foundation.softwares.pgbouncer", "cognition.softwares.redis", "foundation"},
+		{"cognition.softwares.redis", "foundation.softwares.pgbouncer", ""},
+		{"cognition.softwares.redis", "foundation.services.postgres", "*.softwares"},
+		// Layer and kind wildcard rule ranks above a layer rule, deny wins over allow.
+		{"interaction.applications.dashboards", "foundation.services.postgres", ""},
+		{"interaction.applications.dashboards", "cognition.skills.analyzer", "interaction.*"},
+		// Malformed names match no rule.
+		{"foundation.postgres", "interaction.applications.dashboards", ""},
+	}
+
+	for _, tt := range tests {
+		v := m.Check(tt.from, tt.to)
+		switch {
+		case tt.rule == "" && v != nil:
+			t.Errorf("%s -> %s: expected allowed, got %s", tt.from, tt.to, v)
+		case tt.rule != "" && v == nil:
+			t.Errorf("%s -> %s: expected violation of rule %q", tt.from, tt.to, tt.rule)
+		case tt.rule != "" && v.Rule != tt.rule:
+			t.Errorf("%s -> %s: expected violation of rule %q, got %s", tt.from, tt.to, tt.rule, v)
+		}
+	}
+
+	v := m.Check("interaction.applications.dashboards", "cognition.skills.analyzer")
+	if v == nil || v.Reason != "cognition.skills is denied" {
+		t.Errorf("expected deny reason, got %v", v)
+	}
+	if v = m.Check("foundation.applications.auth", "foundation.softwares.pgbouncer"); v == nil || v.Reason != "not in allowed list" {
+		t.Errorf("expected allowed list reason, got %v", v)
+	}
+
+	violations := m.CheckAll(map[string][]string{
+		"foundation.services.postgres":        {"interaction.softwares.grafana", "foundation.services.keycloak"},
+		"cognition.skills.analyzer":           {"interaction.applications.dashboards"},
+		"interaction.applications.dashboards": {"cognition.skills.analyzer"},
+	})
+	if len(violations) != 3 || violations[0].From != "cognition.skills.analyzer" || violations[2].From != "interaction.applications.dashboards" {
+		t.Errorf("expected sorted violations, got %+v", violations)
+	}
+}
+
+func TestMatrixValidate(t *testing.T) {
+	for _, invalid := range []Matrix{
+		{{From: ""}},
+		{{From: "foundation.services.postgres"}},
+		{{From: "foundation."}},
+		{{From: "foundation"}, {From: "foundation"}},
+		{{From: "foundation", Allow: []string{"a.b.c"}}},
+		{{From: "foundation", Deny: []string{""}}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected %+v to be invalid", invalid)
+		}
+	}
+}
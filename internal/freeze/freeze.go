@@ -0,0 +1,95 @@
+// Package freeze matches components against release freeze windows declared in the project config.
+package freeze
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+)
+
+// DateLayout is the format of window dates.
+const DateLayout = "2006-01-02"
+
+// Window is a period during which components attached to the chassis scopes must not be modified by sync.
+// Both dates are inclusive. A window without chassis scopes freezes all components.
+type Window struct {
+	Name    string   `yaml:"name"`
+	From    string   `yaml:"from"`
+	To      string   `yaml:"to"`
+	Chassis []string `yaml:"chassis"`
+}
+
+// Windows is a set of freeze windows.
+type Windows []Window
+
+// String implements [fmt.Stringer] interface.
+func (w Window) String() string {
+	if w.Name != "" {
+		return fmt.Sprintf("%s (%s..%s)", w.Name, w.From, w.To)
+	}
+	return fmt.Sprintf("%s..%s", w.From, w.To)
+}
+
+// Validate checks that all windows have well-formed and ordered dates.
+func (ws Windows) Validate() error {
+	for i, w := range ws {
+		from, err := time.Parse(DateLayout, w.From)
+		if err != nil {
+			return fmt.Errorf("window #%d: invalid from date %q (expected: %s)", i, w.From, DateLayout)
+		}
+		to, err := time.Parse(DateLayout, w.To)
+		if err != nil {
+			return fmt.Errorf("window #%d: invalid to date %q (expected: %s)", i, w.To, DateLayout)
+		}
+		if to.Before(from) {
+			return fmt.Errorf("window #%d: to date %s is before from date %s", i, w.To, w.From)
+		}
+		for _, c := range w.Chassis {
+			if c == "" {
+				return fmt.Errorf("window #%d: empty chassis scope", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Active returns windows covering the given time, dates are taken in its location.
+func (ws Windows) Active(t time.Time) Windows {
+	var active Windows
+	for _, w := range ws {
+		from, errFrom := time.ParseInLocation(DateLayout, w.From, t.Location())
+		to, errTo := time.ParseInLocation(DateLayout, w.To, t.Location())
+		if errFrom != nil || errTo != nil {
+			continue
+		}
+
+		if !t.Before(from) && t.Before(to.AddDate(0, 0, 1)) {
+			active = append(active, w)
+		}
+	}
+
+	return active
+}
+
+// Match returns the first window freezing a component attached to the chassis sections.
+// A component is in scope when attached to a scope section, one of its children or its ancestors,
+// as the latter also runs on the scope hosts.
+func (ws Windows) Match(chassis []string) (Window, bool) {
+	for _, w := range ws {
+		if len(w.Chassis) == 0 {
+			return w, true
+		}
+
+		for _, scope := range w.Chassis {
+			for _, c := range chassis {
+				if playbook.IsAncestorOrSelf(scope, c) || playbook.IsAncestorOrSelf(c, scope) {
+					return w, true
+				}
+			}
+		}
+	}
+
+	return Window{}, false
+}
@@ -0,0 +1,52 @@
+package freeze
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindows(t *testing.T) {
+	ws := Windows{
+		{Name: "year-end", From: "2026-12-15", To: "2027-01-05", Chassis: []string{"platform.interaction"}},
+		{From: "2027-02-01", To: "2027-02-01"},
+	}
+	if err := ws.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	day := func(s string) time.Time {
+		d, _ := time.Parse(DateLayout, s)
+		return d.Add(12 * time.Hour)
+	}
+	for date, expected := range map[string]int{"2026-12-14": 0, "2026-12-15": 1, "2027-01-05": 1, "2027-01-06": 0, "2027-02-01": 1} {
+		if active := ws.Active(day(date)); len(active) != expected {
+			t.Errorf("%s: expected %d active windows, got %d", date, expected, len(active))
+		}
+	}
+
+	active := ws.Active(day("2026-12-20"))
+	for chassis, expected := range map[string]bool{
+		"platform.interaction":               true,
+		"platform.interaction.observability": true,
+		"platform":                           true,
+		"platform.foundation":                false,
+	} {
+		if _, ok := active.Match([]string{chassis}); ok != expected {
+			t.Errorf("%s: expected match %v", chassis, expected)
+		}
+	}
+	if _, ok := ws.Active(day("2027-02-01")).Match(nil); !ok {
+		t.Error("expected window without scope to match unattached components")
+	}
+
+	for _, invalid := range []Windows{
+		{{From: "2026-12-15"}},
+		{{From: "15/12/2026", To: "2027-01-05"}},
+		{{From: "2027-01-05", To: "2026-12-15"}},
+		{{From: "2026-12-15", To: "2027-01-05", Chassis: []string{""}}},
+	} {
+		if err := invalid.Validate(); err == nil {
+			t.Errorf("expected %v to be invalid", invalid)
+		}
+	}
+}
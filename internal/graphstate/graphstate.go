@@ -0,0 +1,172 @@
+// Package graphstate records components modified since the platform graph was generated, so actions reading the
+// graph report it may be outdated until it is rebuilt.
+package graphstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+// File is the state location relative to the domain directory.
+var File = filepath.Join(".plasmactl", "graph-stale.json")
+
+// Modes of refreshing the platform graph after actions modify components.
+const (
+	ModeMark    = "mark"    // record modified components, reported by actions reading the graph
+	ModeRebuild = "rebuild" // rebuild the graph, record modified components if it can't be rebuilt
+	ModeOff     = "off"     // leave the graph as is
+)
+
+// maxListed is the number of modified components named in the outdated graph warning.
+const maxListed = 5
+
+// Mode is how the platform graph is refreshed after actions modify components, ModeMark if empty.
+type Mode string
+
+// Validate checks the mode is known.
+func (m Mode) Validate() error {
+	switch m {
+	case "", ModeMark, ModeRebuild, ModeOff:
+		return nil
+	}
+	return fmt.Errorf("unknown graph refresh mode %q (expected: %s, %s or %s)", m, ModeMark, ModeRebuild, ModeOff)
+}
+
+// Builder rebuilds the platform graph of the domain directory.
+type Builder func(ctx context.Context, dir string) error
+
+// State lists components modified since the platform graph was generated.
+type State struct {
+	Since      time.Time `json:"since"`
+	Actions    []string  `json:"actions"`
+	Components []string  `json:"components"`
+}
+
+// Load reads the state of the domain directory, nil if the graph isn't known to be outdated.
+func Load(dir string) (*State, error) {
+	path := filepath.Join(dir, File)
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	s := &State{}
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("malformed graph state %s > %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Mark records components modified by the action in the state of the domain directory.
+func Mark(dir, action string, components ...string) error {
+	s, err := Load(dir)
+	if err != nil || s == nil {
+		s = &State{Since: time.Now()}
+	}
+
+	s.Actions = merge(s.Actions, action)
+	s.Components = merge(s.Components, components...)
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, File)
+	if err = os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Clear removes the state of the domain directory, once the graph is rebuilt.
+func Clear(dir string) error {
+	err := os.Remove(filepath.Join(dir, File))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Refresh updates the graph after the action modified components of the domain directory, according to the mode.
+// In rebuild mode, components are recorded if no builder is given or it fails, which is returned as warning.
+func Refresh(ctx context.Context, dir string, mode Mode, build Builder, action string, components ...string) (string, error) {
+	if len(components) == 0 {
+		return "", nil
+	}
+
+	switch mode {
+	case ModeOff:
+		return "", nil
+	case ModeRebuild:
+		if build == nil {
+			return "No plugin rebuilds the platform graph, marked as outdated", Mark(dir, action, components...)
+		}
+		if err := build(ctx, dir); err != nil {
+			return fmt.Sprintf("Failed to rebuild the platform graph, marked as outdated: %s", err), Mark(dir, action, components...)
+		}
+		return "", Clear(dir)
+	default:
+		return "", Mark(dir, action, components...)
+	}
+}
+
+// Update refreshes the graph like Refresh, the issue leaving the graph outdated is added to the warnings and printed
+// to the terminal.
+func Update(ctx context.Context, dir string, mode Mode, build Builder, term *launchr.Terminal, warnings *warning.List, action string, components ...string) error {
+	msg, err := Refresh(ctx, dir, mode, build, action, components...)
+	if msg != "" {
+		term.Warning().Println(warnings.Add(warning.StaleGraph, "", "%s", msg))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record outdated platform graph > %w", err)
+	}
+
+	return nil
+}
+
+// Check returns the warning of actions reading the graph of the domain directory, empty if it isn't known
+// to be outdated.
+func Check(dir string) string {
+	s, err := Load(dir)
+	if err != nil {
+		return fmt.Sprintf("Platform graph may be outdated: %s", err)
+	}
+	if s == nil {
+		return ""
+	}
+
+	listed := s.Components
+	if len(listed) > maxListed {
+		listed = append(listed[:maxListed:maxListed], fmt.Sprintf("and %d more", len(s.Components)-maxListed))
+	}
+
+	return fmt.Sprintf("Platform graph may be outdated, %s modified %d component(s) since %s: %s",
+		strings.Join(s.Actions, ", "), len(s.Components), s.Since.Format(time.DateTime), strings.Join(listed, ", "))
+}
+
+// merge adds values to the sorted set.
+func merge(set []string, values ...string) []string {
+	for _, v := range values {
+		if i, found := slices.BinarySearch(set, v); !found {
+			set = slices.Insert(set, i, v)
+		}
+	}
+	return set
+}
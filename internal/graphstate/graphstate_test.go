@@ -0,0 +1,86 @@
+package graphstate
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+func TestRefresh(t *testing.T) {
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	if msg := Check(dir); msg != "" {
+		t.Errorf("expected no warning without state, got %q", msg)
+	}
+
+	if _, err := Refresh(ctx, dir, ModeOff, nil, "attach", "foundation.services.postgres"); err != nil {
+		t.Fatal(err)
+	}
+	if s, _ := Load(dir); s != nil {
+		t.Errorf("expected nothing recorded in off mode, got %+v", s)
+	}
+
+	if _, err := Refresh(ctx, dir, "", nil, "attach", "foundation.services.postgres"); err != nil {
+		t.Fatal(err)
+	}
+	msg, err := Refresh(ctx, dir, ModeRebuild, nil, "depend", "foundation.applications.auth", "foundation.services.postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg == "" {
+		t.Error("expected a warning without builder")
+	}
+
+	s, err := Load(dir)
+	if err != nil || s == nil {
+		t.Fatalf("expected recorded state, got %+v, %v", s, err)
+	}
+	if strings.Join(s.Actions, ",") != "attach,depend" || strings.Join(s.Components, ",") != "foundation.applications.auth,foundation.services.postgres" {
+		t.Errorf("unexpected state %+v", s)
+	}
+	if msg = Check(dir); !strings.Contains(msg, "attach, depend modified 2 component(s)") {
+		t.Errorf("unexpected warning %q", msg)
+	}
+
+	failing := func(context.Context, string) error { return errors.New("no inventory") }
+	if msg, err = Refresh(ctx, dir, ModeRebuild, failing, "bump", "interaction.applications.dashboards"); err != nil || !strings.Contains(msg, "no inventory") {
+		t.Errorf("expected failed rebuild warning, got %q, %v", msg, err)
+	}
+
+	built := ""
+	build := func(_ context.Context, d string) error { built = d; return nil }
+	if msg, err = Refresh(ctx, dir, ModeRebuild, build, "bump", "interaction.applications.dashboards"); err != nil || msg != "" {
+		t.Errorf("expected rebuild without warning, got %q, %v", msg, err)
+	}
+	if built != dir {
+		t.Errorf("expected graph of %s rebuilt, got %q", dir, built)
+	}
+	if msg = Check(dir); msg != "" {
+		t.Errorf("expected state cleared after rebuild, got %q", msg)
+	}
+
+	if err = Mode("always").Validate(); err == nil {
+		t.Error("expected unknown mode to be invalid")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	dir := t.TempDir()
+	var warnings warning.List
+
+	if err := Update(context.Background(), dir, ModeRebuild, nil, launchr.Term(), &warnings, "attach", "foundation.services.postgres"); err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != warning.StaleGraph {
+		t.Errorf("expected outdated graph warning, got %+v", warnings)
+	}
+	if msg := Check(dir); msg == "" {
+		t.Error("expected graph marked as outdated")
+	}
+}
@@ -0,0 +1,380 @@
+// Package jinja validates Jinja2 templates of components without rendering them.
+// It reports unbalanced delimiters, brackets and block tags, unknown tags and filters missing from a known list,
+// which Ansible would otherwise only report when running the template task.
+package jinja
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Error is a template error at a position of a template.
+type Error struct {
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Filters is a set of known filter names. A filter used with its collection name, e.g. ansible.builtin.to_json,
+// is known if either the full name or, for ansible.builtin, the short name is in the set.
+type Filters map[string]bool
+
+// With returns a copy of the filters with additional names.
+func (f Filters) With(names ...string) Filters {
+	result := make(Filters, len(f)+len(names))
+	for name := range f {
+		result[name] = true
+	}
+	for _, name := range names {
+		result[name] = true
+	}
+
+	return result
+}
+
+func (f Filters) has(name string) bool {
+	if f[name] {
+		return true
+	}
+	short, ok := strings.CutPrefix(name, "ansible.builtin.")
+	return ok && f[short]
+}
+
+// DefaultFilters returns Jinja2 builtin filters and filters shipped with ansible-core.
+func DefaultFilters() Filters {
+	return Filters{}.With(
+		// Jinja2.
+		"abs", "attr", "batch", "capitalize", "center", "count", "d", "default", "dictsort", "e", "escape",
+		"filesizeformat", "first", "float", "forceescape", "format", "groupby", "indent", "int", "items", "join",
+		"last", "length", "list", "lower", "map", "max", "min", "pprint", "random", "reject", "rejectattr",
+		"replace", "reverse", "round", "safe", "select", "selectattr", "slice", "sort", "string", "striptags",
+		"sum", "title", "tojson", "trim", "truncate", "unique", "upper", "urlencode", "urlize", "wordcount",
+		"wordwrap", "xmlattr",
+		// Ansible.
+		"b64decode", "b64encode", "basename", "bool", "checksum", "combinations", "combine", "comment",
+		"dict2items", "difference", "dirname", "expanduser", "expandvars", "extract", "fileglob", "flatten",
+		"from_json", "from_yaml", "from_yaml_all", "hash", "human_readable", "human_to_bytes", "intersect",
+		"items2dict", "log", "mandatory", "md5", "password_hash", "path_join", "permutations", "pow", "product",
+		"quote", "random_mac", "realpath", "regex_escape", "regex_findall", "regex_replace", "regex_search",
+		"relpath", "root", "sha1", "shuffle", "split", "splitext", "strftime", "subelements",
+		"symmetric_difference", "ternary", "to_datetime", "to_json", "to_nice_json", "to_nice_yaml", "to_uuid",
+		"to_yaml", "type_debug", "union", "unvault", "urldecode", "urlsplit", "vault", "win_basename",
+		"win_dirname", "win_splitdrive", "zip", "zip_longest",
+	)
+}
+
+// blockTags are tags which require a closing end tag.
+var blockTags = map[string]bool{
+	"if": true, "for": true, "block": true, "macro": true, "call": true, "filter": true, "with": true,
+	"autoescape": true, "trans": true, "set": true,
+}
+
+// inlineTags are tags without closing tag.
+var inlineTags = map[string]bool{
+	"extends": true, "include": true, "import": true, "from": true, "do": true, "break": true, "continue": true,
+	"pluralize": true,
+}
+
+type block struct {
+	name string
+	pos  int
+}
+
+// tag is an expression or statement of the template, its body starts at the offset.
+type tag struct {
+	statement bool
+	body      string
+	start     int
+}
+
+type checker struct {
+	src   string
+	known Filters
+	errs  []*Error
+	stack []block
+	tags  []tag
+}
+
+// Check parses the template and returns its errors ordered by position.
+// Filters are only checked when known filters are given.
+func Check(src string, known Filters) []*Error {
+	c := &checker{src: src, known: known}
+	c.run()
+
+	for i := len(c.stack) - 1; i >= 0; i-- {
+		c.errorf(c.stack[i].pos, "unclosed %q block, expected end%s", c.stack[i].name, c.stack[i].name)
+	}
+
+	sort.SliceStable(c.errs, func(i, j int) bool {
+		if c.errs[i].Line != c.errs[j].Line {
+			return c.errs[i].Line < c.errs[j].Line
+		}
+		return c.errs[i].Column < c.errs[j].Column
+	})
+
+	return c.errs
+}
+
+func (c *checker) errorf(pos int, format string, a ...any) {
+	line := strings.Count(c.src[:pos], "\n") + 1
+	column := pos - strings.LastIndex(c.src[:pos], "\n")
+	c.errs = append(c.errs, &Error{Line: line, Column: column, Message: fmt.Sprintf(format, a...)})
+}
+
+func (c *checker) run() {
+	pos := 0
+	for {
+		i := strings.IndexByte(c.src[pos:], '{')
+		if i < 0 || pos+i+1 >= len(c.src) {
+			return
+		}
+		open := pos + i
+
+		switch c.src[open+1] {
+		case '#':
+			end := strings.Index(c.src[open+2:], "#}")
+			if end < 0 {
+				c.errorf(open, "unclosed comment")
+				return
+			}
+			pos = open + 2 + end + 2
+		case '{':
+			body, start, end, ok := c.scan(open, "}}")
+			if !ok {
+				return
+			}
+			pos = end
+			if start == 0 {
+				continue
+			}
+			if strings.TrimSpace(body) == "" {
+				c.errorf(open, "empty expression")
+			}
+			c.tags = append(c.tags, tag{body: body, start: start})
+			c.checkFilters(body, start)
+		case '%':
+			body, start, end, ok := c.scan(open, "%}")
+			if !ok {
+				return
+			}
+			pos = end
+			if start > 0 {
+				pos = c.tag(open, body, start, end)
+			}
+		default:
+			pos = open + 1
+		}
+	}
+}
+
+// scan returns the body of the tag opened at the position, without whitespace control markers,
+// with its start offset and the position after the closing delimiter. Start is 0 if the tag is malformed,
+// the error is already reported then. Scanning stops if the tag isn't closed until the end of the template.
+func (c *checker) scan(open int, closing string) (string, int, int, bool) {
+	kind := "variable"
+	if closing == "%}" {
+		kind = "block"
+	}
+
+	var brackets []int
+	for i := open + 2; i < len(c.src); i++ {
+		ch := c.src[i]
+		last := len(brackets) - 1
+		switch {
+		case ch == '\'' || ch == '"':
+			end := stringEnd(c.src, i)
+			if end < 0 {
+				c.errorf(i, "unterminated string")
+				return c.skipTo(open, closing)
+			}
+			i = end
+		case strings.HasPrefix(c.src[i:], closing) && (last < 0 || c.src[brackets[last]] != '{'):
+			if last >= 0 {
+				c.errorf(brackets[last], "unclosed %q", c.src[brackets[last]])
+			}
+			start := open + 2
+			body := c.src[start:i]
+			if trimmed := strings.TrimLeft(body, "-+"); len(trimmed) < len(body) {
+				start += len(body) - len(trimmed)
+				body = trimmed
+			}
+			return strings.TrimSuffix(body, "-"), start, i + len(closing), true
+		case last < 0 && (strings.HasPrefix(c.src[i:], "{{") || strings.HasPrefix(c.src[i:], "{%")):
+			c.errorf(open, "unclosed %s tag", kind)
+			return "", 0, i, true
+		case ch == '(' || ch == '[' || ch == '{':
+			brackets = append(brackets, i)
+		case ch == ')' || ch == ']' || ch == '}':
+			if last < 0 || !matches(c.src[brackets[last]], ch) {
+				c.errorf(i, "unexpected %q", ch)
+				return c.skipTo(open, closing)
+			}
+			brackets = brackets[:last]
+		}
+	}
+
+	c.errorf(open, "unclosed %s tag", kind)
+	return "", 0, 0, false
+}
+
+// skipTo resumes scanning after the closing delimiter once an error is reported for the tag.
+func (c *checker) skipTo(open int, closing string) (string, int, int, bool) {
+	end := strings.Index(c.src[open+2:], closing)
+	if end < 0 {
+		return "", 0, 0, false
+	}
+	return "", 0, open + 2 + end + len(closing), true
+}
+
+// tag checks block tag nesting and returns the position to continue scanning from.
+func (c *checker) tag(open int, body string, start, end int) int {
+	fields := strings.Fields(body)
+	if len(fields) == 0 {
+		c.errorf(open, "empty block tag")
+		return end
+	}
+
+	name := fields[0]
+	rest := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(body), name))
+	restStart := start + strings.Index(body, name) + len(name)
+	if name != "raw" {
+		c.tags = append(c.tags, tag{statement: true, body: body, start: start})
+	}
+
+	switch {
+	case name == "raw":
+		closing := strings.Index(c.src[end:], "endraw")
+		if closing < 0 {
+			c.errorf(open, "unclosed \"raw\" block, expected endraw")
+			return len(c.src)
+		}
+		_, _, next, ok := c.scan(strings.LastIndex(c.src[:end+closing], "{%"), "%}")
+		if !ok {
+			return len(c.src)
+		}
+		return next
+	case name == "elif" || name == "else":
+		top := c.top()
+		if top != "if" && !(name == "else" && top == "for") {
+			c.errorf(open, "unexpected %q outside of if block", name)
+		}
+	case strings.HasPrefix(name, "end"):
+		c.closeBlock(open, strings.TrimPrefix(name, "end"))
+	case name == "set":
+		// Only set without assignment captures a block.
+		if !strings.Contains(rest, "=") {
+			c.stack = append(c.stack, block{name: name, pos: open})
+		}
+	case blockTags[name]:
+		c.stack = append(c.stack, block{name: name, pos: open})
+	case inlineTags[name]:
+	default:
+		c.errorf(open, "unknown tag %q", name)
+		return end
+	}
+
+	switch name {
+	case "if", "elif", "for":
+		if rest == "" {
+			c.errorf(open, "missing expression in %q tag", name)
+		}
+		c.checkFilters(body[restStart-start:], restStart)
+	case "filter":
+		c.checkFilters("|"+body[restStart-start:], restStart-1)
+	case "set", "with", "do", "call":
+		c.checkFilters(body[restStart-start:], restStart)
+	}
+
+	return end
+}
+
+func (c *checker) top() string {
+	if len(c.stack) == 0 {
+		return ""
+	}
+	return c.stack[len(c.stack)-1].name
+}
+
+func (c *checker) closeBlock(pos int, name string) {
+	top := c.top()
+	switch {
+	case top == "":
+		c.errorf(pos, "unexpected \"end%s\", no block is open", name)
+	case top != name:
+		c.errorf(pos, "unexpected \"end%s\", expected end%s", name, top)
+		// Recover if the block is open further up, e.g. a missing endif inside a for loop.
+		for i := len(c.stack) - 1; i >= 0; i-- {
+			if c.stack[i].name == name {
+				c.stack = c.stack[:i]
+				return
+			}
+		}
+	default:
+		c.stack = c.stack[:len(c.stack)-1]
+	}
+}
+
+// checkFilters reports filters of the expression missing from known filters.
+// Start is the position of the expression in the template.
+func (c *checker) checkFilters(expr string, start int) {
+	if c.known == nil {
+		return
+	}
+
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\'', '"':
+			if end := stringEnd(expr, i); end >= 0 {
+				i = end
+			}
+		case '|':
+			j := i + 1
+			for j < len(expr) && (expr[j] == ' ' || expr[j] == '\t' || expr[j] == '\n') {
+				j++
+			}
+			k := j
+			for k < len(expr) && isNameChar(expr[k]) {
+				k++
+			}
+
+			name := strings.TrimSuffix(expr[j:k], ".")
+			switch {
+			case name == "" || name[0] >= '0' && name[0] <= '9':
+				c.errorf(start+i, "missing filter name after \"|\"")
+			case !c.known.has(name):
+				c.errorf(start+j, "unknown filter %q", name)
+			}
+			i = k - 1
+		}
+	}
+}
+
+func isNameChar(ch byte) bool {
+	return ch == '_' || ch == '.' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// stringEnd returns position of the quote closing the string literal opened at the position, -1 if unterminated.
+func stringEnd(s string, open int) int {
+	for i := open + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case s[open]:
+			return i
+		case '\n':
+			return -1
+		}
+	}
+
+	return -1
+}
+
+func matches(open, closing byte) bool {
+	return open == '(' && closing == ')' || open == '[' && closing == ']' || open == '{' && closing == '}'
+}
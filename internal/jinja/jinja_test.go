@@ -0,0 +1,140 @@
+package jinja
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string
+	}{
+		{
+			name: "valid",
+			src: "{# config #}\nport: {{ grafana_port | default(3000) }}\n{% for u in users | sort %}\n" +
+				"- {{ u.name | ansible.builtin.to_json }}\n{% else %}\n- none\n{% endfor %}\n" +
+				"{% if x %}{{ {'a': 1} | to_nice_yaml }}{% elif y %}y{% endif %}\n" +
+				"{% raw %}{{ not parsed {% endraw %}\n{%- set v = '}}' -%}\n{% filter upper %}text{% endfilter %}\n",
+		},
+		{
+			name:     "unclosed block",
+			src:      "{% if enabled %}\nenabled: true\n",
+			expected: []string{`1:1: unclosed "if" block, expected endif`},
+		},
+		{
+			name:     "mismatched end",
+			src:      "{% for x in xs %}\n{% if x %}\n{% endfor %}\n",
+			expected: []string{`3:1: unexpected "endfor", expected endif`},
+		},
+		{
+			name:     "unclosed variable",
+			src:      "a: {{ value\nb: {{ other }}\n",
+			expected: []string{"1:4: unclosed variable tag"},
+		},
+		{
+			name:     "unbalanced brackets",
+			src:      "{{ items[0 }}\n{{ fn(a)) }}\n",
+			expected: []string{`1:9: unclosed '['`, `2:9: unexpected ')'`},
+		},
+		{
+			name:     "unknown filter and tag",
+			src:      "{{ v | to_jsn }}\n{% iff v %}{{ v | }}\n",
+			expected: []string{`1:8: unknown filter "to_jsn"`, `2:1: unknown tag "iff"`, `2:17: missing filter name after "|"`},
+		},
+		{
+			name:     "else outside if",
+			src:      "{% else %}\n",
+			expected: []string{`1:1: unexpected "else" outside of if block`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var messages []string
+			for _, e := range Check(tt.src, DefaultFilters()) {
+				messages = append(messages, e.Error())
+			}
+			if strings.Join(messages, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("expected %q, got %q", tt.expected, messages)
+			}
+		})
+	}
+}
+
+func TestCheckCustomFilters(t *testing.T) {
+	src := "{{ data | community.general.json_query('a') }}"
+	if errs := Check(src, DefaultFilters()); len(errs) != 1 {
+		t.Fatalf("expected unknown filter, got %v", errs)
+	}
+	if errs := Check(src, DefaultFilters().With("community.general.json_query")); len(errs) != 0 {
+		t.Errorf("expected no errors, got %v", errs)
+	}
+	if errs := Check(src, nil); len(errs) != 0 {
+		t.Errorf("expected filters not checked, got %v", errs)
+	}
+}
+
+func TestNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      string
+		expected []string // name, with "=" suffix when assigned and "?" when guarded
+	}{
+		{
+			name:     "expression",
+			src:      "{{ user.name | upper }} {{ items[idx] }} {{ lookup('env', 'HOME') }} {{ a if cond else 'b' }}",
+			expected: []string{"user", "items", "idx", "a", "cond"},
+		},
+		{
+			name:     "guards",
+			src:      "{{ port | default(80) }} {{ cfg.x | d('') }} {% if debug is defined and level is not undefined %}{% endif %}",
+			expected: []string{"port?", "cfg?", "debug?", "level?"},
+		},
+		{
+			name:     "tests and keyword arguments",
+			src:      "{% if v is number and w is not none %}{{ x | to_json(indent=2) }}{% endif %}",
+			expected: []string{"v", "w", "x"},
+		},
+		{
+			name:     "strings comments and raw",
+			src:      "{{ 'quoted' ~ \"also\" }}{# commented #}{% raw %}{{ raw_name }}{% endraw %}{{ 1.5 + n2 }}",
+			expected: []string{"n2"},
+		},
+		{
+			name: "statements",
+			src: "{% for k, v in pairs | dictsort %}{% endfor %}{% set total = base + 1 %}{% set block %}x{% endset %}" +
+				"{% macro field(label, value='') %}{% endmacro %}{% from 'forms.j2' import input as i with context %}" +
+				"{%- with scoped = outer -%}{% endwith %}{% filter upper %}{% endfilter %}",
+			expected: []string{"k=", "v=", "pairs", "total=", "base", "block=", "label=", "value=", "i=", "scoped=", "outer"},
+		},
+		{
+			name:     "comparison isn't assignment",
+			src:      "{% set same = a == b %}",
+			expected: []string{"same=", "a", "b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, n := range Names(tt.src) {
+				if !strings.HasPrefix(tt.src[n.Pos:], n.Name) {
+					t.Errorf("name %q at %d doesn't match template", n.Name, n.Pos)
+				}
+				name := n.Name
+				if n.Assigned {
+					name += "="
+				}
+				if n.Guarded {
+					name += "?"
+				}
+				names = append(names, name)
+			}
+			if strings.Join(names, " ") != strings.Join(tt.expected, " ") {
+				t.Errorf("expected %q, got %q", tt.expected, names)
+			}
+		})
+	}
+}
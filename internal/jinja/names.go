@@ -0,0 +1,272 @@
+package jinja
+
+import "strings"
+
+// Name is a variable name used by an expression or a statement of a template.
+type Name struct {
+	Name string
+	// Pos is the offset of the name in the template.
+	Pos int
+	// Assigned tells the name is set by the template: target of set, for and with statements, macro argument or
+	// imported name.
+	Assigned bool
+	// Guarded tells the variable has a default value or is tested for definition, e.g. `x | default(1)` or
+	// `x is defined`.
+	Guarded bool
+}
+
+// keywords are operators and literals of expressions, never variables.
+var keywords = map[string]bool{
+	"and": true, "or": true, "not": true, "in": true, "is": true, "if": true, "else": true, "recursive": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true,
+}
+
+// importKeywords are keywords of import statements.
+var importKeywords = map[string]bool{"as": true, "import": true, "with": true, "without": true, "context": true}
+
+// Names returns variable names used by expressions and statements of the template, in order. Attributes, filters,
+// tests, called functions, keyword arguments and names inside strings, comments and raw blocks aren't variables.
+// Names of malformed tags are skipped.
+func Names(src string) []Name {
+	c := &checker{src: src}
+	c.run()
+
+	var names []Name
+	for _, t := range c.tags {
+		names = append(names, t.names()...)
+	}
+
+	return names
+}
+
+func (t tag) names() []Name {
+	if !t.statement {
+		return exprNames(t.body, t.start, false)
+	}
+
+	trimmed := strings.TrimLeft(t.body, " \t\r\n")
+	name := strings.Fields(trimmed)[0]
+	rest := trimmed[len(name):]
+	restStart := t.start + len(t.body) - len(trimmed) + len(name)
+
+	switch name {
+	case "for":
+		i := keywordIndex(rest, "in")
+		if i < 0 {
+			return exprNames(rest, restStart, false)
+		}
+		return append(exprNames(rest[:i], restStart, true), exprNames(rest[i+2:], restStart+i+2, false)...)
+	case "set", "with":
+		i := assignIndex(rest)
+		if i < 0 {
+			// Only set without assignment captures a block into the name.
+			return exprNames(rest, restStart, name == "set")
+		}
+		return append(exprNames(rest[:i], restStart, true), exprNames(rest[i+1:], restStart+i+1, false)...)
+	case "macro":
+		return exprNames(rest, restStart, true)
+	case "import", "from":
+		var names []Name
+		for _, n := range exprNames(rest, restStart, true) {
+			// Imported name is assigned to its alias if any.
+			if next, _ := firstWord(rest[n.Pos-restStart+len(n.Name):]); !importKeywords[n.Name] && next != "as" {
+				names = append(names, n)
+			}
+		}
+		return names
+	case "if", "elif", "do", "call", "include", "extends":
+		return exprNames(rest, restStart, false)
+	}
+
+	return nil
+}
+
+// exprNames returns variable names of the expression starting at the offset of the template.
+func exprNames(expr string, offset int, assigned bool) []Name {
+	var names []Name
+	for i := 0; i < len(expr); i++ {
+		ch := expr[i]
+		switch {
+		case ch == '\'' || ch == '"':
+			end := stringEnd(expr, i)
+			if end < 0 {
+				return names
+			}
+			i = end
+		case ch >= '0' && ch <= '9':
+			for i+1 < len(expr) && isNameChar(expr[i+1]) {
+				i++
+			}
+		case isIdentChar(ch):
+			j := i
+			for j < len(expr) && isIdentChar(expr[j]) {
+				j++
+			}
+			if isVariable(expr, i, j, assigned) {
+				names = append(names, Name{Name: expr[i:j], Pos: offset + i, Assigned: assigned, Guarded: guarded(expr[j:])})
+			}
+			i = j - 1
+		}
+	}
+
+	return names
+}
+
+// isVariable tells the name between the positions of the expression is a variable, not a keyword, an attribute,
+// a filter, a test, a called function or a keyword argument. Assigned names followed by "=" are macro arguments with
+// a default value.
+func isVariable(expr string, start, end int, assigned bool) bool {
+	if keywords[expr[start:end]] {
+		return false
+	}
+
+	prev := strings.TrimRight(expr[:start], " \t\r\n")
+	if strings.HasSuffix(prev, ".") || strings.HasSuffix(prev, "|") {
+		return false
+	}
+	word, before := lastWord(prev)
+	if word == "not" {
+		word, _ = lastWord(before)
+	}
+	if word == "is" {
+		return false
+	}
+
+	next := strings.TrimLeft(expr[end:], " \t\r\n")
+	return !strings.HasPrefix(next, "(") && (assigned || !strings.HasPrefix(next, "=") || strings.HasPrefix(next, "=="))
+}
+
+// guarded tells the rest of the expression after a variable gives it a default or tests its definition, after
+// attributes and subscripts of the variable.
+func guarded(rest string) bool {
+	for {
+		rest = strings.TrimLeft(rest, " \t\r\n")
+		if strings.HasPrefix(rest, ".") {
+			k := 1
+			for k < len(rest) && isIdentChar(rest[k]) {
+				k++
+			}
+			rest = rest[k:]
+			continue
+		}
+		if strings.HasPrefix(rest, "[") {
+			end := closingBracket(rest)
+			if end < 0 {
+				return false
+			}
+			rest = rest[end+1:]
+			continue
+		}
+		break
+	}
+
+	if filter, ok := strings.CutPrefix(rest, "|"); ok {
+		name, _ := firstWord(filter)
+		return name == "default" || name == "d"
+	}
+
+	word, rest := firstWord(rest)
+	if word != "is" {
+		return false
+	}
+	if word, rest = firstWord(rest); word == "not" {
+		word, _ = firstWord(rest)
+	}
+
+	return word == "defined" || word == "undefined"
+}
+
+// closingBracket returns the position of the bracket closing the one opening the string, -1 if unbalanced.
+func closingBracket(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"':
+			if i = stringEnd(s, i); i < 0 {
+				return -1
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			if depth--; depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// keywordIndex returns the position of the keyword as a standalone word outside strings and brackets, -1 if none.
+func keywordIndex(s, keyword string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch ch := s[i]; {
+		case ch == '\'' || ch == '"':
+			if i = stringEnd(s, i); i < 0 {
+				return -1
+			}
+		case ch == '(' || ch == '[' || ch == '{':
+			depth++
+		case ch == ')' || ch == ']' || ch == '}':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], keyword) && (i == 0 || !isIdentChar(s[i-1])) &&
+			(i+len(keyword) == len(s) || !isIdentChar(s[i+len(keyword)])):
+			return i
+		}
+	}
+
+	return -1
+}
+
+// assignIndex returns the position of the first assignment outside strings and brackets, -1 if none.
+func assignIndex(s string) int {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\'', '"':
+			if i = stringEnd(s, i); i < 0 {
+				return -1
+			}
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+		case '=':
+			if i+1 < len(s) && s[i+1] == '=' {
+				i++
+				continue
+			}
+			if depth == 0 && (i == 0 || !strings.ContainsRune("=!<>", rune(s[i-1]))) {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// firstWord returns the identifier starting the string after whitespace, and the rest of the string.
+func firstWord(s string) (string, string) {
+	s = strings.TrimLeft(s, " \t\r\n")
+	i := 0
+	for i < len(s) && isIdentChar(s[i]) {
+		i++
+	}
+
+	return s[:i], s[i:]
+}
+
+// lastWord returns the identifier ending the string, and the string before it.
+func lastWord(s string) (string, string) {
+	i := len(s)
+	for i > 0 && isIdentChar(s[i-1]) {
+		i--
+	}
+
+	return s[i:], strings.TrimRight(s[:i], " \t\r\n")
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
@@ -0,0 +1,225 @@
+// Package notify routes component changes to teams owning the components.
+// Owners are declared in component meta:
+//
+//	plasma:
+//	  version: ...
+//	  owners:
+//	    - team-data
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/launchrctl/launchr"
+	"gopkg.in/yaml.v3"
+)
+
+// Change actions.
+const (
+	ActionBumped     = "bumped"
+	ActionPropagated = "propagated"
+)
+
+const webhookTimeout = 10 * time.Second
+
+// Change represents a component version change.
+type Change struct {
+	Component  string `json:"component" yaml:"component"`
+	OldVersion string `json:"old_version" yaml:"old_version"`
+	NewVersion string `json:"new_version" yaml:"new_version"`
+	Action     string `json:"action" yaml:"action"`
+}
+
+// Notification lists changes of components owned by a team.
+type Notification struct {
+	Team    string   `json:"team" yaml:"team"`
+	Changes []Change `json:"changes" yaml:"changes"`
+}
+
+// Config is the notification configuration of the plugin.
+type Config struct {
+	// Webhooks maps team to URL receiving its notification.
+	Webhooks map[string]string `yaml:"webhooks"`
+}
+
+// Options tell how an action notifies owners of its changes.
+type Options struct {
+	// File is the routing file written with changes grouped by team, none if empty.
+	File string
+	// Send posts the notification of each team to its webhook.
+	Send bool
+}
+
+// OptionsFrom reads notify options of an action with the option getter, e.g. [action.Input.Opt].
+func OptionsFrom(opt func(name string) any) Options {
+	return Options{File: opt("notify-file").(string), Send: opt("notify").(bool)}
+}
+
+type ownersMeta struct {
+	Plasma struct {
+		Owners []string `yaml:"owners"`
+	} `yaml:"plasma"`
+}
+
+// Owners returns owners of the component, looked up in directories in the given order.
+func Owners(name string, dirs ...string) []string {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	for _, dir := range dirs {
+		for _, path := range []string{
+			filepath.Join(dir, parts[0], parts[1], parts[2], "meta", "plasma.yaml"),
+			filepath.Join(dir, parts[0], parts[1], "roles", parts[2], "meta", "plasma.yaml"),
+		} {
+			data, err := os.ReadFile(filepath.Clean(path))
+			if err != nil {
+				continue
+			}
+
+			var meta ownersMeta
+			if err = yaml.Unmarshal(data, &meta); err != nil {
+				return nil
+			}
+
+			return meta.Plasma.Owners
+		}
+	}
+
+	return nil
+}
+
+// Route groups changes by owning teams. Components without owners are returned separately.
+func Route(changes []Change, dirs ...string) ([]Notification, []string) {
+	byTeam := make(map[string][]Change)
+	var unowned []string
+	for _, c := range changes {
+		owners := Owners(c.Component, dirs...)
+		if len(owners) == 0 {
+			unowned = append(unowned, c.Component)
+			continue
+		}
+
+		for _, team := range owners {
+			byTeam[team] = append(byTeam[team], c)
+		}
+	}
+
+	notifications := make([]Notification, 0, len(byTeam))
+	for team, teamChanges := range byTeam {
+		sort.Slice(teamChanges, func(i, j int) bool {
+			return teamChanges[i].Component < teamChanges[j].Component
+		})
+		notifications = append(notifications, Notification{Team: team, Changes: teamChanges})
+	}
+
+	sort.Slice(notifications, func(i, j int) bool {
+		return notifications[i].Team < notifications[j].Team
+	})
+	sort.Strings(unowned)
+
+	return notifications, unowned
+}
+
+// WriteFile writes routing file, in YAML for .yaml and .yml extensions, in JSON otherwise.
+func WriteFile(path string, notifications []Notification) error {
+	var data []byte
+	var err error
+	if ext := filepath.Ext(path); ext == ".yaml" || ext == ".yml" {
+		data, err = yaml.Marshal(notifications)
+	} else {
+		data, err = json.MarshalIndent(notifications, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal notifications: %w", err)
+	}
+
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write routing file: %w", err)
+	}
+
+	return nil
+}
+
+// Send posts notification of each team to its webhook. Teams without webhook are skipped.
+func Send(webhooks map[string]string, notifications []Notification) error {
+	client := &http.Client{Timeout: webhookTimeout}
+
+	var errs []error
+	for _, n := range notifications {
+		url, ok := webhooks[n.Team]
+		if !ok {
+			continue
+		}
+
+		body, err := json.Marshal(n)
+		if err != nil {
+			return err
+		}
+
+		resp, err := client.Post(url, "application/json", bytes.NewReader(body))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("team %s > %w", n.Team, err))
+			continue
+		}
+		_ = resp.Body.Close()
+
+		if resp.StatusCode >= http.StatusBadRequest {
+			errs = append(errs, fmt.Errorf("team %s webhook responded with %s", n.Team, resp.Status))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Notify routes changes to the teams owning changed components, looked up in directories in the given order, and
+// writes the routing file or posts notifications according to options. Webhooks aren't called in dry-run mode.
+func Notify(term *launchr.Terminal, cfg Config, opts Options, dryRun bool, changes []Change, dirs ...string) error {
+	if len(changes) == 0 || (opts.File == "" && !opts.Send) {
+		return nil
+	}
+
+	notifications, unowned := Route(changes, dirs...)
+	if len(unowned) > 0 {
+		term.Warning().Printfln("Components without owners: %s", strings.Join(unowned, ", "))
+	}
+
+	if opts.File != "" {
+		if err := WriteFile(opts.File, notifications); err != nil {
+			return err
+		}
+		term.Info().Printfln("Routing of %d team(s) written to %s", len(notifications), opts.File)
+	}
+
+	if !opts.Send {
+		return nil
+	}
+
+	if dryRun {
+		term.Info().Println("Dry-run, skipping owners notification")
+		return nil
+	}
+
+	if err := Send(cfg.Webhooks, notifications); err != nil {
+		return fmt.Errorf("failed to notify owners > %w", err)
+	}
+
+	for _, n := range notifications {
+		if _, ok := cfg.Webhooks[n.Team]; !ok {
+			term.Warning().Printfln("No webhook configured for team %s", n.Team)
+		}
+	}
+
+	return nil
+}
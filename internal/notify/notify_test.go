@@ -0,0 +1,100 @@
+package notify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/launchrctl/launchr"
+)
+
+func writeMeta(t *testing.T, dir, path, content string) {
+	t.Helper()
+	full := filepath.Join(dir, path, "meta", "plasma.yaml")
+	if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRouteGroupsByOwner(t *testing.T) {
+	build := t.TempDir()
+	domain := t.TempDir()
+	writeMeta(t, build, "foundation/services/postgres", "plasma:\n  version: a\n  owners: [data, platform]\n")
+	writeMeta(t, domain, "foundation/applications/roles/auth", "plasma:\n  version: b\n  owners: [identity]\n")
+	writeMeta(t, build, "foundation/applications/orphan", "plasma:\n  version: c\n")
+
+	changes := []Change{
+		{Component: "foundation.services.postgres", OldVersion: "a", NewVersion: "a1", Action: ActionBumped},
+		{Component: "foundation.applications.auth", OldVersion: "b", NewVersion: "b-a1", Action: ActionPropagated},
+		{Component: "foundation.applications.orphan", OldVersion: "c", NewVersion: "c-a1", Action: ActionPropagated},
+	}
+
+	notifications, unowned := Route(changes, build, domain)
+	if len(unowned) != 1 || unowned[0] != "foundation.applications.orphan" {
+		t.Fatalf("unexpected unowned components: %v", unowned)
+	}
+
+	teams := make([]string, 0, len(notifications))
+	for _, n := range notifications {
+		teams = append(teams, n.Team)
+		if len(n.Changes) != 1 {
+			t.Fatalf("team %s: expected 1 change, got %d", n.Team, len(n.Changes))
+		}
+	}
+
+	expected := []string{"data", "identity", "platform"}
+	if len(teams) != len(expected) {
+		t.Fatalf("expected teams %v, got %v", expected, teams)
+	}
+	for i := range expected {
+		if teams[i] != expected[i] {
+			t.Fatalf("expected teams %v, got %v", expected, teams)
+		}
+	}
+}
+
+func TestOwnersBuildTakesPrecedence(t *testing.T) {
+	build := t.TempDir()
+	domain := t.TempDir()
+	writeMeta(t, build, "foundation/services/postgres", "plasma:\n  owners: [build-team]\n")
+	writeMeta(t, domain, "foundation/services/postgres", "plasma:\n  owners: [domain-team]\n")
+
+	owners := Owners("foundation.services.postgres", build, domain)
+	if len(owners) != 1 || owners[0] != "build-team" {
+		t.Fatalf("expected build owners, got %v", owners)
+	}
+}
+
+func TestNotify(t *testing.T) {
+	dir := t.TempDir()
+	writeMeta(t, dir, "foundation/services/postgres", "plasma:\n  version: a\n  owners: [data]\n")
+	changes := []Change{{Component: "foundation.services.postgres", OldVersion: "a", NewVersion: "a1", Action: ActionBumped}}
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	cfg := Config{Webhooks: map[string]string{"data": server.URL}}
+	routing := filepath.Join(dir, "routing.json")
+
+	if err := Notify(launchr.Term(), cfg, Options{}, false, changes, dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := Notify(launchr.Term(), cfg, Options{File: routing, Send: true}, true, changes, dir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(routing); err != nil || requests != 0 {
+		t.Fatalf("expected routing file written without webhook call on dry-run, got %v, %d request(s)", err, requests)
+	}
+
+	if err := Notify(launchr.Term(), cfg, Options{Send: true}, false, changes, dir); err != nil || requests != 1 {
+		t.Fatalf("expected webhook called, got %v, %d request(s)", err, requests)
+	}
+}
@@ -1,8 +1,11 @@
 package playbook
 
 import (
+	"bytes"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -72,14 +75,28 @@ func ExtractLayer(mrn string) string {
 
 // FindPlaybook locates the layer playbook file
 func FindPlaybook(source, layer string) (string, error) {
+	if source == "" {
+		source = "."
+	}
+
+	found, err := FindPlaybookFS(os.DirFS(source), layer)
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(source, filepath.FromSlash(found)), nil
+}
+
+// FindPlaybookFS locates the layer playbook file in a filesystem, the path is relative to its root
+func FindPlaybookFS(fsys fs.FS, layer string) (string, error) {
 	candidates := []string{
-		filepath.Join(source, "src", layer, layer+".yaml"),
-		filepath.Join(source, layer, layer+".yaml"),
+		path.Join("src", layer, layer+".yaml"),
+		path.Join(layer, layer+".yaml"),
 	}
 
-	for _, path := range candidates {
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+	for _, candidate := range candidates {
+		if _, err := fs.Stat(fsys, candidate); err == nil {
+			return candidate, nil
 		}
 	}
 
@@ -93,6 +110,21 @@ func Load(path string) ([]Play, error) {
 		return nil, fmt.Errorf("failed to read playbook: %w", err)
 	}
 
+	return parse(data)
+}
+
+// LoadFS reads and parses the playbook YAML of a filesystem
+func LoadFS(fsys fs.FS, path string) ([]Play, error) {
+	data, err := fs.ReadFile(fsys, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playbook: %w", err)
+	}
+
+	return parse(data)
+}
+
+// parse parses the playbook YAML
+func parse(data []byte) ([]Play, error) {
 	var plays []Play
 	if err := yaml.Unmarshal(data, &plays); err != nil {
 		return nil, fmt.Errorf("failed to parse playbook: %w", err)
@@ -168,3 +200,233 @@ func RemoveRole(plays []Play, component, chassis string) ([]Play, bool) {
 
 	return plays, false
 }
+
+// RemoveRolesAt returns playbook content without the roles which name is declared at the positions, keeping
+// comments and key order. Plays left without roles are removed. Returns the number of removed roles.
+func RemoveRolesAt(data []byte, positions []Position) ([]byte, int, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to parse playbook: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return data, 0, nil
+	}
+
+	at := make(map[Position]bool, len(positions))
+	for _, p := range positions {
+		at[p] = true
+	}
+
+	removed := 0
+	root := doc.Content[0]
+	plays := root.Content[:0]
+	for _, play := range root.Content {
+		roles := mappingValue(play, "roles")
+		if roles == nil || roles.Kind != yaml.SequenceNode {
+			plays = append(plays, play)
+			continue
+		}
+
+		kept := roles.Content[:0]
+		for _, role := range roles.Content {
+			name := role
+			if role.Kind == yaml.MappingNode {
+				name = mappingValue(role, "role")
+			}
+			if name != nil && at[Position{Line: name.Line, Column: name.Column}] {
+				removed++
+				continue
+			}
+			kept = append(kept, role)
+		}
+		roles.Content = kept
+
+		if len(kept) > 0 {
+			plays = append(plays, play)
+		}
+	}
+	root.Content = plays
+
+	if removed == 0 {
+		return data, 0, nil
+	}
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal playbook: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, 0, fmt.Errorf("failed to marshal playbook: %w", err)
+	}
+
+	return b.Bytes(), removed, nil
+}
+
+// Position is a line and column of a playbook.
+type Position struct {
+	Line   int
+	Column int
+}
+
+// mappingValue returns the value of the key of a mapping node, nil if missing.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+// OrderIssue describes a role placed before its dependency in the chassis scope
+type OrderIssue struct {
+	Component  string `json:"component"`
+	Dependency string `json:"dependency"`
+	Hosts      string `json:"hosts"` // Play where the dependency is attached
+}
+
+// IsAncestorOrSelf reports whether chassis section is equal to or contains other section
+func IsAncestorOrSelf(chassis, other string) bool {
+	return other == chassis || strings.HasPrefix(other, chassis+".")
+}
+
+// FindPlay returns the position of the first play of the chassis, -1 if none.
+func FindPlay(plays []Play, chassis string) int {
+	for i, play := range plays {
+		if play.Hosts == chassis {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// CheckOrder returns dependencies attached to the chassis scope after the roles of the chassis play requiring them.
+// A dependency must either precede the role in the same play, or be attached to an earlier play of an ancestor section.
+// Plays are in run order, e.g. those of [PlatformPlaybooks], and target is the position of the chassis play among them.
+// requires returns transitive dependencies of a role.
+//
+// Only roles of the chassis play are checked, the order of other chassis plays isn't.
+func CheckOrder(plays []Play, target int, requires func(string) map[string]bool) []OrderIssue {
+	if target < 0 || target >= len(plays) {
+		return nil
+	}
+	chassis := plays[target].Hosts
+
+	var issues []OrderIssue
+	for ri, role := range plays[target].Roles {
+		deps := requires(role.Name)
+		if len(deps) == 0 {
+			continue
+		}
+
+		for pi := target; pi < len(plays); pi++ {
+			if !IsAncestorOrSelf(plays[pi].Hosts, chassis) {
+				continue
+			}
+
+			for di, dep := range plays[pi].Roles {
+				if !deps[dep.Name] || (pi == target && di < ri) {
+					continue
+				}
+
+				issues = append(issues, OrderIssue{Component: role.Name, Dependency: dep.Name, Hosts: plays[pi].Hosts})
+			}
+		}
+	}
+
+	return issues
+}
+
+// PlatformPlaybooks returns paths of the playbooks imported by the platform playbook of the source, in run order.
+// Plays of the platform playbook itself aren't returned.
+func PlatformPlaybooks(source string) ([]string, error) {
+	platformPath, err := FindPlaybook(source, "platform")
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(platformPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read playbook: %w", err)
+	}
+
+	var entries []struct {
+		ImportPlaybook string `yaml:"import_playbook"`
+	}
+	if err = yaml.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to parse playbook: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.ImportPlaybook != "" {
+			paths = append(paths, filepath.Join(filepath.Dir(platformPath), filepath.FromSlash(entry.ImportPlaybook)))
+		}
+	}
+
+	return paths, nil
+}
+
+// Reorder sorts roles of the chassis play so dependencies precede roles requiring them.
+// Roles keep their original order otherwise. Returns false if the order didn't change.
+// Only the chassis play is reordered, dependencies attached to later ancestor plays remain reported by CheckOrder.
+func Reorder(plays []Play, chassis string, requires func(string) map[string]bool) bool {
+	for i, play := range plays {
+		if play.Hosts != chassis {
+			continue
+		}
+
+		sorted := make([]Role, 0, len(play.Roles))
+		pending := append([]Role(nil), play.Roles...)
+		for len(pending) > 0 {
+			next := -1
+			for j, role := range pending {
+				if dependenciesPlaced(role.Name, pending, requires) {
+					next = j
+					break
+				}
+			}
+
+			// Dependency cycle, keep the remaining roles as is.
+			if next < 0 {
+				sorted = append(sorted, pending...)
+				break
+			}
+
+			sorted = append(sorted, pending[next])
+			pending = append(pending[:next], pending[next+1:]...)
+		}
+
+		changed := false
+		for j := range sorted {
+			if sorted[j].Name != play.Roles[j].Name {
+				changed = true
+				break
+			}
+		}
+
+		plays[i].Roles = sorted
+		return changed
+	}
+
+	return false
+}
+
+// dependenciesPlaced reports whether none of the pending roles is a dependency of the role
+func dependenciesPlaced(name string, pending []Role, requires func(string) map[string]bool) bool {
+	deps := requires(name)
+	for _, role := range pending {
+		if role.Name != name && deps[role.Name] {
+			return false
+		}
+	}
+
+	return true
+}
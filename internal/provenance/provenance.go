@@ -0,0 +1,290 @@
+// Package provenance signs component content hashes and verifies them to detect out-of-band changes.
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// SignatureFile is the signature sidecar of a component, relative to the component directory.
+var SignatureFile = filepath.Join("meta", "signature.yaml")
+
+// Algorithm is the signature algorithm.
+const Algorithm = "ed25519"
+
+// metaFile is the component meta, its version is excluded from the content hash.
+const metaFile = "meta/plasma.yaml"
+
+// unsignedFiles don't trigger a bump, so they aren't part of the content hash either.
+var unsignedFiles = map[string]struct{}{
+	"README.md":  {},
+	"README.svg": {},
+}
+
+var (
+	// ErrUnsigned is returned when a component has no signature.
+	ErrUnsigned = errors.New("component is not signed")
+	// ErrTampered is returned when the component content doesn't match the signed hash.
+	ErrTampered = errors.New("component content doesn't match the signed hash")
+	// ErrInvalidSignature is returned when the signature isn't made by a trusted key.
+	ErrInvalidSignature = errors.New("signature isn't valid for trusted keys")
+)
+
+// Config is the provenance section of the plugin config.
+type Config struct {
+	// SigningKey is the PEM private key signing bumped components, signing is disabled without it.
+	SigningKey string `yaml:"signing_key"`
+	// PublicKeys are PEM public key files trusted to verify component signatures.
+	PublicKeys []string `yaml:"public_keys"`
+}
+
+// Signature is the signed content hash of a component.
+type Signature struct {
+	Algorithm string `yaml:"algorithm"`
+	KeyID     string `yaml:"key_id"`
+	Hash      string `yaml:"hash"`
+	Signature string `yaml:"signature"`
+}
+
+// ContentHash returns sha256 of component files in dir, sorted by path.
+// The signature sidecar, README files, the actions directory and the meta version are excluded: they change
+// without a bump, or with propagated versions of sync.
+func ContentHash(dir string) (string, error) {
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel == "actions" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if _, ok := unsignedFiles[path.Base(rel)]; ok || rel == filepath.ToSlash(SignatureFile) {
+			return nil
+		}
+
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(files)
+
+	h := sha256.New()
+	for _, rel := range files {
+		data, errRead := readContent(dir, rel)
+		if errRead != nil {
+			return "", errRead
+		}
+
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(h, "%s\x00%s\n", rel, hex.EncodeToString(sum[:]))
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readContent(dir, rel string) ([]byte, error) {
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	info, err := os.Lstat(p)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		target, errLink := os.Readlink(p)
+		return []byte(target), errLink
+	}
+
+	data, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return nil, err
+	}
+
+	if rel == metaFile {
+		data, err = sync.SetMetaVersion(data, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s > %w", p, err)
+		}
+	}
+
+	return data, nil
+}
+
+// KeyID returns the identifier of a public key, the beginning of its sha256.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return Algorithm + ":" + hex.EncodeToString(sum[:8])
+}
+
+// Sign signs the content hash of the component in dir and writes the signature sidecar.
+func Sign(dir string, key ed25519.PrivateKey) (*Signature, error) {
+	hash, err := ContentHash(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	sig := &Signature{
+		Algorithm: Algorithm,
+		KeyID:     KeyID(key.Public().(ed25519.PublicKey)),
+		Hash:      hash,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, []byte(hash))),
+	}
+
+	data, err := yaml.Marshal(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	p := filepath.Join(dir, SignatureFile)
+	if err = os.WriteFile(p, data, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write signature %s > %w", p, err)
+	}
+
+	return sig, nil
+}
+
+// Verify recomputes the content hash of the component in dir and checks its signature against trusted keys.
+// It returns ErrUnsigned, ErrTampered or ErrInvalidSignature accordingly.
+func Verify(dir string, keys []ed25519.PublicKey) (*Signature, error) {
+	p := filepath.Join(dir, SignatureFile)
+	data, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrUnsigned
+		}
+		return nil, err
+	}
+
+	sig := &Signature{}
+	if err = yaml.Unmarshal(data, sig); err != nil {
+		return nil, fmt.Errorf("malformed signature %s > %w", p, err)
+	}
+	if sig.Algorithm != Algorithm {
+		return sig, fmt.Errorf("unsupported signature algorithm %q in %s", sig.Algorithm, p)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(sig.Signature)
+	if err != nil {
+		return sig, fmt.Errorf("malformed signature %s > %w", p, err)
+	}
+
+	trusted := false
+	for _, key := range keys {
+		if ed25519.Verify(key, []byte(sig.Hash), raw) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return sig, ErrInvalidSignature
+	}
+
+	hash, err := ContentHash(dir)
+	if err != nil {
+		return sig, err
+	}
+	if hash != sig.Hash {
+		return sig, ErrTampered
+	}
+
+	return sig, nil
+}
+
+// LoadPrivateKey reads a PEM encoded PKCS #8 ed25519 private key.
+func LoadPrivateKey(p string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(p)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s > %w", p, err)
+	}
+
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an %s key", p, Algorithm)
+	}
+
+	return private, nil
+}
+
+// LoadPublicKeys reads PEM encoded PKIX ed25519 public keys, a file may hold several keys.
+func LoadPublicKeys(paths ...string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Clean(p))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read public key %s > %w", p, err)
+		}
+
+		found := len(keys)
+		for {
+			var block *pem.Block
+			block, data = pem.Decode(data)
+			if block == nil {
+				break
+			}
+
+			key, errParse := x509.ParsePKIXPublicKey(block.Bytes)
+			if errParse != nil {
+				return nil, fmt.Errorf("failed to parse public key %s > %w", p, errParse)
+			}
+
+			public, ok := key.(ed25519.PublicKey)
+			if !ok {
+				return nil, fmt.Errorf("public key %s is not an %s key", p, Algorithm)
+			}
+			keys = append(keys, public)
+		}
+
+		if len(keys) == found {
+			return nil, fmt.Errorf("no PEM public key found in %s", p)
+		}
+	}
+
+	return keys, nil
+}
+
+func readPEM(p string) (*pem.Block, error) {
+	data, err := os.ReadFile(filepath.Clean(p))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key %s > %w", p, err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || !strings.HasSuffix(block.Type, "PRIVATE KEY") {
+		return nil, fmt.Errorf("no PEM private key found in %s", p)
+	}
+
+	return block, nil
+}
@@ -0,0 +1,136 @@
+package provenance
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newComponent(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "meta", "plasma.yaml"), "plasma:\n  version: \"aaa1111111111\"\n")
+	writeFile(t, filepath.Join(dir, "tasks", "main.yaml"), "- name: install\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "# Component\n")
+	return dir
+}
+
+func newKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return public, private
+}
+
+func TestSignVerify(t *testing.T) {
+	public, private := newKey(t)
+	other, _ := newKey(t)
+
+	tests := []struct {
+		name   string
+		change func(dir string)
+		keys   []ed25519.PublicKey
+		err    error
+	}{
+		{"signed", func(string) {}, []ed25519.PublicKey{public}, nil},
+		{"one of trusted keys", func(string) {}, []ed25519.PublicKey{other, public}, nil},
+		{"version propagated", func(dir string) {
+			writeFile(t, filepath.Join(dir, "meta", "plasma.yaml"), "plasma:\n  version: \"aaa1111111111-bbb2222222222\"\n")
+		}, []ed25519.PublicKey{public}, nil},
+		{"readme edited", func(dir string) {
+			writeFile(t, filepath.Join(dir, "README.md"), "# Edited\n")
+		}, []ed25519.PublicKey{public}, nil},
+		{"actions edited", func(dir string) {
+			writeFile(t, filepath.Join(dir, "actions", "run.yaml"), "action: {}\n")
+		}, []ed25519.PublicKey{public}, nil},
+		{"task edited", func(dir string) {
+			writeFile(t, filepath.Join(dir, "tasks", "main.yaml"), "- name: backdoor\n")
+		}, []ed25519.PublicKey{public}, ErrTampered},
+		{"file added", func(dir string) {
+			writeFile(t, filepath.Join(dir, "files", "payload.sh"), "#!/bin/sh\n")
+		}, []ed25519.PublicKey{public}, ErrTampered},
+		{"meta edited", func(dir string) {
+			writeFile(t, filepath.Join(dir, "meta", "plasma.yaml"), "plasma:\n  version: \"aaa1111111111\"\n  owner: eve\n")
+		}, []ed25519.PublicKey{public}, ErrTampered},
+		{"untrusted key", func(string) {}, []ed25519.PublicKey{other}, ErrInvalidSignature},
+		{"unsigned", func(dir string) {
+			_ = os.Remove(filepath.Join(dir, SignatureFile))
+		}, []ed25519.PublicKey{public}, ErrUnsigned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := newComponent(t)
+			signed, err := Sign(dir, private)
+			if err != nil {
+				t.Fatalf("sign: %v", err)
+			}
+			if signed.KeyID != KeyID(public) {
+				t.Errorf("expected key id %s, got %s", KeyID(public), signed.KeyID)
+			}
+
+			tt.change(dir)
+			if _, err = Verify(dir, tt.keys); !errors.Is(err, tt.err) {
+				t.Errorf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestLoadKeys(t *testing.T) {
+	public, private := newKey(t)
+	dir := t.TempDir()
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "key.pem"), string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+
+	der, err = x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+	writeFile(t, filepath.Join(dir, "keys.pem"), block+block)
+
+	loaded, err := LoadPrivateKey(filepath.Join(dir, "key.pem"))
+	if err != nil {
+		t.Fatalf("load private key: %v", err)
+	}
+	if !loaded.Equal(private) {
+		t.Error("expected loaded private key equal")
+	}
+
+	keys, err := LoadPublicKeys(filepath.Join(dir, "keys.pem"))
+	if err != nil {
+		t.Fatalf("load public keys: %v", err)
+	}
+	if len(keys) != 2 || !keys[0].Equal(public) {
+		t.Errorf("expected 2 public keys, got %d", len(keys))
+	}
+
+	if _, err = LoadPublicKeys(filepath.Join(dir, "key.pem")); err == nil {
+		t.Error("expected error for private key as public key")
+	}
+	if _, err = LoadPrivateKey(filepath.Join(dir, "keys.pem")); err == nil {
+		t.Error("expected error for public key as private key")
+	}
+}
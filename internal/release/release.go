@@ -0,0 +1,112 @@
+// Package release provides the release manifest model shared by release-manifest and sync actions.
+package release
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Component is a released component entry.
+type Component struct {
+	Name    string     `json:"name" yaml:"name"`
+	Version string     `json:"version" yaml:"version"`
+	Chassis []string   `json:"chassis" yaml:"chassis"`
+	Package string     `json:"package" yaml:"package"`
+	Commit  string     `json:"commit,omitempty" yaml:"commit,omitempty"`
+	Date    *time.Time `json:"date,omitempty" yaml:"date,omitempty"`
+}
+
+// Manifest describes all attached components of a platform build.
+type Manifest struct {
+	Ref        string      `json:"ref" yaml:"ref"`
+	Commit     string      `json:"commit" yaml:"commit"`
+	Date       time.Time   `json:"date" yaml:"date"`
+	Components []Component `json:"components" yaml:"components"`
+	Checksum   string      `json:"checksum" yaml:"checksum"`
+}
+
+// Find returns manifest component by name.
+func (m *Manifest) Find(name string) *Component {
+	for i := range m.Components {
+		if m.Components[i].Name == name {
+			return &m.Components[i]
+		}
+	}
+	return nil
+}
+
+// Checksum returns sha256 of manifest content excluding the checksum itself.
+// Content is always hashed in JSON form, so YAML and JSON manifests of the same release share checksum.
+func Checksum(m *Manifest) (string, error) {
+	c := *m
+	c.Checksum = ""
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return "sha256:" + hex.EncodeToString(sum[:]), nil
+}
+
+// Save writes manifest to file, in YAML for .yaml and .yml extensions, in JSON otherwise.
+func Save(path string, m *Manifest) error {
+	var data []byte
+	var err error
+	if isYaml(path) {
+		data, err = yaml.Marshal(m)
+	} else {
+		data, err = json.MarshalIndent(m, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal manifest: %w", err)
+	}
+
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return nil
+}
+
+// Load reads manifest file and verifies its checksum.
+func Load(path string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+
+	var m Manifest
+	if isYaml(path) {
+		err = yaml.Unmarshal(data, &m)
+	} else {
+		err = json.Unmarshal(data, &m)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest %s: %w", path, err)
+	}
+
+	sum, err := Checksum(&m)
+	if err != nil {
+		return nil, err
+	}
+
+	if sum != m.Checksum {
+		return nil, fmt.Errorf("manifest %s checksum mismatch: expected %s, got %s", path, m.Checksum, sum)
+	}
+
+	return &m, nil
+}
+
+func isYaml(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
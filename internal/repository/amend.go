@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"crypto/sha1" //nolint:gosec // identifies uncommitted content like git object hashes do
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// AmendedBump is the bump commit at HEAD to amend.
+type AmendedBump struct {
+	// Files are files changed by the bump commit with their content before it, nil for files it created.
+	Files map[string][]byte
+	// Commits are commits bumped by it, since the previous bump, latest first.
+	Commits []*Commit
+}
+
+// GetAmendedBump returns the bump commit at HEAD with files it changed and commits it bumped.
+func (r *Bumper) GetAmendedBump() (*AmendedBump, error) {
+	if !r.IsOwnCommit() {
+		return nil, errors.New("the latest commit isn't a bump commit, nothing to amend")
+	}
+
+	headRef, err := r.git.Head()
+	if err != nil {
+		return nil, err
+	}
+	head, err := r.git.CommitObject(headRef.Hash())
+	if err != nil {
+		return nil, err
+	}
+	if head.NumParents() == 0 {
+		return nil, errors.New("the bump commit has no parent, nothing to amend")
+	}
+
+	parent, err := head.Parent(0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the parent of the bump commit > %w", err)
+	}
+
+	files, err := changedFiles(parent, head)
+	if err != nil {
+		return nil, err
+	}
+
+	commits, err := r.commitsInRange(parent, CommitRange{})
+	if err != nil {
+		return nil, err
+	}
+
+	return &AmendedBump{Files: files, Commits: commits}, nil
+}
+
+// changedFiles returns files changed between the commits with their content in the first one, nil if missing.
+func changedFiles(from, to *object.Commit) (map[string][]byte, error) {
+	fromTree, err := from.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := to.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := fromTree.Diff(toTree)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make(map[string][]byte, len(changes))
+	for _, ch := range changes {
+		action, _ := ch.Action()
+		if action == merkletrie.Insert {
+			files[ch.To.Name] = nil
+			continue
+		}
+
+		file, errFile := fromTree.TreeEntryFile(&ch.From.TreeEntry)
+		if errFile != nil {
+			return nil, errFile
+		}
+		reader, errFile := file.Reader()
+		if errFile != nil {
+			return nil, errFile
+		}
+		data, errFile := io.ReadAll(reader)
+		_ = reader.Close()
+		if errFile != nil {
+			return nil, errFile
+		}
+		files[ch.From.Name] = data
+	}
+
+	return files, nil
+}
+
+// GetWorktreeChanges returns tracked files changed since HEAD, staged or not, as a commit to be, nil if nothing
+// changed. As the commit doesn't exist yet, its hash identifies the content: paths with their blob hashes.
+func (r *Bumper) GetWorktreeChanges() (*Commit, error) {
+	w, err := r.git.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for path, s := range status {
+		if s.Worktree == git.Untracked || (s.Staging == git.Unmodified && s.Worktree == git.Unmodified) {
+			continue
+		}
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	h := sha1.New() //nolint:gosec
+	for _, path := range files {
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		// Deleted files have no content, their path alone is hashed.
+		if data, errRead := os.ReadFile(filepath.Join(w.Filesystem.Root(), filepath.FromSlash(path))); errRead == nil {
+			blob := plumbing.ComputeHash(plumbing.BlobObject, data)
+			h.Write(blob[:])
+		}
+		h.Write([]byte{'\n'})
+	}
+
+	return &Commit{Hash: hex.EncodeToString(h.Sum(nil)), Files: files, Uncommitted: true}, nil
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// BumpAuthors matches authors of bump commits, in addition to the bumper itself.
+// Platforms which bumped versions with other bot identities in the past list them to group history correctly.
+// A nil [*BumpAuthors] matches the bumper only.
+type BumpAuthors struct {
+	identities map[string]bool
+	patterns   []*regexp.Regexp
+	key        string
+}
+
+// NewBumpAuthors returns matcher of the given author names or emails.
+// Entries enclosed in slashes are regular expressions matched against both, e.g. "/^ci-bot/".
+func NewBumpAuthors(authors []string) (*BumpAuthors, error) {
+	b := &BumpAuthors{identities: make(map[string]bool)}
+	var keys []string
+	for _, a := range authors {
+		a = strings.TrimSpace(a)
+		if a == "" {
+			continue
+		}
+		keys = append(keys, a)
+
+		if len(a) > 2 && strings.HasPrefix(a, "/") && strings.HasSuffix(a, "/") {
+			re, err := regexp.Compile(a[1 : len(a)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid bump author pattern %s > %w", a, err)
+			}
+			b.patterns = append(b.patterns, re)
+			continue
+		}
+
+		b.identities[a] = true
+	}
+	b.key = strings.Join(keys, ",")
+
+	return b, nil
+}
+
+// Match reports whether the commit author name or email belongs to a bump author.
+func (b *BumpAuthors) Match(name, email string) bool {
+	name = strings.TrimSpace(name)
+	if name == Author {
+		return true
+	}
+
+	if b == nil {
+		return false
+	}
+
+	if b.identities[name] || (email != "" && b.identities[email]) {
+		return true
+	}
+
+	for _, re := range b.patterns {
+		if re.MatchString(name) || (email != "" && re.MatchString(email)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns configured authors, e.g. to invalidate history grouped with other authors.
+func (b *BumpAuthors) String() string {
+	if b == nil {
+		return ""
+	}
+	return b.key
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// IsBareDir reports whether the directory is a bare repository, e.g. a package checkout cached by compose
+// without worktree. Such repository is the git directory itself, its files are only in the object store.
+func IsBareDir(path string) bool {
+	if _, err := os.Stat(filepath.Join(path, git.GitDirName)); err == nil {
+		return false
+	}
+
+	head, err := os.Stat(filepath.Join(path, "HEAD"))
+	if err != nil || head.IsDir() {
+		return false
+	}
+
+	objects, err := os.Stat(filepath.Join(path, "objects"))
+	return err == nil && objects.IsDir()
+}
+
+// HeadFiles returns paths of files in HEAD tree of the repository, with a reader of their content.
+func HeadFiles(r *git.Repository) ([]string, func(path string) ([]byte, error), error) {
+	head, err := r.Head()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	commit, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get HEAD commit object > %w", err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get HEAD tree > %w", err)
+	}
+
+	var paths []string
+	err = tree.Files().ForEach(func(f *object.File) error {
+		paths = append(paths, f.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	read := func(path string) ([]byte, error) {
+		f, errFile := tree.File(filepath.ToSlash(path))
+		if errFile != nil {
+			return nil, fmt.Errorf("opening file %s in commit %s > %w", path, commit.Hash, errFile)
+		}
+
+		content, errContent := f.Contents()
+		return []byte(content), errContent
+	}
+
+	return paths, read, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+func TestBareRepositoryComponents(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	meta := filepath.Join("foundation", "services", "postgres", "meta", "plasma.yaml")
+	if err = os.MkdirAll(filepath.Join(dir, filepath.Dir(meta)), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, meta), []byte("plasma:\n  version: abc1234567890\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	w, _ := repo.Worktree()
+	if _, err = w.Add(meta); err != nil {
+		t.Fatalf("git add: %v", err)
+	}
+	_, err = w.Commit("add postgres", &git.CommitOptions{
+		Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatalf("commit: %v", err)
+	}
+
+	bareDir := filepath.Join(t.TempDir(), "package")
+	bare, err := git.PlainClone(bareDir, true, &git.CloneOptions{URL: dir})
+	if err != nil {
+		t.Fatalf("bare clone: %v", err)
+	}
+
+	if IsBareDir(dir) {
+		t.Error("expected repository with worktree not to be bare")
+	}
+	if !IsBareDir(bareDir) {
+		t.Fatal("expected bare clone to be detected")
+	}
+
+	paths, readFile, err := HeadFiles(bare)
+	if err != nil {
+		t.Fatalf("head files: %v", err)
+	}
+
+	cm := sync.NewComponentsMapFromFiles(paths, bareDir, readFile)
+	c, ok := cm.Get("foundation.services.postgres")
+	if !ok || cm.Len() != 1 {
+		t.Fatalf("expected only postgres component, got %v", cm.Keys())
+	}
+
+	version, _, err := c.GetVersion()
+	if err != nil || version != "abc1234567890" {
+		t.Errorf("expected version read from object store, got %q (%v)", version, err)
+	}
+}
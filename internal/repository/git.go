@@ -2,8 +2,10 @@
 package repository
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
@@ -20,20 +22,34 @@ const (
 	BumpMessage = "versions bump"
 	// Author is the name of bump commit author.
 	Author = "Bumper"
+	// AuthorEmail is the email of bump commit author.
+	AuthorEmail = "noreply@plasma.sh"
 )
 
+// RenameDetection are options of tree diffs detecting renamed files, a pair of deleted and inserted files
+// at least 60% similar being a rename.
+var RenameDetection = &object.DiffTreeOptions{
+	DetectRenames: true,
+	RenameScore:   60,
+}
+
 // Bumper encapsulates Git-related operations for bumping versions in a Git repository.
 type Bumper struct {
 	git           *git.Repository
 	name          string
 	mail          string
 	commitMessage string
+	signer        git.Signer
 }
 
 // Commit stores commits hash and list of modified files in it.
 type Commit struct {
 	Hash  string
 	Files []string
+	// Renames are files renamed by the commit, new paths by old ones. Their new paths are part of Files.
+	Renames map[string]string
+	// Uncommitted tells the changes aren't committed yet, the hash identifies their content.
+	Uncommitted bool
 }
 
 // NewBumper returns new instance of [Bumper].
@@ -46,7 +62,7 @@ func NewBumper() (*Bumper, error) {
 	return &Bumper{
 		git:           r,
 		name:          Author,
-		mail:          "noreply@plasma.sh",
+		mail:          AuthorEmail,
 		commitMessage: BumpMessage,
 	}, nil
 }
@@ -73,10 +89,29 @@ func (r *Bumper) IsOwnCommit() bool {
 	return r.name == commit.Author.Name && r.mail == commit.Author.Email
 }
 
+// CommitRange selects commits of which changed files are bumped, commits since the latest bump by default.
+type CommitRange struct {
+	// Last selects the last commit only.
+	Last bool
+	// Since is a revision, e.g. a branch, tag or hash, commits since its merge base with HEAD are selected.
+	Since string
+	// SinceDate selects commits committed on or after the date.
+	SinceDate time.Time
+}
+
+// IsSet reports whether the range is bounded by a revision or date instead of the latest bump.
+func (c CommitRange) IsSet() bool {
+	return c.Since != "" || !c.SinceDate.IsZero()
+}
+
 // GetCommits gets a list of commits before Bump.
 func (r *Bumper) GetCommits(last bool) ([]*Commit, error) {
-	var result []*Commit
+	return r.GetCommitsInRange(CommitRange{Last: last})
+}
 
+// GetCommitsInRange gets a list of commits of the range with their changed files, latest first.
+// Bump commits don't bound an explicit range, their files are skipped instead.
+func (r *Bumper) GetCommitsInRange(rng CommitRange) ([]*Commit, error) {
 	headRef, err := r.git.Head()
 	if err != nil {
 		return nil, err
@@ -87,7 +122,25 @@ func (r *Bumper) GetCommits(last bool) ([]*Commit, error) {
 		return nil, err
 	}
 
-	commits, err := r.git.Log(&git.LogOptions{From: headRef.Hash()})
+	return r.commitsInRange(headCommit, rng)
+}
+
+// commitsInRange gets a list of commits of the range ending with the head commit, latest first.
+func (r *Bumper) commitsInRange(headCommit *object.Commit, rng CommitRange) ([]*Commit, error) {
+	var result []*Commit
+
+	base, err := r.rangeBase(headCommit, rng.Since)
+	if err != nil {
+		return nil, err
+	}
+	excluded := func(c *object.Commit) bool {
+		return (base != nil && c.Hash == *base) || (!rng.SinceDate.IsZero() && c.Committer.When.Before(rng.SinceDate))
+	}
+	if excluded(headCommit) {
+		return nil, nil
+	}
+
+	commits, err := r.git.Log(&git.LogOptions{From: headCommit.Hash})
 	if err != nil {
 		return nil, err
 	}
@@ -104,47 +157,13 @@ func (r *Bumper) GetCommits(last bool) ([]*Commit, error) {
 			return nil
 		}
 
-		// Process previous commits.
-		prevTree, _ := prevCommit.Tree()
-		currentTree, _ := commit.Tree()
-
-		diff, diffErr := currentTree.Diff(prevTree)
-		if diffErr != nil {
-			return diffErr
-		}
-
-		var modifiedFiles []string
-
-		for _, ch := range diff {
-			action, _ := ch.Action()
-			var path string
-
-			switch action {
-			case merkletrie.Delete:
-				path = ch.From.Name
-			case merkletrie.Modify:
-				path = ch.From.Name
-			case merkletrie.Insert:
-				path = ch.To.Name
-			}
-
-			if path == "" {
-				continue
-			}
-
-			modifiedFiles = append(modifiedFiles, path)
-		}
-
-		c := &Commit{
-			Hash:  prevCommit.Hash.String(),
-			Files: modifiedFiles,
+		if err := r.appendCommit(&result, prevCommit, commit, rng.IsSet()); err != nil {
+			return err
 		}
-
-		result = append(result, c)
-		if last {
+		if rng.Last || (rng.IsSet() && excluded(commit)) {
 			return storer.ErrStop
 		}
-		if strings.TrimSpace(commit.Author.Name) == r.name {
+		if !rng.IsSet() && strings.TrimSpace(commit.Author.Name) == r.name {
 			return storer.ErrStop
 		}
 
@@ -177,9 +196,115 @@ func (r *Bumper) GetCommits(last bool) ([]*Commit, error) {
 	return result, nil
 }
 
+// appendCommit appends the commit with files changed since its predecessor in history.
+// Renamed files, detected by content similarity, are changed at their new path.
+// Bump commits are skipped if skipBumps is set.
+func (r *Bumper) appendCommit(result *[]*Commit, commit, predecessor *object.Commit, skipBumps bool) error {
+	if skipBumps && strings.TrimSpace(commit.Author.Name) == r.name {
+		return nil
+	}
+
+	currentTree, _ := commit.Tree()
+	predecessorTree, _ := predecessor.Tree()
+
+	diff, err := object.DiffTreeWithOptions(context.Background(), predecessorTree, currentTree, RenameDetection)
+	if err != nil {
+		return err
+	}
+
+	c := &Commit{Hash: commit.Hash.String()}
+	for _, ch := range diff {
+		action, _ := ch.Action()
+		var path string
+
+		switch action {
+		case merkletrie.Delete:
+			path = ch.From.Name
+		case merkletrie.Modify:
+			path = ch.To.Name
+			if ch.From.Name != ch.To.Name {
+				if c.Renames == nil {
+					c.Renames = make(map[string]string)
+				}
+				c.Renames[ch.From.Name] = ch.To.Name
+			}
+		case merkletrie.Insert:
+			path = ch.To.Name
+		}
+
+		if path == "" {
+			continue
+		}
+
+		c.Files = append(c.Files, path)
+	}
+
+	*result = append(*result, c)
+
+	return nil
+}
+
+// rangeBase returns the merge base of HEAD and the revision, nil without revision.
+func (r *Bumper) rangeBase(head *object.Commit, revision string) (*plumbing.Hash, error) {
+	if revision == "" {
+		return nil, nil
+	}
+
+	hash, err := r.git.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s > %w", revision, err)
+	}
+
+	since, err := r.git.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commit %s > %w", revision, err)
+	}
+
+	bases, err := head.MergeBase(since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find merge base of HEAD and %s > %w", revision, err)
+	}
+	if len(bases) == 0 {
+		return nil, fmt.Errorf("HEAD and %s have no common history", revision)
+	}
+
+	return &bases[0].Hash, nil
+}
+
+// Add stages files, e.g. created by the bump, to be stored by Commit along with modified files.
+func (r *Bumper) Add(paths ...string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	w, err := r.git.Worktree()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		if _, err = w.Add(filepath.ToSlash(filepath.Clean(path))); err != nil {
+			return fmt.Errorf("failed to add %s to git > %w", path, err)
+		}
+	}
+
+	return nil
+}
+
 // Commit stores the current changes to the Git repository with the default commit message and author.
 func (r *Bumper) Commit() error {
 	fmt.Println("Commit changes to updated resources")
+	return r.commit(false)
+}
+
+// Amend replaces the bump commit at HEAD with the current changes, with the default commit message and author.
+func (r *Bumper) Amend() error {
+	fmt.Println("Amend the bump commit with changes to updated resources")
+	return r.commit(true)
+}
+
+// commit stores modified tracked files, deleted ones too when amending, in a new commit or in place of HEAD.
+func (r *Bumper) commit(amend bool) error {
 	w, _ := r.git.Worktree()
 	status, _ := w.Status()
 
@@ -189,7 +314,7 @@ func (r *Bumper) Commit() error {
 	}
 
 	for path, s := range status {
-		if s.Worktree == git.Modified {
+		if s.Worktree == git.Modified || (amend && s.Worktree == git.Deleted) {
 			err := w.AddWithOptions(&git.AddOptions{
 				Path:       path,
 				SkipStatus: true,
@@ -206,6 +331,8 @@ func (r *Bumper) Commit() error {
 			Email: r.mail,
 			When:  time.Now(),
 		},
+		Signer: r.signer,
+		Amend:  amend,
 	})
 
 	return err
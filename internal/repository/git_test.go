@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/plumbing/storer"
 )
@@ -450,3 +451,104 @@ func TestBumpWorkflowInWorktree(t *testing.T) {
 		t.Error("expected to find diffs between commits")
 	}
 }
+
+func TestBumpAuthors(t *testing.T) {
+	authors, err := NewBumpAuthors([]string{"ci-bot", "bot@example.com", "/^release-/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name, email string
+		expected    bool
+	}{
+		{Author, "", true},
+		{"ci-bot", "ci@example.com", true},
+		{"Bot", "bot@example.com", true},
+		{"release-2024", "", true},
+		{"Developer", "dev@example.com", false},
+	}
+	for _, c := range cases {
+		if got := authors.Match(c.name, c.email); got != c.expected {
+			t.Errorf("Match(%q, %q) = %v, expected %v", c.name, c.email, got, c.expected)
+		}
+	}
+
+	var none *BumpAuthors
+	if !none.Match(Author, "") || none.Match("ci-bot", "") {
+		t.Error("nil matcher must only match the bumper")
+	}
+
+	if _, err = NewBumpAuthors([]string{"/[/"}); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+}
+
+func TestGetCommitsInRange(t *testing.T) {
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("worktree: %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	commit := func(file, author string, day int) string {
+		t.Helper()
+		if err = os.WriteFile(filepath.Join(dir, file), []byte(file), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Add(file); err != nil {
+			t.Fatal(err)
+		}
+		hash, errCommit := w.Commit("change "+file, &git.CommitOptions{
+			Author: &object.Signature{Name: author, Email: "dev@test.com", When: start.AddDate(0, 0, day)},
+		})
+		if errCommit != nil {
+			t.Fatal(errCommit)
+		}
+		return hash.String()
+	}
+
+	commit("a", "Developer", 0)
+	tagged := commit("b", "Developer", 1)
+	if _, err = repo.CreateTag("v1.2.0", plumbing.NewHash(tagged), nil); err != nil {
+		t.Fatal(err)
+	}
+	c := commit("c", "Developer", 2)
+	commit("d", Author, 3)
+	e := commit("e", "Developer", 4)
+
+	bumper := &Bumper{git: repo, name: Author}
+	hashes := func(rng CommitRange) []string {
+		t.Helper()
+		commits, errRange := bumper.GetCommitsInRange(rng)
+		if errRange != nil {
+			t.Fatalf("GetCommitsInRange: %v", errRange)
+		}
+		var result []string
+		for _, commit := range commits {
+			result = append(result, commit.Hash)
+		}
+		return result
+	}
+
+	if got := hashes(CommitRange{}); len(got) != 1 || got[0] != e {
+		t.Errorf("expected commits since bump [%s], got %v", e, got)
+	}
+	if got := hashes(CommitRange{Since: "v1.2.0"}); len(got) != 2 || got[0] != e || got[1] != c {
+		t.Errorf("expected commits since tag without bump [%s %s], got %v", e, c, got)
+	}
+	if got := hashes(CommitRange{SinceDate: start.AddDate(0, 0, 2)}); len(got) != 2 || got[0] != e || got[1] != c {
+		t.Errorf("expected commits since date without bump [%s %s], got %v", e, c, got)
+	}
+	if got := hashes(CommitRange{Since: "HEAD"}); len(got) != 0 {
+		t.Errorf("expected no commit since HEAD, got %v", got)
+	}
+	if _, err = bumper.GetCommitsInRange(CommitRange{Since: "unknown"}); err == nil {
+		t.Error("expected error for unknown revision")
+	}
+}
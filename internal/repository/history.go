@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// VersionEdit describes a commit which changed a component version in meta file.
+type VersionEdit struct {
+	Component  string
+	Commit     string
+	Author     string
+	Email      string
+	Date       time.Time
+	OldVersion string
+	NewVersion string
+}
+
+// FindManualVersionEdits walks history from HEAD and returns, per component, the version changes
+// done by non-bump authors after the latest bump of that component.
+// metaPaths maps component name to the meta file path relative to repository root.
+func FindManualVersionEdits(r *git.Repository, metaPaths map[string]string, authors *BumpAuthors) (map[string][]VersionEdit, error) {
+	result := make(map[string][]VersionEdit)
+	if len(metaPaths) == 0 {
+		return result, nil
+	}
+
+	pending := make(map[string]string, len(metaPaths))
+	for name, path := range metaPaths {
+		pending[path] = name
+	}
+
+	ref, err := r.Head()
+	if err != nil {
+		return nil, fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	cIter, err := r.Log(&git.LogOptions{From: ref.Hash()})
+	if err != nil {
+		return nil, fmt.Errorf("git log error > %w", err)
+	}
+
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if len(pending) == 0 {
+			return storer.ErrStop
+		}
+
+		// Creation of component is not considered as version edit.
+		if c.NumParents() == 0 {
+			return storer.ErrStop
+		}
+
+		parent, errP := c.Parent(0)
+		if errP != nil {
+			return errP
+		}
+
+		parentTree, errP := parent.Tree()
+		if errP != nil {
+			return errP
+		}
+
+		currentTree, errP := c.Tree()
+		if errP != nil {
+			return errP
+		}
+
+		changes, errP := parentTree.Diff(currentTree)
+		if errP != nil {
+			return errP
+		}
+
+		for _, ch := range changes {
+			path := ch.To.Name
+			if path == "" {
+				path = ch.From.Name
+			}
+
+			component, ok := pending[path]
+			if !ok {
+				continue
+			}
+
+			newVersion, errV := metaVersionFromTree(currentTree, path)
+			if errV != nil {
+				return errV
+			}
+
+			oldVersion, errV := metaVersionFromTree(parentTree, path)
+			if errV != nil {
+				return errV
+			}
+
+			if oldVersion == newVersion {
+				continue
+			}
+
+			if authors.Match(c.Author.Name, c.Author.Email) {
+				// Everything older than latest bump is considered as valid history.
+				delete(pending, path)
+				continue
+			}
+
+			result[component] = append(result[component], VersionEdit{
+				Component:  component,
+				Commit:     c.Hash.String(),
+				Author:     c.Author.Name,
+				Email:      c.Author.Email,
+				Date:       c.Author.When,
+				OldVersion: oldVersion,
+				NewVersion: newVersion,
+			})
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func metaVersionFromTree(tree *object.Tree, path string) (string, error) {
+	file, err := tree.File(path)
+	if err != nil {
+		if errors.Is(err, object.ErrFileNotFound) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("opening file %s > %w", path, err)
+	}
+
+	reader, err := file.Reader()
+	if err != nil {
+		return "", fmt.Errorf("can't read %s > %w", path, err)
+	}
+	defer reader.Close()
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return "", fmt.Errorf("can't read %s > %w", path, err)
+	}
+
+	meta, err := sync.LoadYamlFileFromBytes(contents)
+	if err != nil {
+		return "", fmt.Errorf("YAML load %s > %w", path, err)
+	}
+
+	return sync.GetMetaVersion(meta), nil
+}
@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+)
+
+// DefaultTicketPattern extracts ticket IDs such as `PLAT-123` from branch names.
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-[0-9]+`
+
+var trailerRegexp = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9-]*: \S`)
+
+// BumpCommit customizes the bump commit, e.g. to satisfy commit message lint rules or signed commits policies.
+// Message and trailers may reference `{branch}`, the current branch, and `{ticket}`, the ticket ID found in it.
+type BumpCommit struct {
+	// Message is the commit message, [BumpMessage] by default.
+	Message string `yaml:"message"`
+	// Trailers are `Key: value` lines appended to the message, trailers referencing `{ticket}` are
+	// omitted when the branch has no ticket.
+	Trailers []string `yaml:"trailers"`
+	// TicketPattern is the regular expression of ticket IDs in branch names, [DefaultTicketPattern] by default.
+	// The first group is the ticket if the pattern has groups.
+	TicketPattern string `yaml:"ticket_pattern"`
+	// Signoff appends a `Signed-off-by` trailer of the git user.
+	Signoff bool `yaml:"signoff"`
+	// Sign signs the commit with `user.signingkey` of git config, see [Bumper.EnableSigning].
+	Sign bool `yaml:"sign"`
+}
+
+// SetCommitMessage sets the message of the bump commit.
+func (r *Bumper) SetCommitMessage(c BumpCommit) error {
+	branch := r.branch()
+	ticket, err := c.ticket(branch)
+	if err != nil {
+		return err
+	}
+
+	var signoff string
+	if c.Signoff {
+		signoff, err = r.signoff()
+		if err != nil {
+			return err
+		}
+	}
+
+	message, err := c.build(branch, ticket, signoff)
+	if err != nil {
+		return err
+	}
+
+	r.commitMessage = message
+	return nil
+}
+
+// build returns the commit message with placeholders replaced and trailers appended.
+func (c BumpCommit) build(branch, ticket, signoff string) (string, error) {
+	replacer := strings.NewReplacer("{branch}", branch, "{ticket}", ticket)
+
+	message := strings.TrimSpace(c.Message)
+	if message == "" {
+		message = BumpMessage
+	}
+	message = replacer.Replace(message)
+
+	var trailers []string
+	for _, t := range c.Trailers {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !trailerRegexp.MatchString(t) {
+			return "", fmt.Errorf("invalid trailer %q, expected `Key: value`", t)
+		}
+		if ticket == "" && strings.Contains(t, "{ticket}") {
+			continue
+		}
+		trailers = append(trailers, replacer.Replace(t))
+	}
+	if signoff != "" {
+		trailers = append(trailers, "Signed-off-by: "+signoff)
+	}
+
+	if len(trailers) == 0 {
+		return message, nil
+	}
+
+	return message + "\n\n" + strings.Join(trailers, "\n"), nil
+}
+
+// ticket returns the ticket ID found in the branch name, empty if none.
+func (c BumpCommit) ticket(branch string) (string, error) {
+	pattern := c.TicketPattern
+	if pattern == "" {
+		pattern = DefaultTicketPattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticket pattern %s > %w", pattern, err)
+	}
+
+	match := re.FindStringSubmatch(branch)
+	switch {
+	case match == nil:
+		return "", nil
+	case len(match) > 1:
+		return match[1], nil
+	default:
+		return match[0], nil
+	}
+}
+
+// branch returns the current branch name, empty when HEAD is detached.
+func (r *Bumper) branch() string {
+	ref, err := r.git.Head()
+	if err != nil || !ref.Name().IsBranch() {
+		return ""
+	}
+
+	return ref.Name().Short()
+}
+
+// signoff returns the identity of the git user, as `Name <email>`.
+func (r *Bumper) signoff() (string, error) {
+	cfg, err := r.git.ConfigScoped(config.GlobalScope)
+	if err != nil {
+		return "", fmt.Errorf("failed to read git config > %w", err)
+	}
+
+	if cfg.User.Name == "" || cfg.User.Email == "" {
+		return "", fmt.Errorf("signoff requires git user.name and user.email")
+	}
+
+	return fmt.Sprintf("%s <%s>", cfg.User.Name, cfg.User.Email), nil
+}
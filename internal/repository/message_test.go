@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestBumpCommitBuild(t *testing.T) {
+	tests := []struct {
+		name     string
+		commit   BumpCommit
+		branch   string
+		expected string
+	}{
+		{"default", BumpCommit{}, "main", BumpMessage},
+		{"message", BumpCommit{Message: "chore: bump versions"}, "main", "chore: bump versions"},
+		{"ticket in message", BumpCommit{Message: "{ticket}: bump versions"}, "feature/PLAT-42-auth", "PLAT-42: bump versions"},
+		{
+			"trailers",
+			BumpCommit{Trailers: []string{"Refs: {ticket}", "Co-authored-by: Jane <jane@example.com>"}},
+			"feature/PLAT-42-auth",
+			BumpMessage + "\n\nRefs: PLAT-42\nCo-authored-by: Jane <jane@example.com>",
+		},
+		{
+			"trailer without ticket",
+			BumpCommit{Trailers: []string{"Refs: {ticket}", "Branch: {branch}"}},
+			"main",
+			BumpMessage + "\n\nBranch: main",
+		},
+		{
+			"ticket pattern group",
+			BumpCommit{Trailers: []string{"Issue: #{ticket}"}, TicketPattern: `issue-([0-9]+)`},
+			"fix/issue-17",
+			BumpMessage + "\n\nIssue: #17",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ticket, err := tt.commit.ticket(tt.branch)
+			if err != nil {
+				t.Fatal(err)
+			}
+			message, err := tt.commit.build(tt.branch, ticket, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if message != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, message)
+			}
+		})
+	}
+
+	if _, err := (BumpCommit{Trailers: []string{"not a trailer"}}).build("main", "", ""); err == nil {
+		t.Error("expected error for invalid trailer")
+	}
+	if _, err := (BumpCommit{TicketPattern: "("}).ticket("main"); err == nil {
+		t.Error("expected error for invalid ticket pattern")
+	}
+}
+
+func TestSetCommitMessage(t *testing.T) {
+	repoDir := initTestRepo(t)
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	branch := plumbing.NewBranchReferenceName("feature/PLAT-7-bump")
+	if err = repo.Storer.SetReference(plumbing.NewHashReference(branch, head.Hash())); err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, branch)); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.User.Name = "Jane"
+	cfg.User.Email = "jane@example.com"
+	if err = repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	bumper := &Bumper{git: repo, name: Author, commitMessage: BumpMessage}
+	err = bumper.SetCommitMessage(BumpCommit{Message: "{ticket} versions bump", Signoff: true})
+	if err != nil {
+		t.Fatalf("SetCommitMessage: %v", err)
+	}
+
+	expected := "PLAT-7 versions bump\n\nSigned-off-by: Jane <jane@example.com>"
+	if bumper.commitMessage != expected {
+		t.Errorf("expected %q, got %q", expected, bumper.commitMessage)
+	}
+}
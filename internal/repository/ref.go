@@ -0,0 +1,226 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// RefFS returns the tree of the commit a ref (commit hash, tag or branch) resolves to as a read-only filesystem,
+// with the commit. Files are read from the object store, without checkout.
+func RefFS(dir, ref string) (fs.FS, *object.Commit, error) {
+	r, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open repository %s > %w", dir, err)
+	}
+
+	hash, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to resolve ref %q > %w", ref, err)
+	}
+
+	commit, err := r.CommitObject(*hash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get commit object of %q > %w", ref, err)
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't get tree of %q > %w", ref, err)
+	}
+
+	return &TreeFS{tree: tree, modTime: commit.Committer.When}, commit, nil
+}
+
+// TreeFS is a read-only [fs.FS] of a git tree. Modification time of files is the commit time.
+// Submodules are listed as irregular files and can't be opened.
+type TreeFS struct {
+	tree    *object.Tree
+	modTime time.Time
+}
+
+// Open implements [fs.FS].
+func (t *TreeFS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+
+	if name == "." {
+		return &treeDir{info: treeInfo{name: ".", mode: fs.ModeDir | 0555, modTime: t.modTime}, tree: t.tree, fsys: t}, nil
+	}
+
+	entry, err := t.tree.FindEntry(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	info := t.entryInfo(entry)
+	switch {
+	case entry.Mode == filemode.Dir:
+		sub, errTree := t.tree.Tree(name)
+		if errTree != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: errTree}
+		}
+		return &treeDir{info: info, tree: sub, fsys: t}, nil
+	case entry.Mode == filemode.Submodule:
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+
+	file, err := t.tree.TreeEntryFile(entry)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	reader, err := file.Reader()
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	info.size = file.Size
+
+	return &treeFile{info: info, reader: reader}, nil
+}
+
+// entryInfo returns the file info of a tree entry, without the size of files which requires reading their blob.
+func (t *TreeFS) entryInfo(entry *object.TreeEntry) treeInfo {
+	info := treeInfo{name: path.Base(entry.Name), modTime: t.modTime, mode: 0444}
+	switch entry.Mode {
+	case filemode.Dir:
+		info.mode = fs.ModeDir | 0555
+	case filemode.Executable:
+		info.mode = 0555
+	case filemode.Symlink:
+		info.mode = fs.ModeSymlink | 0444
+	case filemode.Submodule:
+		info.mode = fs.ModeIrregular
+	}
+
+	return info
+}
+
+// treeInfo implements [fs.FileInfo] of tree entries.
+type treeInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func (i treeInfo) Name() string       { return i.name }
+func (i treeInfo) Size() int64        { return i.size }
+func (i treeInfo) Mode() fs.FileMode  { return i.mode }
+func (i treeInfo) ModTime() time.Time { return i.modTime }
+func (i treeInfo) IsDir() bool        { return i.mode.IsDir() }
+func (i treeInfo) Sys() any           { return nil }
+
+// treeEntry implements [fs.DirEntry] of tree entries, the size of files is read on Info.
+type treeEntry struct {
+	fsys  *TreeFS
+	entry object.TreeEntry
+}
+
+func (e treeEntry) Name() string      { return e.entry.Name }
+func (e treeEntry) IsDir() bool       { return e.entry.Mode == filemode.Dir }
+func (e treeEntry) Type() fs.FileMode { return e.fsys.entryInfo(&e.entry).mode.Type() }
+
+func (e treeEntry) Info() (fs.FileInfo, error) {
+	info := e.fsys.entryInfo(&e.entry)
+	if !info.mode.IsRegular() && info.mode&fs.ModeSymlink == 0 {
+		return info, nil
+	}
+
+	file, err := e.fsys.tree.TreeEntryFile(&e.entry)
+	if err != nil {
+		return nil, err
+	}
+	info.size = file.Size
+
+	return info, nil
+}
+
+// treeFile is an opened blob of the tree.
+type treeFile struct {
+	info   treeInfo
+	reader io.ReadCloser
+}
+
+func (f *treeFile) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *treeFile) Read(b []byte) (int, error) { return f.reader.Read(b) }
+func (f *treeFile) Close() error               { return f.reader.Close() }
+
+// treeDir is an opened subtree, its entries are read in tree order.
+type treeDir struct {
+	info   treeInfo
+	tree   *object.Tree
+	fsys   *TreeFS
+	offset int
+}
+
+func (d *treeDir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *treeDir) Close() error               { return nil }
+
+func (d *treeDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+// ReadDir implements [fs.ReadDirFile].
+func (d *treeDir) ReadDir(n int) ([]fs.DirEntry, error) {
+	entries := d.tree.Entries[d.offset:]
+	if n > 0 && len(entries) == 0 {
+		return nil, io.EOF
+	}
+	if n > 0 && len(entries) > n {
+		entries = entries[:n]
+	}
+
+	list := make([]fs.DirEntry, 0, len(entries))
+	for _, entry := range entries {
+		list = append(list, treeEntry{fsys: d.fsys, entry: entry})
+	}
+	d.offset += len(entries)
+
+	return list, nil
+}
+
+// CommitBefore returns the latest commit of the history of HEAD of the repository of the directory committed before
+// the time, e.g. to read the domain as it was at a date.
+func CommitBefore(dir string, before time.Time) (*object.Commit, error) {
+	r, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{DetectDotGit: true, EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository %s > %w", dir, err)
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return nil, fmt.Errorf("can't get HEAD ref > %w", err)
+	}
+
+	cIter, err := r.Log(&git.LogOptions{From: head.Hash(), Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("git log error > %w", err)
+	}
+
+	var found *object.Commit
+	err = cIter.ForEach(func(c *object.Commit) error {
+		if c.Committer.When.Before(before) {
+			found = c
+			return storer.ErrStop
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("no commit before %s", before.Format(time.DateTime))
+	}
+
+	return found, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestRefFS(t *testing.T) {
+	dir := initTestRepo(t)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	meta := filepath.Join("foundation", "services", "postgres", "meta", "plasma.yaml")
+	write := func(version string) {
+		t.Helper()
+		if err = os.MkdirAll(filepath.Join(dir, filepath.Dir(meta)), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, meta), []byte("plasma:\n  version: "+version+"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+		w, _ := repo.Worktree()
+		if _, err = w.Add(meta); err != nil {
+			t.Fatalf("git add: %v", err)
+		}
+		_, err = w.Commit("postgres "+version, &git.CommitOptions{
+			Author: &object.Signature{Name: "Test", Email: "test@test.com", When: time.Now()},
+		})
+		if err != nil {
+			t.Fatalf("commit: %v", err)
+		}
+	}
+
+	write("aaa1111111111")
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("head: %v", err)
+	}
+	if _, err = repo.CreateTag("v1", head.Hash(), nil); err != nil {
+		t.Fatalf("tag: %v", err)
+	}
+	write("bbb2222222222")
+
+	fsys, commit, err := RefFS(dir, "v1")
+	if err != nil {
+		t.Fatalf("ref fs: %v", err)
+	}
+	if commit.Message != "postgres aaa1111111111" {
+		t.Errorf("expected tagged commit, got %q", commit.Message)
+	}
+
+	if err = fstest.TestFS(fsys, "README.md", "foundation/services/postgres/meta/plasma.yaml"); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := fs.ReadFile(fsys, "foundation/services/postgres/meta/plasma.yaml")
+	if err != nil || string(data) != "plasma:\n  version: aaa1111111111\n" {
+		t.Errorf("expected meta of the tagged commit, got %q (%v)", data, err)
+	}
+
+	if _, _, err = RefFS(dir, "missing"); err == nil {
+		t.Error("expected error for unknown ref")
+	}
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ShallowBoundary returns commits of a shallow clone which parents are missing from the object store,
+// it is empty for a repository with complete history.
+func ShallowBoundary(r *git.Repository) ([]plumbing.Hash, error) {
+	shallow, err := r.Storer.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("can't read shallow commits > %w", err)
+	}
+
+	return shallow, nil
+}
+
+// ShallowParents returns parents of the boundary commits, they must be skipped when walking history.
+func ShallowParents(r *git.Repository, boundary []plumbing.Hash) ([]plumbing.Hash, error) {
+	var parents []plumbing.Hash
+	for _, h := range boundary {
+		c, err := r.CommitObject(h)
+		if err != nil {
+			return nil, fmt.Errorf("can't get shallow commit object %s > %w", h, err)
+		}
+		parents = append(parents, c.ParentHashes...)
+	}
+
+	return parents, nil
+}
+
+// Unshallow fetches complete history of a shallow clone from the remote.
+func Unshallow(r *git.Repository, remote string) error {
+	err := r.Fetch(&git.FetchOptions{RemoteName: remote, Depth: math.MaxInt32, Tags: git.NoTags})
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return fmt.Errorf("fetch history from %s > %w", remote, err)
+	}
+
+	boundary, err := ShallowBoundary(r)
+	if err != nil {
+		return err
+	}
+
+	// Fetched history isn't removed from the shallow file, keep only commits which parents are still missing.
+	var kept []plumbing.Hash
+	for _, h := range boundary {
+		c, errCommit := r.CommitObject(h)
+		if errCommit != nil {
+			return fmt.Errorf("can't get shallow commit object %s > %w", h, errCommit)
+		}
+
+		for _, p := range c.ParentHashes {
+			if _, errParent := r.CommitObject(p); errParent != nil {
+				kept = append(kept, h)
+				break
+			}
+		}
+	}
+
+	if err = r.Storer.SetShallow(kept); err != nil {
+		return fmt.Errorf("can't update shallow commits > %w", err)
+	}
+
+	if len(kept) > 0 {
+		return fmt.Errorf("history fetched from %s is still incomplete", remote)
+	}
+
+	return nil
+}
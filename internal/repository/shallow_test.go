@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestUnshallow(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git CLI not available")
+	}
+
+	dir := initTestRepoWithResource(t)
+	cloneDir := filepath.Join(t.TempDir(), "shallow")
+	cmd := exec.Command("git", "clone", "--depth=1", "file://"+filepath.ToSlash(dir), cloneDir) //nolint:gosec
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git clone: %s: %v", out, err)
+	}
+
+	repo, err := git.PlainOpen(cloneDir)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+
+	boundary, err := ShallowBoundary(repo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(boundary) != 1 {
+		t.Fatalf("expected 1 shallow commit, got %v", boundary)
+	}
+
+	parents, err := ShallowParents(repo, boundary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parents) != 1 {
+		t.Fatalf("expected 1 skipped parent, got %v", parents)
+	}
+
+	if err = Unshallow(repo, git.DefaultRemoteName); err != nil {
+		t.Fatalf("unshallow: %v", err)
+	}
+
+	if boundary, _ = ShallowBoundary(repo); len(boundary) != 0 {
+		t.Errorf("expected complete history, got shallow commits %v", boundary)
+	}
+
+	head, _ := repo.Head()
+	iter, err := repo.Log(&git.LogOptions{From: head.Hash()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var count int
+	if err = iter.ForEach(func(*object.Commit) error { count++; return nil }); err != nil {
+		t.Fatalf("log: %v", err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 commits after unshallow, got %d", count)
+	}
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-git/go-git/v5/config"
+	format "github.com/go-git/go-git/v5/plumbing/format/config"
+)
+
+// Signature formats of `gpg.format` git option.
+const (
+	SignFormatOpenPGP = "openpgp"
+	SignFormatX509    = "x509"
+	SignFormatSSH     = "ssh"
+)
+
+// commitSigner signs commits with the program and key configured in git, as `git commit -S` does.
+type commitSigner struct {
+	format  string
+	program string
+	key     string
+}
+
+// EnableSigning signs the bump commit with `user.signingkey` of git config, in `gpg.format` format.
+func (r *Bumper) EnableSigning() error {
+	raws := []*format.Config{}
+	if local, err := r.git.Storer.Config(); err == nil {
+		raws = append(raws, local.Raw)
+	}
+	for _, scope := range []config.Scope{config.GlobalScope, config.SystemScope} {
+		if cfg, err := config.LoadConfig(scope); err == nil {
+			raws = append(raws, cfg.Raw)
+		}
+	}
+
+	get := func(section, subsection, key string) string {
+		for _, raw := range raws {
+			if raw == nil || !raw.HasSection(section) {
+				continue
+			}
+			s := raw.Section(section)
+			opts := s.Options
+			if subsection != "" {
+				if !s.HasSubsection(subsection) {
+					continue
+				}
+				opts = s.Subsection(subsection).Options
+			}
+			if v := opts.Get(key); v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	s := &commitSigner{
+		format: get("gpg", "", "format"),
+		key:    get("user", "", "signingkey"),
+	}
+	if s.format == "" {
+		s.format = SignFormatOpenPGP
+	}
+	if s.key == "" {
+		return fmt.Errorf("signing requires git user.signingkey")
+	}
+
+	switch s.format {
+	case SignFormatOpenPGP:
+		s.program = get("gpg", SignFormatOpenPGP, "program")
+		if s.program == "" {
+			s.program = get("gpg", "", "program")
+		}
+		if s.program == "" {
+			s.program = "gpg"
+		}
+	case SignFormatX509:
+		s.program = get("gpg", SignFormatX509, "program")
+		if s.program == "" {
+			s.program = "gpgsm"
+		}
+	case SignFormatSSH:
+		s.program = get("gpg", SignFormatSSH, "program")
+		if s.program == "" {
+			s.program = "ssh-keygen"
+		}
+	default:
+		return fmt.Errorf("unsupported git gpg.format %q", s.format)
+	}
+
+	r.signer = s
+	return nil
+}
+
+// Sign implements [git.Signer] interface.
+func (s *commitSigner) Sign(message io.Reader) ([]byte, error) {
+	if s.format == SignFormatSSH {
+		return s.signSSH(message)
+	}
+
+	return s.run(message, "--status-fd=2", "-bsau", s.key)
+}
+
+// signSSH signs with ssh-keygen, the key is a private key file or, when literal, a public key of ssh-agent.
+func (s *commitSigner) signSSH(message io.Reader) ([]byte, error) {
+	key := strings.TrimPrefix(s.key, "key::")
+	if key == s.key && !strings.HasPrefix(key, "ssh-") {
+		return s.run(message, "-Y", "sign", "-n", "git", "-f", expandHome(key))
+	}
+
+	f, err := os.CreateTemp("", "bump-signing-key-*.pub")
+	if err != nil {
+		return nil, fmt.Errorf("failed to write signing key > %w", err)
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.WriteString(key + "\n")
+	if errClose := f.Close(); err == nil {
+		err = errClose
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to write signing key > %w", err)
+	}
+
+	return s.run(message, "-Y", "sign", "-n", "git", "-U", "-f", f.Name())
+}
+
+func (s *commitSigner) run(message io.Reader, args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(s.program, args...) //nolint:gosec
+	cmd.Stdin = message
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed to sign the commit: %s > %w", s.program, strings.TrimSpace(stderr.String()), err)
+	}
+	if stdout.Len() == 0 {
+		return nil, fmt.Errorf("%s returned no signature", s.program)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// expandHome expands `~/` at the beginning of the path, as git does for paths in config.
+func expandHome(path string) string {
+	if !strings.HasPrefix(path, "~/") {
+		return path
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+
+	return filepath.Join(home, path[2:])
+}
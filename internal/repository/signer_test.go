@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestEnableSigningSSH(t *testing.T) {
+	if _, err := exec.LookPath("ssh-keygen"); err != nil {
+		t.Skip("ssh-keygen is not available")
+	}
+
+	// Global git config must not provide a signing key.
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	repoDir := initTestRepo(t)
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bumper := &Bumper{git: repo, name: Author, mail: "noreply@plasma.sh", commitMessage: BumpMessage}
+	if err = bumper.EnableSigning(); err == nil {
+		t.Fatal("expected error without signing key")
+	}
+
+	key := filepath.Join(t.TempDir(), "id_ed25519")
+	if out, errGen := exec.Command("ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-f", key).CombinedOutput(); errGen != nil {
+		t.Fatalf("ssh-keygen: %s: %v", out, errGen)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Raw.SetOption("gpg", "", "format", SignFormatSSH)
+	cfg.Raw.SetOption("user", "", "signingkey", key)
+	if err = repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = bumper.EnableSigning(); err != nil {
+		t.Fatalf("EnableSigning: %v", err)
+	}
+
+	if err = os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("bumped"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = bumper.Commit(); err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(commit.PGPSignature, "-----BEGIN SSH SIGNATURE-----") {
+		t.Errorf("expected ssh signature, got %q", commit.PGPSignature)
+	}
+
+	cfg.Raw.SetOption("gpg", "", "format", "unknown")
+	if err = repo.SetConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err = bumper.EnableSigning(); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
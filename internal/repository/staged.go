@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"crypto/sha1" //nolint:gosec // identifies staged content like git object hashes do
+	"encoding/hex"
+	"sort"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// GetStagedChanges returns files staged in the index as a commit to be, nil if nothing is staged.
+// As the commit doesn't exist yet, its hash identifies the staged content: paths with their blob hashes.
+func (r *Bumper) GetStagedChanges() (*Commit, error) {
+	w, err := r.git.Worktree()
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for path, s := range status {
+		if s.Staging == git.Unmodified || s.Staging == git.Untracked {
+			continue
+		}
+		files = append(files, path)
+	}
+	if len(files) == 0 {
+		return nil, nil
+	}
+	sort.Strings(files)
+
+	idx, err := r.git.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+
+	h := sha1.New() //nolint:gosec
+	for _, path := range files {
+		h.Write([]byte(path))
+		h.Write([]byte{0})
+		// Deleted files have no entry, their path alone is hashed.
+		if e, errEntry := idx.Entry(path); errEntry == nil {
+			h.Write(e.Hash[:])
+		}
+		h.Write([]byte{'\n'})
+	}
+
+	return &Commit{Hash: hex.EncodeToString(h.Sum(nil)), Files: files, Uncommitted: true}, nil
+}
@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// SyncMessage is the commit message of versions propagated on a branch worktree.
+const SyncMessage = "versions sync"
+
+// Worktree is a temporary checkout of a branch of the repository, linked to it as `git worktree add` does,
+// so changes committed there update the branch without touching the current checkout.
+type Worktree struct {
+	Dir    string
+	Branch string
+
+	repo     *git.Repository
+	adminDir string
+}
+
+// AddWorktree checks out the branch of the repository of the directory in a temporary linked worktree.
+// A branch only known to origin is created locally. The branch must not be checked out in another worktree.
+func AddWorktree(dir, branch string) (*Worktree, error) {
+	common, current, err := gitDirs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, err
+	}
+
+	refName := plumbing.NewBranchReferenceName(branch)
+	if _, err = repo.Reference(refName, true); err != nil {
+		remote, errRemote := repo.Reference(plumbing.NewRemoteReferenceName("origin", branch), true)
+		if errRemote != nil {
+			return nil, fmt.Errorf("branch %s not found > %w", branch, err)
+		}
+		if err = repo.Storer.SetReference(plumbing.NewHashReference(refName, remote.Hash())); err != nil {
+			return nil, fmt.Errorf("can't create branch %s from origin > %w", branch, err)
+		}
+	}
+
+	switch checkedOut := checkedOutIn(common, refName); checkedOut {
+	case "":
+	case current:
+		return nil, fmt.Errorf("branch %s is the current branch", branch)
+	default:
+		return nil, fmt.Errorf("branch %s is checked out in %s", branch, checkedOut)
+	}
+
+	checkout, err := os.MkdirTemp("", "plasmactl-branch-")
+	if err != nil {
+		return nil, err
+	}
+
+	w := &Worktree{
+		Dir:      checkout,
+		Branch:   branch,
+		adminDir: filepath.Join(common, "worktrees", filepath.Base(checkout)),
+	}
+	if err = w.link(refName); err != nil {
+		return nil, errors.Join(err, w.Remove())
+	}
+
+	w.repo, err = git.PlainOpenWithOptions(checkout, &git.PlainOpenOptions{EnableDotGitCommonDir: true})
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("can't open worktree of %s > %w", branch, err), w.Remove())
+	}
+
+	tree, err := w.repo.Worktree()
+	if err == nil {
+		err = tree.Checkout(&git.CheckoutOptions{Branch: refName, Force: true})
+	}
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("can't checkout %s > %w", branch, err), w.Remove())
+	}
+
+	return w, nil
+}
+
+// link writes the administrative files of the worktree in the common git directory, and the worktree .git file
+// pointing to them.
+func (w *Worktree) link(branch plumbing.ReferenceName) error {
+	if err := os.MkdirAll(w.adminDir, 0750); err != nil {
+		return err
+	}
+
+	files := map[string]string{
+		filepath.Join(w.adminDir, "HEAD"):      "ref: " + branch.String() + "\n",
+		filepath.Join(w.adminDir, "commondir"): filepath.Join("..", "..") + "\n",
+		filepath.Join(w.adminDir, "gitdir"):    filepath.Join(w.Dir, git.GitDirName) + "\n",
+		filepath.Join(w.Dir, git.GitDirName):   "gitdir: " + w.adminDir + "\n",
+	}
+	for path, content := range files {
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			return fmt.Errorf("can't link worktree > %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Commit stores changes to tracked files of the worktree on its branch with the bumper identity, so history
+// grouping treats the commit as a bump one. The returned hash is zero if there is nothing to commit.
+func (w *Worktree) Commit(message string) (plumbing.Hash, error) {
+	tree, err := w.repo.Worktree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	status, err := tree.Status()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	changed := false
+	for _, s := range status {
+		if s.Worktree != git.Untracked && (s.Worktree != git.Unmodified || s.Staging != git.Unmodified) {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return plumbing.ZeroHash, nil
+	}
+
+	return tree.Commit(message, &git.CommitOptions{
+		All: true,
+		Author: &object.Signature{
+			Name:  Author,
+			Email: AuthorEmail,
+			When:  time.Now(),
+		},
+	})
+}
+
+// Remove deletes the worktree checkout and unlinks it from the repository, the branch is kept.
+func (w *Worktree) Remove() error {
+	return errors.Join(os.RemoveAll(w.Dir), os.RemoveAll(w.adminDir))
+}
+
+// gitDirs returns the common git directory of the repository of the directory, and the git directory of its
+// worktree, which differ in linked worktrees.
+func gitDirs(dir string) (string, string, error) {
+	dotGit := filepath.Join(dir, git.GitDirName)
+	stat, err := os.Stat(dotGit)
+	if err != nil {
+		return "", "", fmt.Errorf("%s isn't the root of a git repository > %w", dir, err)
+	}
+	if stat.IsDir() {
+		abs, errAbs := filepath.Abs(dotGit)
+		return abs, abs, errAbs
+	}
+
+	current, err := readGitPointer(dotGit, "gitdir: ")
+	if err != nil {
+		return "", "", err
+	}
+	common, err := readGitPointer(filepath.Join(current, "commondir"), "")
+	if errors.Is(err, os.ErrNotExist) {
+		return current, current, nil
+	}
+
+	return common, current, err
+}
+
+// readGitPointer reads the path stored in the file after the prefix, relative to the file directory.
+func readGitPointer(file, prefix string) (string, error) {
+	data, err := os.ReadFile(filepath.Clean(file))
+	if err != nil {
+		return "", err
+	}
+
+	line := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(line, prefix) {
+		return "", fmt.Errorf("malformed %s", file)
+	}
+
+	path := strings.TrimPrefix(line, prefix)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(file), path)
+	}
+
+	return filepath.Abs(path)
+}
+
+// checkedOutIn returns the git directory of the worktree having the branch checked out, empty if none has.
+func checkedOutIn(common string, branch plumbing.ReferenceName) string {
+	dirs := []string{common}
+	if entries, err := os.ReadDir(filepath.Join(common, "worktrees")); err == nil {
+		for _, e := range entries {
+			dirs = append(dirs, filepath.Join(common, "worktrees", e.Name()))
+		}
+	}
+
+	for _, d := range dirs {
+		head, err := os.ReadFile(filepath.Clean(filepath.Join(d, "HEAD")))
+		if err == nil && strings.TrimSpace(string(head)) == "ref: "+branch.String() {
+			return d
+		}
+	}
+
+	return ""
+}
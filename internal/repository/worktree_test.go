@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+func TestAddWorktree(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	repo, err := git.PlainOpen(repoDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("release/1.0"), head.Hash())); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = AddWorktree(repoDir, head.Name().Short()); err == nil {
+		t.Error("expected the current branch to be rejected")
+	}
+	if _, err = AddWorktree(repoDir, "release/2.0"); err == nil {
+		t.Error("expected unknown branch to be rejected")
+	}
+
+	w, err := AddWorktree(repoDir, "release/1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = AddWorktree(repoDir, "release/1.0"); err == nil {
+		t.Error("expected branch checked out in another worktree to be rejected")
+	}
+
+	if hash, errCommit := w.Commit(SyncMessage); errCommit != nil || !hash.IsZero() {
+		t.Errorf("expected nothing to commit, got %s, %v", hash, errCommit)
+	}
+
+	if err = os.WriteFile(filepath.Join(w.Dir, "README.md"), []byte("hotfix"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := w.Commit(SyncMessage)
+	if err != nil || hash.IsZero() {
+		t.Fatalf("expected a commit, got %s, %v", hash, err)
+	}
+
+	if err = w.Remove(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(w.Dir); !os.IsNotExist(err) {
+		t.Errorf("expected worktree %s removed", w.Dir)
+	}
+
+	branch, err := repo.Reference(plumbing.NewBranchReferenceName("release/1.0"), true)
+	if err != nil || branch.Hash() != hash {
+		t.Errorf("expected branch at %s, got %v, %v", hash, branch, err)
+	}
+	if current, _ := repo.Head(); current.Hash() != head.Hash() {
+		t.Errorf("expected current branch untouched, got %s", current.Hash())
+	}
+	if data, _ := os.ReadFile(filepath.Join(repoDir, "README.md")); string(data) != "test" {
+		t.Errorf("expected current checkout untouched, got %q", data)
+	}
+
+	commit, err := repo.CommitObject(hash)
+	if err != nil || commit.Author.Name != Author || commit.Message != SyncMessage {
+		t.Errorf("unexpected commit %v, %v", commit, err)
+	}
+
+	// The branch can be checked out again once the worktree is removed.
+	w, err = AddWorktree(repoDir, "release/1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(w.Dir, "README.md")); string(data) != "hotfix" {
+		t.Errorf("expected branch content in worktree, got %q", data)
+	}
+	if err = w.Remove(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,357 @@
+// Package strictyaml decodes component files rejecting unknown fields, with positioned diagnostics.
+// Regular loading is lenient and skips malformed files, strict mode is used by lint and --strict runs.
+package strictyaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+var (
+	lineRe         = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+	unknownFieldRe = regexp.MustCompile(`^field (\S+) not found in type`)
+)
+
+// Error is a YAML error at the exact position of a file.
+// Column is 0 when the parser doesn't report it.
+type Error struct {
+	File    string `json:"file"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Error implements error interface.
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %s", e.File, e.Message)
+	}
+	if e.Column > 0 {
+		return fmt.Sprintf("%s:%d:%d: %s", e.File, e.Line, e.Column, e.Message)
+	}
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+// Errors returns positioned errors joined in err.
+func Errors(err error) []*Error {
+	var result []*Error
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			result = append(result, Errors(e)...)
+		}
+		return result
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		result = append(result, e)
+	}
+
+	return result
+}
+
+// Meta is the schema of component meta/plasma.yaml.
+type Meta struct {
+	Plasma struct {
+		Version     string   `yaml:"version"`
+		Description string   `yaml:"description"`
+		Owners      []string `yaml:"owners"`
+	} `yaml:"plasma"`
+}
+
+// Dependencies is the schema of tasks/dependencies.yaml declared as a list of dependencies, names or names mapped
+// to version constraints.
+type Dependencies struct {
+	Dependencies []sync.Dependency `yaml:"dependencies"`
+}
+
+// Play is the schema of a layer playbook play.
+type Play struct {
+	Hosts          string      `yaml:"hosts"`
+	Serial         int         `yaml:"serial"`
+	AnyErrorsFatal bool        `yaml:"any_errors_fatal"`
+	Roles          []yaml.Node `yaml:"roles"`
+	Tags           []string    `yaml:"tags"`
+}
+
+// Unmarshal decodes data of the file into v, rejecting unknown fields.
+// All type errors are returned, joined, as [*Error].
+func Unmarshal(file string, data []byte, v any) error {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return positioned(file, nil, err.Error())
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	err := dec.Decode(v)
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return positioned(file, &root, err.Error())
+	}
+
+	errs := make([]error, 0, len(typeErr.Errors))
+	for _, msg := range typeErr.Errors {
+		errs = append(errs, positioned(file, &root, msg))
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckMeta strictly validates component meta file.
+func CheckMeta(path string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var meta Meta
+	return Unmarshal(path, data, &meta)
+}
+
+// CheckDependencies strictly validates component dependencies file.
+// Dependencies are either declared as a list under dependencies key, or as a list of tasks.
+func CheckDependencies(path string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var root yaml.Node
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return positioned(path, nil, err.Error())
+	}
+
+	if len(root.Content) > 0 && root.Content[0].Kind == yaml.SequenceNode {
+		var tasks []map[string]any
+		return Unmarshal(path, data, &tasks)
+	}
+
+	var deps Dependencies
+	return Unmarshal(path, data, &deps)
+}
+
+// CheckPlaybook strictly validates layer playbook.
+func CheckPlaybook(path string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var plays []Play
+	if err = Unmarshal(path, data, &plays); err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, play := range plays {
+		for i := range play.Roles {
+			errs = append(errs, checkRole(path, &play.Roles[i]))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkRole validates role declared either as a name or as a map with role and vars.
+func checkRole(path string, node *yaml.Node) error {
+	at := func(n *yaml.Node, format string, a ...any) error {
+		return &Error{File: path, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, a...)}
+	}
+
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Value == "" {
+			return at(node, "empty role name")
+		}
+		return nil
+	case yaml.MappingNode:
+		var errs []error
+		hasRole := false
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			switch key.Value {
+			case "role":
+				hasRole = value.Kind == yaml.ScalarNode && value.Value != ""
+				if !hasRole {
+					errs = append(errs, at(value, "role name must be a non-empty string"))
+				}
+			case "vars":
+				if value.Kind != yaml.MappingNode {
+					errs = append(errs, at(value, "role vars must be a map"))
+				}
+			default:
+				errs = append(errs, at(key, "field %s not allowed in role", key.Value))
+			}
+		}
+		if !hasRole && len(errs) == 0 {
+			errs = append(errs, at(node, "role name is missing"))
+		}
+		return errors.Join(errs...)
+	default:
+		return at(node, "role must be a name or a map with role and vars")
+	}
+}
+
+// DeprecatedModules are modules reported in tasks when no list is configured.
+var DeprecatedModules = []string{"include"}
+
+// mrnRe matches component name in layer.kind.name format.
+var mrnRe = regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+$`)
+
+// CheckTasks validates a component tasks file is a list of task maps, roles included by its tasks are named by MRN
+// and exist in components, and tasks don't use deprecated modules. Existence isn't checked for nil components.
+// Dependencies declared as a list under dependencies key are validated by [CheckDependencies].
+func CheckTasks(path string, components map[string]bool, deprecated []string) error {
+	data, err := os.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+
+	var root yaml.Node
+	if err = yaml.Unmarshal(data, &root); err != nil {
+		return positioned(path, nil, err.Error())
+	}
+
+	if len(root.Content) == 0 {
+		return nil
+	}
+
+	doc := root.Content[0]
+	if doc.Kind == yaml.ScalarNode && doc.Tag == "!!null" {
+		// Empty tasks file, e.g. a lone document marker.
+		return nil
+	}
+	if doc.Kind == yaml.MappingNode && filepath.Base(path) == "dependencies.yaml" {
+		return nil
+	}
+	if doc.Kind != yaml.SequenceNode {
+		return &Error{File: path, Line: doc.Line, Column: doc.Column, Message: "tasks file must be a list of tasks"}
+	}
+
+	t := &tasksCheck{path: path, components: components, deprecated: deprecated}
+	t.list(doc)
+
+	return errors.Join(t.errs...)
+}
+
+type tasksCheck struct {
+	path       string
+	components map[string]bool
+	deprecated []string
+	errs       []error
+}
+
+func (t *tasksCheck) at(n *yaml.Node, format string, a ...any) {
+	t.errs = append(t.errs, &Error{File: t.path, Line: n.Line, Column: n.Column, Message: fmt.Sprintf(format, a...)})
+}
+
+func (t *tasksCheck) list(node *yaml.Node) {
+	for _, task := range node.Content {
+		if task.Kind != yaml.MappingNode {
+			t.at(task, "task must be a map")
+			continue
+		}
+
+		for i := 0; i+1 < len(task.Content); i += 2 {
+			key, value := task.Content[i], task.Content[i+1]
+			module := strings.TrimPrefix(strings.TrimPrefix(key.Value, "ansible.builtin."), "ansible.legacy.")
+			switch {
+			case module == "block" || module == "rescue" || module == "always":
+				if value.Kind != yaml.SequenceNode {
+					t.at(value, "%s must be a list of tasks", key.Value)
+					continue
+				}
+				t.list(value)
+			case module == "include_role" || module == "import_role":
+				t.role(key, value)
+			case slices.Contains(t.deprecated, key.Value) || slices.Contains(t.deprecated, module):
+				t.at(key, "module %s is deprecated", key.Value)
+			}
+		}
+	}
+}
+
+// role validates name of the role included by the task.
+func (t *tasksCheck) role(key, value *yaml.Node) {
+	if value.Kind != yaml.MappingNode {
+		t.at(value, "%s must be a map with name", key.Value)
+		return
+	}
+
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		if value.Content[i].Value != "name" {
+			continue
+		}
+
+		name := value.Content[i+1]
+		switch {
+		case name.Kind != yaml.ScalarNode || name.Value == "":
+			t.at(name, "%s name must be a non-empty string", key.Value)
+		case strings.Contains(name.Value, "{{"):
+			// Templated names are resolved at runtime.
+		case !mrnRe.MatchString(name.Value):
+			t.at(name, "role %s doesn't match MRN format layer.kind.name", name.Value)
+		case t.components != nil && !t.components[name.Value]:
+			t.at(name, "role %s is not an existing component", name.Value)
+		}
+		return
+	}
+
+	t.at(value, "%s name is missing", key.Value)
+}
+
+// positioned converts yaml error message to [*Error], resolving the column from the parsed document.
+func positioned(file string, root *yaml.Node, msg string) *Error {
+	m := lineRe.FindStringSubmatch(msg)
+	if m == nil {
+		return &Error{File: file, Message: msg}
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	e := &Error{File: file, Line: line, Message: m[2]}
+	if root != nil {
+		key := ""
+		if f := unknownFieldRe.FindStringSubmatch(e.Message); f != nil {
+			key = f[1]
+		}
+		e.Column = column(root, line, key)
+	}
+
+	return e
+}
+
+// column returns the column of the first node on the line, or of the mapping key if given.
+func column(n *yaml.Node, line int, key string) int {
+	if n.Line == line && n.Kind == yaml.ScalarNode && (key == "" || n.Value == key) {
+		return n.Column
+	}
+
+	for _, child := range n.Content {
+		if c := column(child, line, key); c > 0 {
+			return c
+		}
+	}
+
+	if n.Line == line && key == "" {
+		return n.Column
+	}
+
+	return 0
+}
@@ -0,0 +1,130 @@
+package strictyaml
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "file.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckMetaUnknownField(t *testing.T) {
+	path := writeFile(t, "plasma:\n  version: abc\n  verison: def\n")
+
+	errs := Errors(CheckMeta(path))
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %v", errs)
+	}
+	if errs[0].Line != 3 || errs[0].Column != 3 {
+		t.Errorf("expected error at 3:3, got %d:%d (%s)", errs[0].Line, errs[0].Column, errs[0].Message)
+	}
+}
+
+func TestCheckMetaSyntaxError(t *testing.T) {
+	path := writeFile(t, "plasma:\n  version: abc\n owners: [\n")
+
+	errs := Errors(CheckMeta(path))
+	if len(errs) != 1 || errs[0].Line == 0 {
+		t.Fatalf("expected positioned syntax error, got %v", errs)
+	}
+}
+
+func TestCheckDependenciesFormats(t *testing.T) {
+	if err := CheckDependencies(writeFile(t, "dependencies:\n  - foundation.services.postgres\n")); err != nil {
+		t.Errorf("list format: %v", err)
+	}
+	if err := CheckDependencies(writeFile(t, "- include_role:\n    name: foundation.services.postgres\n")); err != nil {
+		t.Errorf("tasks format: %v", err)
+	}
+	if err := CheckDependencies(writeFile(t, "dependencies: foundation.services.postgres\n")); err == nil {
+		t.Error("expected error for scalar dependencies")
+	}
+	if err := CheckDependencies(writeFile(t, "dependencies:\n  - foundation.services.postgres: \">=1.2.0, <2.0.0\"\n")); err != nil {
+		t.Errorf("constrained dependency: %v", err)
+	}
+	errs := Errors(CheckDependencies(writeFile(t, "dependencies:\n  - foundation.applications.auth\n  - foundation.services.postgres: \">=\"\n")))
+	if len(errs) != 1 || errs[0].Line != 3 {
+		t.Errorf("expected invalid constraint error at line 3, got %v", errs)
+	}
+}
+
+func TestCheckPlaybookRoles(t *testing.T) {
+	path := writeFile(t, `- hosts: platform.interaction.observability
+  roles:
+    - interaction.applications.dashboards
+    - role: foundation.applications.auth
+      vars: {port: 80}
+    - name: foundation.services.postgres
+  become: true
+`)
+
+	errs := Errors(CheckPlaybook(path))
+	if len(errs) != 1 {
+		t.Fatalf("expected unknown play field error only, got %v", errs)
+	}
+	if errs[0].Line != 7 || errs[0].Column != 3 {
+		t.Errorf("expected error at 7:3, got %s", errs[0])
+	}
+
+	// Role errors are only checked once the plays decode.
+	path = writeFile(t, "- hosts: platform\n  roles:\n    - name: foundation.services.postgres\n")
+	errs = Errors(CheckPlaybook(path))
+	if len(errs) != 1 || errs[0].Line != 3 || errs[0].Column != 7 {
+		t.Fatalf("expected role field error at 3:7, got %v", errs)
+	}
+}
+
+func TestCheckTasks(t *testing.T) {
+	components := map[string]bool{"foundation.services.postgres": true}
+	path := writeFile(t, `- name: Include postgres
+  include_role:
+    name: foundation.services.postgres
+- block:
+    - ansible.builtin.include_role:
+        name: foundation.services.redis
+    - include: other.yaml
+- import_role:
+    name: postgres
+- include_role:
+    name: "{{ dependency }}"
+- debug msg=hello
+`)
+
+	errs := Errors(CheckTasks(path, components, DeprecatedModules))
+	expected := []string{
+		"6:15: role foundation.services.redis is not an existing component",
+		"7:7: module include is deprecated",
+		"9:11: role postgres doesn't match MRN format layer.kind.name",
+		"12:3: task must be a map",
+	}
+	if len(errs) != len(expected) {
+		t.Fatalf("expected %d errors, got %v", len(expected), errs)
+	}
+	for i, e := range errs {
+		if got := fmt.Sprintf("%d:%d: %s", e.Line, e.Column, e.Message); got != expected[i] {
+			t.Errorf("expected %q, got %q", expected[i], got)
+		}
+	}
+
+	deps := filepath.Join(t.TempDir(), "dependencies.yaml")
+	if err := os.WriteFile(deps, []byte("dependencies:\n  - foundation.services.postgres\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckTasks(deps, components, nil); err != nil {
+		t.Errorf("dependencies list format: %v", err)
+	}
+	if errs = Errors(CheckTasks(writeFile(t, "name: not a list\n"), components, nil)); len(errs) != 1 {
+		t.Errorf("expected error for tasks map, got %v", errs)
+	}
+	if err := CheckTasks(writeFile(t, "---\n"), components, nil); err != nil {
+		t.Errorf("expected empty tasks file accepted, got %v", err)
+	}
+}
@@ -0,0 +1,138 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AncestorFunc reports whether the commit of a version is an ancestor of the commit of another one, to order
+// versions which are commit hashes.
+type AncestorFunc func(version, other string) (bool, error)
+
+// VersionConstraint restricts versions of a dependency with comparisons separated by commas, all of them must be
+// satisfied, e.g. `>=abc1234` or `>=1.2.0, <2.0.0`. Operators are =, !=, >, >=, < and <=, a version without
+// operator must be equal. Semantic versions are ordered by precedence, commit hashes by history.
+type VersionConstraint string
+
+// constraintOperators are checked in order, longer operators first.
+var constraintOperators = []string{">=", "<=", "!=", ">", "<", "="}
+
+type comparison struct {
+	op      string
+	version string
+}
+
+// Validate checks comparisons of the constraint have a version.
+func (c VersionConstraint) Validate() error {
+	_, err := c.comparisons()
+	return err
+}
+
+func (c VersionConstraint) comparisons() ([]comparison, error) {
+	var result []comparison
+	for _, part := range strings.Split(string(c), ",") {
+		part = strings.TrimSpace(part)
+		op := "="
+		for _, o := range constraintOperators {
+			if rest, ok := strings.CutPrefix(part, o); ok {
+				op, part = o, strings.TrimSpace(rest)
+				break
+			}
+		}
+
+		if part == "" || strings.ContainsAny(part, "<>=! \t") {
+			return nil, fmt.Errorf("invalid version constraint %q (expected comparisons like >=1.2.0 separated by commas)", string(c))
+		}
+		result = append(result, comparison{op: op, version: part})
+	}
+
+	return result, nil
+}
+
+// Satisfied reports whether the version satisfies all comparisons of the constraint. Hashes are equal when one
+// prefixes the other, and ordered with the ancestor function. Semantic versions and hashes can't be ordered, and an
+// empty version satisfies no constraint.
+func (c VersionConstraint) Satisfied(version string, ancestor AncestorFunc) (bool, error) {
+	comparisons, err := c.comparisons()
+	if err != nil {
+		return false, err
+	}
+	if version == "" {
+		return false, fmt.Errorf("empty version doesn't satisfy constraint %q", string(c))
+	}
+
+	for _, cmp := range comparisons {
+		ok, errCmp := cmp.satisfied(version, ancestor)
+		if errCmp != nil || !ok {
+			return false, errCmp
+		}
+	}
+
+	return true, nil
+}
+
+func (cmp comparison) satisfied(version string, ancestor AncestorFunc) (bool, error) {
+	same := sameVersion(version, cmp.version)
+	switch cmp.op {
+	case "=":
+		return same, nil
+	case "!=":
+		return !same, nil
+	}
+
+	if same {
+		return cmp.op == ">=" || cmp.op == "<=", nil
+	}
+
+	before, err := precedes(version, cmp.version, ancestor)
+	if err != nil {
+		return false, err
+	}
+	after, err := precedes(cmp.version, version, ancestor)
+	if err != nil {
+		return false, err
+	}
+
+	if cmp.op == ">" || cmp.op == ">=" {
+		return after, nil
+	}
+	return before, nil
+}
+
+// sameVersion tells semantic versions are equal, with or without their `v` prefix, or hashes prefix each other.
+// Empty versions are never the same.
+func sameVersion(version, other string) bool {
+	if version == "" || other == "" {
+		return false
+	}
+
+	a, aok := parseSemver(strings.TrimPrefix(version, "v"))
+	b, bok := parseSemver(strings.TrimPrefix(other, "v"))
+	if aok || bok {
+		return aok && bok && a == b
+	}
+
+	return strings.HasPrefix(version, other) || strings.HasPrefix(other, version)
+}
+
+// precedes tells the version is lower than the other one, different versions on diverged histories don't precede
+// each other.
+func precedes(version, other string, ancestor AncestorFunc) (bool, error) {
+	a, aok := parseSemver(strings.TrimPrefix(version, "v"))
+	b, bok := parseSemver(strings.TrimPrefix(other, "v"))
+	switch {
+	case aok && bok:
+		for i := range a {
+			if a[i] != b[i] {
+				return a[i] < b[i], nil
+			}
+		}
+		return false, nil
+	case aok || bok:
+		return false, fmt.Errorf("can't order semantic version and hash %s, %s", version, other)
+	case ancestor == nil:
+		return false, fmt.Errorf("can't order hashes %s, %s without history", version, other)
+	}
+
+	return ancestor(version, other)
+}
@@ -0,0 +1,89 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVersionConstraint(t *testing.T) {
+	// History of hashes: aaa → bbb → ccc, ddd diverged from aaa.
+	history := map[string][]string{"aaa": {}, "bbb": {"aaa"}, "ccc": {"bbb", "aaa"}, "ddd": {"aaa"}}
+	ancestor := func(version, other string) (bool, error) {
+		for _, h := range history[other[:3]] {
+			if strings.HasPrefix(version, h) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	tests := []struct {
+		constraint VersionConstraint
+		version    string
+		satisfied  bool
+	}{
+		{"1.2.0", "1.2.0", true},
+		{"=v1.2.0", "1.2.0", true},
+		{"!=1.2.0", "1.2.1", true},
+		{">=1.2.0, <2.0.0", "1.10.0", true},
+		{">=1.2.0, <2.0.0", "2.0.0", false},
+		{">1.2.0", "1.2.0", false},
+		{"<=1.2.0", "1.1.9", true},
+		{"bbb", "bbb1234567890", true},
+		{">=bbb", "bbb1234567890", true},
+		{">=bbb", "ccc1234567890", true},
+		{">bbb", "aaa1234567890", false},
+		{"<bbb", "aaa1234567890", true},
+		{">=bbb", "ddd1234567890", false},
+		{"<=bbb", "ddd1234567890", false},
+		{"!=bbb", "ddd1234567890", true},
+	}
+
+	for _, tt := range tests {
+		satisfied, err := tt.constraint.Satisfied(tt.version, ancestor)
+		if err != nil {
+			t.Errorf("%q with %s: unexpected error %v", tt.constraint, tt.version, err)
+			continue
+		}
+		if satisfied != tt.satisfied {
+			t.Errorf("%q with %s: expected %t, got %t", tt.constraint, tt.version, tt.satisfied, satisfied)
+		}
+	}
+
+	if _, err := VersionConstraint(">=1.2.0").Satisfied("bbb1234567890", ancestor); err == nil {
+		t.Error("expected error ordering a hash and a semantic version")
+	}
+	if _, err := VersionConstraint(">=bbb").Satisfied("ccc1234567890", nil); err == nil {
+		t.Error("expected error ordering hashes without history")
+	}
+	for _, c := range []VersionConstraint{"bbb", ">=bbb", "<=bbb", "!=bbb"} {
+		if ok, err := c.Satisfied("", ancestor); ok || err == nil {
+			t.Errorf("%q: expected empty version to fail, got %t, %v", c, ok, err)
+		}
+	}
+	if sameVersion("", "") || sameVersion("bbb", "") {
+		t.Error("expected empty versions to never be the same")
+	}
+	for _, c := range []VersionConstraint{"", ">=", "1.2.0,", ">= 1.2.0 2.0.0", "=>1.2.0"} {
+		if err := c.Validate(); err == nil {
+			t.Errorf("%q: expected invalid constraint", c)
+		}
+	}
+}
+
+func TestParseDependencies(t *testing.T) {
+	deps, err := ParseDependencies([]byte("dependencies:\n  - foundation.applications.auth\n  - foundation.services.postgres: \">=1.2.0\"\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deps) != 2 || deps[0].Constraint != "" || deps[1].Name != "foundation.services.postgres" || deps[1].Constraint != ">=1.2.0" || deps[1].Line != 3 {
+		t.Errorf("unexpected dependencies %+v", deps)
+	}
+
+	if deps, err = ParseDependencies([]byte("- foundation.applications.auth\n")); err != nil || len(deps) != 1 {
+		t.Errorf("expected plain list to parse, got %+v, %v", deps, err)
+	}
+	if _, err = ParseDependencies([]byte("dependencies:\n  - foundation.services.postgres: \"<\"\n")); err == nil {
+		t.Error("expected invalid constraint to fail")
+	}
+}
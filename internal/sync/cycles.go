@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Declaration is where a dependency is declared, a tasks file and the line of the included role name.
+type Declaration struct {
+	File string `json:"file"`
+	Line int    `json:"line,omitempty"`
+}
+
+// CycleEdge is a dependency of a cycle, with its declaration.
+type CycleEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Build tells the dependency is declared by a build tasks file, not by dependencies.yaml.
+	Build bool `json:"build,omitempty"`
+	Declaration
+}
+
+// Cycle is a chain of dependencies leading back to its first component.
+type Cycle []CycleEdge
+
+// String returns the chain of components of the cycle, e.g. a → b → a.
+func (c Cycle) String() string {
+	if len(c) == 0 {
+		return ""
+	}
+
+	names := make([]string, 0, len(c)+1)
+	for _, e := range c {
+		names = append(names, e.From)
+	}
+	return strings.Join(append(names, c[0].From), " → ")
+}
+
+// CycleError is returned by inventory init when components depend on each other in a cycle.
+type CycleError struct {
+	Cycles []Cycle
+}
+
+// Error implements error interface.
+func (e *CycleError) Error() string {
+	chains := make([]string, 0, len(e.Cycles))
+	for _, c := range e.Cycles {
+		chains = append(chains, c.String())
+	}
+	return fmt.Sprintf("dependency cycle(s) found, check them with component:depend --check-cycles: %s", strings.Join(chains, "; "))
+}
+
+// maxCycles limits the number of reported cycles, components of a large cycle being part of many.
+const maxCycles = 100
+
+// FindCycles returns cycles of semantic dependencies, including build dependencies if build is set.
+// Each cycle starts from its first component in alphabetical order, cycles are sorted by it.
+func (i *Inventory) FindCycles(build bool) []Cycle {
+	edges := make(map[string][]CycleEdge)
+	addEdges := func(requires map[string]*OrderedMap[bool], declared map[string]map[string]Declaration, isBuild bool) {
+		for from, deps := range requires {
+			for _, to := range deps.Keys() {
+				if hasEdge(edges[from], to) {
+					continue
+				}
+				edges[from] = append(edges[from], CycleEdge{From: from, To: to, Build: isBuild, Declaration: declared[from][to]})
+			}
+		}
+	}
+	addEdges(i.requires, i.requiresAt, false)
+	if build {
+		addEdges(i.buildRequires, i.buildRequiresAt, true)
+	}
+
+	names := make([]string, 0, len(edges))
+	for name := range edges {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// Cycles are searched within strongly connected components only, the rest of the graph can't lead back.
+	scc := stronglyConnected(names, edges)
+
+	var cycles []Cycle
+	for _, start := range names {
+		var path []CycleEdge
+		onPath := map[string]bool{start: true}
+		var walk func(name string)
+		walk = func(name string) {
+			for _, e := range edges[name] {
+				switch {
+				case len(cycles) >= maxCycles:
+					return
+				case e.To == start:
+					cycles = append(cycles, append(append(Cycle{}, path...), e))
+				case e.To > start && scc[e.To] == scc[start] && !onPath[e.To]:
+					onPath[e.To] = true
+					path = append(path, e)
+					walk(e.To)
+					path = path[:len(path)-1]
+					onPath[e.To] = false
+				}
+			}
+		}
+		walk(start)
+	}
+
+	return cycles
+}
+
+// stronglyConnected returns the index of the strongly connected component of each node, with Tarjan's algorithm.
+func stronglyConnected(names []string, edges map[string][]CycleEdge) map[string]int {
+	index := make(map[string]int)
+	low := make(map[string]int)
+	onStack := make(map[string]bool)
+	scc := make(map[string]int)
+	var stack []string
+	count := 0
+
+	var connect func(name string)
+	connect = func(name string) {
+		index[name] = len(index)
+		low[name] = index[name]
+		stack = append(stack, name)
+		onStack[name] = true
+
+		for _, e := range edges[name] {
+			if _, visited := index[e.To]; !visited {
+				connect(e.To)
+				low[name] = min(low[name], low[e.To])
+			} else if onStack[e.To] {
+				low[name] = min(low[name], index[e.To])
+			}
+		}
+
+		if low[name] == index[name] {
+			for {
+				top := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[top] = false
+				scc[top] = count
+				if top == name {
+					break
+				}
+			}
+			count++
+		}
+	}
+
+	for _, name := range names {
+		if _, visited := index[name]; !visited {
+			connect(name)
+		}
+	}
+
+	return scc
+}
+
+func hasEdge(edges []CycleEdge, to string) bool {
+	for _, e := range edges {
+		if e.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// includeRoleLines returns lines of role names included by the tasks, by role name.
+func includeRoleLines(doc *yaml.Node) map[string]int {
+	lines := make(map[string]int)
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.SequenceNode {
+		return lines
+	}
+
+	for _, task := range doc.Content[0].Content {
+		role := mappingValue(task, "include_role")
+		if role == nil {
+			continue
+		}
+		if name := mappingValue(role, "name"); name != nil {
+			if _, ok := lines[name.Value]; !ok {
+				lines[name.Value] = name.Line
+			}
+		}
+	}
+
+	return lines
+}
+
+// mappingValue returns the value of the key of a mapping node, nil if missing.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for j := 0; j+1 < len(node.Content); j += 2 {
+		if node.Content[j].Value == key {
+			return node.Content[j+1]
+		}
+	}
+
+	return nil
+}
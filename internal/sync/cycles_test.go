@@ -0,0 +1,52 @@
+package sync
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/launchrctl/launchr"
+)
+
+func TestFindCycles(t *testing.T) {
+	meta := &fstest.MapFile{Data: []byte("plasma:\n  version: \"aaa1111111111\"\n")}
+	deps := func(names ...string) *fstest.MapFile {
+		data := "---\n"
+		for _, n := range names {
+			data += "- include_role:\n    name: " + n + "\n"
+		}
+		return &fstest.MapFile{Data: []byte(data)}
+	}
+	fsys := fstest.MapFS{
+		"foundation/services/postgres/meta/plasma.yaml":               meta,
+		"foundation/services/postgres/tasks/dependencies.yaml":        deps("foundation.applications.auth"),
+		"foundation/applications/auth/meta/plasma.yaml":               meta,
+		"foundation/applications/auth/tasks/dependencies.yaml":        deps("foundation.services.redis", "foundation.services.postgres"),
+		"foundation/services/redis/meta/plasma.yaml":                  meta,
+		"foundation/services/redis/tasks/main.yaml":                   deps("foundation.services.postgres"),
+		"interaction/applications/dashboards/meta/plasma.yaml":        meta,
+		"interaction/applications/dashboards/tasks/dependencies.yaml": deps("foundation.applications.auth"),
+	}
+
+	inv, err := NewInventoryFS(fsys, "build", launchr.Log())
+	var cycleErr *CycleError
+	if !errors.As(err, &cycleErr) {
+		t.Fatalf("expected cycle error, got %v", err)
+	}
+
+	cycles := inv.FindCycles(false)
+	if len(cycles) != 1 {
+		t.Fatalf("expected one cycle, got %v", cycles)
+	}
+	if chain := cycles[0].String(); chain != "foundation.applications.auth → foundation.services.postgres → foundation.applications.auth" {
+		t.Errorf("unexpected chain %s", chain)
+	}
+	if e := cycles[0][0]; e.File != "build/foundation/applications/auth/tasks/dependencies.yaml" || e.Line != 5 {
+		t.Errorf("expected auth dependency declared at line 5 of its dependencies, got %s:%d", e.File, e.Line)
+	}
+
+	cycles = inv.FindCycles(true)
+	if len(cycles) != 2 {
+		t.Fatalf("expected build cycle as well, got %v", cycles)
+	}
+}
@@ -0,0 +1,67 @@
+package sync
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Dependency is an entry of a dependencies list of tasks/dependencies.yaml, a component name, or a mapping of a
+// component name to the version constraint of the dependency:
+//
+//	dependencies:
+//	  - foundation.applications.auth
+//	  - foundation.services.postgres: ">=1.2.0, <2.0.0"
+type Dependency struct {
+	Name       string
+	Constraint VersionConstraint
+	// Line is the line of the entry in the file it was read from.
+	Line int
+}
+
+// DependenciesFile is the content of tasks/dependencies.yaml declaring dependencies as a list.
+type DependenciesFile struct {
+	Dependencies []Dependency `yaml:"dependencies"`
+}
+
+// UnmarshalYAML implements [yaml.Unmarshaler].
+func (d *Dependency) UnmarshalYAML(value *yaml.Node) error {
+	switch {
+	case value.Kind == yaml.ScalarNode:
+		d.Name = value.Value
+	case value.Kind == yaml.MappingNode && len(value.Content) == 2 && value.Content[1].Kind == yaml.ScalarNode:
+		d.Name = value.Content[0].Value
+		d.Constraint = VersionConstraint(value.Content[1].Value)
+		if err := d.Constraint.Validate(); err != nil {
+			return fmt.Errorf("line %d: %w", value.Content[1].Line, err)
+		}
+	default:
+		return fmt.Errorf("line %d: dependency must be a component name or a component name mapped to its version constraint", value.Line)
+	}
+	d.Line = value.Line
+
+	return nil
+}
+
+// MarshalYAML implements [yaml.Marshaler], dependencies without constraint are written as names.
+func (d Dependency) MarshalYAML() (any, error) {
+	if d.Constraint == "" {
+		return d.Name, nil
+	}
+
+	return map[string]string{d.Name: string(d.Constraint)}, nil
+}
+
+// ParseDependencies parses dependencies declared as a list under dependencies key, or as a plain list.
+func ParseDependencies(data []byte) ([]Dependency, error) {
+	var content DependenciesFile
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		var deps []Dependency
+		if errList := yaml.Unmarshal(data, &deps); errList != nil {
+			return nil, fmt.Errorf("failed to parse dependencies: %w", err)
+		}
+		return deps, nil
+	}
+
+	return content.Dependencies, nil
+}
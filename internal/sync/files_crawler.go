@@ -1,6 +1,7 @@
 package sync
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,16 +11,30 @@ const roles = "roles"
 
 // FilesCrawler is a type that represents a crawler for components in a given directory.
 type FilesCrawler struct {
-	rootDir string
+	fsys fs.FS
 }
 
 // NewFilesCrawler creates a new instance of FilesCrawler with initialized taskSources and templateSources maps.
 func NewFilesCrawler(directory string) *FilesCrawler {
+	return NewFilesCrawlerFS(os.DirFS(directory))
+}
+
+// NewFilesCrawlerFS creates a new instance of FilesCrawler crawling the filesystem, e.g. in memory or a commit tree.
+func NewFilesCrawlerFS(fsys fs.FS) *FilesCrawler {
 	return &FilesCrawler{
-		rootDir: directory,
+		fsys: fsys,
 	}
 }
 
+// walkRoot returns the root of the platform to walk in the filesystem, the whole filesystem without platform.
+func walkRoot(platform string) string {
+	if platform == "" {
+		return "."
+	}
+
+	return platform
+}
+
 // FindVarsFiles return list of variables files in platform.
 // If platform is empty, search across all.
 func (cr *FilesCrawler) FindVarsFiles(platform string) (map[string][]string, error) {
@@ -29,22 +44,20 @@ func (cr *FilesCrawler) FindVarsFiles(platform string) (map[string][]string, err
 	kindPart := 1
 
 	files := make(map[string][]string)
-	dir := filepath.Join(cr.rootDir, platform)
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := fs.WalkDir(cr.fsys, walkRoot(platform), func(relPath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		relPath := strings.TrimPrefix(path, cr.rootDir+"/")
-		if info.Mode()&os.ModeSymlink != 0 {
+		if entry.Type()&fs.ModeSymlink != 0 {
 			return nil
 		}
 
-		if strings.Contains(path, "scripts") {
+		if strings.Contains(relPath, "scripts") {
 			return filepath.SkipDir
 		}
 
-		if info.IsDir() {
+		if entry.IsDir() {
 			return nil
 		}
 
@@ -53,7 +66,7 @@ func (cr *FilesCrawler) FindVarsFiles(platform string) (map[string][]string, err
 			(rolePart == 0 || parts[rolePart] == roles) {
 
 			if parts[kindPart] == "group_vars" {
-				filename := filepath.Base(path)
+				filename := filepath.Base(relPath)
 				if filename == "vars.yaml" || filename == vaultFile {
 					files[parts[platformPart]] = append(files[parts[platformPart]], relPath)
 				}
@@ -66,48 +79,49 @@ func (cr *FilesCrawler) FindVarsFiles(platform string) (map[string][]string, err
 	return files, err
 }
 
-// FindComponentsFiles return list of components files in platform.
+// FindComponentsFiles return list of components files in platform, Jinja templates and tasks configuration of
+// components in any of [Layouts].
 // If platform is empty, search across all.
 func (cr *FilesCrawler) FindComponentsFiles(platform string) (map[string][]string, error) {
-	partsCount := 4
-	platformPart := 0
-	rolePart := 2
-	kindPart := 4
-
 	files := make(map[string][]string)
-	dir := filepath.Join(cr.rootDir, platform)
-	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+	err := fs.WalkDir(cr.fsys, walkRoot(platform), func(relPath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		relPath := strings.TrimPrefix(path, cr.rootDir+"/")
-		if info.Mode()&os.ModeSymlink != 0 {
+		if entry.Type()&fs.ModeSymlink != 0 {
 			return nil
 		}
 
-		if strings.Contains(path, "scripts") {
+		if strings.Contains(relPath, "scripts") {
 			return filepath.SkipDir
 		}
 
-		if info.IsDir() {
+		if entry.IsDir() {
 			return nil
 		}
 
-		parts := strings.Split(relPath, "/")
-		if len(parts) > partsCount && (platform == "" || parts[platformPart] == platform) &&
-			(rolePart == 0 || parts[rolePart] == roles) {
+		for _, layout := range Layouts {
+			layer, kind, name, ok := layout.Parse(relPath)
+			if !ok || (platform != "" && layer != platform) {
+				continue
+			}
 
-			if parts[kindPart] == "templates" {
-				ext := filepath.Ext(path)
-				if ext != ".j2" {
-					return nil
-				}
-				files[parts[platformPart]] = append(files[parts[platformPart]], relPath)
+			// Path within the component, its directory is at least the file one.
+			inner := strings.Split(strings.TrimPrefix(relPath, filepath.ToSlash(layout.Dir(layer, kind, name))+"/"), "/")
+			if len(inner) < 2 {
+				continue
+			}
 
-			} else if parts[kindPart] == "tasks" && filepath.Base(path) == "configuration.yaml" {
-				files[parts[platformPart]] = append(files[parts[platformPart]], relPath)
+			switch {
+			case inner[0] == "templates" && filepath.Ext(relPath) == ".j2":
+				files[layer] = append(files[layer], relPath)
+			case inner[0] == "tasks" && filepath.Base(relPath) == "configuration.yaml":
+				files[layer] = append(files[layer], relPath)
+			default:
+				continue
 			}
+			break
 		}
 
 		return nil
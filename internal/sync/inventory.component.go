@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -17,6 +20,9 @@ var (
 	tplVersionSet = "failed to update component version (%s)"
 )
 
+// PropagationManual is the plasma.propagation value of components versioned by hand, skipped by bump and sync.
+const PropagationManual = "manual"
+
 // PrepareComponentName creates a dot-notation component name from parts.
 // Example: PrepareComponentName("foundation", "applications", "auth") -> "foundation.applications.auth"
 func PrepareComponentName(layer, kind, name string) string {
@@ -40,11 +46,24 @@ type Component struct {
 	platform   string
 	kind       string
 	role       string
+	layout     MetaLayout
+
+	// readFile reads component files from a source without checked out files, e.g. bare repository object store.
+	readFile func(path string) ([]byte, error)
+	// fsys is the filesystem of the source component files are read from, the path prefix on disk if nil.
+	fsys fs.FS
 }
 
 // NewComponent returns new [Component] instance.
 // Accepts dot notation: "foundation.applications.auth"
+// The component is looked up in the prefix with [Layouts], in [RootLayout] if it doesn't exist there.
 func NewComponent(name, prefix string) (*Component, error) {
+	return NewComponentFS(name, prefix, nil)
+}
+
+// NewComponentFS returns new [Component] instance of which files are read from the filesystem of the source,
+// e.g. in memory or a commit tree. Updates are still written on disk in the prefix.
+func NewComponentFS(name, prefix string, fsys fs.FS) (*Component, error) {
 	parts := strings.Split(name, ".")
 	if len(parts) != 3 {
 		return nil, fmt.Errorf("invalid component name %q (expected: layer.kind.name)", name)
@@ -56,6 +75,8 @@ func NewComponent(name, prefix string) (*Component, error) {
 		platform:   parts[0],
 		kind:       parts[1],
 		role:       parts[2],
+		layout:     resolveLayout(fsys, prefix, parts[0], parts[1], parts[2]),
+		fsys:       fsys,
 	}, nil
 }
 
@@ -81,10 +102,9 @@ func (c *Component) GetRole() string {
 
 // IsValidComponent checks if component has meta file.
 func (c *Component) IsValidComponent() bool {
-	metaPath := c.getRealMetaPath()
-	_, err := os.Stat(metaPath)
+	_, err := statSource(c.fsys, c.pathPrefix, c.BuildMetaPath())
 
-	return !os.IsNotExist(err)
+	return !errors.Is(err, fs.ErrNotExist)
 }
 
 func (c *Component) getRealMetaPath() string {
@@ -92,40 +112,162 @@ func (c *Component) getRealMetaPath() string {
 	return filepath.Join(c.pathPrefix, meta)
 }
 
-// BuildMetaPath returns common path to component meta.
+// MetaPath returns path to component meta prefixed with component directory.
+func (c *Component) MetaPath() string {
+	return c.getRealMetaPath()
+}
+
+// BuildMetaPath returns path to component meta relative to the component source, according to its layout.
 func (c *Component) BuildMetaPath() string {
-	parts := strings.Split(c.GetName(), ".")
-	meta := filepath.Join(parts[0], parts[1], parts[2], "meta", "plasma.yaml")
-	return meta
+	return filepath.Join(c.layout.Dir(c.platform, c.kind, c.role), metaFileName)
+}
+
+// Layout returns the layout of the component in its source.
+func (c *Component) Layout() MetaLayout {
+	return c.layout
 }
 
 // GetVersion retrieves the version of the component from the plasma.yaml
 func (c *Component) GetVersion() (string, []string, error) {
-	var debug []string
-	metaFile := c.getRealMetaPath()
-	if _, err := os.Stat(metaFile); err == nil {
-		data, errRead := os.ReadFile(filepath.Clean(metaFile))
-		if errRead != nil {
-			debug = append(debug, errRead.Error())
-			return "", debug, fmt.Errorf(tplVersionGet, metaFile)
-		}
+	meta, debug, err := c.readMeta()
+	if err != nil {
+		return "", debug, err
+	}
 
-		var meta map[string]any
-		errUnmarshal := yaml.Unmarshal(data, &meta)
-		if errUnmarshal != nil {
-			debug = append(debug, errUnmarshal.Error())
-			return "", debug, fmt.Errorf(tplVersionGet, metaFile)
+	version := GetMetaVersion(meta)
+	if version == "" {
+		debug = append(debug, fmt.Sprintf("Empty meta file %s version, return empty string as version", c.getRealMetaPath()))
+	}
+
+	return version, debug, nil
+}
+
+// GetVersioning retrieves the versioning scheme of the component from the plasma.yaml, empty if not set.
+func (c *Component) GetVersioning() (string, []string, error) {
+	meta, debug, err := c.readMeta()
+	if err != nil {
+		return "", debug, err
+	}
+
+	if plasma, ok := meta["plasma"].(map[string]any); ok {
+		if scheme, ok := plasma["versioning"].(string); ok {
+			return scheme, debug, nil
 		}
+	}
+
+	return "", debug, nil
+}
+
+// GetDeprecation tells if the component is deprecated with plasma.deprecated of the plasma.yaml, set to true
+// or to a message, e.g. the replacing component.
+func (c *Component) GetDeprecation() (bool, string, error) {
+	meta, _, err := c.readMeta()
+	if err != nil {
+		return false, "", err
+	}
+
+	plasma, ok := meta["plasma"].(map[string]any)
+	if !ok {
+		return false, "", nil
+	}
+
+	switch v := plasma["deprecated"].(type) {
+	case bool:
+		return v, "", nil
+	case string:
+		return v != "", v, nil
+	default:
+		return false, "", nil
+	}
+}
+
+// GetFrozen tells if the component is excluded from bump and sync, with plasma.frozen set to true, e.g. under release
+// freeze, or plasma.propagation set to manual, e.g. externally versioned. The reason names the matching meta field.
+func (c *Component) GetFrozen() (bool, string, error) {
+	meta, _, err := c.readMeta()
+	if err != nil {
+		return false, "", err
+	}
+
+	plasma, ok := meta["plasma"].(map[string]any)
+	if !ok {
+		return false, "", nil
+	}
+
+	if frozen, _ := plasma["frozen"].(bool); frozen {
+		return true, "frozen", nil
+	}
+	if propagation, _ := plasma["propagation"].(string); propagation == PropagationManual {
+		return true, "manual propagation", nil
+	}
+
+	return false, "", nil
+}
+
+// GetPropagationDisabled tells if the component opted out of propagated versions with plasma.propagation set to false:
+// sync and cascading bumps leave it untouched, while changes of its own files are still bumped.
+func (c *Component) GetPropagationDisabled() (bool, error) {
+	meta, _, err := c.readMeta()
+	if err != nil {
+		return false, err
+	}
+
+	plasma, ok := meta["plasma"].(map[string]any)
+	if !ok {
+		return false, nil
+	}
+
+	propagation, ok := plasma["propagation"].(bool)
+	return ok && !propagation, nil
+}
+
+// UpdateVersioning stores the versioning scheme of the component in the plasma.yaml file.
+func (c *Component) UpdateVersioning(scheme string) error {
+	metaFilepath := c.getRealMetaPath()
+	data, err := os.ReadFile(filepath.Clean(metaFilepath))
+	if err != nil {
+		return fmt.Errorf("failed to update component versioning (%s) > %w", metaFilepath, err)
+	}
+
+	data, err = SetMetaField(data, "versioning", scheme)
+	if err != nil {
+		return fmt.Errorf("failed to update component versioning (%s) > %w", metaFilepath, err)
+	}
+
+	return os.WriteFile(metaFilepath, data, 0600)
+}
+
+// readMeta reads and parses the plasma.yaml of the component.
+func (c *Component) readMeta() (map[string]any, []string, error) {
+	var debug []string
+	metaFile := c.getRealMetaPath()
 
-		version := GetMetaVersion(meta)
-		if version == "" {
-			debug = append(debug, fmt.Sprintf("Empty meta file %s version, return empty string as version", metaFile))
+	var data []byte
+	var errRead error
+	switch {
+	case c.readFile != nil:
+		data, errRead = c.readFile(c.BuildMetaPath())
+	case c.fsys != nil:
+		data, errRead = fs.ReadFile(c.fsys, filepath.ToSlash(c.BuildMetaPath()))
+	default:
+		if _, err := os.Stat(metaFile); err != nil {
+			return nil, debug, fmt.Errorf(tplVersionGet, metaFile)
 		}
+		data, errRead = os.ReadFile(filepath.Clean(metaFile))
+	}
+	if errRead != nil {
+		debug = append(debug, errRead.Error())
+		return nil, debug, fmt.Errorf(tplVersionGet, metaFile)
+	}
 
-		return version, debug, nil
+	var meta map[string]any
+	errUnmarshal := yaml.Unmarshal(data, &meta)
+	if errUnmarshal != nil {
+		debug = append(debug, errUnmarshal.Error())
+		return nil, debug, fmt.Errorf(tplVersionGet, metaFile)
 	}
 
-	return "", debug, fmt.Errorf(tplVersionGet, metaFile)
+	return meta, debug, nil
 }
 
 // GetMetaVersion searches for version in meta data.
@@ -146,8 +288,8 @@ func GetMetaVersion(meta map[string]any) string {
 	return ""
 }
 
-// GetBaseVersion returns component version without `-` if any.
-func (c *Component) GetBaseVersion() (string, string, []string, error) {
+// GetBaseVersion returns component version without propagated part of the format if any.
+func (c *Component) GetBaseVersion(format VersionFormat) (string, string, []string, error) {
 	var debug []string
 	version, debugMessages, err := c.GetVersion()
 	debug = append(debug, debugMessages...)
@@ -155,90 +297,184 @@ func (c *Component) GetBaseVersion() (string, string, []string, error) {
 		return "", "", debug, err
 	}
 
-	split := strings.Split(version, "-")
-	if len(split) > 2 {
-		debug = append(debug, fmt.Sprintf("Component %s has incorrect format %s", c.GetName(), version))
+	if _, reason := format.Normalize(version); reason != "" {
+		debug = append(debug, fmt.Sprintf("Component %s has incorrect format %s: version %s", c.GetName(), version, reason))
 	}
 
-	return split[0], version, debug, nil
+	base, _ := format.Split(version)
+	return base, version, debug, nil
 }
 
 // UpdateVersion updates the version of the component in the plasma.yaml file
 func (c *Component) UpdateVersion(version string) ([]string, error) {
+	metaFilepath := c.getRealMetaPath()
+	_, data, debug, err := c.RenderVersion(version)
+	if err != nil {
+		return debug, err
+	}
+
+	errWrite := os.WriteFile(metaFilepath, data, 0600)
+	if errWrite != nil {
+		debug = append(debug, errWrite.Error())
+		return debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	}
+
+	return debug, nil
+}
+
+// RenderVersion returns current and updated content of the plasma.yaml file with the version, without writing it.
+func (c *Component) RenderVersion(version string) ([]byte, []byte, []string, error) {
 	var debug []string
 	metaFilepath := c.getRealMetaPath()
-	if _, err := os.Stat(metaFilepath); err == nil {
-		data, errRead := os.ReadFile(filepath.Clean(metaFilepath))
-		if errRead != nil {
-			debug = append(debug, errRead.Error())
-			return debug, fmt.Errorf(tplVersionSet, metaFilepath)
-		}
+	if _, err := os.Stat(metaFilepath); err != nil {
+		return nil, nil, debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	}
 
-		var b bytes.Buffer
-		var meta map[string]any
-		errUnmarshal := yaml.Unmarshal(data, &meta)
-		if errUnmarshal != nil {
-			debug = append(debug, errUnmarshal.Error())
-			return debug, fmt.Errorf(tplVersionSet, metaFilepath)
-		}
+	data, errRead := os.ReadFile(filepath.Clean(metaFilepath))
+	if errRead != nil {
+		debug = append(debug, errRead.Error())
+		return nil, nil, debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	}
+
+	var b bytes.Buffer
+	var meta map[string]any
+	errUnmarshal := yaml.Unmarshal(data, &meta)
+	if errUnmarshal != nil {
+		debug = append(debug, errUnmarshal.Error())
+		return nil, nil, debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	}
+
+	if plasma, ok := meta["plasma"].(map[string]any); ok {
+		plasma["version"] = version
+	} else {
+		meta["plasma"] = map[string]any{"version": version}
+	}
+
+	yamlEncoder := yaml.NewEncoder(&b)
+	yamlEncoder.SetIndent(2)
+	errEncode := yamlEncoder.Encode(&meta)
+	if errEncode != nil {
+		debug = append(debug, errEncode.Error())
+		return nil, nil, debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	}
+
+	return data, b.Bytes(), debug, nil
+}
 
-		if plasma, ok := meta["plasma"].(map[string]any); ok {
-			plasma["version"] = version
-		} else {
-			meta["plasma"] = map[string]any{"version": version}
+// NewComponentsMapFromFiles builds components map from file paths of a source without checked out files,
+// e.g. HEAD tree of a bare repository. Component files are read with readFile, paths are relative to the source.
+func NewComponentsMapFromFiles(paths []string, pathPrefix string, readFile func(path string) ([]byte, error)) *OrderedMap[*Component] {
+	cm := NewOrderedMap[*Component]()
+	for _, path := range paths {
+		excluded := false
+		for _, d := range InventoryExcluded {
+			if strings.Contains(path, d) {
+				excluded = true
+				break
+			}
 		}
 
-		yamlEncoder := yaml.NewEncoder(&b)
-		yamlEncoder.SetIndent(2)
-		errEncode := yamlEncoder.Encode(&meta)
-		if errEncode != nil {
-			debug = append(debug, errEncode.Error())
-			return debug, fmt.Errorf(tplVersionSet, metaFilepath)
+		if excluded || strings.ToLower(filepath.Base(path)) != "plasma.yaml" || !strings.HasSuffix(filepath.Dir(path), "/meta") {
+			continue
 		}
 
-		errWrite := os.WriteFile(metaFilepath, b.Bytes(), 0600)
-		if errWrite != nil {
-			debug = append(debug, errWrite.Error())
-			return debug, fmt.Errorf(tplVersionSet, metaFilepath)
+		// Meta file must be at the component root, files can't be checked on the filesystem.
+		var component *Component
+		for _, layout := range Layouts {
+			platform, kind, role, ok := layout.Parse(path)
+			if !ok {
+				continue
+			}
+
+			c, err := NewComponent(PrepareComponentName(platform, kind, role), pathPrefix)
+			if err != nil {
+				continue
+			}
+			c.layout = layout
+			if c.BuildMetaPath() == filepath.FromSlash(path) {
+				component = c
+				break
+			}
+		}
+		if component == nil {
+			continue
 		}
 
-		return debug, nil
+		component.readFile = readFile
+		cm.Set(component.GetName(), component)
 	}
 
-	return debug, fmt.Errorf(tplVersionSet, metaFilepath)
+	cm.SortKeysAlphabetically()
+	return cm
 }
 
-// BuildComponentFromPath builds a new instance of Component from the given path.
+// BuildComponentFromPath builds a new instance of Component from the given path, in the first of [Layouts]
+// the path belongs to a valid component of.
 func BuildComponentFromPath(path, pathPrefix string) *Component {
-	platform, kind, role, err := ProcessComponentPath(path)
-	if err != nil || (platform == "" || kind == "" || role == "") {
-		return nil
+	return buildComponentFromPath(path, pathPrefix, nil)
+}
+
+// buildComponentFromPath is [BuildComponentFromPath] with components read from the filesystem of the source if set.
+func buildComponentFromPath(path, pathPrefix string, fsys fs.FS) *Component {
+	for _, layout := range Layouts {
+		platform, kind, role, ok := layout.Parse(filepath.ToSlash(path))
+		if !ok {
+			continue
+		}
+
+		component, err := NewComponentFS(PrepareComponentName(platform, kind, role), pathPrefix, fsys)
+		if err != nil {
+			continue
+		}
+		component.layout = layout
+		if component.IsValidComponent() {
+			return component
+		}
 	}
 
-	component, err := NewComponent(PrepareComponentName(platform, kind, role), pathPrefix)
+	return nil
+}
+
+// ComponentAt returns the component the directory belongs to, with the components source it was found in.
+// Parents of the directory are tried as the source from the closest one, components of unknown [Kinds] are skipped
+// as they belong to a deeper layout, e.g. `roles` of `layer/kind/roles/name`, extra kinds are known as well. Nil if
+// the directory isn't in a component.
+func ComponentAt(dir string, extraKinds ...string) (*Component, string) {
+	dir, err := filepath.Abs(dir)
 	if err != nil {
-		return nil
+		return nil, ""
 	}
-	if !component.IsValidComponent() {
-		return nil
+
+	for prefix := filepath.Dir(dir); ; prefix = filepath.Dir(prefix) {
+		rel, errRel := filepath.Rel(prefix, dir)
+		if errRel == nil {
+			if c := BuildComponentFromPath(rel, prefix); c != nil && IsUpdatableKind(c.kind, extraKinds...) {
+				return c, prefix
+			}
+		}
+
+		if filepath.Dir(prefix) == prefix {
+			return nil, ""
+		}
 	}
-	return component
 }
 
-// ProcessComponentPath splits component path onto platform, kind and role.
+// ProcessComponentPath splits component path onto platform, kind and role, in the first of [Layouts]
+// the path matches.
 func ProcessComponentPath(path string) (string, string, string, error) {
-	parts := strings.Split(path, "/")
-	if len(parts) >= 3 {
-		return parts[0], parts[1], parts[2], nil
+	for _, layout := range Layouts {
+		if platform, kind, role, ok := layout.Parse(filepath.ToSlash(path)); ok {
+			return platform, kind, role, nil
+		}
 	}
 
 	return "", "", "", errors.New("empty component path")
 }
 
-// IsUpdatableKind checks if component kind is in [Kinds] range.
-func IsUpdatableKind(kind string) bool {
+// IsUpdatableKind checks if component kind is in [Kinds] range or one of extra kinds.
+func IsUpdatableKind(kind string, extraKinds ...string) bool {
 	_, ok := Kinds[kind]
-	return ok
+	return ok || slices.Contains(extraKinds, kind)
 }
 
 // OrderedMap represents generic struct with map and order keys.
@@ -366,9 +602,9 @@ func (i *Inventory) GetUsedComponents() map[string]bool {
 
 // CalculateComponentsUsage parse platform playbooks and determine components used in platform.
 func (i *Inventory) CalculateComponentsUsage() error {
-	file, err := os.ReadFile(filepath.Join(i.sourceDir, "platform/platform.yaml"))
+	file, err := fs.ReadFile(i.fsys, "platform/platform.yaml")
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return fmt.Errorf("platform/platform.yaml playbook doesn't exist")
 		}
 
@@ -390,8 +626,7 @@ func (i *Inventory) CalculateComponentsUsage() error {
 				if k == "import_playbook" {
 					playbookName, okV := val.(string)
 					if okV {
-						cleanPath := filepath.Clean(strings.ReplaceAll(playbookName, "../", ""))
-						playbooks = append(playbooks, filepath.Join(i.sourceDir, cleanPath))
+						playbooks = append(playbooks, path.Clean(strings.ReplaceAll(playbookName, "../", "")))
 					}
 				}
 
@@ -402,7 +637,7 @@ func (i *Inventory) CalculateComponentsUsage() error {
 
 	for _, playbook := range playbooks {
 		var playbookData []any
-		file, err = os.ReadFile(filepath.Clean(playbook))
+		file, err = fs.ReadFile(i.fsys, playbook)
 		if err != nil {
 			return err
 		}
@@ -3,7 +3,9 @@ package sync
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -31,7 +33,8 @@ var InventoryExcluded = []string{
 	"__pycache__",
 }
 
-// Kinds are list of component kinds which version can be propagated.
+// Kinds are list of built-in component kinds which version can be propagated, plugins register more kinds in the
+// extension registry.
 var Kinds = map[string]struct{}{
 	"applications": {},
 	"services":     {},
@@ -56,6 +59,9 @@ type Inventory struct {
 	requires        map[string]*OrderedMap[bool] // semantic dependencies (from dependencies.yaml)
 	buildRequiredBy map[string]*OrderedMap[bool] // build dependencies (from main.yaml)
 	buildRequires   map[string]*OrderedMap[bool] // build dependencies (from main.yaml)
+	requiresAt      map[string]map[string]Declaration
+	buildRequiresAt map[string]map[string]Declaration
+	constraints     map[string]map[string]VersionConstraint // version constraints of semantic dependencies
 	topOrder        []string
 
 	componentsUsageCalculated bool
@@ -64,26 +70,40 @@ type Inventory struct {
 	variablesUsageCalculated        bool
 	variableVariablesDependencyMap  map[string]map[string]*VariableDependency
 	variableComponentsDependencyMap map[string]map[string][]string
+	variableFilesMap                map[string]map[string][]string
+	variableUsagesMap               map[string]map[string][]VariableUsage
 
 	// options
 	sourceDir string
+	fsys      fs.FS
 }
 
 // NewInventory creates a new instance of Inventory with the provided vault password.
 // It then calls the Init method of the Inventory to build the components graph and returns
 // the initialized Inventory or any error that occurred during initialization.
 func NewInventory(sourceDir string, log *launchr.Logger) (*Inventory, error) {
+	return NewInventoryFS(os.DirFS(sourceDir), sourceDir, log)
+}
+
+// NewInventoryFS creates a new instance of Inventory reading the source from its filesystem, e.g. in memory
+// or a commit tree without checkout. The source directory prefixes paths of messages and of component updates.
+func NewInventoryFS(fsys fs.FS, sourceDir string, log *launchr.Logger) (*Inventory, error) {
 	inv := &Inventory{
 		sourceDir:                       sourceDir,
-		fc:                              NewFilesCrawler(sourceDir),
+		fsys:                            fsys,
+		fc:                              NewFilesCrawlerFS(fsys),
 		log:                             log,
 		componentsMap:                   NewOrderedMap[*Component](),
 		requiredBy:                      make(map[string]*OrderedMap[bool]),
 		requires:                        make(map[string]*OrderedMap[bool]),
 		buildRequiredBy:                 make(map[string]*OrderedMap[bool]),
 		buildRequires:                   make(map[string]*OrderedMap[bool]),
+		requiresAt:                      make(map[string]map[string]Declaration),
+		buildRequiresAt:                 make(map[string]map[string]Declaration),
+		constraints:                     make(map[string]map[string]VersionConstraint),
 		variableVariablesDependencyMap:  make(map[string]map[string]*VariableDependency),
 		variableComponentsDependencyMap: make(map[string]map[string][]string),
+		variableFilesMap:                make(map[string]map[string][]string),
 	}
 
 	err := inv.Init()
@@ -103,25 +123,24 @@ func (i *Inventory) Init() error {
 }
 
 func (i *Inventory) buildComponentsGraph() error {
-	err := filepath.Walk(i.sourceDir, func(path string, info os.FileInfo, err error) error {
+	err := fs.WalkDir(i.fsys, ".", func(relPath string, entry fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
 
-		if info.Mode()&os.ModeSymlink != 0 || info.IsDir() {
+		if entry.Type()&fs.ModeSymlink != 0 || entry.IsDir() {
 			return nil
 		}
 
-		relPath := strings.TrimPrefix(path, i.sourceDir+"/")
 		for _, d := range InventoryExcluded {
 			if strings.Contains(relPath, d) {
 				return nil
 			}
 		}
 
-		entity := strings.ToLower(filepath.Base(relPath))
-		ext := filepath.Ext(entity)
-		dir := filepath.Dir(relPath)
+		entity := strings.ToLower(path.Base(relPath))
+		ext := path.Ext(entity)
+		dir := path.Dir(relPath)
 
 		isMetaDir := strings.HasSuffix(dir, "/meta")
 		isTasksDir := strings.HasSuffix(dir, "/tasks")
@@ -131,7 +150,7 @@ func (i *Inventory) buildComponentsGraph() error {
 		}
 
 		if isMetaDir && entity == "plasma.yaml" {
-			component := BuildComponentFromPath(relPath, i.sourceDir)
+			component := buildComponentFromPath(relPath, i.sourceDir, i.fsys)
 			if component == nil || !component.IsValidComponent() {
 				return nil
 			}
@@ -139,7 +158,7 @@ func (i *Inventory) buildComponentsGraph() error {
 			componentName := component.GetName()
 			i.componentsMap.Set(componentName, component)
 		} else if isTasksDir {
-			component := BuildComponentFromPath(relPath, i.sourceDir)
+			component := buildComponentFromPath(relPath, i.sourceDir, i.fsys)
 			if component == nil || !component.IsValidComponent() {
 				return nil
 			}
@@ -149,15 +168,32 @@ func (i *Inventory) buildComponentsGraph() error {
 				i.componentsMap.Set(componentName, component)
 			}
 
-			data, errRead := os.ReadFile(filepath.Clean(path))
+			data, errRead := fs.ReadFile(i.fsys, relPath)
 			if errRead != nil {
 				return errRead
 			}
 
+			var doc yaml.Node
+			if err = yaml.Unmarshal(data, &doc); err != nil {
+				return fmt.Errorf("%s > %w", filepath.Join(i.sourceDir, relPath), err)
+			}
+			if doc.Kind == 0 {
+				return nil
+			}
+
+			// Dependencies declared as a list, possibly with version constraints.
+			if entity == "dependencies.yaml" && len(doc.Content) > 0 && doc.Content[0].Kind == yaml.MappingNode {
+				var content DependenciesFile
+				if err = doc.Decode(&content); err != nil {
+					return fmt.Errorf("%s > %w", filepath.Join(i.sourceDir, relPath), err)
+				}
+				i.addDependencies(componentName, filepath.Join(i.sourceDir, relPath), content.Dependencies)
+				return nil
+			}
+
 			var tasks []map[string]any
-			err = yaml.Unmarshal(data, &tasks)
-			if err != nil {
-				return fmt.Errorf("%s > %w", path, err)
+			if err = doc.Decode(&tasks); err != nil {
+				return fmt.Errorf("%s > %w", filepath.Join(i.sourceDir, relPath), err)
 			}
 
 			if len(tasks) == 0 {
@@ -170,28 +206,33 @@ func (i *Inventory) buildComponentsGraph() error {
 			isSemanticDeps := entity == "dependencies.yaml"
 			var requiresMap map[string]*OrderedMap[bool]
 			var requiredByMap map[string]*OrderedMap[bool]
+			var declaredAt map[string]map[string]Declaration
 			if isSemanticDeps {
 				requiresMap = i.requires
 				requiredByMap = i.requiredBy
+				declaredAt = i.requiresAt
 			} else {
 				requiresMap = i.buildRequires
 				requiredByMap = i.buildRequiredBy
+				declaredAt = i.buildRequiresAt
 			}
 
 			if requiresMap[componentName] == nil {
 				requiresMap[componentName] = NewOrderedMap[bool]()
 			}
+			if declaredAt[componentName] == nil {
+				declaredAt[componentName] = make(map[string]Declaration)
+			}
+			lines := includeRoleLines(&doc)
 
 			for _, entry := range tasks {
 				if r, ok := entry["include_role"].(map[string]any); ok {
 					if n, ok := r["name"].(string); ok && n != "" {
 						depName := n // use dot notation as-is
-						if requiredByMap[depName] == nil {
-							requiredByMap[depName] = NewOrderedMap[bool]()
-						}
-
-						requiredByMap[depName].Set(componentName, true)
-						requiresMap[componentName].Set(depName, true)
+						addRequirement(requiresMap, requiredByMap, declaredAt, componentName, depName, Declaration{
+							File: filepath.Join(i.sourceDir, relPath),
+							Line: lines[depName],
+						})
 					}
 				}
 			}
@@ -224,6 +265,9 @@ func (i *Inventory) buildComponentsGraph() error {
 
 	order, err := graph.TopSort(rootPlatform)
 	if err != nil {
+		if cycles := i.FindCycles(false); len(cycles) > 0 {
+			return &CycleError{Cycles: cycles}
+		}
 		return err
 	}
 
@@ -238,6 +282,48 @@ func (i *Inventory) buildComponentsGraph() error {
 	return nil
 }
 
+// addDependencies adds semantic dependencies of the component declared in the file, with their version constraints.
+func (i *Inventory) addDependencies(componentName, file string, deps []Dependency) {
+	if i.requires[componentName] == nil {
+		i.requires[componentName] = NewOrderedMap[bool]()
+	}
+	if i.requiresAt[componentName] == nil {
+		i.requiresAt[componentName] = make(map[string]Declaration)
+	}
+
+	for _, dep := range deps {
+		if dep.Name == "" {
+			continue
+		}
+		addRequirement(i.requires, i.requiredBy, i.requiresAt, componentName, dep.Name, Declaration{File: file, Line: dep.Line})
+		if dep.Constraint == "" {
+			continue
+		}
+		if i.constraints[componentName] == nil {
+			i.constraints[componentName] = make(map[string]VersionConstraint)
+		}
+		i.constraints[componentName][dep.Name] = dep.Constraint
+	}
+}
+
+// addRequirement records the component requires the dependency, declared first at the declaration.
+func addRequirement(requiresMap, requiredByMap map[string]*OrderedMap[bool], declaredAt map[string]map[string]Declaration, componentName, depName string, decl Declaration) {
+	if requiredByMap[depName] == nil {
+		requiredByMap[depName] = NewOrderedMap[bool]()
+	}
+
+	requiredByMap[depName].Set(componentName, true)
+	requiresMap[componentName].Set(depName, true)
+	if _, exists := declaredAt[componentName][depName]; !exists {
+		declaredAt[componentName][depName] = decl
+	}
+}
+
+// GetConstraints returns version constraints of semantic dependencies of the component by dependency name.
+func (i *Inventory) GetConstraints(componentName string) map[string]VersionConstraint {
+	return i.constraints[componentName]
+}
+
 // GetComponentsMap returns map of all components found in source dir.
 func (i *Inventory) GetComponentsMap() *OrderedMap[*Component] {
 	return i.componentsMap
@@ -318,7 +404,7 @@ func (i *Inventory) lookupDependenciesRecursively(componentName string, componen
 func (i *Inventory) GetChangedComponents(files []string) *OrderedMap[*Component] {
 	components := NewOrderedMap[*Component]()
 	for _, path := range files {
-		component := BuildComponentFromPath(path, i.sourceDir)
+		component := buildComponentFromPath(path, i.sourceDir, i.fsys)
 		if component == nil {
 			continue
 		}
@@ -4,9 +4,10 @@ import (
 	"bufio"
 	"context"
 	"fmt"
-	"os"
+	"io/fs"
 	"path/filepath"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 )
@@ -25,6 +26,32 @@ type Variable struct {
 	isVault  bool
 }
 
+// Sources of variable usages.
+const (
+	UsageTemplate = "template" // Jinja template of the component
+	UsageTask     = "task"     // tasks configuration of the component
+)
+
+// VariableUsage is a reference to a variable found in a component file.
+type VariableUsage struct {
+	Component string `json:"component"`
+	// Variable is the referenced variable, the looked up one or a variable using it in its value.
+	Variable string `json:"variable"`
+	Platform string `json:"platform"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	// Source is [UsageTemplate] or [UsageTask].
+	Source string `json:"source"`
+}
+
+// usageSource returns the source of a variable usage found in the component file.
+func usageSource(path string) string {
+	if filepath.Ext(path) == ".j2" {
+		return UsageTemplate
+	}
+	return UsageTask
+}
+
 // VariableDependency stores variable name, platform and reference to dependent vars.
 type VariableDependency struct {
 	Name      string
@@ -135,6 +162,75 @@ func (i *Inventory) GetVariableComponents(variableName, variablePlatform string)
 	return result
 }
 
+// GetVariableUsages returns references to the variable in component files, with the file and line where each is found.
+// References to variables using it in their values are included, with the name of the referenced variable.
+func (i *Inventory) GetVariableUsages(variableName, variablePlatform string) []VariableUsage {
+	if !i.variablesUsageCalculated {
+		panic("use inventory.CalculateVariablesUsage first")
+	}
+
+	variablesList := make(map[string]map[string]bool)
+	i.getVariableVariables(variableName, variablePlatform, variablesList)
+
+	if variablesList[variableName] == nil {
+		variablesList[variableName] = make(map[string]bool)
+	}
+	variablesList[variableName][variablePlatform] = true
+
+	var result []VariableUsage
+	for v, m := range variablesList {
+		for p := range m {
+			result = append(result, i.variableUsagesMap[v][p]...)
+		}
+	}
+
+	sort.Slice(result, func(a, b int) bool {
+		if result[a].Component != result[b].Component {
+			return result[a].Component < result[b].Component
+		}
+		return result[a].Variable < result[b].Variable
+	})
+
+	return result
+}
+
+// GetVariableVariablesDependencyMap returns variable -> platform -> dependency map,
+// where each dependency references variables which use it in their values.
+func (i *Inventory) GetVariableVariablesDependencyMap() map[string]map[string]*VariableDependency {
+	if !i.variablesUsageCalculated {
+		panic("use inventory.CalculateVariablesUsage first")
+	}
+
+	return i.variableVariablesDependencyMap
+}
+
+// GetVariableComponentsDependencyMap returns variable -> platform -> list of components using variable directly.
+func (i *Inventory) GetVariableComponentsDependencyMap() map[string]map[string][]string {
+	if !i.variablesUsageCalculated {
+		panic("use inventory.CalculateVariablesUsage first")
+	}
+
+	return i.variableComponentsDependencyMap
+}
+
+// GetVariableFilesMap returns variable -> platform -> list of variables files defining variable.
+func (i *Inventory) GetVariableFilesMap() map[string]map[string][]string {
+	if !i.variablesUsageCalculated {
+		panic("use inventory.CalculateVariablesUsage first")
+	}
+
+	return i.variableFilesMap
+}
+
+// GetVariableFiles returns list of variables files where variable is defined.
+func (i *Inventory) GetVariableFiles(variableName, variablePlatform string) []string {
+	if !i.variablesUsageCalculated {
+		panic("use inventory.CalculateVariablesUsage first")
+	}
+
+	return i.variableFilesMap[variableName][variablePlatform]
+}
+
 // getVariableVariables returns list of variables which depend on variable.
 func (i *Inventory) getVariableVariables(variableName, variablePlatform string, result map[string]map[string]bool) {
 	if p, ok := i.variableVariablesDependencyMap[variableName]; ok {
@@ -163,13 +259,14 @@ func (i *Inventory) CalculateVariablesUsage(vaultpass string) error {
 	// Iterate all files to find {{ and/or }}, get these lines
 	// iterate potential lines with vars usage and check each variable in it.
 
-	variableComponentsDependencyMap, err := i.buildVariableComponentsDependencies(keys, false)
+	variableComponentsDependencyMap, variableUsagesMap, err := i.buildVariableComponentsDependencies(keys)
 	if err != nil {
 		return err
 	}
 
 	i.variableVariablesDependencyMap = variableVariablesDependencyMap
 	i.variableComponentsDependencyMap = variableComponentsDependencyMap
+	i.variableUsagesMap = variableUsagesMap
 
 	i.variablesUsageCalculated = true
 
@@ -394,7 +491,12 @@ func (i *Inventory) processGroup(ctx context.Context, vaultPass, group string, f
 }
 
 func (i *Inventory) processFile(file, group, vaultPass string, groupKeys map[string]map[string]bool, groupVars map[string]map[string]string, mx *sync.Mutex) error {
-	data, debug, err := LoadVariablesFile(filepath.Join(i.sourceDir, file), vaultPass, IsVaultFile(file))
+	input, err := fs.ReadFile(i.fsys, file)
+	if err != nil {
+		return fmt.Errorf("%s > %w", file, err)
+	}
+
+	data, debug, err := LoadVariablesFileFromBytes(input, filepath.Join(i.sourceDir, file), vaultPass, IsVaultFile(file))
 	for _, d := range debug {
 		i.log.Debug(d)
 	}
@@ -402,6 +504,15 @@ func (i *Inventory) processFile(file, group, vaultPass string, groupKeys map[str
 		return fmt.Errorf("%s > %w", file, err)
 	}
 
+	mx.Lock()
+	for key := range data {
+		if i.variableFilesMap[key] == nil {
+			i.variableFilesMap[key] = make(map[string][]string)
+		}
+		i.variableFilesMap[key][group] = append(i.variableFilesMap[key][group], file)
+	}
+	mx.Unlock()
+
 	i.extractKeysAndVars(data, group, groupKeys, groupVars, "", 0, mx)
 	return nil
 }
@@ -469,13 +580,13 @@ func (i *Inventory) extractKeysAndVars(data interface{}, group string, groupKeys
 	}
 }
 
-func (i *Inventory) buildVariableComponentsDependencies(groupKeys map[string]map[string]bool, filesOnly bool) (map[string]map[string][]string, error) {
+func (i *Inventory) buildVariableComponentsDependencies(groupKeys map[string]map[string]bool) (map[string]map[string][]string, map[string]map[string][]VariableUsage, error) {
 	groupFiles, err := i.fc.FindComponentsFiles("")
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	reverseDependencyMap := make(map[string]map[string][]string)
+	reverseDependencyMap := make(map[string]map[string][]VariableUsage)
 
 	errChan := make(chan error, 1)
 	var wg sync.WaitGroup
@@ -497,39 +608,43 @@ func (i *Inventory) buildVariableComponentsDependencies(groupKeys map[string]map
 
 	for err = range errChan {
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 	}
 
-	if filesOnly {
-		return reverseDependencyMap, nil
-	}
-
 	varToComponentsDependencyMap := make(map[string]map[string][]string)
+	varUsagesMap := make(map[string]map[string][]VariableUsage)
 	for v, pl := range reverseDependencyMap {
-		for p, files := range pl {
+		for p, usages := range pl {
 			var res []string
-			for _, path := range files {
-				platform, kind, role, err := ProcessComponentPath(path)
+			var found []VariableUsage
+			for _, usage := range usages {
+				platform, kind, role, err := ProcessComponentPath(usage.File)
 				if err != nil || (platform == "" || kind == "" || role == "") {
 					continue
 				}
 
 				componentName := PrepareComponentName(platform, kind, role)
 				res = append(res, componentName)
+
+				usage.Component = componentName
+				usage.File = filepath.Join(i.sourceDir, usage.File)
+				found = append(found, usage)
 			}
 			if varToComponentsDependencyMap[v] == nil {
 				varToComponentsDependencyMap[v] = make(map[string][]string)
+				varUsagesMap[v] = make(map[string][]VariableUsage)
 			}
 
 			varToComponentsDependencyMap[v][p] = res
+			varUsagesMap[v][p] = found
 		}
 	}
 
-	return varToComponentsDependencyMap, nil
+	return varToComponentsDependencyMap, varUsagesMap, nil
 }
 
-func (i *Inventory) processGroupFiles(group string, files []string, groupKeys map[string]map[string]bool, reverseDependencyMap map[string]map[string][]string, mx *sync.Mutex) error {
+func (i *Inventory) processGroupFiles(group string, files []string, groupKeys map[string]map[string]bool, reverseDependencyMap map[string]map[string][]VariableUsage, mx *sync.Mutex) error {
 	// Get keys for the current group and the platform group
 	currentGroupKeys := groupKeys[group]
 	platformKeys := groupKeys[rootPlatform]
@@ -542,9 +657,9 @@ func (i *Inventory) processGroupFiles(group string, files []string, groupKeys ma
 	}
 
 	// Extract relevant lines from all files for the current group
-	linesWithVariablesByFile := make(map[string][]string)
+	linesWithVariablesByFile := make(map[string][]variableLine)
 	for _, filePath := range files {
-		lines, err := extractLinesWithVariables(filepath.Join(i.sourceDir, filePath))
+		lines, err := extractLinesWithVariables(i.fsys, filePath)
 		if err != nil {
 			return fmt.Errorf("failed to process file %s: %w", filePath, err)
 		}
@@ -577,13 +692,19 @@ func (i *Inventory) processGroupFiles(group string, files []string, groupKeys ma
 
 			// Check if any of the lines contain the key
 			for _, line := range lines {
-				if strings.Contains(line, key) {
+				if strings.Contains(line.text, key) {
 					mx.Lock()
 					if _, ok := reverseDependencyMap[key]; !ok {
-						reverseDependencyMap[key] = make(map[string][]string)
+						reverseDependencyMap[key] = make(map[string][]VariableUsage)
 					}
 
-					reverseDependencyMap[key][keyGroup] = append(reverseDependencyMap[key][keyGroup], filePath)
+					reverseDependencyMap[key][keyGroup] = append(reverseDependencyMap[key][keyGroup], VariableUsage{
+						Variable: key,
+						Platform: keyGroup,
+						File:     filePath,
+						Line:     line.number,
+						Source:   usageSource(filePath),
+					})
 					mx.Unlock()
 					break
 				}
@@ -604,10 +725,10 @@ func combineKeys(current, platform map[string]bool) map[string]bool {
 	return combined
 }
 
-func isProcessedFile(key, filePrefix string, reverseDependencyMap map[string][]string) bool {
-	if filePaths, exists := reverseDependencyMap[key]; exists {
-		for _, path := range filePaths {
-			if getPathPrefix(path, 4) == filePrefix {
+func isProcessedFile(key, filePrefix string, reverseDependencyMap map[string][]VariableUsage) bool {
+	if usages, exists := reverseDependencyMap[key]; exists {
+		for _, usage := range usages {
+			if getPathPrefix(usage.File, 4) == filePrefix {
 				return true
 			}
 		}
@@ -623,23 +744,31 @@ func getPathPrefix(filePath string, parts int) string {
 	return strings.Join(pathParts, string(filepath.Separator))
 }
 
-func extractLinesWithVariables(filePath string) ([]string, error) {
-	file, err := os.Open(filepath.Clean(filePath))
+// variableLine is a line of a component file which may reference variables.
+type variableLine struct {
+	number int
+	text   string
+}
+
+func extractLinesWithVariables(fsys fs.FS, filePath string) ([]variableLine, error) {
+	file, err := fsys.Open(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("opening file %s: %w", filePath, err)
 	}
 
 	defer file.Close()
 
-	var linesWithVariables []string
+	var linesWithVariables []variableLine
 	scanner := bufio.NewScanner(file)
 	buf := make([]byte, 0, 64*1024)
 	scanner.Buffer(buf, 1024*1024)
 
+	number := 0
 	for scanner.Scan() {
+		number++
 		line := scanner.Text()
 		if len(line) > 0 && !strings.HasPrefix(line, "#") && strings.Contains(line, "{{") || strings.Contains(line, "}}") {
-			linesWithVariables = append(linesWithVariables, line)
+			linesWithVariables = append(linesWithVariables, variableLine{number: number, text: line})
 		}
 	}
 	if err = scanner.Err(); err != nil {
@@ -0,0 +1,48 @@
+package sync
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/launchrctl/launchr"
+)
+
+func TestGetVariableUsages(t *testing.T) {
+	meta := &fstest.MapFile{Data: []byte("plasma:\n  version: \"aaa1111111111\"\n")}
+	fsys := fstest.MapFS{
+		"platform/group_vars/platform/vars.yaml": &fstest.MapFile{Data: []byte(`grafana_port: 3000
+grafana_url: "http://grafana:{{ grafana_port }}"
+`)},
+		"interaction/applications/dashboards/meta/plasma.yaml": meta,
+		"interaction/applications/dashboards/templates/grafana.ini.j2": &fstest.MapFile{Data: []byte(`[server]
+http_port = {{ grafana_port }}
+`)},
+		"foundation/services/proxy/meta/plasma.yaml": meta,
+		"foundation/services/proxy/tasks/configuration.yaml": &fstest.MapFile{Data: []byte(`- name: Configure upstream
+  upstream: "{{ grafana_url }}"
+`)},
+	}
+
+	inv, err := NewInventoryFS(fsys, "build", launchr.Log())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = inv.CalculateVariablesUsage(""); err != nil {
+		t.Fatal(err)
+	}
+
+	usages := inv.GetVariableUsages("grafana_port", rootPlatform)
+	if len(usages) != 2 {
+		t.Fatalf("expected direct and indirect usages, got %+v", usages)
+	}
+
+	proxy, dashboards := usages[0], usages[1]
+	if proxy.Component != "foundation.services.proxy" || proxy.Variable != "grafana_url" || proxy.Source != UsageTask ||
+		proxy.File != "build/foundation/services/proxy/tasks/configuration.yaml" || proxy.Line != 2 {
+		t.Errorf("expected proxy to use the port through grafana_url in its tasks, got %+v", proxy)
+	}
+	if dashboards.Component != "interaction.applications.dashboards" || dashboards.Variable != "grafana_port" ||
+		dashboards.Source != UsageTemplate || dashboards.Line != 2 {
+		t.Errorf("expected dashboards to use the port in its template, got %+v", dashboards)
+	}
+}
@@ -0,0 +1,81 @@
+package sync
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MetaLayout is a strategy resolving where component files live in a source directory.
+type MetaLayout interface {
+	// Dir returns the component directory relative to the source.
+	Dir(layer, kind, name string) string
+	// Parse returns the component of a slash separated path relative to the source, ok is false if the path
+	// doesn't belong to a component in the layout.
+	Parse(path string) (layer, kind, name string, ok bool)
+}
+
+// RootLayout places components at `layer/kind/name`.
+type RootLayout struct{}
+
+// Dir implements [MetaLayout] interface.
+func (RootLayout) Dir(layer, kind, name string) string {
+	return filepath.Join(layer, kind, name)
+}
+
+// Parse implements [MetaLayout] interface.
+func (RootLayout) Parse(path string) (string, string, string, bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], parts[2], true
+}
+
+// RolesLayout places components at `layer/kind/roles/name`, as composed packages may do.
+type RolesLayout struct{}
+
+// Dir implements [MetaLayout] interface.
+func (RolesLayout) Dir(layer, kind, name string) string {
+	return filepath.Join(layer, kind, "roles", name)
+}
+
+// Parse implements [MetaLayout] interface.
+func (RolesLayout) Parse(path string) (string, string, string, bool) {
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 || parts[2] != "roles" || parts[0] == "" || parts[1] == "" || parts[3] == "" {
+		return "", "", "", false
+	}
+
+	return parts[0], parts[1], parts[3], true
+}
+
+// Layouts are the layouts components are looked up in, the most specific first. Another layout can be added
+// before inventories are built.
+var Layouts = []MetaLayout{RolesLayout{}, RootLayout{}}
+
+// metaFileName is the component meta file relative to the component directory.
+var metaFileName = filepath.Join("meta", "plasma.yaml")
+
+// resolveLayout returns the layout which meta file of the component exists in the source, [RootLayout] if none.
+func resolveLayout(fsys fs.FS, prefix, layer, kind, name string) MetaLayout {
+	for _, layout := range Layouts {
+		if _, err := statSource(fsys, prefix, filepath.Join(layout.Dir(layer, kind, name), metaFileName)); err == nil {
+			return layout
+		}
+	}
+
+	return RootLayout{}
+}
+
+// statSource returns info of the file relative to the source, from the filesystem of the source if set,
+// in the prefix on disk otherwise.
+func statSource(fsys fs.FS, prefix, path string) (fs.FileInfo, error) {
+	if fsys != nil {
+		return fs.Stat(fsys, filepath.ToSlash(path))
+	}
+
+	return os.Stat(filepath.Join(prefix, path))
+}
@@ -0,0 +1,165 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/launchrctl/launchr"
+)
+
+func TestProcessComponentPath(t *testing.T) {
+	tests := []struct {
+		path string
+		name string
+	}{
+		{"foundation/services/postgres/meta/plasma.yaml", "foundation.services.postgres"},
+		{"foundation/services/roles/postgres/meta/plasma.yaml", "foundation.services.postgres"},
+		{"foundation/services/roles/postgres/templates/pg.conf.j2", "foundation.services.postgres"},
+		{"foundation/services/postgres", "foundation.services.postgres"},
+	}
+
+	for _, tt := range tests {
+		layer, kind, role, err := ProcessComponentPath(tt.path)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		if name := PrepareComponentName(layer, kind, role); name != tt.name {
+			t.Errorf("%s: expected %s, got %s", tt.path, tt.name, name)
+		}
+	}
+
+	if _, _, _, err := ProcessComponentPath("foundation/services"); err == nil {
+		t.Error("expected error for path without component")
+	}
+}
+
+func TestComponentLayouts(t *testing.T) {
+	dir := t.TempDir()
+	for _, meta := range []string{
+		"foundation/services/postgres/meta/plasma.yaml",
+		"foundation/applications/roles/auth/meta/plasma.yaml",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(meta))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("plasma:\n  version: \"aaa1111111111\"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		meta string
+	}{
+		{"foundation.services.postgres", "foundation/services/postgres/meta/plasma.yaml"},
+		{"foundation.applications.auth", "foundation/applications/roles/auth/meta/plasma.yaml"},
+		{"foundation.services.missing", "foundation/services/missing/meta/plasma.yaml"},
+	}
+
+	for _, tt := range tests {
+		c, err := NewComponent(tt.name, dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta := c.BuildMetaPath(); meta != filepath.FromSlash(tt.meta) {
+			t.Errorf("%s: expected meta %s, got %s", tt.name, tt.meta, meta)
+		}
+	}
+
+	c := BuildComponentFromPath("foundation/applications/roles/auth/tasks/main.yaml", dir)
+	if c == nil || c.GetName() != "foundation.applications.auth" {
+		t.Fatalf("expected auth component in roles layout, got %v", c)
+	}
+	if version, _, err := c.GetVersion(); err != nil || version != "aaa1111111111" {
+		t.Errorf("expected auth version read, got %q (%v)", version, err)
+	}
+
+	files := []string{
+		"foundation/services/postgres/meta/plasma.yaml",
+		"foundation/applications/roles/auth/meta/plasma.yaml",
+		"foundation/applications/roles/auth/files/meta/plasma.yaml",
+	}
+	cm := NewComponentsMapFromFiles(files, "", func(path string) ([]byte, error) {
+		return os.ReadFile(filepath.Join(dir, path))
+	})
+	if keys := cm.Keys(); len(keys) != 2 || keys[0] != "foundation.applications.auth" || keys[1] != "foundation.services.postgres" {
+		t.Errorf("expected components of both layouts, got %v", keys)
+	}
+}
+
+func TestComponentAt(t *testing.T) {
+	dir := t.TempDir()
+	for _, meta := range []string{
+		"src/foundation/services/postgres/meta/plasma.yaml",
+		"src/foundation/applications/roles/auth/meta/plasma.yaml",
+	} {
+		path := filepath.Join(dir, filepath.FromSlash(meta))
+		if err := os.MkdirAll(filepath.Join(filepath.Dir(filepath.Dir(path)), "tasks"), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("plasma:\n  version: \"aaa1111111111\"\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		dir  string
+		name string
+	}{
+		{"src/foundation/services/postgres", "foundation.services.postgres"},
+		{"src/foundation/services/postgres/tasks", "foundation.services.postgres"},
+		{"src/foundation/applications/roles/auth/tasks", "foundation.applications.auth"},
+	}
+
+	for _, tt := range tests {
+		c, source := ComponentAt(filepath.Join(dir, filepath.FromSlash(tt.dir)))
+		if c == nil || c.GetName() != tt.name {
+			t.Errorf("%s: expected %s, got %v", tt.dir, tt.name, c)
+			continue
+		}
+		if source != filepath.Join(dir, "src") {
+			t.Errorf("%s: expected source %s, got %s", tt.dir, filepath.Join(dir, "src"), source)
+		}
+	}
+
+	if c, _ := ComponentAt(filepath.Join(dir, "src", "foundation")); c != nil {
+		t.Errorf("expected no component for layer directory, got %s", c.GetName())
+	}
+}
+
+func TestInventoryFS(t *testing.T) {
+	meta := &fstest.MapFile{Data: []byte("plasma:\n  version: \"aaa1111111111\"\n")}
+	fsys := fstest.MapFS{
+		"foundation/services/postgres/meta/plasma.yaml":       meta,
+		"foundation/applications/roles/auth/meta/plasma.yaml": meta,
+		"foundation/applications/roles/auth/tasks/dependencies.yaml": &fstest.MapFile{Data: []byte(`- include_role:
+    name: foundation.services.postgres
+`)},
+	}
+
+	inv, err := NewInventoryFS(fsys, "build", launchr.Log())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if keys := inv.GetComponentsMap().Keys(); len(keys) != 2 {
+		t.Fatalf("expected components of the filesystem, got %v", keys)
+	}
+	if requires := inv.GetRequiresComponents("foundation.applications.auth", 1); !requires["foundation.services.postgres"] {
+		t.Errorf("expected auth to require postgres, got %v", requires)
+	}
+
+	auth, ok := inv.GetComponentsMap().Get("foundation.applications.auth")
+	if !ok {
+		t.Fatal("expected auth component")
+	}
+	if version, _, err := auth.GetVersion(); err != nil || version != "aaa1111111111" {
+		t.Errorf("expected auth version read from the filesystem, got %q (%v)", version, err)
+	}
+}
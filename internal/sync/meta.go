@@ -0,0 +1,74 @@
+package sync
+
+import (
+	"bytes"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetMetaVersion returns plasma.yaml content with plasma.version set to the version, keeping comments, key order
+// and the style of the existing version value. Missing plasma section and version key are added.
+func SetMetaVersion(data []byte, version string) ([]byte, error) {
+	return SetMetaField(data, "version", version)
+}
+
+// SetMetaField returns plasma.yaml content with the key of plasma section set to the string value, the same way
+// as [SetMetaVersion].
+func SetMetaField(data []byte, key, val string) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("meta root is not a mapping")
+	}
+
+	plasma := mappingEntry(root, "plasma")
+	if plasma == nil {
+		plasma = appendMappingEntry(root, "plasma", &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"})
+	}
+	if plasma.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("plasma section is not a mapping")
+	}
+
+	value := mappingEntry(plasma, key)
+	if value == nil {
+		value = appendMappingEntry(plasma, key, &yaml.Node{Kind: yaml.ScalarNode})
+	}
+	value.Kind = yaml.ScalarNode
+	value.Tag = "!!str"
+	value.Value = val
+
+	var b bytes.Buffer
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func mappingEntry(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func appendMappingEntry(node *yaml.Node, key string, value *yaml.Node) *yaml.Node {
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	return value
+}
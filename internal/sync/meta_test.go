@@ -0,0 +1,41 @@
+package sync
+
+import "testing"
+
+func TestSetMetaVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		meta     string
+		expected string
+	}{
+		{
+			"comments and quotes kept",
+			"# Component meta\nplasma:\n  # pinned during incident\n  version: \"abc\" # old\n  kind: services\n",
+			"# Component meta\nplasma:\n  # pinned during incident\n  version: \"0123\" # old\n  kind: services\n",
+		},
+		{
+			"version added",
+			"plasma:\n  kind: services\n",
+			"plasma:\n  kind: services\n  version: \"0123\"\n",
+		},
+		{
+			"empty meta",
+			"",
+			"plasma:\n  version: \"0123\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		data, err := SetMetaVersion([]byte(tt.meta), "0123")
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if string(data) != tt.expected {
+			t.Errorf("%s: expected\n%s\ngot\n%s", tt.name, tt.expected, data)
+		}
+	}
+
+	if _, err := SetMetaVersion([]byte("plasma: abc\n"), "0123"); err == nil {
+		t.Error("expected error for scalar plasma section")
+	}
+}
@@ -1,7 +1,9 @@
 package sync
 
 import (
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/launchrctl/launchr"
@@ -20,6 +22,8 @@ type TimelineItem interface {
 	GetCommit() string
 	GetVersion() string
 	GetDate() time.Time
+	GetMessage() string
+	GetChanges() []string
 	Merge(item TimelineItem)
 	Print()
 }
@@ -28,6 +32,8 @@ type TimelineItem interface {
 type TimelineComponentsItem struct {
 	version    string
 	commit     string
+	message    string
+	changes    []string
 	components *OrderedMap[*Component]
 	date       time.Time
 	printer    *launchr.Terminal
@@ -59,6 +65,22 @@ func (i *TimelineComponentsItem) GetDate() time.Time {
 	return i.date
 }
 
+// SetMessages sets commit subject of timeline item and subjects of underlying changes.
+func (i *TimelineComponentsItem) SetMessages(message string, changes []string) {
+	i.message = message
+	i.changes = changes
+}
+
+// GetMessage returns timeline item commit subject.
+func (i *TimelineComponentsItem) GetMessage() string {
+	return i.message
+}
+
+// GetChanges returns subjects of commits underlying timeline item.
+func (i *TimelineComponentsItem) GetChanges() []string {
+	return i.changes
+}
+
 // AddComponent pushes [Component] into timeline item.
 func (i *TimelineComponentsItem) AddComponent(c *Component) {
 	i.components.Set(c.GetName(), c)
@@ -72,6 +94,8 @@ func (i *TimelineComponentsItem) GetComponents() *OrderedMap[*Component] {
 // Merge allows to merge other timeline item components.
 func (i *TimelineComponentsItem) Merge(item TimelineItem) {
 	if c2, ok := item.(*TimelineComponentsItem); ok {
+		i.version, i.date = mergeVersion(i.version, i.date, c2.version, c2.date)
+		i.changes = mergeChanges(i.changes, c2.changes)
 		for _, key := range c2.components.Keys() {
 			_, exists := i.components.Get(key)
 			if exists {
@@ -91,6 +115,7 @@ func (i *TimelineComponentsItem) Merge(item TimelineItem) {
 // Print outputs common item info.
 func (i *TimelineComponentsItem) Print() {
 	i.printer.Printfln("Version: %s, Date: %s, Commit: %s", i.GetVersion(), i.GetDate(), i.GetCommit())
+	printMessages(i.printer, i.message, i.changes)
 	i.printer.Printf("Component List:\n")
 	for _, key := range i.components.Keys() {
 		v, ok := i.components.Get(key)
@@ -105,6 +130,8 @@ func (i *TimelineComponentsItem) Print() {
 type TimelineVariablesItem struct {
 	version   string
 	commit    string
+	message   string
+	changes   []string
 	variables *OrderedMap[*Variable]
 	date      time.Time
 	printer   *launchr.Terminal
@@ -136,6 +163,22 @@ func (i *TimelineVariablesItem) GetDate() time.Time {
 	return i.date
 }
 
+// SetMessages sets commit subject of timeline item and subjects of underlying changes.
+func (i *TimelineVariablesItem) SetMessages(message string, changes []string) {
+	i.message = message
+	i.changes = changes
+}
+
+// GetMessage returns timeline item commit subject.
+func (i *TimelineVariablesItem) GetMessage() string {
+	return i.message
+}
+
+// GetChanges returns subjects of commits underlying timeline item.
+func (i *TimelineVariablesItem) GetChanges() []string {
+	return i.changes
+}
+
 // AddVariable pushes [Variable] into timeline item.
 func (i *TimelineVariablesItem) AddVariable(v *Variable) {
 	i.variables.Set(v.GetName(), v)
@@ -149,6 +192,8 @@ func (i *TimelineVariablesItem) GetVariables() *OrderedMap[*Variable] {
 // Merge allows to merge other timeline item variables.
 func (i *TimelineVariablesItem) Merge(item TimelineItem) {
 	if v2, ok := item.(*TimelineVariablesItem); ok {
+		i.version, i.date = mergeVersion(i.version, i.date, v2.version, v2.date)
+		i.changes = mergeChanges(i.changes, v2.changes)
 		for _, key := range v2.variables.Keys() {
 			_, exists := i.variables.Get(key)
 			if exists {
@@ -168,6 +213,7 @@ func (i *TimelineVariablesItem) Merge(item TimelineItem) {
 // Print outputs common item info.
 func (i *TimelineVariablesItem) Print() {
 	i.printer.Printfln("Version: %s, Date: %s, Commit: %s", i.GetVersion(), i.GetDate(), i.GetCommit())
+	printMessages(i.printer, i.message, i.changes)
 	i.printer.Printf("Variable List:\n")
 	for _, key := range i.variables.Keys() {
 		v, ok := i.variables.Get(key)
@@ -178,7 +224,33 @@ func (i *TimelineVariablesItem) Print() {
 	}
 }
 
+// printMessages outputs commit subject and underlying changes of timeline item.
+func printMessages(printer *launchr.Terminal, message string, changes []string) {
+	if message != "" {
+		printer.Printfln("Message: %s", message)
+	}
+	if len(changes) > 0 {
+		printer.Printf("Changes:\n")
+		for _, c := range changes {
+			printer.Printfln("- %s", c)
+		}
+	}
+}
+
+// mergeChanges appends missing changes subjects, keeping the original order.
+func mergeChanges(changes, other []string) []string {
+	for _, c := range other {
+		if !slices.Contains(changes, c) {
+			changes = append(changes, c)
+		}
+	}
+
+	return changes
+}
+
 // AddToTimeline inserts items into timeline slice.
+// Item is merged into existing item of the same type created from the same commit.
+// Items without a commit hash (e.g. overridden build values) are merged only when both version and date match.
 func AddToTimeline(list []TimelineItem, item TimelineItem) []TimelineItem {
 	for _, i := range list {
 		switch i.(type) {
@@ -194,7 +266,7 @@ func AddToTimeline(list []TimelineItem, item TimelineItem) []TimelineItem {
 			continue
 		}
 
-		if i.GetVersion() == item.GetVersion() && i.GetDate().Equal(item.GetDate()) {
+		if isSameChange(i, item) {
 			i.Merge(item)
 			return list
 		}
@@ -203,6 +275,43 @@ func AddToTimeline(list []TimelineItem, item TimelineItem) []TimelineItem {
 	return append(list, item)
 }
 
+// isSameChange checks if timeline items originate from the same change.
+func isSameChange(a, b TimelineItem) bool {
+	if isCommitHash(a.GetCommit()) || isCommitHash(b.GetCommit()) {
+		return a.GetCommit() == b.GetCommit()
+	}
+
+	return a.GetVersion() == b.GetVersion() && a.GetDate().Equal(b.GetDate())
+}
+
+// isCommitHash checks if string is a full git commit hash.
+func isCommitHash(s string) bool {
+	if len(s) != 40 {
+		return false
+	}
+
+	for _, c := range s {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// mergeVersion returns version and date of merged items.
+// Items of the same commit may carry different versions, the lowest one is kept, so result doesn't depend on insertion order.
+func mergeVersion(version string, date time.Time, otherVersion string, otherDate time.Time) (string, time.Time) {
+	if otherVersion < version {
+		version = otherVersion
+	}
+	if otherDate.Before(date) {
+		date = otherDate
+	}
+
+	return version, date
+}
+
 // SortTimeline sorts timeline items in slice.
 func SortTimeline(list []TimelineItem, order string) {
 	sort.Slice(list, func(i, j int) bool {
@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	commitA = strings.Repeat("a", 40)
+	commitB = strings.Repeat("b", 40)
+)
+
+// componentsItem creates timeline item for component from a package, as sync does for each namespace.
+func componentsItem(t *testing.T, name, pkg, version, commit string, date time.Time) *TimelineComponentsItem {
+	t.Helper()
+	c, err := NewComponent(name, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	item := NewTimelineComponentsItem(version, commit, date, nil)
+	item.AddComponent(c)
+	return item
+}
+
+func TestAddToTimelineSameCommitFromPackages(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	timeline := CreateTimeline()
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.postgres", "plasma-core", "bbb2222222222", commitA, date))
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.redis", "plasma-extra", "aaa1111111111", commitA, date))
+
+	if len(timeline) != 1 {
+		t.Fatalf("expected items of the same commit to be merged, got %d items", len(timeline))
+	}
+
+	item := timeline[0].(*TimelineComponentsItem)
+	if item.GetComponents().Len() != 2 {
+		t.Errorf("expected 2 components in merged item, got %v", item.GetComponents().Keys())
+	}
+
+	if item.GetVersion() != "aaa1111111111" {
+		t.Errorf("expected lowest version to be kept, got %s", item.GetVersion())
+	}
+}
+
+func TestAddToTimelineMergeOrderIndependent(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	first := componentsItem(t, "foundation.services.postgres", "plasma-core", "bbb2222222222", commitA, date)
+	second := componentsItem(t, "foundation.services.redis", "plasma-extra", "aaa1111111111", commitA, date.Add(-time.Second))
+
+	forward := AddToTimeline(AddToTimeline(CreateTimeline(), first), second)
+
+	first = componentsItem(t, "foundation.services.postgres", "plasma-core", "bbb2222222222", commitA, date)
+	second = componentsItem(t, "foundation.services.redis", "plasma-extra", "aaa1111111111", commitA, date.Add(-time.Second))
+	backward := AddToTimeline(AddToTimeline(CreateTimeline(), second), first)
+
+	if forward[0].GetVersion() != backward[0].GetVersion() || !forward[0].GetDate().Equal(backward[0].GetDate()) {
+		t.Errorf("expected same merge result regardless of order, got %s/%s and %s/%s",
+			forward[0].GetVersion(), forward[0].GetDate(), backward[0].GetVersion(), backward[0].GetDate())
+	}
+}
+
+func TestAddToTimelineDistinctCommits(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	timeline := CreateTimeline()
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.postgres", "plasma-core", "aaa1111111111", commitA, date))
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.redis", "plasma-extra", "aaa1111111111", commitB, date))
+
+	if len(timeline) != 2 {
+		t.Fatalf("expected distinct commits with same version and date to stay separate, got %d items", len(timeline))
+	}
+}
+
+func TestAddToTimelineWithoutCommit(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	timeline := CreateTimeline()
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.postgres", "", "aaa1111111111", "override", date))
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.redis", "", "bbb2222222222", "override", date))
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.keycloak", "", "aaa1111111111", "override", date))
+
+	if len(timeline) != 2 {
+		t.Fatalf("expected overridden items to merge by version and date only, got %d items", len(timeline))
+	}
+}
+
+func TestAddToTimelineKeepsTypesApart(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	variables := NewTimelineVariablesItem(commitA[:13], commitA, date, nil)
+	variables.AddVariable(NewVariable("foundation/group_vars/all/vars.yaml", "postgres_port", 1, false))
+
+	timeline := CreateTimeline()
+	timeline = AddToTimeline(timeline, variables)
+	timeline = AddToTimeline(timeline, componentsItem(t, "foundation.services.postgres", "plasma-core", "aaa1111111111", commitA, date))
+
+	if len(timeline) != 2 {
+		t.Fatalf("expected components and variables items of the same commit to stay separate, got %d items", len(timeline))
+	}
+}
@@ -0,0 +1,142 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	versionBase       = "{base}"
+	versionPropagated = "{propagated}"
+)
+
+// DefaultVersionFormat composes propagated versions as `base-propagated`.
+const DefaultVersionFormat VersionFormat = versionBase + "-" + versionPropagated
+
+// VersionFormat is the template of propagated component versions, e.g. `{base}-{propagated}` or
+// `{base}+p.{propagated}`. Base is the component own version, propagated is the version of the dependency change
+// which triggered the update. Empty format is [DefaultVersionFormat].
+type VersionFormat string
+
+// Validate checks the format has both placeholders, base first, separated by a non-empty text.
+func (f VersionFormat) Validate() error {
+	_, _, _, err := f.parts()
+	return err
+}
+
+// parts splits the format into the text before base, between base and propagated, and after propagated.
+func (f VersionFormat) parts() (string, string, string, error) {
+	format := string(f)
+	if format == "" {
+		format = string(DefaultVersionFormat)
+	}
+
+	if strings.Count(format, versionBase) != 1 || strings.Count(format, versionPropagated) != 1 {
+		return "", "", "", fmt.Errorf("version format %q must contain %s and %s once", format, versionBase, versionPropagated)
+	}
+
+	prefix, rest, _ := strings.Cut(format, versionBase)
+	sep, suffix, found := strings.Cut(rest, versionPropagated)
+	if !found {
+		return "", "", "", fmt.Errorf("version format %q must have %s before %s", format, versionBase, versionPropagated)
+	}
+	if sep == "" {
+		return "", "", "", fmt.Errorf("version format %q must separate %s and %s", format, versionBase, versionPropagated)
+	}
+
+	return prefix, sep, suffix, nil
+}
+
+// Split returns base and propagated parts of the version, propagated is empty if the version isn't composed.
+func (f VersionFormat) Split(version string) (string, string) {
+	prefix, sep, suffix, err := f.parts()
+	if err != nil {
+		return version, ""
+	}
+
+	trimmed, ok := strings.CutPrefix(version, prefix)
+	if !ok {
+		return version, ""
+	}
+	trimmed, ok = strings.CutSuffix(trimmed, suffix)
+	if !ok {
+		return version, ""
+	}
+
+	base, propagated, found := strings.Cut(trimmed, sep)
+	if !found {
+		return version, ""
+	}
+
+	return base, propagated
+}
+
+// IsComposed tells if the version is already composed of base and propagated parts.
+func (f VersionFormat) IsComposed(version string) bool {
+	_, propagated := f.Split(version)
+	return propagated != ""
+}
+
+// Compose returns version made of the base and propagated parts.
+func (f VersionFormat) Compose(base, propagated string) string {
+	prefix, sep, suffix, err := f.parts()
+	if err != nil {
+		prefix, sep, suffix, _ = DefaultVersionFormat.parts()
+	}
+
+	return prefix + base + sep + propagated + suffix
+}
+
+// Normalize checks the version conforms to the format and returns the reason it doesn't, with the version repaired:
+// surrounding spaces are trimmed, empty parts dropped and versions propagated several times keep the base and the
+// latest propagated part. Reason is empty for conforming versions, repaired version is empty if it can't be repaired.
+func (f VersionFormat) Normalize(version string) (string, string) {
+	prefix, sep, suffix, err := f.parts()
+	if err != nil {
+		return version, ""
+	}
+
+	var reasons []string
+	trimmed := strings.TrimSpace(version)
+	if trimmed != version {
+		reasons = append(reasons, "has surrounding spaces")
+	}
+	if trimmed == "" {
+		return "", "is empty"
+	}
+
+	inner, ok := strings.CutPrefix(trimmed, prefix)
+	if ok {
+		inner, ok = strings.CutSuffix(inner, suffix)
+	}
+	if !ok {
+		// Not composed, the whole version is the base.
+		return trimmed, strings.Join(reasons, ", ")
+	}
+
+	var parts []string
+	segments := strings.Split(inner, sep)
+	for _, s := range segments {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+
+	switch {
+	case len(parts) == 0:
+		return "", "has no base"
+	case len(parts) < len(segments):
+		reasons = append(reasons, "has empty parts")
+	case len(parts) > 2:
+		reasons = append(reasons, fmt.Sprintf("has %d parts instead of base and propagated", len(parts)))
+	}
+
+	if len(reasons) == 0 {
+		return version, ""
+	}
+	if len(parts) == 1 {
+		return parts[0], strings.Join(reasons, ", ")
+	}
+
+	return f.Compose(parts[0], parts[len(parts)-1]), strings.Join(reasons, ", ")
+}
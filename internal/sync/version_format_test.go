@@ -0,0 +1,74 @@
+package sync
+
+import "testing"
+
+func TestVersionFormat(t *testing.T) {
+	tests := []struct {
+		format     VersionFormat
+		version    string
+		base       string
+		propagated string
+	}{
+		{"", "abc123", "abc123", ""},
+		{"", "abc123-def456", "abc123", "def456"},
+		{DefaultVersionFormat, "1.2.0-def456", "1.2.0", "def456"},
+		{"{base}+p.{propagated}", "1.2.0-rc1+p.def456", "1.2.0-rc1", "def456"},
+		{"{base}+p.{propagated}", "1.2.0-rc1", "1.2.0-rc1", ""},
+		{"v{base}_{propagated}", "v1_2", "1", "2"},
+		{"v{base}_{propagated}", "1_2", "1_2", ""},
+	}
+
+	for _, tt := range tests {
+		base, propagated := tt.format.Split(tt.version)
+		if base != tt.base || propagated != tt.propagated {
+			t.Errorf("%q.Split(%q): expected %q, %q, got %q, %q", tt.format, tt.version, tt.base, tt.propagated, base, propagated)
+		}
+		if tt.propagated == "" {
+			continue
+		}
+		if composed := tt.format.Compose(tt.base, tt.propagated); composed != tt.version {
+			t.Errorf("%q.Compose(%q, %q): expected %q, got %q", tt.format, tt.base, tt.propagated, tt.version, composed)
+		}
+	}
+}
+
+func TestVersionFormatValidate(t *testing.T) {
+	for _, format := range []VersionFormat{"", DefaultVersionFormat, "{base}+p.{propagated}"} {
+		if err := format.Validate(); err != nil {
+			t.Errorf("%q: unexpected error %v", format, err)
+		}
+	}
+
+	for _, format := range []VersionFormat{"{base}", "{propagated}-{base}", "{base}{propagated}", "{base}-{base}-{propagated}"} {
+		if err := format.Validate(); err == nil {
+			t.Errorf("%q: expected error", format)
+		}
+	}
+}
+
+func TestVersionFormatNormalize(t *testing.T) {
+	tests := []struct {
+		format  VersionFormat
+		version string
+		fixed   string
+		invalid bool
+	}{
+		{"", "abc", "abc", false},
+		{"", "abc-def", "abc-def", false},
+		{"", " abc-def\n", "abc-def", true},
+		{"", "abc-def-ghi", "abc-ghi", true},
+		{"", "abc-", "abc", true},
+		{"", "abc--def", "abc-def", true},
+		{"", "-", "", true},
+		{"", "  ", "", true},
+		{"{base}+p.{propagated}", "1.2.0-rc1+p.def", "1.2.0-rc1+p.def", false},
+		{"{base}+p.{propagated}", "1.2.0+p.abc+p.def", "1.2.0+p.def", true},
+	}
+
+	for _, tt := range tests {
+		fixed, reason := tt.format.Normalize(tt.version)
+		if fixed != tt.fixed || (reason != "") != tt.invalid {
+			t.Errorf("%q.Normalize(%q): expected %q (invalid %v), got %q (%q)", tt.format, tt.version, tt.fixed, tt.invalid, fixed, reason)
+		}
+	}
+}
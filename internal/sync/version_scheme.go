@@ -0,0 +1,134 @@
+package sync
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Versioning schemes of components, stored in plasma.versioning of the meta file.
+const (
+	// HashVersioning versions components with the short hash of the commit changing them, the default.
+	HashVersioning = "hash"
+	// SemverVersioning versions components with semantic versions, e.g. 1.4.2.
+	SemverVersioning = "semver"
+)
+
+// VersionLevel is the part of a semantic version to increment.
+type VersionLevel string
+
+// Semantic version levels.
+const (
+	LevelPatch VersionLevel = "patch"
+	LevelMinor VersionLevel = "minor"
+	LevelMajor VersionLevel = "major"
+)
+
+// Validate checks the level is patch, minor or major. Empty level is patch.
+func (l VersionLevel) Validate() error {
+	switch l {
+	case "", LevelPatch, LevelMinor, LevelMajor:
+		return nil
+	default:
+		return fmt.Errorf("unknown version level %q (expected: %s, %s or %s)", l, LevelPatch, LevelMinor, LevelMajor)
+	}
+}
+
+// VersionScheme computes versions of bumped components.
+type VersionScheme interface {
+	// Next returns the version following the current base version for changes of the commit hash.
+	Next(current, hash string, level VersionLevel) (string, error)
+}
+
+var versionSchemes = map[string]VersionScheme{
+	HashVersioning:   hashScheme{},
+	SemverVersioning: semverScheme{},
+}
+
+// RegisterVersionScheme adds the versioning scheme available to components by name, replacing an existing one.
+func RegisterVersionScheme(name string, scheme VersionScheme) {
+	versionSchemes[name] = scheme
+}
+
+// GetVersionScheme returns the versioning scheme by name, [HashVersioning] if the name is empty.
+func GetVersionScheme(name string) (VersionScheme, error) {
+	if name == "" {
+		name = HashVersioning
+	}
+
+	scheme, ok := versionSchemes[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown versioning scheme %q (expected one of: %s)", name, strings.Join(VersionSchemes(), ", "))
+	}
+
+	return scheme, nil
+}
+
+// VersionSchemes returns names of available versioning schemes, sorted.
+func VersionSchemes() []string {
+	names := make([]string, 0, len(versionSchemes))
+	for name := range versionSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hashScheme versions components with the commit hash, regardless of the current version.
+type hashScheme struct{}
+
+func (hashScheme) Next(_, hash string, _ VersionLevel) (string, error) {
+	return hash, nil
+}
+
+// semverScheme increments the level of the current semantic version, keeping its `v` prefix if any.
+// Components switching from another scheme start from 0.0.0.
+type semverScheme struct{}
+
+func (semverScheme) Next(current, _ string, level VersionLevel) (string, error) {
+	if err := level.Validate(); err != nil {
+		return "", err
+	}
+
+	prefix := ""
+	if strings.HasPrefix(current, "v") {
+		prefix = "v"
+	}
+
+	parts, ok := parseSemver(strings.TrimPrefix(current, prefix))
+	if !ok {
+		prefix = ""
+		parts = [3]int{}
+	}
+
+	switch level {
+	case LevelMajor:
+		parts = [3]int{parts[0] + 1, 0, 0}
+	case LevelMinor:
+		parts = [3]int{parts[0], parts[1] + 1, 0}
+	default:
+		parts[2]++
+	}
+
+	return fmt.Sprintf("%s%d.%d.%d", prefix, parts[0], parts[1], parts[2]), nil
+}
+
+// parseSemver parses MAJOR.MINOR.PATCH version without pre-release and build parts.
+func parseSemver(version string) ([3]int, bool) {
+	var parts [3]int
+	fields := strings.Split(version, ".")
+	if len(fields) != 3 {
+		return parts, false
+	}
+
+	for i, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 0 || f != strconv.Itoa(n) {
+			return parts, false
+		}
+		parts[i] = n
+	}
+
+	return parts, true
+}
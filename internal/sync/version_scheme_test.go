@@ -0,0 +1,54 @@
+package sync
+
+import "testing"
+
+func TestSemverScheme(t *testing.T) {
+	tests := []struct {
+		current  string
+		level    VersionLevel
+		expected string
+	}{
+		{"1.2.3", "", "1.2.4"},
+		{"1.2.3", LevelPatch, "1.2.4"},
+		{"1.2.3", LevelMinor, "1.3.0"},
+		{"1.2.3", LevelMajor, "2.0.0"},
+		{"v0.9.9", LevelMinor, "v0.10.0"},
+		{"", LevelPatch, "0.0.1"},
+		{"abc123def4567", LevelMinor, "0.1.0"},
+		{"1.02.3", LevelMajor, "1.0.0"},
+	}
+
+	scheme, err := GetVersionScheme(SemverVersioning)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		version, err := scheme.Next(tt.current, "0123456789abc", tt.level)
+		if err != nil {
+			t.Errorf("%q %s: unexpected error %v", tt.current, tt.level, err)
+			continue
+		}
+		if version != tt.expected {
+			t.Errorf("%q %s: expected %q, got %q", tt.current, tt.level, tt.expected, version)
+		}
+	}
+
+	if _, err = scheme.Next("1.2.3", "0123456789abc", "huge"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
+
+func TestGetVersionScheme(t *testing.T) {
+	scheme, err := GetVersionScheme("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	version, err := scheme.Next("1.2.3", "0123456789abc", LevelMajor)
+	if err != nil || version != "0123456789abc" {
+		t.Errorf("default scheme: expected hash version, got %q, %v", version, err)
+	}
+
+	if _, err = GetVersionScheme("calver"); err == nil {
+		t.Error("expected error for unknown scheme")
+	}
+}
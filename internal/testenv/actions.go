@@ -0,0 +1,70 @@
+package testenv
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/launchrctl/launchr"
+
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+)
+
+// Components of the platform created by [NewPlatform], each one depending on the next.
+const (
+	Dashboards = "interaction.applications.dashboards"
+	Auth       = "foundation.applications.auth"
+	Postgres   = "foundation.services.postgres"
+	// Chassis is the chassis of the interaction layer playbook.
+	Chassis = "platform.interaction.observability"
+)
+
+// Action is an action run by [Run].
+type Action interface {
+	SetLogger(*launchr.Logger)
+	SetTerm(*launchr.Terminal)
+	Execute() error
+}
+
+// Run executes the action with the default logger and terminal.
+func Run(t testing.TB, a Action) error {
+	t.Helper()
+	a.SetLogger(launchr.Log())
+	a.SetTerm(launchr.Term())
+	return a.Execute()
+}
+
+// NewPlatform creates a platform with a few components, a layer playbook and initial commit.
+func NewPlatform(t testing.TB) *Platform {
+	t.Helper()
+	p := New(t)
+	p.AddComponent(Postgres, "aaa1111111111")
+	p.AddComponent(Auth, "aaa1111111111", Postgres)
+	p.AddComponent(Dashboards, "aaa1111111111", Auth)
+	p.AddPlaybook("interaction", playbook.Play{Hosts: Chassis})
+	p.Commit("initial platform", DeveloperName)
+
+	return p
+}
+
+// QuietStreams returns streams without input and discarding output, for actions asking for confirmation.
+func QuietStreams() launchr.Streams {
+	return launchr.NewBasicStreams(io.NopCloser(strings.NewReader("")), io.Discard, io.Discard)
+}
+
+// BuildVersions returns versions of the components in the build directory.
+func BuildVersions(t testing.TB, buildDir string, names ...string) []string {
+	t.Helper()
+	components, err := component.LoadFromPath(buildDir)
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+
+	var versions []string
+	for _, name := range names {
+		versions = append(versions, components.Find(name).Version)
+	}
+
+	return versions
+}
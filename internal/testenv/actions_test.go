@@ -0,0 +1,1882 @@
+package testenv_test
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/launchrctl/launchr"
+	"github.com/plasmash/plasmactl-model/pkg/model"
+	vault "github.com/sosedoff/ansible-vault-go"
+
+	"github.com/plasmash/plasmactl-component/actions/attach"
+	"github.com/plasmash/plasmactl-component/actions/bump"
+	"github.com/plasmash/plasmactl-component/actions/configure"
+	"github.com/plasmash/plasmactl-component/actions/convertkind"
+	"github.com/plasmash/plasmactl-component/actions/create"
+	"github.com/plasmash/plasmactl-component/actions/depend"
+	"github.com/plasmash/plasmactl-component/actions/detach"
+	"github.com/plasmash/plasmactl-component/actions/doctor"
+	"github.com/plasmash/plasmactl-component/actions/history"
+	"github.com/plasmash/plasmactl-component/actions/lint"
+	"github.com/plasmash/plasmactl-component/actions/list"
+	"github.com/plasmash/plasmactl-component/actions/setversion"
+	"github.com/plasmash/plasmactl-component/actions/show"
+	"github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/actions/variables"
+	"github.com/plasmash/plasmactl-component/actions/verify"
+	"github.com/plasmash/plasmactl-component/internal/architecture"
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	"github.com/plasmash/plasmactl-component/internal/testenv"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/extension"
+	"github.com/plasmash/plasmactl-component/pkg/warning"
+)
+
+const (
+	dashboards = testenv.Dashboards
+	auth       = testenv.Auth
+	postgres   = testenv.Postgres
+	chassis    = testenv.Chassis
+)
+
+var (
+	run         = testenv.Run
+	newPlatform = testenv.NewPlatform
+)
+
+func TestAttachDetach(t *testing.T) {
+	p := newPlatform(t)
+
+	att := &attach.Attach{Component: dashboards, Chassis: chassis, Source: "."}
+	if err := run(t, att); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+
+	if !strings.Contains(p.ReadFile(filepath.Join("interaction", "interaction.yaml")), dashboards) {
+		t.Fatal("expected component in playbook after attach")
+	}
+
+	att = &attach.Attach{Component: dashboards, Chassis: chassis, Source: "."}
+	if err := run(t, att); err != nil {
+		t.Fatalf("attach again: %v", err)
+	}
+	res := att.Result().(*attach.AttachResult)
+	if res.Attached {
+		t.Error("expected second attach to be a no-op")
+	}
+	if len(res.Warnings) != 1 || res.Warnings[0].Code != warning.Unchanged || res.Warnings[0].Subject != dashboards {
+		t.Errorf("expected an unchanged warning about %s, got %+v", dashboards, res.Warnings)
+	}
+
+	det := &detach.Detach{Component: dashboards, Chassis: chassis, Source: "."}
+	if err := run(t, det); err != nil {
+		t.Fatalf("detach: %v", err)
+	}
+
+	if strings.Contains(p.ReadFile(filepath.Join("interaction", "interaction.yaml")), dashboards) {
+		t.Error("expected component removed from playbook after detach")
+	}
+}
+
+func TestDependOperations(t *testing.T) {
+	p := testenv.New(t)
+	p.AddComponent(postgres, "aaa1111111111")
+	p.AddComponent(auth, "aaa1111111111")
+	p.AddComponent(dashboards, "aaa1111111111")
+
+	dep := &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{postgres}, Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend add: %v", err)
+	}
+
+	if !strings.Contains(p.ReadFile(filepath.Join("foundation", "applications", "auth", "tasks", "dependencies.yaml")), postgres) {
+		t.Error("expected dependency written to dependencies.yaml")
+	}
+
+	matrix := architecture.Matrix{{From: "foundation", Deny: []string{"interaction"}}}
+	dep = &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{dashboards}, Depth: 1, Architecture: matrix}
+	if err := run(t, dep); err == nil {
+		t.Error("expected denied dependency to be rejected")
+	}
+}
+
+func TestDependValidation(t *testing.T) {
+	p := testenv.New(t)
+	p.AddComponent(postgres, "aaa1111111111")
+	p.AddComponent(auth, "aaa1111111111", postgres)
+	p.AddComponent(dashboards, "aaa1111111111", auth)
+
+	postgresDeps := filepath.Join("foundation", "services", "postgres", "tasks", "dependencies.yaml")
+	dep := &depend.Depend{DomainDir: ".", Source: ".", Target: postgres, Operations: []string{dashboards}, Depth: 1}
+	if err := run(t, dep); err == nil || !strings.Contains(err.Error(), postgres+" → "+dashboards+" → "+auth+" → "+postgres) {
+		t.Errorf("expected dependency closing a cycle to be rejected with the cycle, got %v", err)
+	}
+	if err := run(t, &depend.Depend{DomainDir: ".", Source: ".", Target: postgres, Operations: []string{"foundation.services.missing"}, Depth: 1}); err == nil {
+		t.Error("expected unknown dependency to be rejected")
+	}
+	if _, err := os.Stat(postgresDeps); err == nil {
+		t.Error("expected rejected dependencies not to be written")
+	}
+
+	dep = &depend.Depend{DomainDir: ".", Source: ".", Target: postgres, Operations: []string{dashboards}, Depth: 1, Force: true}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend add with --force: %v", err)
+	}
+	if w := dep.Result().(*depend.DependResult).Warnings; len(w) == 0 || w[len(w)-1].Code != warning.Cycle {
+		t.Errorf("expected cycle warning with --force, got %v", w)
+	}
+	if !strings.Contains(p.ReadFile(postgresDeps), dashboards) {
+		t.Error("expected forced dependency written to dependencies.yaml")
+	}
+}
+
+func TestDependFromFile(t *testing.T) {
+	p := testenv.New(t)
+	keycloak := "foundation.services.keycloak"
+	for _, name := range []string{postgres, keycloak, auth, dashboards} {
+		p.AddComponent(name, "aaa1111111111")
+	}
+
+	depsFile := func(mrn string) string {
+		return filepath.Join(strings.ReplaceAll(mrn, ".", string(filepath.Separator)), "tasks", "dependencies.yaml")
+	}
+
+	p.WriteFile("ops.yaml", `
+- targets: [`+auth+`, `+dashboards+`]
+  operations: [`+postgres+`]
+- target: `+dashboards+`
+  operations: [`+postgres+`/`+keycloak+`]
+`)
+	dep := &depend.Depend{DomainDir: ".", FromFile: "ops.yaml", Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend from file: %v", err)
+	}
+	if !strings.Contains(p.ReadFile(depsFile(auth)), postgres) {
+		t.Errorf("expected %s to depend on %s", auth, postgres)
+	}
+	if deps := p.ReadFile(depsFile(dashboards)); !strings.Contains(deps, keycloak) || strings.Contains(deps, postgres) {
+		t.Errorf("expected %s dependency replaced, got %s", dashboards, deps)
+	}
+	if ops := dep.Result().(*depend.DependResult).Operations; len(ops) != 3 || ops[0].Target != auth || ops[2].Target != dashboards {
+		t.Errorf("expected operations of each target, got %+v", ops)
+	}
+
+	// An operation failing leaves all dependencies files untouched.
+	matrix := architecture.Matrix{{From: "foundation", Deny: []string{"interaction"}}}
+	ops := "- target: " + postgres + "\n  operations: [" + keycloak + "]\n- target: " + auth + "\n  operations: [" + dashboards + "]\n"
+	in := launchr.NewBasicStreams(io.NopCloser(strings.NewReader(ops)), io.Discard, io.Discard)
+	if err := run(t, &depend.Depend{DomainDir: ".", FromFile: "-", Streams: in, Depth: 1, Architecture: matrix}); err == nil || !strings.Contains(err.Error(), "nothing was written") {
+		t.Errorf("expected denied dependency to fail the whole file, got %v", err)
+	}
+	if _, err := os.Stat(depsFile(postgres)); err == nil {
+		t.Error("expected no dependencies written when an operation fails")
+	}
+
+	p.WriteFile("typo.yaml", "- target: "+auth+"\n  operation: ["+keycloak+"]\n")
+	if err := run(t, &depend.Depend{DomainDir: ".", FromFile: "typo.yaml", Depth: 1}); err == nil {
+		t.Error("expected unknown field of the operations file to be rejected")
+	}
+}
+
+func TestDependReverseEdit(t *testing.T) {
+	p := testenv.New(t)
+	keycloak := "foundation.services.keycloak"
+	for _, name := range []string{postgres, keycloak, auth, dashboards} {
+		p.AddComponent(name, "aaa1111111111")
+	}
+	p.WriteFile("ops.yaml", "- target: "+auth+"\n  operations: ["+postgres+"]\n- target: "+dashboards+"\n  operations: ["+auth+", "+postgres+"]\n")
+	if err := run(t, &depend.Depend{DomainDir: ".", FromFile: "ops.yaml", Depth: 1}); err != nil {
+		t.Fatalf("depend from file: %v", err)
+	}
+
+	depsFile := func(mrn string) string {
+		return filepath.Join(strings.ReplaceAll(mrn, ".", string(filepath.Separator)), "tasks", "dependencies.yaml")
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: postgres, ReverseEdit: true, Depth: 1}); err == nil {
+		t.Error("expected --reverse-edit without operations to be rejected")
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: postgres, Operations: []string{auth + "/" + keycloak}, ReverseEdit: true, Depth: 1}); err != nil {
+		t.Fatalf("depend --reverse-edit: %v", err)
+	}
+	if strings.Contains(p.ReadFile(depsFile(auth)), postgres) || !strings.Contains(p.ReadFile(depsFile(keycloak)), postgres) {
+		t.Errorf("expected %s moved from %s to %s", postgres, auth, keycloak)
+	}
+
+	dep := &depend.Depend{DomainDir: ".", Target: postgres, RemoveFromAll: true, Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend --remove-from-all: %v", err)
+	}
+	var targets []string
+	for _, op := range dep.Result().(*depend.DependResult).Operations {
+		if op.Applied && op.Type == "remove" && op.Dep == postgres {
+			targets = append(targets, op.Target)
+		}
+	}
+	if strings.Join(targets, ",") != keycloak+","+dashboards {
+		t.Errorf("expected %s removed from %s and %s, got %v", postgres, keycloak, dashboards, targets)
+	}
+	for _, name := range []string{keycloak, dashboards} {
+		if strings.Contains(p.ReadFile(depsFile(name)), postgres) {
+			t.Errorf("expected %s removed from %s", postgres, name)
+		}
+	}
+	if !strings.Contains(p.ReadFile(depsFile(dashboards)), auth) {
+		t.Errorf("expected other dependencies of %s kept", dashboards)
+	}
+
+	dep = &depend.Depend{DomainDir: ".", Target: postgres, RemoveFromAll: true, Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend --remove-from-all without dependents: %v", err)
+	}
+	if res := dep.Result().(*depend.DependResult); len(res.Operations) != 0 || len(res.Warnings) != 1 {
+		t.Errorf("expected a warning and no operation without dependents, got %+v", res)
+	}
+}
+
+func TestDependConstraints(t *testing.T) {
+	p := testenv.New(t)
+	keycloak := "foundation.services.keycloak"
+	for _, name := range []string{postgres, keycloak, auth} {
+		p.AddComponent(name, "aaa1111111111")
+	}
+	authDeps := filepath.Join("foundation", "applications", "auth", "tasks", "dependencies.yaml")
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{postgres + "@>=1.2.0, <2.0.0", keycloak}, Depth: 1}); err != nil {
+		t.Fatalf("depend add with constraint: %v", err)
+	}
+	if deps := p.ReadFile(authDeps); !strings.Contains(deps, postgres+": '>=1.2.0, <2.0.0'") || !strings.Contains(deps, "- "+keycloak+"\n") {
+		t.Errorf("expected constrained dependency written, got:\n%s", deps)
+	}
+
+	dep := &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{postgres + "@>=1.3.0"}, Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend change constraint: %v", err)
+	}
+	if ops := dep.Result().(*depend.DependResult).Operations; len(ops) != 1 || !ops[0].Applied || ops[0].Constraint != ">=1.3.0" {
+		t.Errorf("expected constraint changed, got %+v", ops)
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{postgres + "@=>1.3.0"}, Depth: 1}); err == nil {
+		t.Error("expected invalid constraint to be rejected")
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{postgres + "@"}, Depth: 1}); err != nil {
+		t.Fatalf("depend clear constraint: %v", err)
+	}
+	if deps := p.ReadFile(authDeps); strings.Contains(deps, ">=") || !strings.Contains(deps, "- "+postgres+"\n") {
+		t.Errorf("expected constraint cleared, got:\n%s", deps)
+	}
+}
+
+func TestDependCheckCycles(t *testing.T) {
+	p := testenv.New(t)
+	p.AddComponent(postgres, "aaa1111111111")
+	p.AddComponent(auth, "aaa1111111111", postgres)
+	p.AddComponent(dashboards, "aaa1111111111", auth)
+
+	dep := &depend.Depend{DomainDir: ".", Source: ".", CheckCycles: true, Depth: 1}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("check cycles of acyclic graph: %v", err)
+	}
+
+	p.AddComponent(postgres, "aaa1111111111", dashboards)
+	dep = &depend.Depend{DomainDir: ".", Source: ".", CheckCycles: true, Depth: 1}
+	if err := run(t, dep); err == nil {
+		t.Fatal("expected cycle to fail the check")
+	}
+
+	cycles := dep.Result().(*depend.DependResult).Cycles
+	if len(cycles) != 1 || len(cycles[0]) != 3 {
+		t.Fatalf("expected one cycle of 3 dependencies, got %v", cycles)
+	}
+	if chain := cycles[0].String(); chain != auth+" → "+postgres+" → "+dashboards+" → "+auth {
+		t.Errorf("unexpected cycle %s", chain)
+	}
+	if e := cycles[0][1]; e.File != filepath.Join("foundation", "services", "postgres", "tasks", "dependencies.yaml") || e.Line != 2 {
+		t.Errorf("expected postgres dependency declared in its dependencies, got %s:%d", e.File, e.Line)
+	}
+}
+
+func TestDependWhy(t *testing.T) {
+	p := testenv.New(t)
+	p.AddComponent(postgres, "aaa1111111111")
+	p.AddComponent(auth, "aaa1111111111", postgres)
+	p.AddComponent(dashboards, "aaa1111111111", auth, postgres)
+	p.Commit("initial platform", testenv.DeveloperName)
+
+	paths := func(target, other string) []string {
+		t.Helper()
+		dep := &depend.Depend{DomainDir: ".", Target: target, Why: other, Ref: "HEAD"}
+		if err := run(t, dep); err != nil {
+			t.Fatalf("depend --why: %v", err)
+		}
+		var chains []string
+		for _, path := range dep.Result().(*depend.DependResult).Paths {
+			chain := path[0].From
+			for _, e := range path {
+				chain += " " + e.Type + " " + e.To
+			}
+			chains = append(chains, chain)
+		}
+		return chains
+	}
+
+	expected := []string{
+		dashboards + " requires " + postgres,
+		dashboards + " requires " + auth + " requires " + postgres,
+	}
+	if chains := paths(dashboards, postgres); strings.Join(chains, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected paths %v, got %v", expected, chains)
+	}
+	if chains := paths(postgres, auth); len(chains) != 1 || chains[0] != auth+" requires "+postgres {
+		t.Errorf("expected reverse path from %s, got %v", auth, chains)
+	}
+
+	p.AddComponent("foundation.services.keycloak", "aaa1111111111")
+	p.Commit("add keycloak", testenv.DeveloperName)
+	if chains := paths(dashboards, "foundation.services.keycloak"); len(chains) != 0 {
+		t.Errorf("expected no path to an unrelated component, got %v", chains)
+	}
+}
+
+func TestDependFormat(t *testing.T) {
+	p := testenv.New(t)
+	p.AddComponent(postgres, "aaa1111111111")
+	p.AddComponent(auth, "aaa1111111111", postgres)
+	p.AddComponent(dashboards, "aaa1111111111", auth, postgres)
+	p.AddComponent("foundation.services.keycloak", "aaa1111111111")
+	p.Commit("initial platform", testenv.DeveloperName)
+
+	edges := func(dep *depend.Depend) []string {
+		t.Helper()
+		if err := run(t, dep); err != nil {
+			t.Fatalf("depend --format %s: %v", dep.Format, err)
+		}
+		var keys []string
+		for _, e := range dep.Result().(*depend.DependResult).Edges {
+			keys = append(keys, e.From+" "+e.Type+" "+e.To)
+		}
+		return keys
+	}
+
+	expected := strings.Join([]string{
+		auth + " requires " + postgres,
+		dashboards + " requires " + auth,
+		dashboards + " requires " + postgres,
+	}, ",")
+	for _, format := range []string{depend.FormatDot, depend.FormatMermaid, depend.FormatJSON} {
+		if got := edges(&depend.Depend{DomainDir: ".", Target: dashboards, Depth: -1, Ref: "HEAD", Format: format}); strings.Join(got, ",") != expected {
+			t.Errorf("expected %s edges %s, got %v", format, expected, got)
+		}
+	}
+	if got := edges(&depend.Depend{DomainDir: ".", Target: postgres, Depth: 1, Reverse: true, Ref: "HEAD", Format: depend.FormatDot}); strings.Join(got, ",") != expected {
+		t.Errorf("expected reverse edges %s, got %v", expected, got)
+	}
+	if got := edges(&depend.Depend{DomainDir: ".", Target: "foundation.services.keycloak", Depth: -1, Ref: "HEAD", Format: depend.FormatJSON}); len(got) != 0 {
+		t.Errorf("expected no edges of an isolated component, got %v", got)
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: dashboards, Ref: "HEAD", Format: "svg"}); err == nil {
+		t.Error("expected unknown format to be rejected")
+	}
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: dashboards, Ref: "HEAD", Tree: true, Format: depend.FormatDot}); err == nil {
+		t.Error("expected --format with --tree to be rejected")
+	}
+}
+
+func TestBumpAndLintManualVersions(t *testing.T) {
+	p := newPlatform(t)
+
+	b := &bump.Bump{}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	head := p.HeadCommit()
+	if head.Author.Name != repository.Author {
+		t.Fatalf("expected bump commit, got author %q", head.Author.Name)
+	}
+
+	l := &lint.Lint{Source: ".", ManualVersions: true}
+	if err := run(t, l); err != nil {
+		t.Fatalf("lint after bump: %v", err)
+	}
+
+	p.SetVersion(auth, "manual0000000")
+	p.Commit("set version by hand", testenv.DeveloperName)
+
+	l = &lint.Lint{Source: ".", ManualVersions: true}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report manual version edit")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 || issues[0].Subject != auth {
+		t.Errorf("expected single issue for %s, got %+v", auth, issues)
+	}
+}
+
+func TestBumpSemverScheme(t *testing.T) {
+	p := newPlatform(t)
+
+	b := &bump.Bump{Scheme: "semver", Level: "minor"}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	for _, c := range b.Result().(*bump.BumpResult).Components {
+		if c.NewVersion != "0.1.0" || c.Scheme != "semver" {
+			t.Errorf("expected %s bumped to semver 0.1.0, got %+v", c.Name, c)
+		}
+	}
+
+	meta := filepath.Join("foundation", "applications", "auth", "meta", "plasma.yaml")
+	if !strings.Contains(p.ReadFile(meta), "versioning: semver") {
+		t.Fatalf("expected scheme stored in meta, got\n%s", p.ReadFile(meta))
+	}
+
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change auth", testenv.DeveloperName)
+
+	b = &bump.Bump{}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump with stored scheme: %v", err)
+	}
+
+	bumped := b.Result().(*bump.BumpResult).Components
+	if len(bumped) != 1 || bumped[0].Name != auth || bumped[0].NewVersion != "0.1.1" {
+		t.Errorf("expected %s bumped to 0.1.1, got %+v", auth, bumped)
+	}
+}
+
+func TestBumpIgnoreFile(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	p.WriteFile(bump.BumpIgnoreFile, "# documentation\n**/docs/**\n*.md\n")
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "docs", "usage.txt"), "usage\n")
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "CHANGELOG.md"), "# Changelog\n")
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	commit := p.Commit("document components", testenv.DeveloperName)
+
+	b := &bump.Bump{}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump with ignore file: %v", err)
+	}
+
+	res := b.Result().(*bump.BumpResult)
+	bumped := res.Components
+	if len(bumped) != 1 || bumped[0].Name != dashboards {
+		t.Fatalf("expected only %s bumped, got %+v", dashboards, bumped)
+	}
+	if bumped[0].Commit != commit || len(bumped[0].Files) != 1 {
+		t.Errorf("expected bump triggered by tasks of commit %s, got %+v", commit, bumped[0])
+	}
+
+	reasons := make(map[string]string)
+	for _, s := range res.Skipped {
+		reasons[s.File] = s.Reason
+	}
+	if reasons[bump.BumpIgnoreFile] != "no-component" || reasons["foundation/services/postgres/CHANGELOG.md"] != "unversioned" {
+		t.Errorf("expected ignored and outside files skipped, got %+v", res.Skipped)
+	}
+}
+
+func TestBumpStaged(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	head := p.HeadCommit().Hash
+
+	authTasks := filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml")
+	p.WriteFile(authTasks, "---\n- debug: {}\n")
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Stage(authTasks)
+
+	b := &bump.Bump{Staged: true}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump staged: %v", err)
+	}
+
+	bumped := b.Result().(*bump.BumpResult).Components
+	if len(bumped) != 1 || bumped[0].Name != auth {
+		t.Fatalf("expected only %s bumped, got %+v", auth, bumped)
+	}
+	if p.HeadCommit().Hash != head {
+		t.Error("expected no commit in staged mode")
+	}
+
+	w, err := p.Repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := w.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := filepath.ToSlash(filepath.Join("foundation", "applications", "auth", "meta", "plasma.yaml"))
+	if s := status.File(meta); s.Staging != git.Modified || s.Worktree != git.Unmodified {
+		t.Errorf("expected %s staged, got %c%c", meta, s.Staging, s.Worktree)
+	}
+}
+
+func TestBumpAmend(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	commit := p.Commit("change postgres", testenv.DeveloperName)
+
+	b := &bump.Bump{}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	first := b.Result().(*bump.BumpResult).Components
+	if len(first) != 1 || first[0].Name != postgres {
+		t.Fatalf("expected %s bumped, got %+v", postgres, first)
+	}
+
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "tasks", "main.yaml"), "---\n- debug: {}\n")
+
+	b = &bump.Bump{Amend: true}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump amend: %v", err)
+	}
+
+	versions := make(map[string]bump.BumpedComponent)
+	for _, c := range b.Result().(*bump.BumpResult).Components {
+		versions[c.Name] = c
+	}
+	if c := versions[postgres]; c.OldVersion != first[0].OldVersion || c.NewVersion != commit[:13] || c.Commit != commit {
+		t.Errorf("expected %s bumped once from %s to %s, got %+v", postgres, first[0].OldVersion, commit[:13], c)
+	}
+	if c, ok := versions[dashboards]; !ok || c.Commit != "" || c.NewVersion == c.OldVersion {
+		t.Errorf("expected uncommitted %s bumped, got %+v", dashboards, c)
+	}
+
+	head := p.HeadCommit()
+	if head.Author.Name != repository.Author || head.NumParents() != 1 || head.ParentHashes[0].String() != commit {
+		t.Errorf("expected a single bump commit on top of %s, got %s by %s", commit, head.Hash, head.Author.Name)
+	}
+	if meta := p.ReadFile(filepath.Join("interaction", "applications", "dashboards", "meta", "plasma.yaml")); !strings.Contains(meta, versions[dashboards].NewVersion) {
+		t.Errorf("expected %s meta updated, got %q", dashboards, meta)
+	}
+
+	w, err := p.Repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, errStatus := w.Status(); errStatus != nil || !status.IsClean() {
+		t.Errorf("expected changes amended to the bump commit, got %v (%v)", status, errStatus)
+	}
+
+	if err = run(t, &bump.Bump{Amend: true, Last: true}); err == nil {
+		t.Error("expected amend to be rejected with last commit")
+	}
+}
+
+func TestBumpRename(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	w, err := p.Repo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	const postgresql = "foundation.services.postgresql"
+	for _, file := range []string{"meta/plasma.yaml", "tasks/main.yaml"} {
+		if err = os.MkdirAll(filepath.Join(p.Dir, "foundation", "services", "postgresql", filepath.Dir(file)), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = w.Move("foundation/services/postgres/"+file, "foundation/services/postgresql/"+file); err != nil {
+			t.Fatalf("move %s: %v", file, err)
+		}
+	}
+	commit := p.Commit("rename postgres", testenv.DeveloperName)
+
+	b := &bump.Bump{}
+	if err = run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	result := b.Result().(*bump.BumpResult)
+	if len(result.Components) != 1 || result.Components[0].Name != postgresql || result.Components[0].NewVersion != commit[:13] {
+		t.Errorf("expected %s bumped to %s, got %+v", postgresql, commit[:13], result.Components)
+	}
+	if len(result.Renamed) != 1 || result.Renamed[0].From != postgres || result.Renamed[0].To != postgresql || result.Renamed[0].Commit != commit {
+		t.Errorf("expected %s renamed to %s in %s, got %+v", postgres, postgresql, commit, result.Renamed)
+	}
+}
+
+func TestBumpCascade(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	commit := p.Commit("change postgres", testenv.DeveloperName)
+
+	b := &bump.Bump{Cascade: true}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump cascade: %v", err)
+	}
+
+	versions := make(map[string]bump.BumpedComponent)
+	for _, c := range b.Result().(*bump.BumpResult).Components {
+		versions[c.Name] = c
+	}
+	if versions[postgres].NewVersion != commit[:13] {
+		t.Fatalf("expected %s bumped to %s, got %+v", postgres, commit[:13], versions[postgres])
+	}
+	for _, name := range []string{auth, dashboards} {
+		c := versions[name]
+		if c.PropagatedFrom != postgres || !strings.HasSuffix(c.NewVersion, "-"+commit[:13]) {
+			t.Errorf("expected %s cascaded from %s, got %+v", name, postgres, c)
+		}
+	}
+
+	if head := p.HeadCommit(); head.Author.Name != repository.Author {
+		t.Errorf("expected cascade in the bump commit, got author %q", head.Author.Name)
+	}
+}
+
+func TestBumpRunGranularity(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change postgres", testenv.DeveloperName)
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	latest := p.Commit("change auth", testenv.DeveloperName)
+
+	b := &bump.Bump{Granularity: bump.GranularityRun}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump run: %v", err)
+	}
+
+	bumped := b.Result().(*bump.BumpResult).Components
+	if len(bumped) != 2 {
+		t.Fatalf("expected 2 bumped components, got %+v", bumped)
+	}
+	for _, c := range bumped {
+		if c.NewVersion != latest[:13] {
+			t.Errorf("expected %s bumped to the run version %s, got %s", c.Name, latest[:13], c.NewVersion)
+		}
+	}
+
+	if err := run(t, &bump.Bump{Granularity: "squash"}); err == nil {
+		t.Error("expected unknown granularity to fail")
+	}
+}
+
+func TestBumpFrozen(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	authMeta := filepath.Join("foundation", "applications", "auth", "meta", "plasma.yaml")
+	dashboardsMeta := filepath.Join("interaction", "applications", "dashboards", "meta", "plasma.yaml")
+	p.WriteFile(authMeta, p.ReadFile(authMeta)+"  frozen: true\n")
+	p.WriteFile(dashboardsMeta, p.ReadFile(dashboardsMeta)+"  propagation: manual\n")
+	p.Commit("freeze auth and dashboards", testenv.DeveloperName)
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change postgres and auth", testenv.DeveloperName)
+
+	b := &bump.Bump{Since: "HEAD~1", Cascade: true}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	result := b.Result().(*bump.BumpResult)
+	if len(result.Components) != 1 || result.Components[0].Name != postgres {
+		t.Errorf("expected only %s bumped, frozen ones skipped, got %+v", postgres, result.Components)
+	}
+
+	frozen := false
+	for _, s := range result.Skipped {
+		if s.Reason == "frozen" && strings.HasPrefix(s.File, filepath.Join("foundation", "applications", "auth")) {
+			frozen = true
+		}
+	}
+	if !frozen {
+		t.Errorf("expected changed files of %s skipped as frozen, got %+v", auth, result.Skipped)
+	}
+}
+
+func TestBumpPropagationDisabled(t *testing.T) {
+	p := newPlatform(t)
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	dashboardsMeta := filepath.Join("interaction", "applications", "dashboards", "meta", "plasma.yaml")
+	p.WriteFile(dashboardsMeta, p.ReadFile(dashboardsMeta)+"  propagation: false\n")
+	p.Commit("pin dashboards", testenv.DeveloperName)
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change postgres", testenv.DeveloperName)
+
+	b := &bump.Bump{Since: "HEAD~1", Cascade: true}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	var bumped []string
+	for _, c := range b.Result().(*bump.BumpResult).Components {
+		bumped = append(bumped, c.Name)
+	}
+	if strings.Join(bumped, ",") != postgres+","+auth {
+		t.Errorf("expected %s bumped and %s cascaded only, got %v", postgres, auth, bumped)
+	}
+
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change dashboards", testenv.DeveloperName)
+
+	b = &bump.Bump{}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	if result := b.Result().(*bump.BumpResult); len(result.Components) != 1 || result.Components[0].Name != dashboards {
+		t.Errorf("expected own changes of %s bumped, got %+v", dashboards, result.Components)
+	}
+}
+
+func TestBumpHooks(t *testing.T) {
+	p := newPlatform(t)
+	post := filepath.Join(t.TempDir(), "post.txt")
+
+	b := &bump.Bump{Hooks: bump.Hooks{
+		Pre:  []bump.Hook{{Run: `echo '{{ join .Names " " }}' > BUMPED`, Add: []string{"BUMPED"}}},
+		Post: []bump.Hook{{Run: "echo {{ .Commit }} > " + post}},
+	}}
+	if err := run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	head := p.HeadCommit()
+	f, err := head.File("BUMPED")
+	if err != nil {
+		t.Fatalf("expected pre hook output in the bump commit: %v", err)
+	}
+	content, _ := f.Contents()
+	for _, name := range []string{auth, postgres, dashboards} {
+		if !strings.Contains(content, name) {
+			t.Errorf("expected %s in pre hook output, got %q", name, content)
+		}
+	}
+
+	data, err := os.ReadFile(post)
+	if err != nil {
+		t.Fatalf("expected post hook output: %v", err)
+	}
+	if strings.TrimSpace(string(data)) != head.Hash.String() {
+		t.Errorf("expected bump commit %s in post hook, got %q", head.Hash, data)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.Commit("change auth", testenv.DeveloperName)
+
+	b = &bump.Bump{Hooks: bump.Hooks{Pre: []bump.Hook{{Run: "exit 3"}}}}
+	if err = run(t, b); err == nil {
+		t.Fatal("expected failing pre hook to stop the bump")
+	}
+	if head = p.HeadCommit(); head.Author.Name == repository.Author {
+		t.Error("expected no bump commit after failing pre hook")
+	}
+}
+
+func TestLintArchitecture(t *testing.T) {
+	p := newPlatform(t)
+	p.AddComponent("foundation.services.redis", "aaa1111111111", dashboards)
+	p.Commit("add wrong dependency", testenv.DeveloperName)
+
+	matrix := architecture.Matrix{{From: "foundation", Deny: []string{"interaction"}}}
+	l := &lint.Lint{Source: ".", Architecture: true, Matrix: matrix}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report architecture violation")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 || issues[0].Subject != "foundation.services.redis" {
+		t.Errorf("expected single violation for foundation.services.redis, got %+v", issues)
+	}
+}
+
+func TestLintDocs(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "README.md"), "# Dashboards\n\n## Usage\n")
+
+	rules := lint.DocsRules{"applications": {README: true, Sections: []string{"Usage", "Configuration"}}}
+	l := &lint.Lint{Source: ".", Docs: true, DocsRules: rules}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report missing docs")
+	}
+
+	var messages []string
+	for _, issue := range l.Result().(*lint.LintResult).Issues {
+		messages = append(messages, issue.Subject+": "+issue.Message)
+	}
+	expected := []string{
+		auth + ": missing README.md",
+		dashboards + `: README.md has no "Configuration" section`,
+	}
+	if strings.Join(messages, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, got %v", expected, messages)
+	}
+}
+
+func TestLintSecrets(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "defaults", "main.yaml"),
+		"auth_db_user: auth\nauth_db_password: hunter22\nauth_admin_password: \"{{ vault_auth_admin_password }}\"\n")
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "defaults", "vault.yaml"), "vault_auth_admin_password: hunter22\n")
+
+	l := &lint.Lint{Source: ".", Secrets: true}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report secrets")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Subject != auth || issues[0].Line != 2 {
+		t.Errorf("expected issue in %s at line 2, got %+v", auth, issues[0])
+	}
+}
+
+func TestLintTemplates(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "templates", "postgresql.conf.j2"),
+		"port = {{ postgres_port | default(5432) }}\n{% if postgres_ssl %}\nssl = on\n")
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "templates", "auth.json.j2"),
+		"{{ auth_config | json_query('a') | to_json }}\n")
+
+	l := &lint.Lint{Source: ".", Templates: true, TemplateFilters: []string{"json_query"}}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report template errors")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Subject != postgres || issues[0].Line != 2 || issues[0].Column != 1 {
+		t.Errorf("expected unclosed block in %s at 2:1, got %+v", postgres, issues[0])
+	}
+}
+
+func TestLintTasks(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"),
+		"- include_role:\n    name: "+postgres+"\n- include_role:\n    name: foundation.services.redis\n")
+
+	l := &lint.Lint{Source: ".", Tasks: true}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report missing role")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 {
+		t.Fatalf("expected 1 issue, got %v", issues)
+	}
+	if issues[0].Subject != auth || issues[0].Line != 4 {
+		t.Errorf("expected missing role in %s at line 4, got %+v", auth, issues[0])
+	}
+}
+
+func TestLintBaselineAndSuppression(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("foundation", "applications", "auth", "tasks", "main.yaml"),
+		"- include_role:\n    name: foundation.services.redis\n")
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"),
+		"# lint:ignore tasks -- provided by the legacy package\n- include_role:\n    name: foundation.services.redis\n")
+
+	l := &lint.Lint{Source: ".", Tasks: true}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report missing role")
+	}
+	res := l.Result().(*lint.LintResult)
+	if res.Suppressed != 1 {
+		t.Fatalf("expected postgres issue suppressed inline, got %+v", res.Issues)
+	}
+
+	baseline := filepath.Join(t.TempDir(), "baseline.json")
+	l = &lint.Lint{Source: ".", Tasks: true, Baseline: baseline, WriteBaseline: true}
+	if err := run(t, l); err != nil {
+		t.Fatalf("write baseline: %v", err)
+	}
+
+	l = &lint.Lint{Source: ".", Tasks: true, Baseline: baseline}
+	if err := run(t, l); err != nil {
+		t.Fatalf("expected baselined issue not to fail: %v", err)
+	}
+
+	p.WriteFile(filepath.Join("interaction", "applications", "dashboards", "tasks", "main.yaml"),
+		"- include_role:\n    name: foundation.services.redis\n")
+	l = &lint.Lint{Source: ".", Tasks: true, Baseline: baseline}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected new issue to fail")
+	}
+
+	var unsuppressed []string
+	for _, issue := range l.Result().(*lint.LintResult).Issues {
+		if issue.Suppressed == "" {
+			unsuppressed = append(unsuppressed, issue.Subject)
+		}
+	}
+	if len(unsuppressed) != 1 || unsuppressed[0] != dashboards {
+		t.Errorf("expected only %s issue to be new, got %v", dashboards, unsuppressed)
+	}
+}
+
+func TestLintVersions(t *testing.T) {
+	p := newPlatform(t)
+	p.SetVersion(auth, "aaa1111111111-bbb2222222222-ccc3333333333")
+	p.Commit("propagate twice", testenv.DeveloperName)
+
+	l := &lint.Lint{Source: ".", Versions: true}
+	if err := run(t, l); err == nil {
+		t.Fatal("expected lint to report malformed version")
+	}
+
+	issues := l.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 || issues[0].Subject != auth {
+		t.Fatalf("expected single issue for %s, got %+v", auth, issues)
+	}
+
+	l = &lint.Lint{Source: ".", Versions: true, Fix: true}
+	if err := run(t, l); err != nil {
+		t.Fatalf("lint with fix: %v", err)
+	}
+
+	fixed, err := component.LoadFromPath(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := fixed.Find(auth).Version; v != "aaa1111111111-ccc3333333333" {
+		t.Errorf("expected normalized version, got %s", v)
+	}
+	if head := p.HeadCommit(); head.Author.Name != repository.Author {
+		t.Errorf("expected fix committed as bump, got author %q", head.Author.Name)
+	}
+}
+
+func TestConfigureChassisScope(t *testing.T) {
+	newPlatform(t)
+
+	set := &configure.Configure{Key: "grafana_port", Value: "3000", At: chassis}
+	if err := run(t, set); err != nil {
+		t.Fatalf("configure set: %v", err)
+	}
+
+	get := &configure.Configure{Key: "grafana_port", Get: true, At: chassis}
+	if err := run(t, get); err != nil {
+		t.Fatalf("configure get: %v", err)
+	}
+}
+
+func TestConfigureValidate(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile(filepath.Join("src", "interaction", "interaction.yaml"), "- hosts: "+chassis+"\n  roles:\n    - "+dashboards+"\n")
+	dir := filepath.Join("src", "interaction", "applications", "dashboards")
+	p.WriteFile(filepath.Join(dir, "templates", "grafana.ini.j2"),
+		"{# {{ commented }} #}\nport = {{ grafana_port | default(3000) }}\nurl = {{ grafana_url }}/{{ 'path' }}\n"+
+			"{% for user in grafana_users if user.enabled is defined %}{{ user.name | upper }}{% endfor %}\n"+
+			"{% if grafana_ldap is defined %}ldap = {{ grafana_ldap.host }}{% endif %}\n{{ lookup('env', 'HOME') }}\n")
+	p.WriteFile(filepath.Join(dir, "tasks", "main.yaml"),
+		"- register: rendered\n  ansible.builtin.template:\n    src: grafana.ini.j2\n    dest: \"{{ grafana_dir }}\"\n"+
+			"- debug:\n    msg: \"{{ rendered.changed }}\"\n")
+
+	validate := &configure.Configure{Validate: true, At: chassis}
+	if err := run(t, validate); err == nil {
+		t.Fatal("expected undefined variables")
+	}
+
+	var undefined []string
+	for _, u := range validate.Result().(*configure.ConfigureResult).Undefined {
+		undefined = append(undefined, u.Variable)
+	}
+	if expected := []string{"grafana_dir", "grafana_url", "grafana_users"}; !slices.Equal(undefined, expected) {
+		t.Errorf("expected undefined %v, got %v", expected, undefined)
+	}
+
+	p.WriteFile(filepath.Join("src", "interaction", "cfg", chassis, "vars.yaml"),
+		"grafana_dir: /etc/grafana\ngrafana_url: http://grafana\ngrafana_users: []\n")
+	if err := run(t, &configure.Configure{Validate: true, At: chassis}); err != nil {
+		t.Errorf("expected all variables defined: %v", err)
+	}
+}
+
+func TestConfigureScaffold(t *testing.T) {
+	p := newPlatform(t)
+	observability := "platform.observability.grafana"
+	p.WriteFile(filepath.Join("src", "interaction", "interaction.yaml"),
+		"- hosts: "+observability+"\n  roles:\n    - "+dashboards+"\n")
+
+	set := &configure.Configure{Key: "grafana_port", Value: "3000", At: observability + ".web"}
+	if err := run(t, set); err != nil {
+		t.Fatalf("configure set: %v", err)
+	}
+
+	dir := filepath.Join("src", "interaction", "cfg", observability+".web")
+	if res := set.Result().(*configure.ConfigureResult); res.Scaffolded != dir {
+		t.Fatalf("expected %s scaffolded in layer of attachments, got %q", dir, res.Scaffolded)
+	}
+
+	vars := p.ReadFile(filepath.Join(dir, "vars.yaml"))
+	if !strings.HasPrefix(vars, "# Variables of chassis section "+observability+".web (layer interaction).\n# Attached components: "+dashboards) {
+		t.Errorf("expected skeleton header, got:\n%s", vars)
+	}
+	if !strings.Contains(vars, "grafana_port: \"3000\"") {
+		t.Errorf("expected value set, got:\n%s", vars)
+	}
+
+	set = &configure.Configure{Key: "grafana_user", Value: "admin", At: observability + ".web"}
+	if err := run(t, set); err != nil {
+		t.Fatalf("configure set again: %v", err)
+	}
+	if vars = p.ReadFile(filepath.Join(dir, "vars.yaml")); !strings.HasPrefix(vars, "# Variables") || !strings.Contains(vars, "grafana_user: admin") {
+		t.Errorf("expected header kept and value added, got:\n%s", vars)
+	}
+}
+
+func TestConfigureRekey(t *testing.T) {
+	p := testenv.New(t)
+	vaultPath := filepath.Join("src", "foundation", "applications", "auth", "defaults", "vault.yaml")
+	p.WriteFile(vaultPath, "")
+	if err := vault.EncryptFile(vaultPath, "vault_auth_admin_password: hunter22\n", "old"); err != nil {
+		t.Fatalf("encrypt vault: %v", err)
+	}
+	newPassFile := "new-pass"
+	p.WriteFile(newPassFile, "new\n")
+	t.Setenv("TEST_VAULT_PASS", "wrong")
+
+	rekey := func(provider vaultpass.Provider) error {
+		return run(t, &configure.Configure{
+			Rekey: true, YesIAmSure: true,
+			VaultPassProvider:    provider,
+			NewVaultPassProvider: vaultpass.Provider{File: newPassFile},
+		})
+	}
+
+	if err := rekey(vaultpass.Provider{Env: "TEST_VAULT_PASS"}); err == nil {
+		t.Fatal("expected wrong current vault password to fail")
+	}
+	if _, err := vault.DecryptFile(vaultPath, "old"); err != nil {
+		t.Fatalf("expected vault untouched after failure: %v", err)
+	}
+
+	t.Setenv("TEST_VAULT_PASS", "old")
+	if err := rekey(vaultpass.Provider{Env: "TEST_VAULT_PASS"}); err != nil {
+		t.Fatalf("rekey: %v", err)
+	}
+	if content, err := vault.DecryptFile(vaultPath, "new"); err != nil || !strings.Contains(content, "hunter22") {
+		t.Errorf("expected vault re-encrypted with the new password, got %q, %v", content, err)
+	}
+}
+
+func TestVariablesRename(t *testing.T) {
+	p := newPlatform(t)
+	varsPath := filepath.Join("platform", "group_vars", "platform", "vars.yaml")
+	vaultPath := filepath.Join("platform", "group_vars", "platform", "vault.yaml")
+	templatePath := filepath.Join("interaction", "applications", "dashboards", "templates", "grafana.ini.j2")
+	vars := "grafana_port: 3000\ngrafana_port_tls: 3443\n"
+	template := "{# grafana_port #}\nhttp_port = {{ grafana_port }}\nhttps_port = {{ grafana_port_tls }}\n" +
+		"{% if grafana_port is defined %}grafana_port = {{ cfg.grafana_port | default(grafana_port) }}{% endif %}\n"
+	p.WriteFile(varsPath, vars)
+	p.WriteFile(vaultPath, "")
+	if err := vault.EncryptFile(vaultPath, "grafana_admin_url: \"http://admin@grafana:{{ grafana_port }}\"\n", "pass"); err != nil {
+		t.Fatalf("encrypt vault: %v", err)
+	}
+	p.WriteFile(templatePath, template)
+
+	rename := func(pass string, dryRun bool) (*variables.Variables, error) {
+		v := &variables.Variables{Command: []string{"rename", "grafana_port", "grafana_http_port"}, Source: ".", VaultPass: pass, DryRun: dryRun}
+		return v, run(t, v)
+	}
+
+	if _, err := rename("wrong", false); err == nil {
+		t.Fatal("expected wrong vault password to fail")
+	}
+	if _, err := rename("pass", true); err != nil {
+		t.Fatalf("dry-run rename: %v", err)
+	}
+	if p.ReadFile(varsPath) != vars || p.ReadFile(templatePath) != template {
+		t.Fatal("expected files untouched by failed and dry-run renames")
+	}
+
+	v, err := rename("pass", false)
+	if err != nil {
+		t.Fatalf("rename: %v", err)
+	}
+	if files := v.Result().(*variables.VariablesResult).Rename.Files; len(files) != 3 {
+		t.Errorf("expected variables, vault and template files renamed, got %+v", files)
+	}
+	if got := p.ReadFile(varsPath); got != "grafana_http_port: 3000\ngrafana_port_tls: 3443\n" {
+		t.Errorf("expected definition renamed, got:\n%s", got)
+	}
+	expected := "{# grafana_port #}\nhttp_port = {{ grafana_http_port }}\nhttps_port = {{ grafana_port_tls }}\n" +
+		"{% if grafana_http_port is defined %}grafana_port = {{ cfg.grafana_port | default(grafana_http_port) }}{% endif %}\n"
+	if got := p.ReadFile(templatePath); got != expected {
+		t.Errorf("expected template references renamed, got:\n%s", got)
+	}
+	if content, err := vault.DecryptFile(vaultPath, "pass"); err != nil || !strings.Contains(content, "{{ grafana_http_port }}") {
+		t.Errorf("expected vault reference renamed, got %q, %v", content, err)
+	}
+}
+
+func TestDoctor(t *testing.T) {
+	p := newPlatform(t)
+	vaultPath := filepath.Join("foundation", "applications", "auth", "defaults", "vault.yaml")
+	p.WriteFile(vaultPath, "")
+	if err := vault.EncryptFile(filepath.Join(p.Dir, vaultPath), "vault_auth_admin_password: hunter22\n", "secret"); err != nil {
+		t.Fatalf("encrypt vault: %v", err)
+	}
+	p.Commit("add vault", testenv.DeveloperName)
+	p.Compose()
+
+	newDoctor := func(pass string) *doctor.Doctor {
+		return &doctor.Doctor{DomainDir: ".", BuildDir: model.MergedSrcDir, PackagesDir: model.PackagesDir, VaultPass: pass}
+	}
+	statuses := func(d *doctor.Doctor) map[string]string {
+		result := make(map[string]string)
+		for _, c := range d.Result().(*doctor.DoctorResult).Checks {
+			result[c.Name] = c.Status
+		}
+		return result
+	}
+
+	d := newDoctor("secret")
+	if err := run(t, d); err != nil {
+		t.Fatalf("doctor: %v", err)
+	}
+	for name, status := range map[string]string{
+		"repository":     doctor.StatusOK,
+		"vault password": doctor.StatusOK,
+		"build":          doctor.StatusOK,
+		"packages":       doctor.StatusSkipped,
+	} {
+		if got := statuses(d)[name]; got != status {
+			t.Errorf("expected %s check %s, got %q", name, status, got)
+		}
+	}
+
+	p.SetVersion(auth, "fff9999999999")
+	d = newDoctor("wrong")
+	if err := run(t, d); err == nil {
+		t.Fatal("expected doctor to fail with stale build and wrong vault password")
+	}
+	for name, status := range map[string]string{
+		"repository":     doctor.StatusWarning,
+		"vault password": doctor.StatusError,
+		"build":          doctor.StatusError,
+	} {
+		if got := statuses(d)[name]; got != status {
+			t.Errorf("expected %s check %s, got %q", name, status, got)
+		}
+	}
+}
+
+func TestComposeBuildDir(t *testing.T) {
+	p := newPlatform(t)
+	p.AddPackageComponent("plasma-core", "foundation.services.keycloak", "ccc3333333333")
+
+	components, err := component.LoadFromPath(p.Compose())
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+
+	for _, name := range []string{postgres, auth, dashboards, "foundation.services.keycloak"} {
+		if components.Find(name) == nil {
+			t.Errorf("expected %s in build dir", name)
+		}
+	}
+}
+
+func TestLoadContext(t *testing.T) {
+	p := newPlatform(t)
+	p.AddPackageComponent("plasma-core", "foundation.services.keycloak", "ccc3333333333")
+	buildDir := p.Compose()
+
+	expected, err := component.LoadFromPath(buildDir)
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+
+	done, total := 0, 0
+	components, err := component.LoadContext(context.Background(), buildDir, component.LoadOptions{
+		Workers:  2,
+		Progress: func(d, tot int) { done, total = d, tot },
+	})
+	if err != nil {
+		t.Fatalf("load build dir concurrently: %v", err)
+	}
+	if strings.Join(components.Names(), ",") != strings.Join(expected.Names(), ",") {
+		t.Errorf("expected %v, got %v", expected.Names(), components.Names())
+	}
+	if total == 0 || done != total {
+		t.Errorf("expected progress to complete, got %d/%d", done, total)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err = component.LoadContext(ctx, buildDir, component.LoadOptions{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected cancellation error, got %v", err)
+	}
+}
+
+func TestLoadAttachedWithoutBuild(t *testing.T) {
+	p := newPlatform(t)
+
+	// Without composed output, components are read from the domain.
+	components, err := component.LoadAttached(context.Background(), ".", component.LoadOptions{})
+	if err != nil {
+		t.Fatalf("load without build: %v", err)
+	}
+
+	expected, err := component.LoadFromPath(p.Compose())
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+	if strings.Join(components.Names(), ",") != strings.Join(expected.Names(), ",") {
+		t.Errorf("expected %v, got %v", expected.Names(), components.Names())
+	}
+}
+
+func TestRef(t *testing.T) {
+	p := newPlatform(t)
+	initial := p.HeadCommit().Hash.String()
+
+	keycloak := "foundation.services.keycloak"
+	p.SetVersion(postgres, "bbb2222222222")
+	p.AddComponent(keycloak, "bbb2222222222")
+	p.AddComponent(dashboards, "bbb2222222222", auth, keycloak)
+	p.Commit("add keycloak", testenv.DeveloperName)
+
+	l := &list.List{All: true, Ref: initial}
+	if err := run(t, l); err != nil {
+		t.Fatalf("list at ref: %v", err)
+	}
+	var listed []string
+	for _, c := range l.Result().(*list.ListResult).Components {
+		listed = append(listed, component.FormatDisplayName(c.Name, c.Version))
+	}
+	expected := []string{auth + "@aaa1111111111", postgres + "@aaa1111111111", dashboards + "@aaa1111111111"}
+	if strings.Join(listed, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected components of the initial commit %v, got %v", expected, listed)
+	}
+
+	s := &show.Show{DomainDir: ".", Component: postgres, Ref: initial[:7]}
+	if err := run(t, s); err != nil {
+		t.Fatalf("show at ref: %v", err)
+	}
+	if c := s.Result().(*show.ShowResult).Component; c == nil || c.Version != "aaa1111111111" {
+		t.Errorf("expected %s version at the initial commit, got %+v", postgres, c)
+	}
+
+	dep := &depend.Depend{DomainDir: ".", Target: dashboards, Depth: -1, Ref: initial}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend at ref: %v", err)
+	}
+	if requires := dep.Result().(*depend.DependResult).Requires; strings.Join(requires, ",") != auth+","+postgres {
+		t.Errorf("expected dependencies of the initial commit, got %v", requires)
+	}
+
+	dep = &depend.Depend{DomainDir: ".", Target: postgres, Depth: 1, Reverse: true, Ref: "HEAD"}
+	if err := run(t, dep); err != nil {
+		t.Fatalf("depend at HEAD: %v", err)
+	}
+	if requiredBy := dep.Result().(*depend.DependResult).RequiredBy; strings.Join(requiredBy, ",") != auth {
+		t.Errorf("expected %s required by %s, got %v", postgres, auth, requiredBy)
+	}
+
+	if err := run(t, &depend.Depend{DomainDir: ".", Target: auth, Operations: []string{keycloak}, Depth: 1, Ref: initial}); err == nil {
+		t.Error("expected operations to be rejected with a ref")
+	}
+	if err := run(t, &list.List{Ref: "missing"}); err == nil {
+		t.Error("expected unknown ref to fail")
+	}
+}
+
+func TestResolvePaths(t *testing.T) {
+	p := newPlatform(t)
+	keycloak := "foundation.services.keycloak"
+	p.AddPackageComponent("plasma-core", keycloak, "ccc3333333333")
+	p.Compose()
+
+	dir, err := filepath.EvalSymlinks(p.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := map[string]component.Location{
+		postgres:   {Component: postgres, Path: filepath.Join(dir, "foundation", "services", "postgres"), Source: component.SourceDomain},
+		dashboards: {Component: dashboards, Path: filepath.Join(dir, "interaction", "applications", "dashboards"), Source: component.SourceDomain},
+		keycloak:   {Component: keycloak, Path: filepath.Join(dir, model.MergedSrcDir, "foundation", "services", "keycloak"), Source: component.SourceBuild},
+	}
+	check := func(action, name string, loc *component.Location) {
+		t.Helper()
+		if loc == nil {
+			t.Errorf("%s: expected %s to be located", action, name)
+			return
+		}
+		loc.Path, _ = filepath.EvalSymlinks(loc.Path)
+		if *loc != expected[name] {
+			t.Errorf("%s: expected %+v, got %+v", action, expected[name], *loc)
+		}
+	}
+
+	l := &list.List{All: true, ResolvePaths: true}
+	if err = run(t, l); err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	for _, c := range l.Result().(*list.ListResult).Components {
+		if _, ok := expected[c.Name]; ok {
+			check("list", c.Name, c.Location)
+		}
+	}
+
+	s := &show.Show{DomainDir: ".", Component: postgres, ResolvePaths: true}
+	if err = run(t, s); err != nil {
+		t.Fatalf("show: %v", err)
+	}
+	check("show", postgres, s.Result().(*show.ShowResult).Component.Location)
+
+	if err = run(t, &show.Show{DomainDir: ".", Component: postgres, Ref: "HEAD", ResolvePaths: true}); err == nil {
+		t.Error("expected paths resolution to be rejected with a ref")
+	}
+}
+
+func TestSyncUndo(t *testing.T) {
+	p := newPlatform(t)
+	buildDir := p.Compose()
+	p.WriteFile(".plasmactl/sync-journal.json", `{"runs": [{"date": "2026-01-02T00:00:00Z", "changes": [
+		{"component": "`+postgres+`", "old_version": "0000000000000", "new_version": "aaa1111111111"},
+		{"component": "`+auth+`", "old_version": "0000000000000", "new_version": "bbb2222222222"}
+	]}]}`)
+
+	s := &sync.Sync{DomainDir: ".", BuildDir: buildDir, Undo: true}
+	if err := run(t, s); err == nil {
+		t.Fatal("expected undo to report component modified since sync")
+	}
+
+	restored, err := component.LoadFromPath(buildDir)
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+	if v := restored.Find(postgres).Version; v != "0000000000000" {
+		t.Errorf("expected %s restored, got %s", postgres, v)
+	}
+	if v := restored.Find(auth).Version; v != "aaa1111111111" {
+		t.Errorf("expected %s modified since sync untouched, got %s", auth, v)
+	}
+	if !strings.Contains(p.ReadFile(".plasmactl/sync-journal.json"), `"runs": []`) {
+		t.Error("expected undone run removed from journal")
+	}
+}
+
+func TestSyncResume(t *testing.T) {
+	p := newPlatform(t)
+	buildDir := p.Compose()
+	p.WriteFile(".plasmactl/sync-state.json", `{"date": "2026-01-02T00:00:00Z", "changes": [
+		{"component": "`+postgres+`", "old_version": "aaa1111111111", "new_version": "ddd4444444444", "written": false},
+		{"component": "`+auth+`", "old_version": "0000000000000", "new_version": "aaa1111111111", "written": false}
+	]}`)
+
+	if err := run(t, &sync.Sync{DomainDir: ".", BuildDir: buildDir, Resume: true, Only: []string{postgres}}); err == nil {
+		t.Fatal("expected error for --resume with --only")
+	}
+
+	s := &sync.Sync{DomainDir: ".", BuildDir: buildDir, Resume: true}
+	if err := run(t, s); err != nil {
+		t.Fatalf("resume: %v", err)
+	}
+
+	resumed, err := component.LoadFromPath(buildDir)
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+	if v := resumed.Find(postgres).Version; v != "ddd4444444444" {
+		t.Errorf("expected %s written, got %s", postgres, v)
+	}
+	if v := resumed.Find(auth).Version; v != "aaa1111111111" {
+		t.Errorf("expected %s already written untouched, got %s", auth, v)
+	}
+	if _, err = os.Stat(filepath.Join(p.Dir, ".plasmactl/sync-state.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Error("expected sync state removed after resume")
+	}
+	if journal := p.ReadFile(".plasmactl/sync-journal.json"); !strings.Contains(journal, "ddd4444444444") || strings.Contains(journal, auth) {
+		t.Errorf("expected only resumed write journaled, got:\n%s", journal)
+	}
+}
+
+func TestSyncDryRunPatch(t *testing.T) {
+	p := newPlatform(t)
+	buildDir := p.Compose()
+	p.WriteFile(".plasmactl/sync-journal.json", `{"runs": [{"date": "2026-01-02T00:00:00Z", "changes": [
+		{"component": "`+postgres+`", "old_version": "0000000000000", "new_version": "aaa1111111111"}
+	]}]}`)
+
+	if err := run(t, &sync.Sync{DomainDir: ".", BuildDir: buildDir, Undo: true, Patch: "sync.patch"}); err == nil {
+		t.Fatal("expected error for --patch without --dry-run")
+	}
+
+	s := &sync.Sync{DomainDir: ".", BuildDir: buildDir, Undo: true, DryRun: true, Patch: "sync.patch"}
+	if err := run(t, s); err != nil {
+		t.Fatalf("dry-run undo: %v", err)
+	}
+
+	patch := p.ReadFile("sync.patch")
+	if !strings.Contains(patch, "/foundation/services/postgres/meta/plasma.yaml\n") ||
+		!strings.Contains(patch, "\n-  version: \"aaa1111111111\"\n+  version: \"0000000000000\"\n") {
+		t.Errorf("unexpected patch:\n%s", patch)
+	}
+
+	restored, err := component.LoadFromPath(buildDir)
+	if err != nil {
+		t.Fatalf("load build dir: %v", err)
+	}
+	if v := restored.Find(postgres).Version; v != "aaa1111111111" {
+		t.Errorf("expected %s untouched in dry-run, got %s", postgres, v)
+	}
+}
+
+func TestSyncBranch(t *testing.T) {
+	p := newPlatform(t)
+	buildDir := p.Compose()
+
+	if err := run(t, &sync.Sync{DomainDir: ".", BuildDir: buildDir, Branch: "release/1.0", FromManifest: "release.json"}); err == nil {
+		t.Error("expected --branch with --from-manifest to be rejected")
+	}
+	if err := run(t, &sync.Sync{DomainDir: ".", BuildDir: buildDir, Branch: "release/1.0"}); err == nil {
+		t.Error("expected unknown branch to be rejected")
+	}
+
+	head, err := p.Repo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = run(t, &sync.Sync{DomainDir: ".", BuildDir: buildDir, Branch: head.Name().Short()}); err == nil {
+		t.Error("expected current branch to be rejected")
+	}
+
+	if entries, _ := os.ReadDir(filepath.Join(p.Dir, ".git", "worktrees")); len(entries) > 0 {
+		t.Errorf("expected no worktree left, got %d", len(entries))
+	}
+}
+
+func TestSyncConstraints(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile("plasma-compose.yaml", "name: platform\n")
+	initial := p.HeadCommit().Hash.String()[:13]
+	for _, name := range []string{postgres, auth, dashboards} {
+		p.SetVersion(name, initial)
+	}
+	p.Commit("versions bump", repository.Author)
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	p.SetVersion(postgres, p.Commit("change postgres", testenv.DeveloperName)[:13])
+	p.Commit("versions bump", repository.Author)
+
+	// Constraints are read from the build, postgres changed after its initial version.
+	buildDir := p.Compose()
+	authDeps := filepath.Join(model.MergedSrcDir, "foundation", "applications", "auth", "tasks", "dependencies.yaml")
+	p.WriteFile(authDeps, "dependencies:\n  - "+postgres+": \"<"+initial+"\"\n")
+	streams := testenv.QuietStreams()
+
+	s := &sync.Sync{Streams: streams, DomainDir: ".", BuildDir: buildDir, DryRun: true}
+	if err := run(t, s); err == nil || !strings.Contains(err.Error(), "don't satisfy constraints") {
+		t.Fatalf("expected violated constraint to fail sync, got %v", err)
+	}
+
+	s = &sync.Sync{Streams: streams, DomainDir: ".", BuildDir: buildDir, DryRun: true, SkipConstraints: true}
+	if err := run(t, s); err != nil {
+		t.Fatalf("sync with --skip-constraints: %v", err)
+	}
+	res := s.Result().(*sync.SyncResult)
+	if v := res.ConstraintViolations; len(v) != 1 || v[0].Component != auth || v[0].Dependency != postgres || v[0].Version == initial {
+		t.Errorf("expected violation of %s constraint on %s, got %+v", auth, postgres, v)
+	}
+	if !slices.ContainsFunc(res.Warnings, func(w warning.Warning) bool { return w.Code == warning.Constraint }) {
+		t.Errorf("expected constraint warning, got %+v", res.Warnings)
+	}
+
+	p.WriteFile(authDeps, "dependencies:\n  - "+postgres+": \">"+initial+"\"\n")
+	s = &sync.Sync{Streams: streams, DomainDir: ".", BuildDir: buildDir, DryRun: true}
+	if err := run(t, s); err != nil {
+		t.Fatalf("sync with satisfied constraint: %v", err)
+	}
+	if v := s.Result().(*sync.SyncResult).ConstraintViolations; len(v) != 0 {
+		t.Errorf("expected no violation, got %+v", v)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	p := newPlatform(t)
+	initial := p.HeadCommit().Hash.String()
+	if err := run(t, &bump.Bump{}); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "---\n- debug: {}\n")
+	change := p.Commit("change postgres", testenv.DeveloperName)
+	if err := run(t, &bump.Bump{Cascade: true}); err != nil {
+		t.Fatalf("bump cascade: %v", err)
+	}
+	head := p.HeadCommit().Hash.String()
+
+	keycloak := "foundation.services.keycloak"
+	h := &history.History{At: "HEAD", Components: []string{postgres, auth, keycloak}}
+	if err := run(t, h); err != nil {
+		t.Fatalf("history: %v", err)
+	}
+	res := h.Result().(*history.HistoryResult)
+	if res.Commit != head || len(res.Components) != 2 || strings.Join(res.Missing, ",") != keycloak {
+		t.Fatalf("unexpected history at HEAD: %+v", res)
+	}
+	versions := make(map[string]history.ComponentVersion)
+	for _, c := range res.Components {
+		versions[c.Name] = c
+	}
+	if c := versions[postgres]; c.Version != change[:13] || c.Commit != change || c.Bump != head || c.Message != "change postgres" || c.Date == nil {
+		t.Errorf("expected %s at the change commit, got %+v", postgres, c)
+	}
+	if c := versions[auth]; c.Propagated != change[:13] || c.Commit != initial {
+		t.Errorf("expected %s propagated from the change with the initial commit as base, got %+v", auth, c)
+	}
+
+	h = &history.History{At: initial[:7], Components: []string{postgres}}
+	if err := run(t, h); err != nil {
+		t.Fatalf("history at initial commit: %v", err)
+	}
+	if c := h.Result().(*history.HistoryResult).Components; len(c) != 1 || c[0].Version != "aaa1111111111" || c[0].Commit != "" {
+		t.Errorf("expected manual version without commit at the initial commit, got %+v", c)
+	}
+
+	h = &history.History{At: time.Now().Format(time.DateOnly)}
+	if err := run(t, h); err != nil {
+		t.Fatalf("history today: %v", err)
+	}
+	if res = h.Result().(*history.HistoryResult); res.Commit != head || len(res.Components) != 3 {
+		t.Errorf("expected all components at HEAD today, got %+v", res)
+	}
+
+	if err := run(t, &history.History{At: time.Now().AddDate(0, 0, -7).Format(time.DateOnly)}); err == nil {
+		t.Error("expected a date before the history to fail")
+	}
+	if err := run(t, &history.History{}); err == nil {
+		t.Error("expected --at to be required")
+	}
+}
+
+func TestBumpSignAndVerify(t *testing.T) {
+	p := newPlatform(t)
+	keys := t.TempDir()
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	signKey := filepath.Join(keys, "signing.pem")
+	if err = os.WriteFile(signKey, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	der, _ = x509.MarshalPKIXPublicKey(public)
+	publicKey := filepath.Join(keys, "signing.pub.pem")
+	if err = os.WriteFile(publicKey, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	b := &bump.Bump{SignKey: signKey}
+	if err = run(t, b); err != nil {
+		t.Fatalf("bump: %v", err)
+	}
+	for _, c := range b.Result().(*bump.BumpResult).Components {
+		if !c.Signed {
+			t.Errorf("expected %s signed", c.Name)
+		}
+	}
+	if _, errFile := p.HeadCommit().File("foundation/services/postgres/meta/signature.yaml"); errFile != nil {
+		t.Fatalf("expected signature committed with bump: %v", errFile)
+	}
+
+	v := &verify.Verify{Provenance: true, PublicKeys: []string{publicKey}, Source: "."}
+	if err = run(t, v); err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if res := v.Result().(*verify.VerifyResult); res.Valid != 3 {
+		t.Errorf("expected 3 valid components, got %+v", res)
+	}
+
+	p.WriteFile(filepath.Join("foundation", "services", "postgres", "tasks", "main.yaml"), "- name: out of band\n")
+
+	v = &verify.Verify{Provenance: true, PublicKeys: []string{publicKey}, Source: "."}
+	if err = run(t, v); err == nil {
+		t.Fatal("expected verify to report tampered component")
+	}
+	for _, c := range v.Result().(*verify.VerifyResult).Components {
+		expected := verify.StatusValid
+		if c.Name == postgres {
+			expected = verify.StatusTampered
+		}
+		if c.Status != expected {
+			t.Errorf("expected %s %s, got %s", c.Name, expected, c.Status)
+		}
+	}
+}
+
+func TestSetVersion(t *testing.T) {
+	p := newPlatform(t)
+	meta := filepath.Join("foundation", "services", "postgres", "meta", "plasma.yaml")
+	p.WriteFile(meta, "# pinned by ops\nplasma:\n  version: \"aaa1111111111\" # keep\n")
+
+	if err := run(t, &setversion.SetVersion{Version: "fff9999999999", Source: "."}); err == nil {
+		t.Fatal("expected error without selection")
+	}
+
+	sv := &setversion.SetVersion{Version: "fff9999999999", Layer: "foundation", Source: ".", DryRun: true}
+	if err := run(t, sv); err != nil {
+		t.Fatalf("set-version dry-run: %v", err)
+	}
+	if changes := sv.Result().(*setversion.SetVersionResult).Components; len(changes) != 2 || changes[0].Name != auth || changes[1].Name != postgres {
+		t.Fatalf("expected %s and %s selected, got %+v", auth, postgres, changes)
+	}
+	if !strings.Contains(p.ReadFile(meta), "aaa1111111111") {
+		t.Fatal("expected meta untouched in dry-run")
+	}
+
+	sv = &setversion.SetVersion{Version: "fff9999999999", Layer: "foundation", Kind: "services", Source: "."}
+	if err := run(t, sv); err != nil {
+		t.Fatalf("set-version: %v", err)
+	}
+	if expected := "# pinned by ops\nplasma:\n  version: \"fff9999999999\" # keep\n"; p.ReadFile(meta) != expected {
+		t.Errorf("expected comments kept, got %q", p.ReadFile(meta))
+	}
+
+	components, err := component.LoadFromPath(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := components.Find(auth).Version; v != "aaa1111111111" {
+		t.Errorf("expected %s of another kind untouched, got %s", auth, v)
+	}
+}
+
+func TestAttachOrdering(t *testing.T) {
+	p := newPlatform(t)
+	cluster := "platform.foundation.cluster"
+	p.AddPlaybook("foundation", playbook.Play{Hosts: cluster, Roles: []playbook.Role{{Name: auth}}})
+
+	att := &attach.Attach{Component: postgres, Chassis: cluster, Source: "."}
+	if err := run(t, att); err != nil {
+		t.Fatalf("attach: %v", err)
+	}
+
+	issues := att.Result().(*attach.AttachResult).OrderIssues
+	if len(issues) != 1 || issues[0].Component != auth || issues[0].Dependency != postgres {
+		t.Fatalf("expected %s to be reported after %s, got %+v", postgres, auth, issues)
+	}
+
+	att = &attach.Attach{Component: postgres, Chassis: cluster, Source: ".", Reorder: true}
+	if err := run(t, att); err != nil {
+		t.Fatalf("attach with reorder: %v", err)
+	}
+
+	res := att.Result().(*attach.AttachResult)
+	if !res.Reordered || len(res.OrderIssues) != 0 {
+		t.Fatalf("expected roles reordered without issues, got %+v", res)
+	}
+
+	plays, err := playbook.Load(filepath.Join("foundation", "foundation.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if roles := plays[0].Roles; len(roles) != 2 || roles[0].Name != postgres || roles[1].Name != auth {
+		t.Errorf("expected %s before %s, got %+v", postgres, auth, roles)
+	}
+}
+
+func TestCreateFromTemplates(t *testing.T) {
+	p := newPlatform(t)
+
+	c := &create.Create{Component: "foundation.flows.onboarding", Owner: "team-data", Source: ".", TemplatesDir: create.TemplatesDir}
+	if err := run(t, c); err != nil {
+		t.Fatalf("create flow: %v", err)
+	}
+	if meta := p.ReadFile("foundation/flows/onboarding/meta/plasma.yaml"); !strings.Contains(meta, "- team-data") {
+		t.Errorf("expected owner in meta, got %q", meta)
+	}
+	p.ReadFile("foundation/flows/onboarding/flows/onboarding.yaml")
+
+	p.WriteFile(".plasmactl/templates/services/meta/plasma.yaml.tmpl", "plasma:\n  version: \"\"\n")
+	p.WriteFile(".plasmactl/templates/services/templates/{{.Role}}.conf.j2", "port={{ port }}\n")
+
+	c = &create.Create{Component: "foundation.services.cache", Source: ".", TemplatesDir: create.TemplatesDir}
+	if err := run(t, c); err != nil {
+		t.Fatalf("create service: %v", err)
+	}
+	if conf := p.ReadFile("foundation/services/cache/templates/cache.conf.j2"); conf != "port={{ port }}\n" {
+		t.Errorf("expected Jinja template copied as is, got %q", conf)
+	}
+
+	if err := run(t, c); err == nil {
+		t.Error("expected error creating existing component")
+	}
+}
+
+func TestExtensions(t *testing.T) {
+	p := newPlatform(t)
+	ext := extension.New()
+
+	err := ext.AddLintRule(extension.LintRule{Name: "owners", Check: func(string) ([]extension.Issue, error) {
+		return []extension.Issue{{Subject: postgres, File: "foundation/services/postgres/meta/plasma.yaml", Message: "no owner"}}, nil
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ext.AddTemplate("agents", fstest.MapFS{"meta/plasma.yaml.tmpl": {Data: []byte("plasma:\n  version: \"\"\n  agent: {{.Role}}\n")}}); err != nil {
+		t.Fatal(err)
+	}
+
+	l := &lint.Lint{Source: ".", ExtensionRules: []string{"owners"}, Extensions: ext.LintRules()}
+	if err = run(t, l); err == nil {
+		t.Fatal("expected contributed rule issue")
+	}
+	if res := l.Result().(*lint.LintResult); len(res.Rules) != 1 || len(res.Issues) != 1 || res.Issues[0].Rule != "owners" {
+		t.Errorf("expected only owners rule run, got %+v", res)
+	}
+
+	if err = run(t, &lint.Lint{Source: ".", ExtensionRules: []string{"licenses"}, Extensions: ext.LintRules()}); err == nil {
+		t.Error("expected error for unknown contributed rule")
+	}
+
+	c := &create.Create{Component: "cognition.agents.triage", Source: ".", Templates: ext.Templates()}
+	if err = run(t, c); err != nil {
+		t.Fatalf("create from contributed template: %v", err)
+	}
+	if meta := p.ReadFile("cognition/agents/triage/meta/plasma.yaml"); !strings.Contains(meta, "agent: triage") {
+		t.Errorf("expected contributed template rendered, got %q", meta)
+	}
+}
+
+func TestConvertKind(t *testing.T) {
+	p := newPlatform(t)
+	p.AddPlaybook("foundation", playbook.Play{Hosts: "platform.foundation", Roles: []playbook.Role{{Name: auth}}})
+
+	if err := run(t, &convertkind.ConvertKind{Component: auth, Kind: "unknown", Source: "."}); err == nil {
+		t.Fatal("expected error for unknown kind")
+	}
+
+	ck := &convertkind.ConvertKind{Component: auth, Kind: "services", Source: "."}
+	if err := run(t, ck); err != nil {
+		t.Fatalf("convert kind: %v", err)
+	}
+
+	const converted = "foundation.services.auth"
+	p.ReadFile("foundation/services/auth/meta/plasma.yaml")
+	if deps := p.ReadFile("interaction/applications/dashboards/tasks/dependencies.yaml"); !strings.Contains(deps, converted) {
+		t.Errorf("expected dependency renamed to %s, got %q", converted, deps)
+	}
+	if pb := p.ReadFile("foundation/foundation.yaml"); !strings.Contains(pb, converted) || strings.Contains(pb, auth) {
+		t.Errorf("expected playbook role renamed to %s, got %q", converted, pb)
+	}
+	if deps := p.ReadFile("foundation/services/auth/tasks/dependencies.yaml"); !strings.Contains(deps, postgres) {
+		t.Errorf("expected own dependencies kept, got %q", deps)
+	}
+}
+
+func TestLintAttachments(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile("chassis.yaml", "platform:\n  interaction:\n    - observability\n")
+	p.WriteFile("src/interaction/interaction.yaml", "- hosts: platform.interaction.observabilty\n  roles:\n    - "+dashboards+"\n")
+
+	lt := &lint.Lint{Source: ".", Attachments: true}
+	if err := run(t, lt); err == nil {
+		t.Fatal("expected lint to fail on unknown chassis section")
+	}
+
+	issues := lt.Result().(*lint.LintResult).Issues
+	if len(issues) != 1 || issues[0].Subject != dashboards || !strings.Contains(issues[0].Message, chassis) {
+		t.Errorf("expected %s reported with suggestion %s, got %+v", dashboards, chassis, issues)
+	}
+	if len(issues) == 1 && (issues[0].Line != 3 || issues[0].Column != 7) {
+		t.Errorf("expected issue at 3:7, got %d:%d", issues[0].Line, issues[0].Column)
+	}
+}
+
+func TestLintDuplicateAttachments(t *testing.T) {
+	p := newPlatform(t)
+	p.WriteFile("src/interaction/interaction.yaml", "- hosts: "+chassis+"\n  roles:\n    - "+dashboards+"\n"+
+		"# observability again\n- hosts: "+chassis+"\n  roles:\n    - "+dashboards+"\n    - "+auth+"\n    - "+postgres+"\n")
+	p.WriteFile("src/foundation/foundation.yaml", "- hosts: "+chassis+"\n  roles:\n    - "+auth+"\n    - "+dashboards+"\n")
+
+	lt := &lint.Lint{Source: ".", Attachments: true}
+	if err := run(t, lt); err == nil {
+		t.Fatal("expected lint to fail on duplicate attachments")
+	}
+
+	issues := lt.Result().(*lint.LintResult).Issues
+	if len(issues) != 3 {
+		t.Fatalf("expected 3 duplicates, got %+v", issues)
+	}
+	for _, issue := range issues {
+		canonical := "src/interaction/interaction.yaml:3:7"
+		if issue.Subject == auth {
+			canonical = "src/foundation/foundation.yaml:3:7"
+		}
+		if !strings.Contains(issue.Message, canonical) {
+			t.Errorf("expected %s canonical at %s, got %q", issue.Subject, canonical, issue.Message)
+		}
+	}
+
+	lt = &lint.Lint{Source: ".", Attachments: true, Fix: true}
+	if err := run(t, lt); err != nil {
+		t.Fatalf("lint fix: %v", err)
+	}
+
+	interaction := p.ReadFile("src/interaction/interaction.yaml")
+	if strings.Count(interaction, dashboards) != 1 || strings.Contains(interaction, auth) {
+		t.Errorf("expected duplicates removed from interaction playbook, got %q", interaction)
+	}
+	if !strings.Contains(interaction, "# observability again") {
+		t.Errorf("expected comments kept, got %q", interaction)
+	}
+	if foundation := p.ReadFile("src/foundation/foundation.yaml"); strings.Contains(foundation, dashboards) || !strings.Contains(foundation, auth) {
+		t.Errorf("expected %s kept in foundation playbook only, got %q", auth, foundation)
+	}
+
+	lt = &lint.Lint{Source: ".", Attachments: true}
+	if err := run(t, lt); err != nil {
+		t.Errorf("expected no duplicates after fix: %v", err)
+	}
+}
@@ -0,0 +1,280 @@
+// Package testenv provides a miniature platform fixture to run actions end-to-end in tests.
+package testenv
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/plasmash/plasmactl-model/pkg/model"
+	"gopkg.in/yaml.v3"
+
+	"github.com/plasmash/plasmactl-component/internal/playbook"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+)
+
+// Fixture commit authors.
+const (
+	DeveloperName  = "Developer"
+	DeveloperEmail = "dev@test.com"
+	BumperEmail    = "noreply@plasma.sh"
+)
+
+// Platform is a platform repository created in a temporary directory.
+// The directory becomes the working directory of the test, as actions resolve paths relatively to it.
+type Platform struct {
+	t    testing.TB
+	Dir  string
+	Repo *git.Repository
+
+	clock time.Time
+}
+
+// New initializes an empty platform repository and changes working directory to it.
+// Working directory is restored on test cleanup.
+func New(t testing.TB) *Platform {
+	t.Helper()
+	dir := t.TempDir()
+
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("git init: %v", err)
+	}
+
+	orig, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(orig) })
+
+	if err = os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+
+	return &Platform{
+		t:    t,
+		Dir:  dir,
+		Repo: repo,
+		// Commits are spread in time so history order is stable.
+		clock: time.Now().Add(-24 * time.Hour),
+	}
+}
+
+// WriteFile writes a file relative to the platform root, creating parent directories.
+func (p *Platform) WriteFile(path, content string) {
+	p.t.Helper()
+	full := filepath.Join(p.Dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0750); err != nil {
+		p.t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := os.WriteFile(full, []byte(content), 0600); err != nil {
+		p.t.Fatalf("write %s: %v", path, err)
+	}
+}
+
+// ReadFile reads a file relative to the platform root.
+func (p *Platform) ReadFile(path string) string {
+	p.t.Helper()
+	data, err := os.ReadFile(filepath.Join(p.Dir, path))
+	if err != nil {
+		p.t.Fatalf("read %s: %v", path, err)
+	}
+
+	return string(data)
+}
+
+// AddComponent creates component layer/kind/name with meta and dependencies files.
+func (p *Platform) AddComponent(mrn, version string, deps ...string) {
+	p.t.Helper()
+	p.writeComponent("", mrn, version, deps...)
+}
+
+// SetVersion overwrites component version in its meta file.
+func (p *Platform) SetVersion(mrn, version string) {
+	p.t.Helper()
+	p.WriteFile(filepath.Join(componentPath(p.t, mrn), "meta", "plasma.yaml"), metaContent(version))
+}
+
+// AddPackageComponent creates component inside the package directory, as it would be fetched by compose.
+func (p *Platform) AddPackageComponent(pkg, mrn, version string, deps ...string) {
+	p.t.Helper()
+	p.writeComponent(filepath.Join(model.PackagesDir, pkg), mrn, version, deps...)
+}
+
+// AddPlaybook writes layer playbook with the given plays.
+func (p *Platform) AddPlaybook(layer string, plays ...playbook.Play) {
+	p.t.Helper()
+	path := filepath.Join(p.Dir, layer, layer+".yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		p.t.Fatalf("mkdir: %v", err)
+	}
+
+	if err := playbook.Save(path, plays); err != nil {
+		p.t.Fatalf("save playbook: %v", err)
+	}
+}
+
+// Compose emulates compose by merging package and platform components into the build directory.
+// Platform components override package ones.
+func (p *Platform) Compose() string {
+	p.t.Helper()
+	buildDir := filepath.Join(p.Dir, model.MergedSrcDir)
+	if err := os.RemoveAll(buildDir); err != nil {
+		p.t.Fatalf("clean build dir: %v", err)
+	}
+
+	packages, _ := os.ReadDir(filepath.Join(p.Dir, model.PackagesDir))
+	for _, pkg := range packages {
+		if pkg.IsDir() {
+			p.copyLayers(filepath.Join(p.Dir, model.PackagesDir, pkg.Name()), buildDir)
+		}
+	}
+
+	p.copyLayers(p.Dir, buildDir)
+	return buildDir
+}
+
+// Stage adds files relative to the platform root to the index, without committing.
+func (p *Platform) Stage(paths ...string) {
+	p.t.Helper()
+	w, err := p.Repo.Worktree()
+	if err != nil {
+		p.t.Fatalf("worktree: %v", err)
+	}
+
+	for _, path := range paths {
+		if _, err = w.Add(filepath.ToSlash(path)); err != nil {
+			p.t.Fatalf("git add %s: %v", path, err)
+		}
+	}
+}
+
+// Commit stages all changes and commits them on behalf of the author. Returns the commit hash.
+func (p *Platform) Commit(message, author string) string {
+	p.t.Helper()
+	w, err := p.Repo.Worktree()
+	if err != nil {
+		p.t.Fatalf("worktree: %v", err)
+	}
+
+	if _, err = w.Add("."); err != nil {
+		p.t.Fatalf("git add: %v", err)
+	}
+
+	email := DeveloperEmail
+	if author == repository.Author {
+		email = BumperEmail
+	}
+
+	p.clock = p.clock.Add(time.Minute)
+	hash, err := w.Commit(message, &git.CommitOptions{
+		Author: &object.Signature{
+			Name:  author,
+			Email: email,
+			When:  p.clock,
+		},
+	})
+	if err != nil {
+		p.t.Fatalf("commit %q: %v", message, err)
+	}
+
+	return hash.String()
+}
+
+// HeadCommit returns the HEAD commit.
+func (p *Platform) HeadCommit() *object.Commit {
+	p.t.Helper()
+	ref, err := p.Repo.Head()
+	if err != nil {
+		p.t.Fatalf("head: %v", err)
+	}
+
+	c, err := p.Repo.CommitObject(ref.Hash())
+	if err != nil {
+		p.t.Fatalf("head commit: %v", err)
+	}
+
+	return c
+}
+
+func (p *Platform) writeComponent(prefix, mrn, version string, deps ...string) {
+	p.t.Helper()
+	path := filepath.Join(prefix, componentPath(p.t, mrn))
+	p.WriteFile(filepath.Join(path, "meta", "plasma.yaml"), metaContent(version))
+	p.WriteFile(filepath.Join(path, "tasks", "main.yaml"), "---\n")
+
+	if len(deps) == 0 {
+		return
+	}
+
+	// Dependencies are declared as role includes, the way inventory reads them.
+	tasks := make([]map[string]any, 0, len(deps))
+	for _, d := range deps {
+		tasks = append(tasks, map[string]any{"include_role": map[string]string{"name": d}})
+	}
+
+	data, err := yaml.Marshal(tasks)
+	if err != nil {
+		p.t.Fatalf("marshal dependencies: %v", err)
+	}
+	p.WriteFile(filepath.Join(path, "tasks", "dependencies.yaml"), string(data))
+}
+
+// copyLayers copies layer directories from src to dst, skipping hidden directories.
+func (p *Platform) copyLayers(src, dst string) {
+	p.t.Helper()
+	err := filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(src, path)
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		// Only files inside layers are composed.
+		if !strings.Contains(rel, string(filepath.Separator)) {
+			return nil
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dst, rel)
+		if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return err
+		}
+
+		return os.WriteFile(target, data, 0600)
+	})
+	if err != nil {
+		p.t.Fatalf("compose %s: %v", src, err)
+	}
+}
+
+func componentPath(t testing.TB, mrn string) string {
+	t.Helper()
+	parts := strings.Split(mrn, ".")
+	if len(parts) != 3 {
+		t.Fatalf("invalid component name %q (expected: layer.kind.name)", mrn)
+	}
+
+	return filepath.Join(parts...)
+}
+
+func metaContent(version string) string {
+	return fmt.Sprintf("plasma:\n  version: %q\n", version)
+}
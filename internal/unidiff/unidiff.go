@@ -0,0 +1,138 @@
+// Package unidiff renders changes of text files in unified diff format, as accepted by `git apply`.
+package unidiff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Context is the number of unchanged lines around changes.
+const Context = 3
+
+type opKind byte
+
+const (
+	opEqual  opKind = ' '
+	opDelete opKind = '-'
+	opInsert opKind = '+'
+)
+
+type op struct {
+	kind opKind
+	line string
+	// a and b are 0-based line numbers in old and new files before the operation.
+	a, b int
+}
+
+// Diff returns the unified diff of the file at path relative to the repository root, with git `a/` and `b/`
+// prefixes, or an empty string if contents are identical.
+func Diff(path string, before, after []byte) string {
+	ops := compare(lines(string(before)), lines(string(after)))
+
+	var b strings.Builder
+	for _, h := range hunks(ops) {
+		if b.Len() == 0 {
+			fmt.Fprintf(&b, "diff --git a/%s b/%s\n--- a/%s\n+++ b/%s\n", path, path, path, path)
+		}
+		writeHunk(&b, ops[h[0]:h[1]])
+	}
+
+	return b.String()
+}
+
+func lines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	l := strings.SplitAfter(s, "\n")
+	if l[len(l)-1] == "" {
+		l = l[:len(l)-1]
+	}
+	return l
+}
+
+// compare computes edit operations turning a into b from their longest common subsequence.
+func compare(a, b []string) []op {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []op
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, op{opEqual, a[i], i, j})
+			i++
+			j++
+		case j == len(b) || (i < len(a) && lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, op{opDelete, a[i], i, j})
+			i++
+		default:
+			ops = append(ops, op{opInsert, b[j], i, j})
+			j++
+		}
+	}
+
+	return ops
+}
+
+// hunks returns ranges of operations to print, changes with their context, merged when contexts overlap.
+func hunks(ops []op) [][2]int {
+	var result [][2]int
+	for i, o := range ops {
+		if o.kind == opEqual {
+			continue
+		}
+		start, end := max(i-Context, 0), min(i+Context+1, len(ops))
+		if n := len(result); n > 0 && start <= result[n-1][1] {
+			result[n-1][1] = end
+			continue
+		}
+		result = append(result, [2]int{start, end})
+	}
+
+	return result
+}
+
+func writeHunk(b *strings.Builder, ops []op) {
+	var oldLines, newLines int
+	for _, o := range ops {
+		if o.kind != opInsert {
+			oldLines++
+		}
+		if o.kind != opDelete {
+			newLines++
+		}
+	}
+
+	fmt.Fprintf(b, "@@ -%s +%s @@\n", hunkRange(ops[0].a, oldLines), hunkRange(ops[0].b, newLines))
+	for _, o := range ops {
+		b.WriteByte(byte(o.kind))
+		b.WriteString(o.line)
+		if !strings.HasSuffix(o.line, "\n") {
+			b.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// hunkRange formats 1-based start and length of a hunk, start is the line before an empty range.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
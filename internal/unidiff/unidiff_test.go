@@ -0,0 +1,86 @@
+package unidiff
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := "plasma:\n  version: abc\n"
+	updated := "plasma:\n  version: abc-def\n"
+
+	expected := `diff --git a/meta/plasma.yaml b/meta/plasma.yaml
+--- a/meta/plasma.yaml
++++ b/meta/plasma.yaml
+@@ -1,2 +1,2 @@
+ plasma:
+-  version: abc
++  version: abc-def
+`
+	if d := Diff("meta/plasma.yaml", []byte(old), []byte(updated)); d != expected {
+		t.Errorf("unexpected diff:\n%s", d)
+	}
+
+	if d := Diff("meta/plasma.yaml", []byte(old), []byte(old)); d != "" {
+		t.Errorf("expected empty diff, got:\n%s", d)
+	}
+}
+
+func TestDiffHunks(t *testing.T) {
+	var old []string
+	for i := 1; i <= 20; i++ {
+		old = append(old, strings.Repeat("x", i))
+	}
+	updated := append([]string{}, old...)
+	updated[1] = "changed"
+	updated[17] = "changed"
+
+	d := Diff("f", []byte(strings.Join(old, "\n")), []byte(strings.Join(updated, "\n")))
+	if strings.Count(d, "@@ -") != 2 {
+		t.Fatalf("expected 2 hunks, got:\n%s", d)
+	}
+	if !strings.Contains(d, "@@ -1,5 +1,5 @@") || !strings.Contains(d, "@@ -15,6 +15,6 @@") {
+		t.Errorf("unexpected hunk ranges:\n%s", d)
+	}
+	if !strings.HasSuffix(d, "\\ No newline at end of file\n") {
+		t.Errorf("expected missing newline marker:\n%s", d)
+	}
+}
+
+func TestDiffGitApply(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join("layer", "meta", "plasma.yaml")
+	old := "plasma:\n  version: abc\n  owner: team\nkind: application\n"
+	updated := "plasma:\n  version: abc-def\n  owner: team\nkind: application\n"
+	if err := os.MkdirAll(filepath.Join(dir, filepath.Dir(path)), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, path), []byte(old), 0600); err != nil {
+		t.Fatal(err)
+	}
+	patch := filepath.Join(dir, "sync.patch")
+	if err := os.WriteFile(patch, []byte(Diff(filepath.ToSlash(path), []byte(old), []byte(updated))), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := exec.Command("git", "apply", patch)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git apply failed: %v\n%s", err, out)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != updated {
+		t.Errorf("unexpected patched file:\n%s", data)
+	}
+}
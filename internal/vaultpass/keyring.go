@@ -0,0 +1,47 @@
+package vaultpass
+
+import (
+	"errors"
+
+	"github.com/launchrctl/keyring"
+	"github.com/launchrctl/launchr"
+)
+
+// KeyringKey is the keyring entry holding the vault password.
+const KeyringKey = "vaultpass"
+
+// Resolve returns the vault password given as is, else the one of the provider sources, of the keyring entry, or
+// prompted on the terminal with the title. Keyring is optional, e.g. when actions are embedded.
+func Resolve(pass string, p Provider, k keyring.Keyring, term *launchr.Terminal, title string) (string, error) {
+	if pass != "" {
+		return pass, nil
+	}
+
+	pass, err := p.Password()
+	if err != nil || pass != "" {
+		return pass, err
+	}
+
+	if k != nil {
+		item, errGet := k.GetForKey(KeyringKey)
+		if errGet == nil {
+			return item.Value.(string), nil
+		}
+		if !errors.Is(errGet, keyring.ErrNotFound) {
+			return "", errGet
+		}
+	}
+
+	return Prompt(term, title)
+}
+
+// Prompt reads a vault password from the terminal, printing the title first.
+func Prompt(term *launchr.Terminal, title string) (string, error) {
+	item := keyring.KeyValueItem{Key: KeyringKey, Value: ""}
+	term.Printf("- %s\n", title)
+	if err := keyring.RequestKeyValueFromTty(&item); err != nil {
+		return "", err
+	}
+
+	return item.Value.(string), nil
+}
@@ -0,0 +1,122 @@
+// Package vaultpass reads Ansible Vault password from non-interactive sources, so actions run unattended in CI.
+// Sources follow ansible-vault conventions: an executable password file is run as a client script and its output
+// is used, and ANSIBLE_VAULT_PASSWORD_FILE is read when no source is given.
+package vaultpass
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// EnvPasswordFile is the environment variable of the password file used by ansible-vault.
+const EnvPasswordFile = "ANSIBLE_VAULT_PASSWORD_FILE"
+
+// Provider declares sources of vault password, only one of them may be set.
+type Provider struct {
+	// Env is the name of environment variable holding the password.
+	Env string
+	// File is the path of a file holding the password, or of an executable printing it.
+	File string
+	// Cmd is a shell command printing the password, e.g. a secrets manager CLI.
+	Cmd string
+}
+
+// ProviderFrom reads sources of the vault-pass-env, vault-pass-file and vault-pass-cmd options of an action with the
+// option getter, e.g. [action.Input.Opt]. Prefix selects other options of the same kind, e.g. "new-".
+func ProviderFrom(opt func(name string) any, prefix string) Provider {
+	return Provider{
+		Env:  opt(prefix + "vault-pass-env").(string),
+		File: opt(prefix + "vault-pass-file").(string),
+		Cmd:  opt(prefix + "vault-pass-cmd").(string),
+	}
+}
+
+// Password returns vault password of the configured source, or an empty string if none is configured.
+func (p Provider) Password() (string, error) {
+	var set int
+	for _, source := range []string{p.Env, p.File, p.Cmd} {
+		if source != "" {
+			set++
+		}
+	}
+	if set > 1 {
+		return "", errors.New("only one of vault password environment variable, file or command can be used")
+	}
+
+	switch {
+	case p.Env != "":
+		pass, ok := os.LookupEnv(p.Env)
+		if !ok {
+			return "", fmt.Errorf("vault password environment variable %s is not set", p.Env)
+		}
+		return pass, nil
+	case p.File != "":
+		return fromFile(p.File)
+	case p.Cmd != "":
+		return fromCmd(p.Cmd)
+	}
+
+	if file := os.Getenv(EnvPasswordFile); file != "" {
+		return fromFile(file)
+	}
+
+	return "", nil
+}
+
+// fromFile reads password from the file, or from the output of the file if it is executable.
+func fromFile(path string) (string, error) {
+	path = filepath.Clean(path)
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("vault password file > %w", err)
+	}
+
+	if runtime.GOOS != "windows" && info.Mode()&0111 != 0 {
+		return run(exec.Command(path)) //nolint:gosec
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("vault password file > %w", err)
+	}
+
+	pass := strings.TrimSpace(string(data))
+	if pass == "" {
+		return "", fmt.Errorf("vault password file %s is empty", path)
+	}
+
+	return pass, nil
+}
+
+// fromCmd runs the command with the shell and reads password from its output.
+func fromCmd(command string) (string, error) {
+	if runtime.GOOS == "windows" {
+		return run(exec.Command("cmd", "/C", command)) //nolint:gosec
+	}
+	return run(exec.Command("sh", "-c", command)) //nolint:gosec
+}
+
+func run(cmd *exec.Cmd) (string, error) {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("vault password command %s > %w: %s", cmd.Path, err, msg)
+		}
+		return "", fmt.Errorf("vault password command %s > %w", cmd.Path, err)
+	}
+
+	pass := strings.TrimSpace(string(out))
+	if pass == "" {
+		return "", fmt.Errorf("vault password command %s printed nothing", cmd.Path)
+	}
+
+	return pass, nil
+}
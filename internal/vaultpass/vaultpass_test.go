@@ -0,0 +1,113 @@
+package vaultpass
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func expectPassword(t *testing.T, p Provider, expected string) {
+	t.Helper()
+	pass, err := p.Password()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pass != expected {
+		t.Errorf("expected %q, got %q", expected, pass)
+	}
+}
+
+func TestPassword(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "vault-pass")
+	if err := os.WriteFile(file, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_VAULT_PASS", "from-env")
+	t.Setenv(EnvPasswordFile, "")
+
+	expectPassword(t, Provider{}, "")
+	expectPassword(t, Provider{Env: "TEST_VAULT_PASS"}, "from-env")
+	expectPassword(t, Provider{File: file}, "from-file")
+
+	if runtime.GOOS == "windows" {
+		return
+	}
+
+	script := filepath.Join(dir, "vault-client")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\necho from-script\n"), 0700); err != nil { //nolint:gosec
+		t.Fatal(err)
+	}
+	expectPassword(t, Provider{File: script}, "from-script")
+	expectPassword(t, Provider{Cmd: "echo from-cmd"}, "from-cmd")
+}
+
+func TestPasswordErrors(t *testing.T) {
+	t.Setenv(EnvPasswordFile, "")
+
+	for name, p := range map[string]Provider{
+		"unset env":       {Env: "TEST_VAULT_PASS_UNSET"},
+		"missing file":    {File: filepath.Join(t.TempDir(), "missing")},
+		"failing command": {Cmd: "exit 1"},
+		"several sources": {Env: "TEST_VAULT_PASS", Cmd: "echo pass"},
+	} {
+		if _, err := p.Password(); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestPasswordAnsibleEnv(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "vault-pass")
+	if err := os.WriteFile(file, []byte("ansible"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(EnvPasswordFile, file)
+
+	pass, err := Provider{}.Password()
+	if err != nil || pass != "ansible" {
+		t.Errorf("expected password from %s, got %q, %v", EnvPasswordFile, pass, err)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	t.Setenv("TEST_VAULT_PASS", "from-env")
+	t.Setenv(EnvPasswordFile, "")
+
+	for _, tt := range []struct {
+		pass     string
+		provider Provider
+		expected string
+	}{
+		{"given", Provider{Env: "TEST_VAULT_PASS"}, "given"},
+		{"", Provider{Env: "TEST_VAULT_PASS"}, "from-env"},
+	} {
+		pass, err := Resolve(tt.pass, tt.provider, nil, nil, "Ansible vault password")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pass != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, pass)
+		}
+	}
+
+	if _, err := Resolve("", Provider{Env: "TEST_VAULT_PASS_MISSING"}, nil, nil, "Ansible vault password"); err == nil {
+		t.Error("expected error of the provider to be returned")
+	}
+}
+
+func TestProviderFrom(t *testing.T) {
+	opts := map[string]any{
+		"vault-pass-env": "VAULT_PASS", "vault-pass-file": "", "vault-pass-cmd": "",
+		"new-vault-pass-env": "", "new-vault-pass-file": "new-pass", "new-vault-pass-cmd": "",
+	}
+	opt := func(name string) any { return opts[name] }
+
+	if p := ProviderFrom(opt, ""); p != (Provider{Env: "VAULT_PASS"}) {
+		t.Errorf("expected current password from env, got %+v", p)
+	}
+	if p := ProviderFrom(opt, "new-"); p != (Provider{File: "new-pass"}) {
+		t.Errorf("expected new password from file, got %+v", p)
+	}
+}
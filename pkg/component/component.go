@@ -3,7 +3,10 @@
 package component
 
 import (
-	"os"
+	"context"
+	"errors"
+	"io/fs"
+	"path"
 	"path/filepath"
 	"strings"
 
@@ -39,42 +42,36 @@ func (c Component) DisplayName() string {
 	return FormatDisplayName(c.Name, c.Version)
 }
 
-// plasmaMeta represents the structure of meta/plasma.yaml
-type plasmaMeta struct {
-	Plasma struct {
-		Version string `yaml:"version"`
-	} `yaml:"plasma"`
-}
-
-// readVersion reads the version from a component's meta/plasma.yaml file.
-func readVersion(metaPath string) string {
-	data, err := os.ReadFile(metaPath)
-	if err != nil {
-		return ""
-	}
-	var meta plasmaMeta
-	if err := yaml.Unmarshal(data, &meta); err != nil {
-		return ""
-	}
-	return meta.Plasma.Version
-}
-
 // Attachment represents a component attached to a chassis path.
 type Attachment struct {
 	Component string
 	Playbook  string
 	Chassis   string
+	Line      int            // Line of the role name in the playbook
+	Column    int            // Column of the role name in the playbook
+	Vars      map[string]any // Role vars, if declared in the extended form (role and vars)
 }
 
 // LoadFromPlaybooks discovers components from layer playbooks.
 // It scans src/<layer>/<layer>.yaml files for role declarations.
 func LoadFromPlaybooks(dir string) (Components, error) {
+	components, err := LoadFromPlaybooksFS(dirFS(dir))
+	for i := range components {
+		components[i].Playbook = filepath.Join(dir, filepath.FromSlash(components[i].Playbook))
+	}
+	return components, err
+}
+
+// LoadFromPlaybooksFS discovers components from layer playbooks of a filesystem like LoadFromPlaybooks.
+// Playbook paths are relative to the filesystem root.
+func LoadFromPlaybooksFS(fsys fs.FS) (Components, error) {
 	var components Components
+	var candidates [][]string
 
-	srcDir := filepath.Join(dir, "src")
-	entries, err := os.ReadDir(srcDir)
+	srcDir := "src"
+	entries, err := fs.ReadDir(fsys, srcDir)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
 		}
 		return nil, err
@@ -86,8 +83,8 @@ func LoadFromPlaybooks(dir string) (Components, error) {
 		}
 
 		layer := entry.Name()
-		playbookPath := filepath.Join(srcDir, layer, layer+".yaml")
-		data, err := os.ReadFile(playbookPath)
+		playbookPath := path.Join(srcDir, layer, layer+".yaml")
+		data, err := fs.ReadFile(fsys, playbookPath)
 		if err != nil {
 			continue
 		}
@@ -112,40 +109,34 @@ func LoadFromPlaybooks(dir string) (Components, error) {
 						roleName = name
 					}
 				}
-				if roleName != "" {
-					// Try to read version from meta/plasma.yaml
-					version := ""
-					parts := strings.Split(roleName, ".")
-					if len(parts) >= 3 {
-						// Try src/ first (may have newer changes not yet composed)
-						// roles/ structure
-						metaPath := filepath.Join(srcDir, parts[0], parts[1], "roles", parts[2], "meta", "plasma.yaml")
-						version = readVersion(metaPath)
-						// flat structure
-						if version == "" {
-							metaPath = filepath.Join(srcDir, parts[0], parts[1], parts[2], "meta", "plasma.yaml")
-							version = readVersion(metaPath)
-						}
+				if roleName == "" {
+					continue
+				}
+
+				// Versions are read once all roles are known, concurrently.
+				var metas []string
+				if parts := strings.Split(roleName, "."); len(parts) >= 3 {
+					metas = []string{
+						// Try src/ first (may have newer changes not yet composed), roles/ then flat structure
+						path.Join(srcDir, parts[0], parts[1], "roles", parts[2], "meta", "plasma.yaml"),
+						path.Join(srcDir, parts[0], parts[1], parts[2], "meta", "plasma.yaml"),
 						// Fallback to composed directory (for components from packages)
-						if version == "" {
-							composedDir := filepath.Join(dir, model.MergedSrcDir)
-							metaPath = filepath.Join(composedDir, parts[0], parts[1], parts[2], "meta", "plasma.yaml")
-							version = readVersion(metaPath)
-						}
+						path.Join(filepath.ToSlash(model.MergedSrcDir), parts[0], parts[1], parts[2], "meta", "plasma.yaml"),
 					}
-					components = append(components, Component{
-						Name:     roleName,
-						Kind:     extractKind(roleName),
-						Layer:    layer,
-						Version:  version,
-						Playbook: playbookPath,
-						Chassis:  play.Hosts,
-					})
 				}
+				candidates = append(candidates, metas)
+				components = append(components, Component{
+					Name:     roleName,
+					Kind:     extractKind(roleName),
+					Layer:    layer,
+					Playbook: playbookPath,
+					Chassis:  play.Hosts,
+				})
 			}
 		}
 	}
 
+	readVersions(fsys, components, candidates, 0)
 	return components, nil
 }
 
@@ -171,154 +162,113 @@ func LoadFromFilesystem(dir string) (Components, error) {
 // Auto-detects whether components use roles/ subdirectory structure.
 // Valid components must have a meta/plasma.yaml file.
 func LoadFromPath(basePath string) (Components, error) {
-	var components Components
+	return LoadContext(context.Background(), basePath, LoadOptions{})
+}
+
+// LoadAttachments scans playbooks for component attachments to chassis paths.
+// If chassisPath is empty, returns all attachments.
+// If chassisPath is specified, returns attachments for that path and its children.
+func LoadAttachments(dir, chassisPath string) ([]Attachment, error) {
+	attachments, err := LoadAttachmentsFS(dirFS(dir), chassisPath)
+	for i := range attachments {
+		attachments[i].Playbook = filepath.Join(dir, filepath.FromSlash(attachments[i].Playbook))
+	}
+	return attachments, err
+}
 
-	layers, err := os.ReadDir(basePath)
+// LoadAttachmentsFS scans playbooks of a filesystem for component attachments like LoadAttachments.
+// Playbook paths are relative to the filesystem root.
+func LoadAttachmentsFS(fsys fs.FS, chassisPath string) ([]Attachment, error) {
+	var attachments []Attachment
+
+	srcDir := "src"
+	entries, err := fs.ReadDir(fsys, srcDir)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, nil
 		}
 		return nil, err
 	}
 
-	for _, layer := range layers {
-		if !layer.IsDir() {
+	for _, entry := range entries {
+		if !entry.IsDir() {
 			continue
 		}
-		layerName := layer.Name()
 
-		// Skip hidden directories
-		if strings.HasPrefix(layerName, ".") {
+		playbookPath := path.Join(srcDir, entry.Name(), entry.Name()+".yaml")
+		data, err := fs.ReadFile(fsys, playbookPath)
+		if err != nil {
 			continue
 		}
 
-		layerPath := filepath.Join(basePath, layerName)
-
-		// Scan component kinds (applications, services, flows, etc.)
-		kinds, err := os.ReadDir(layerPath)
-		if err != nil {
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil || len(root.Content) == 0 || root.Content[0].Kind != yaml.SequenceNode {
 			continue
 		}
 
-		for _, kind := range kinds {
-			if !kind.IsDir() {
-				continue
-			}
-			kindName := kind.Name()
-
-			// Skip non-component directories
-			if kindName == "group_vars" || kindName == "host_vars" || strings.HasSuffix(kindName, ".yaml") {
+		for _, play := range root.Content[0].Content {
+			roles := mappingValue(play, "roles")
+			if roles == nil || roles.Kind != yaml.SequenceNode {
 				continue
 			}
 
-			kindPath := filepath.Join(layerPath, kindName)
-
-			// Auto-detect roles/ subdirectory structure
-			rolesPath := filepath.Join(kindPath, "roles")
-			if stat, err := os.Stat(rolesPath); err == nil && stat.IsDir() {
-				kindPath = rolesPath
+			hosts := ""
+			if n := mappingValue(play, "hosts"); n != nil {
+				hosts = n.Value
 			}
 
-			// Scan component names
-			names, err := os.ReadDir(kindPath)
-			if err != nil {
-				continue
-			}
-
-			for _, name := range names {
-				if !name.IsDir() {
-					continue
-				}
-				componentName := name.Name()
-
-				// Skip special directories
-				if componentName == "roles" || strings.HasPrefix(componentName, ".") {
+			// Match chassis path filter
+			if chassisPath != "" {
+				if hosts != chassisPath && !strings.HasPrefix(hosts, chassisPath+".") {
 					continue
 				}
+			}
 
-				// Verify this is a valid component by checking for meta/plasma.yaml
-				metaPath := filepath.Join(kindPath, componentName, "meta", "plasma.yaml")
-				if _, err := os.Stat(metaPath); os.IsNotExist(err) {
+			for _, role := range roles.Content {
+				a, ok := roleAttachment(role)
+				if !ok {
 					continue
 				}
 
-				// Component name: layer.kind.name
-				fullName := layerName + "." + kindName + "." + componentName
-				components = append(components, Component{
-					Name:    fullName,
-					Kind:    kindName,
-					Layer:   layerName,
-					Version: readVersion(metaPath),
-				})
+				a.Playbook = playbookPath
+				a.Chassis = hosts
+				attachments = append(attachments, a)
 			}
 		}
 	}
 
-	return components, nil
+	return attachments, nil
 }
 
-// LoadAttachments scans playbooks for component attachments to chassis paths.
-// If chassisPath is empty, returns all attachments.
-// If chassisPath is specified, returns attachments for that path and its children.
-func LoadAttachments(dir, chassisPath string) ([]Attachment, error) {
-	var attachments []Attachment
-
-	srcDir := filepath.Join(dir, "src")
-	entries, err := os.ReadDir(srcDir)
-	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, nil
+// roleAttachment returns attachment of a role declared either as a name or as a map with role and vars.
+func roleAttachment(role *yaml.Node) (Attachment, bool) {
+	name := role
+	var vars map[string]any
+	if role.Kind == yaml.MappingNode {
+		name = mappingValue(role, "role")
+		if v := mappingValue(role, "vars"); v != nil {
+			_ = v.Decode(&vars)
 		}
-		return nil, err
 	}
 
-	for _, entry := range entries {
-		if !entry.IsDir() {
-			continue
-		}
-
-		playbookPath := filepath.Join(srcDir, entry.Name(), entry.Name()+".yaml")
-		data, err := os.ReadFile(playbookPath)
-		if err != nil {
-			continue
-		}
+	if name == nil || name.Kind != yaml.ScalarNode || name.Value == "" {
+		return Attachment{}, false
+	}
 
-		var plays []struct {
-			Hosts string        `yaml:"hosts"`
-			Roles []interface{} `yaml:"roles"`
-		}
-		if err := yaml.Unmarshal(data, &plays); err != nil {
-			continue
-		}
+	return Attachment{Component: name.Value, Line: name.Line, Column: name.Column, Vars: vars}, true
+}
 
-		for _, play := range plays {
-			// Match chassis path filter
-			if chassisPath != "" {
-				if play.Hosts != chassisPath && !strings.HasPrefix(play.Hosts, chassisPath+".") {
-					continue
-				}
-			}
+// mappingValue returns value node of the key in a mapping node, or nil.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
 
-			for _, r := range play.Roles {
-				var roleName string
-				switch role := r.(type) {
-				case string:
-					roleName = role
-				case map[string]interface{}:
-					if name, ok := role["role"].(string); ok {
-						roleName = name
-					}
-				}
-				if roleName != "" {
-					attachments = append(attachments, Attachment{
-						Component: roleName,
-						Playbook:  playbookPath,
-						Chassis:   play.Hosts,
-					})
-				}
-			}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
 		}
 	}
 
-	return attachments, nil
+	return nil
 }
@@ -0,0 +1,148 @@
+package component
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+
+	"github.com/plasmash/plasmactl-model/pkg/model"
+)
+
+func TestLoadAttachmentsSource(t *testing.T) {
+	dir := t.TempDir()
+	playbook := filepath.Join(dir, "src", "interaction", "interaction.yaml")
+	if err := os.MkdirAll(filepath.Dir(playbook), 0750); err != nil {
+		t.Fatal(err)
+	}
+
+	data := `- hosts: platform.interaction.observability
+  roles:
+    - interaction.applications.dashboards
+    - role: foundation.applications.auth
+      vars:
+        replicas: 2
+`
+	if err := os.WriteFile(playbook, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	attachments, err := LoadAttachments(dir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Attachment{
+		{Component: "interaction.applications.dashboards", Playbook: playbook, Chassis: "platform.interaction.observability", Line: 3, Column: 7},
+		{Component: "foundation.applications.auth", Playbook: playbook, Chassis: "platform.interaction.observability", Line: 4, Column: 13, Vars: map[string]any{"replicas": 2}},
+	}
+	if !reflect.DeepEqual(attachments, expected) {
+		t.Errorf("expected %+v, got %+v", expected, attachments)
+	}
+}
+
+func TestLoadFromPlaybooksVersions(t *testing.T) {
+	dir := t.TempDir()
+	write := func(path, content string) {
+		t.Helper()
+		path = filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("src/interaction/interaction.yaml", `- hosts: platform.interaction.observability
+  roles:
+    - interaction.applications.dashboards
+    - interaction.services.grafana
+    - interaction.services.loki
+    - interaction.services.missing
+- hosts: platform.interaction.monitoring
+  roles:
+    - interaction.applications.dashboards
+`)
+	write("src/interaction/applications/roles/dashboards/meta/plasma.yaml", "plasma:\n  version: aaa1111111111\n")
+	write("src/interaction/services/grafana/meta/plasma.yaml", "plasma:\n  version: bbb2222222222\n")
+	write(filepath.Join(model.MergedSrcDir, "interaction/services/loki/meta/plasma.yaml"), "plasma:\n  version: ccc3333333333\n")
+
+	components, err := LoadFromPlaybooks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var versions []string
+	for _, c := range components {
+		versions = append(versions, c.DisplayName())
+	}
+	expected := []string{
+		"interaction.applications.dashboards@aaa1111111111",
+		"interaction.services.grafana@bbb2222222222",
+		"interaction.services.loki@ccc3333333333",
+		"interaction.services.missing@-",
+		"interaction.applications.dashboards@aaa1111111111",
+	}
+	if !reflect.DeepEqual(versions, expected) {
+		t.Errorf("expected %v, got %v", expected, versions)
+	}
+}
+
+func TestLoadFS(t *testing.T) {
+	meta := func(version string) *fstest.MapFile {
+		return &fstest.MapFile{Data: []byte("plasma:\n  version: " + version + "\n")}
+	}
+	fsys := fstest.MapFS{
+		"src/interaction/interaction.yaml": &fstest.MapFile{Data: []byte(`- hosts: platform.interaction.observability
+  roles:
+    - interaction.applications.dashboards
+`)},
+		"src/interaction/applications/roles/dashboards/meta/plasma.yaml": meta("aaa1111111111"),
+		"src/foundation/services/postgres/meta/plasma.yaml":              meta("bbb2222222222"),
+	}
+
+	components, err := LoadFS(context.Background(), fsys, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(components) != 0 {
+		t.Errorf("expected no components at the root, got %v", components)
+	}
+
+	sub, err := fs.Sub(fsys, "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	components, err = LoadFS(context.Background(), sub, LoadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, c := range components {
+		names = append(names, c.DisplayName())
+	}
+	expected := []string{"foundation.services.postgres@bbb2222222222", "interaction.applications.dashboards@aaa1111111111"}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %v, got %v", expected, names)
+	}
+
+	attachments, err := LoadAttachmentsFS(fsys, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attachments) != 1 || attachments[0].Playbook != "src/interaction/interaction.yaml" {
+		t.Errorf("expected the attachment of the fs playbook, got %+v", attachments)
+	}
+
+	attached, err := LoadFromPlaybooksFS(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(attached) != 1 || attached[0].DisplayName() != "interaction.applications.dashboards@aaa1111111111" {
+		t.Errorf("expected the versioned attached component, got %+v", attached)
+	}
+}
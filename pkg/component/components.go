@@ -1,7 +1,9 @@
 package component
 
 import (
+	"path/filepath"
 	"sort"
+	"strings"
 
 	"github.com/plasmash/plasmactl-chassis/pkg/chassis"
 )
@@ -28,28 +30,114 @@ func (cs Components) Names() []string {
 	return names
 }
 
-// ByKind returns components filtered by kind.
-func (cs Components) ByKind(kind string) Components {
+// Filter returns components matching the predicate.
+func (cs Components) Filter(match func(Component) bool) Components {
 	var result Components
 	for _, c := range cs {
-		if c.Kind == kind {
+		if match(c) {
 			result = append(result, c)
 		}
 	}
 	return result
 }
 
+// ByKind returns components filtered by kind.
+func (cs Components) ByKind(kind string) Components {
+	return cs.Filter(func(c Component) bool {
+		return c.Kind == kind
+	})
+}
+
 // ByLayer returns components filtered by layer.
 func (cs Components) ByLayer(layer string) Components {
-	var result Components
+	return cs.Filter(func(c Component) bool {
+		return c.Layer == layer
+	})
+}
+
+// Field returns the value of a component field, used as a grouping key.
+type Field func(Component) string
+
+// Component fields to group by.
+var (
+	FieldKind     Field = func(c Component) string { return c.Kind }
+	FieldLayer    Field = func(c Component) string { return c.Layer }
+	FieldVersion  Field = func(c Component) string { return c.Version }
+	FieldPlaybook Field = func(c Component) string { return c.Playbook }
+	FieldChassis  Field = func(c Component) string { return c.Chassis }
+)
+
+// GroupBy returns components grouped by the value of the field, keeping their order within groups.
+func (cs Components) GroupBy(field Field) map[string]Components {
+	result := make(map[string]Components)
 	for _, c := range cs {
-		if c.Layer == layer {
-			result = append(result, c)
+		key := field(c)
+		result[key] = append(result[key], c)
+	}
+	return result
+}
+
+// Versions returns a map of component name to version.
+// A component listed several times, e.g. once per attachment, keeps its first version.
+func (cs Components) Versions() map[string]string {
+	result := make(map[string]string, len(cs))
+	for _, c := range cs {
+		if _, ok := result[c.Name]; !ok {
+			result[c.Name] = c.Version
 		}
 	}
 	return result
 }
 
+// VersionChange is a component which version differs between two collections.
+type VersionChange struct {
+	Name       string `json:"name"`
+	OldVersion string `json:"old_version"`
+	NewVersion string `json:"new_version"`
+}
+
+// ComponentsDiff is the difference of a collection against another one, sorted by name.
+type ComponentsDiff struct {
+	Added   []string        `json:"added,omitempty"`
+	Removed []string        `json:"removed,omitempty"`
+	Changed []VersionChange `json:"changed,omitempty"`
+}
+
+// Empty reports whether both collections have the same components and versions.
+func (d ComponentsDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffAgainst compares components with the other collection taken as the old state:
+// components missing from other are added, components missing from cs are removed.
+func (cs Components) DiffAgainst(other Components) ComponentsDiff {
+	current, old := cs.Versions(), other.Versions()
+
+	var diff ComponentsDiff
+	for name, version := range current {
+		oldVersion, ok := old[name]
+		switch {
+		case !ok:
+			diff.Added = append(diff.Added, name)
+		case oldVersion != version:
+			diff.Changed = append(diff.Changed, VersionChange{Name: name, OldVersion: oldVersion, NewVersion: version})
+		}
+	}
+	for name := range old {
+		if _, ok := current[name]; !ok {
+			diff.Removed = append(diff.Removed, name)
+		}
+	}
+
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.Changed, func(i, j int) bool {
+		return diff.Changed[i].Name < diff.Changed[j].Name
+	})
+
+	return diff
+}
+
 // Attachments returns a map of component name to chassis paths.
 // Unlike node allocations, component attachments don't use distribution -
 // they are explicit bindings defined in playbooks.
@@ -75,15 +163,17 @@ func (cs Components) Attachments(_ *chassis.Chassis) map[string][]string {
 	return result
 }
 
+// MatchesChassis reports whether a component attached to the attached chassis path is served by chassisPath.
+// With inherit, attachments to descendants of chassisPath match as well.
+func MatchesChassis(attached, chassisPath string, inherit bool) bool {
+	return attached == chassisPath || inherit && chassis.IsDescendantOf(attached, chassisPath)
+}
+
 // ForChassis returns components attached to a chassis path or its children.
 func (cs Components) ForChassis(chassisPath string) Components {
-	var result Components
-	for _, c := range cs {
-		if c.Chassis == chassisPath || chassis.IsDescendantOf(c.Chassis, chassisPath) {
-			result = append(result, c)
-		}
-	}
-	return result
+	return cs.Filter(func(c Component) bool {
+		return MatchesChassis(c.Chassis, chassisPath, true)
+	})
 }
 
 // appendUnique appends value to slice if not already present.
@@ -95,3 +185,153 @@ func appendUnique(slice []string, value string) []string {
 	}
 	return append(slice, value)
 }
+
+// maxSuggestionDistance is the maximum edit distance of a chassis path suggested for a misspelled one.
+const maxSuggestionDistance = 3
+
+// InvalidAttachment is an attachment to a chassis path missing from the chassis model.
+type InvalidAttachment struct {
+	Component  string `json:"component"`
+	Chassis    string `json:"chassis"`
+	Playbook   string `json:"playbook"`
+	Line       int    `json:"line,omitempty"`
+	Column     int    `json:"column,omitempty"`
+	Suggestion string `json:"suggestion,omitempty"`
+}
+
+// InvalidAttachments returns attachments which chassis path doesn't exist in the chassis model.
+// The closest existing path is suggested when the path looks misspelled.
+func InvalidAttachments(attachments []Attachment, c *chassis.Chassis) []InvalidAttachment {
+	paths := c.Flatten()
+	existing := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		existing[p] = true
+	}
+
+	var result []InvalidAttachment
+	for _, a := range attachments {
+		if existing[a.Chassis] {
+			continue
+		}
+
+		invalid := InvalidAttachment{Component: a.Component, Chassis: a.Chassis, Playbook: a.Playbook, Line: a.Line, Column: a.Column}
+		best := maxSuggestionDistance + 1
+		for _, p := range paths {
+			if d := editDistance(a.Chassis, p); d < best {
+				best = d
+				invalid.Suggestion = p
+			}
+		}
+		result = append(result, invalid)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Chassis != result[j].Chassis {
+			return result[i].Chassis < result[j].Chassis
+		}
+		return result[i].Component < result[j].Component
+	})
+
+	return result
+}
+
+// editDistance returns Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
+
+// DuplicateAttachment is an attachment of a component to a chassis path it is already attached to, by another
+// play or another layer playbook. Ansible runs the role once per occurrence.
+type DuplicateAttachment struct {
+	Component string `json:"component"`
+	Chassis   string `json:"chassis"`
+	Playbook  string `json:"playbook"`
+	Line      int    `json:"line,omitempty"`
+	Column    int    `json:"column,omitempty"`
+	// Canonical is the occurrence kept: the first one of the playbook of the component layer, the first one otherwise.
+	Canonical Attachment `json:"canonical"`
+}
+
+// DuplicateAttachments returns attachments repeating a component and chassis path pair, each with its canonical
+// occurrence.
+func DuplicateAttachments(attachments []Attachment) []DuplicateAttachment {
+	type key struct{ component, chassis string }
+	occurrences := make(map[key][]Attachment)
+	var keys []key
+	for _, a := range attachments {
+		k := key{a.Component, a.Chassis}
+		if _, ok := occurrences[k]; !ok {
+			keys = append(keys, k)
+		}
+		occurrences[k] = append(occurrences[k], a)
+	}
+
+	var result []DuplicateAttachment
+	for _, k := range keys {
+		list := occurrences[k]
+		if len(list) < 2 {
+			continue
+		}
+
+		canonical := 0
+		for i, a := range list {
+			if layerOfPlaybook(a.Playbook) == extractLayer(a.Component) {
+				canonical = i
+				break
+			}
+		}
+
+		for i, a := range list {
+			if i == canonical {
+				continue
+			}
+			result = append(result, DuplicateAttachment{
+				Component: a.Component,
+				Chassis:   a.Chassis,
+				Playbook:  a.Playbook,
+				Line:      a.Line,
+				Column:    a.Column,
+				Canonical: list[canonical],
+			})
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Chassis != result[j].Chassis {
+			return result[i].Chassis < result[j].Chassis
+		}
+		return result[i].Component < result[j].Component
+	})
+
+	return result
+}
+
+// layerOfPlaybook returns the layer of a layer playbook path, named after its layer directory.
+func layerOfPlaybook(playbook string) string {
+	return strings.TrimSuffix(filepath.Base(playbook), filepath.Ext(playbook))
+}
+
+// extractLayer extracts the layer from a full component name.
+// e.g., "interaction.applications.dashboards" -> "interaction"
+func extractLayer(name string) string {
+	layer, _, _ := strings.Cut(name, ".")
+	return layer
+}
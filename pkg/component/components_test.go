@@ -0,0 +1,47 @@
+package component
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testComponents() Components {
+	return Components{
+		{Name: "foundation.services.postgres", Kind: "services", Layer: "foundation", Version: "aaa"},
+		{Name: "foundation.applications.auth", Kind: "applications", Layer: "foundation", Version: "bbb", Chassis: "platform.foundation"},
+		{Name: "foundation.applications.auth", Kind: "applications", Layer: "foundation", Version: "bbb", Chassis: "platform.interaction"},
+		{Name: "interaction.applications.dashboards", Kind: "applications", Layer: "interaction", Version: "ccc"},
+	}
+}
+
+func TestComponentsGroupBy(t *testing.T) {
+	groups := testComponents().GroupBy(FieldLayer)
+	if len(groups) != 2 || len(groups["foundation"]) != 3 || len(groups["interaction"]) != 1 {
+		t.Fatalf("unexpected groups %v", groups)
+	}
+
+	attached := testComponents().Filter(func(c Component) bool { return c.Chassis != "" })
+	if names := attached.GroupBy(FieldChassis); len(names) != 2 {
+		t.Errorf("expected 2 chassis groups, got %v", names)
+	}
+}
+
+func TestComponentsDiffAgainst(t *testing.T) {
+	old := testComponents()
+	current := append(testComponents()[1:], Component{Name: "foundation.services.redis", Version: "ddd"})
+	current[2].Version = "eee"
+
+	diff := current.DiffAgainst(old)
+	expected := ComponentsDiff{
+		Added:   []string{"foundation.services.redis"},
+		Removed: []string{"foundation.services.postgres"},
+		Changed: []VersionChange{{Name: "interaction.applications.dashboards", OldVersion: "ccc", NewVersion: "eee"}},
+	}
+	if !reflect.DeepEqual(diff, expected) {
+		t.Errorf("expected %+v, got %+v", expected, diff)
+	}
+
+	if !old.DiffAgainst(testComponents()).Empty() {
+		t.Error("expected no difference between identical collections")
+	}
+}
@@ -0,0 +1,334 @@
+package component
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/launchrctl/compose/compose"
+	"github.com/plasmash/plasmactl-model/pkg/model"
+)
+
+// Progress is called after each kind directory is scanned, with the number of scanned and total directories.
+// Calls are serialized.
+type Progress func(done, total int)
+
+// LoadOptions configures LoadContext and LoadFS.
+type LoadOptions struct {
+	Workers  int      // Concurrent directory scans, runtime.NumCPU() if not positive
+	Progress Progress // Optional progress callback
+}
+
+// kindDir is a directory holding components of a layer kind, path is relative to the loaded filesystem.
+type kindDir struct {
+	layer string
+	kind  string
+	path  string
+}
+
+// dirFS returns the filesystem of the directory, the working directory if empty.
+func dirFS(dir string) fs.FS {
+	if dir == "" {
+		dir = "."
+	}
+	return os.DirFS(dir)
+}
+
+// LoadContext discovers components from a given base path like LoadFromPath,
+// scanning kind directories concurrently. Components are returned in the same order as LoadFromPath.
+// Loading stops with the context error once ctx is cancelled.
+func LoadContext(ctx context.Context, basePath string, opts LoadOptions) (Components, error) {
+	return LoadFS(ctx, dirFS(basePath), opts)
+}
+
+// LoadFS discovers components from the root of a filesystem like LoadContext,
+// e.g. an in-memory or embedded filesystem, or a tree of a git commit.
+func LoadFS(ctx context.Context, fsys fs.FS, opts LoadOptions) (Components, error) {
+	dirs, err := kindDirs(ctx, fsys)
+	if err != nil || len(dirs) == 0 {
+		return nil, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	scanned := make([]Components, len(dirs))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	var mx sync.Mutex
+	done := 0
+
+	for w := 0; w < min(workers, len(dirs)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				scanned[i] = scanKindDir(ctx, fsys, dirs[i])
+				if opts.Progress == nil {
+					continue
+				}
+				mx.Lock()
+				done++
+				opts.Progress(done, len(dirs))
+				mx.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for i := range dirs {
+		select {
+		case <-ctx.Done():
+			break feed
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var components Components
+	for _, cs := range scanned {
+		components = append(components, cs...)
+	}
+
+	return components, nil
+}
+
+// LoadAttached discovers all components of the composed output like LoadFromFilesystem, with their playbook attachments.
+// Without composed output, components of the domain and its packages are merged with LoadComposed.
+// A component attached to several chassis paths is returned once per attachment, unattached components have no Chassis.
+// It is used when the platform graph isn't available.
+func LoadAttached(ctx context.Context, dir string, opts LoadOptions) (Components, error) {
+	var components Components
+	var err error
+	if _, errStat := os.Stat(filepath.Join(dir, model.MergedSrcDir)); errStat == nil {
+		components, err = LoadContext(ctx, filepath.Join(dir, model.MergedSrcDir), opts)
+	} else {
+		components, err = LoadComposed(ctx, dir, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := LoadAttachments(dir, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return attach(components, attachments), nil
+}
+
+// LoadAttachedFS discovers components of a domain filesystem with their playbook attachments like LoadAttached,
+// e.g. of a git commit tree. Components are read from SourcesFS, packages aren't part of the domain and aren't loaded.
+func LoadAttachedFS(ctx context.Context, fsys fs.FS, opts LoadOptions) (Components, error) {
+	components, err := LoadFS(ctx, SourcesFS(fsys), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := LoadAttachmentsFS(fsys, "")
+	if err != nil {
+		return nil, err
+	}
+
+	return attach(components, attachments), nil
+}
+
+// SourcesFS returns the filesystem of components of a domain filesystem, its src/ directory if it exists,
+// the root otherwise.
+func SourcesFS(fsys fs.FS) fs.FS {
+	if stat, err := fs.Stat(fsys, "src"); err == nil && stat.IsDir() {
+		if sub, errSub := fs.Sub(fsys, "src"); errSub == nil {
+			return sub
+		}
+	}
+
+	return fsys
+}
+
+// attach returns components once per attachment, unattached components once without Chassis.
+func attach(components Components, attachments []Attachment) Components {
+	attached := make(map[string][]Attachment)
+	for _, a := range attachments {
+		attached[a.Component] = append(attached[a.Component], a)
+	}
+
+	var result Components
+	for _, c := range components {
+		if len(attached[c.Name]) == 0 {
+			result = append(result, c)
+			continue
+		}
+
+		for _, a := range attached[c.Name] {
+			c.Playbook = a.Playbook
+			c.Chassis = a.Chassis
+			result = append(result, c)
+		}
+	}
+
+	return result
+}
+
+// LoadComposed discovers components of the domain directory and of its compose packages, scanning them concurrently.
+// Components are merged by compose priority: the domain wins over packages, later packages over earlier ones.
+// Packages missing from disk are ignored. Progress, if set, is called after each domain or package is scanned.
+func LoadComposed(ctx context.Context, dir string, opts LoadOptions) (Components, error) {
+	// Lowest priority first.
+	var paths []string
+	if plasmaCompose, err := compose.Lookup(os.DirFS(dir)); err == nil {
+		for _, dep := range plasmaCompose.Dependencies {
+			pkg := dep.ToPackage(dep.Name)
+			paths = append(paths, filepath.Join(dir, model.PackagesDir, pkg.GetName(), pkg.GetTarget()))
+		}
+	}
+	paths = append(paths, dir)
+
+	scanned := make([]Components, len(paths))
+	errs := make([]error, len(paths))
+	var wg sync.WaitGroup
+	var mx sync.Mutex
+	done := 0
+
+	// Namespaces share workers, as each of them scans kind directories concurrently.
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	nsOpts := LoadOptions{Workers: max(1, workers/len(paths))}
+
+	for i, nsPath := range paths {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			scanned[i], errs[i] = LoadContext(ctx, nsPath, nsOpts)
+			if opts.Progress == nil {
+				return
+			}
+			mx.Lock()
+			done++
+			opts.Progress(done, len(paths))
+			mx.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	index := make(map[string]int)
+	var components Components
+	for _, cs := range scanned {
+		for _, c := range cs {
+			if i, ok := index[c.Name]; ok {
+				components[i] = c
+				continue
+			}
+			index[c.Name] = len(components)
+			components = append(components, c)
+		}
+	}
+
+	return components, nil
+}
+
+// kindDirs lists kind directories of the filesystem, detecting roles/ subdirectory structure.
+func kindDirs(ctx context.Context, fsys fs.FS) ([]kindDir, error) {
+	layers, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var dirs []kindDir
+	for _, layer := range layers {
+		if err = ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		// Skip hidden directories
+		layerName := layer.Name()
+		if !layer.IsDir() || strings.HasPrefix(layerName, ".") {
+			continue
+		}
+
+		kinds, err := fs.ReadDir(fsys, layerName)
+		if err != nil {
+			continue
+		}
+
+		for _, kind := range kinds {
+			kindName := kind.Name()
+
+			// Skip non-component directories
+			if !kind.IsDir() || kindName == "group_vars" || kindName == "host_vars" || strings.HasSuffix(kindName, ".yaml") {
+				continue
+			}
+
+			kindPath := path.Join(layerName, kindName)
+
+			// Auto-detect roles/ subdirectory structure
+			rolesPath := path.Join(kindPath, "roles")
+			if stat, err := fs.Stat(fsys, rolesPath); err == nil && stat.IsDir() {
+				kindPath = rolesPath
+			}
+
+			dirs = append(dirs, kindDir{layer: layerName, kind: kindName, path: kindPath})
+		}
+	}
+
+	return dirs, nil
+}
+
+// scanKindDir returns valid components of the kind directory, having a meta/plasma.yaml file.
+func scanKindDir(ctx context.Context, fsys fs.FS, dir kindDir) Components {
+	names, err := fs.ReadDir(fsys, dir.path)
+	if err != nil {
+		return nil
+	}
+
+	var components Components
+	for _, name := range names {
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// Skip special directories
+		componentName := name.Name()
+		if !name.IsDir() || componentName == "roles" || strings.HasPrefix(componentName, ".") {
+			continue
+		}
+
+		version, found := readMeta(fsys, path.Join(dir.path, componentName, "meta", "plasma.yaml"))
+		if !found {
+			continue
+		}
+
+		// Component name: layer.kind.name
+		components = append(components, Component{
+			Name:    dir.layer + "." + dir.kind + "." + componentName,
+			Kind:    dir.kind,
+			Layer:   dir.layer,
+			Version: version,
+		})
+	}
+
+	return components
+}
@@ -0,0 +1,88 @@
+package component
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/launchrctl/compose/compose"
+	"github.com/plasmash/plasmactl-model/pkg/model"
+)
+
+// Sources of located components.
+const (
+	SourceDomain  = "src"     // Domain repository
+	SourcePackage = "package" // Compose package checkout
+	SourceBuild   = "build"   // Composed build only
+)
+
+// Location is the directory holding the sources of a component.
+type Location struct {
+	Component string `json:"component"`
+	Path      string `json:"path"`   // Absolute path of the component directory
+	Source    string `json:"source"` // One of SourceDomain, SourcePackage or SourceBuild
+	Package   string `json:"package,omitempty"`
+}
+
+// Locator resolves components to their source directories the way compose resolves them: the domain wins, then
+// later packages of plasma-compose.yaml. Components only found in the build are located there.
+type Locator struct {
+	roots []locatorRoot
+}
+
+type locatorRoot struct {
+	dir    string
+	source string
+	pkg    string
+}
+
+// NewLocator returns the locator of components of the domain directory, its compose packages and its build.
+func NewLocator(dir string) (*Locator, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Locator{roots: []locatorRoot{{dir: sourcesDir(abs), source: SourceDomain}}}
+	if plasmaCompose, errCompose := compose.Lookup(os.DirFS(abs)); errCompose == nil {
+		for i := len(plasmaCompose.Dependencies) - 1; i >= 0; i-- {
+			dep := plasmaCompose.Dependencies[i]
+			pkg := dep.ToPackage(dep.Name)
+			pkgDir := filepath.Join(abs, model.PackagesDir, pkg.GetName(), pkg.GetTarget())
+			l.roots = append(l.roots, locatorRoot{dir: sourcesDir(pkgDir), source: SourcePackage, pkg: dep.Name})
+		}
+	}
+	l.roots = append(l.roots, locatorRoot{dir: filepath.Join(abs, model.MergedSrcDir), source: SourceBuild})
+
+	return l, nil
+}
+
+// Locate returns the location of the component given in MRN format, nil if no source provides it.
+func (l *Locator) Locate(name string) *Location {
+	parts := strings.Split(name, ".")
+	if len(parts) != 3 {
+		return nil
+	}
+
+	for _, r := range l.roots {
+		for _, dir := range []string{
+			filepath.Join(r.dir, parts[0], parts[1], parts[2]),
+			filepath.Join(r.dir, parts[0], parts[1], "roles", parts[2]),
+		} {
+			if _, err := os.Stat(filepath.Join(dir, "meta", "plasma.yaml")); err == nil {
+				return &Location{Component: name, Path: dir, Source: r.source, Package: r.pkg}
+			}
+		}
+	}
+
+	return nil
+}
+
+// sourcesDir returns the components directory of a domain or package, its src/ directory if it exists,
+// like [SourcesFS].
+func sourcesDir(dir string) string {
+	if stat, err := os.Stat(filepath.Join(dir, "src")); err == nil && stat.IsDir() {
+		return filepath.Join(dir, "src")
+	}
+	return dir
+}
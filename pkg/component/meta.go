@@ -0,0 +1,96 @@
+package component
+
+import (
+	"errors"
+	"io/fs"
+	"runtime"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// plasmaMeta represents the structure of meta/plasma.yaml
+type plasmaMeta struct {
+	Plasma struct {
+		Version string `yaml:"version"`
+	} `yaml:"plasma"`
+}
+
+// readMeta reads the version from a component's meta/plasma.yaml file, and tells if the file exists.
+// A single read replaces a stat followed by a read.
+func readMeta(fsys fs.FS, metaPath string) (string, bool) {
+	data, err := fs.ReadFile(fsys, metaPath)
+	if err != nil {
+		return "", !errors.Is(err, fs.ErrNotExist)
+	}
+	var meta plasmaMeta
+	if err = yaml.Unmarshal(data, &meta); err != nil {
+		return "", true
+	}
+	return meta.Plasma.Version, true
+}
+
+// metaCache reads meta files shared by concurrent workers of a load, each file once.
+type metaCache struct {
+	fsys  fs.FS
+	mx    sync.Mutex
+	metas map[string]*cachedMeta
+}
+
+type cachedMeta struct {
+	once    sync.Once
+	version string
+	found   bool
+}
+
+func newMetaCache(fsys fs.FS) *metaCache {
+	return &metaCache{fsys: fsys, metas: make(map[string]*cachedMeta)}
+}
+
+// read returns the version of the meta file and whether it exists, reading it on first request.
+func (c *metaCache) read(metaPath string) (string, bool) {
+	c.mx.Lock()
+	m, ok := c.metas[metaPath]
+	if !ok {
+		m = &cachedMeta{}
+		c.metas[metaPath] = m
+	}
+	c.mx.Unlock()
+
+	m.once.Do(func() {
+		m.version, m.found = readMeta(c.fsys, metaPath)
+	})
+	return m.version, m.found
+}
+
+// readVersions sets versions of components from the first of their candidate meta files having one,
+// reading them with a pool of workers, runtime.NumCPU() if not positive.
+func readVersions(fsys fs.FS, components Components, candidates [][]string, workers int) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	cache := newMetaCache(fsys)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(workers, len(components)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				for _, metaPath := range candidates[i] {
+					if version, _ := cache.read(metaPath); version != "" {
+						components[i].Version = version
+						break
+					}
+				}
+			}
+		}()
+	}
+
+	for i := range components {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
@@ -0,0 +1,173 @@
+// Package extension provides the launchr service through which other plugins extend the component subsystem
+// with component kinds, lint rules, component:create templates and the platform graph builder.
+//
+// Plugins get the registry in OnAppInit and contribute to it before actions are discovered:
+//
+//	var ext *extension.Registry
+//	app.Services().Get(&ext)
+//	err := ext.AddKind("agents")
+package extension
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	async "sync"
+
+	"github.com/launchrctl/launchr"
+)
+
+// namePattern is the allowed name of kinds and lint rules.
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+// Issue is a finding reported by a contributed lint rule.
+type Issue struct {
+	Subject string
+	File    string
+	Line    int
+	Column  int
+	Message string
+}
+
+// LintRule is a lint rule contributed to component:lint.
+type LintRule struct {
+	// Name selects the rule with `component:lint --rule`, it must not be a built-in rule name.
+	Name string
+	// Check reports issues of components in the source directory.
+	Check func(source string) ([]Issue, error)
+}
+
+// GraphBuilder rebuilds the platform graph of the domain directory, after actions modified its components.
+type GraphBuilder func(ctx context.Context, dir string) error
+
+// Registry is a [launchr.Service] collecting extensions contributed by plugins.
+type Registry struct {
+	mx        async.RWMutex
+	kinds     map[string]struct{}
+	rules     []LintRule
+	templates map[string]fs.FS
+	graph     GraphBuilder
+}
+
+// New returns an empty [Registry].
+func New() *Registry {
+	return &Registry{
+		kinds:     make(map[string]struct{}),
+		templates: make(map[string]fs.FS),
+	}
+}
+
+// ServiceInfo implements [launchr.Service] interface.
+func (r *Registry) ServiceInfo() launchr.ServiceInfo {
+	return launchr.ServiceInfo{}
+}
+
+// ServiceCreate creates the registry on first request, so plugins get it regardless of their init order.
+func (r *Registry) ServiceCreate(_ *launchr.ServiceManager) launchr.Service {
+	return New()
+}
+
+// AddKind registers a component kind, its components are propagated by sync and accepted by convert-kind.
+func (r *Registry) AddKind(kind string) error {
+	if !namePattern.MatchString(kind) {
+		return fmt.Errorf("invalid component kind %q", kind)
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.kinds[kind] = struct{}{}
+	return nil
+}
+
+// Kinds returns registered component kinds, sorted.
+func (r *Registry) Kinds() []string {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	kinds := make([]string, 0, len(r.kinds))
+	for kind := range r.kinds {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	return kinds
+}
+
+// AddLintRule registers a lint rule, rule names are unique.
+func (r *Registry) AddLintRule(rule LintRule) error {
+	if !namePattern.MatchString(rule.Name) {
+		return fmt.Errorf("invalid lint rule name %q", rule.Name)
+	}
+	if rule.Check == nil {
+		return fmt.Errorf("lint rule %s has no check", rule.Name)
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	for _, existing := range r.rules {
+		if existing.Name == rule.Name {
+			return fmt.Errorf("lint rule %s is already registered", rule.Name)
+		}
+	}
+	r.rules = append(r.rules, rule)
+	return nil
+}
+
+// LintRules returns registered lint rules in order of registration.
+func (r *Registry) LintRules() []LintRule {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	return append([]LintRule(nil), r.rules...)
+}
+
+// AddTemplate registers the component:create template of a kind, "default" for kinds without own template.
+// Template files follow built-in templates conventions. A template registered again replaces the previous one.
+func (r *Registry) AddTemplate(kind string, fsys fs.FS) error {
+	if !namePattern.MatchString(kind) {
+		return fmt.Errorf("invalid template kind %q", kind)
+	}
+	if fsys == nil {
+		return fmt.Errorf("template %s has no files", kind)
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	r.templates[kind] = fsys
+	return nil
+}
+
+// Templates returns registered component:create templates by kind.
+func (r *Registry) Templates() map[string]fs.FS {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+
+	templates := make(map[string]fs.FS, len(r.templates))
+	for kind, fsys := range r.templates {
+		templates[kind] = fsys
+	}
+	return templates
+}
+
+// SetGraphBuilder registers the platform graph builder, e.g. by the plugin generating the graph. A single builder
+// is registered.
+func (r *Registry) SetGraphBuilder(build GraphBuilder) error {
+	if build == nil {
+		return fmt.Errorf("graph builder is nil")
+	}
+
+	r.mx.Lock()
+	defer r.mx.Unlock()
+	if r.graph != nil {
+		return fmt.Errorf("graph builder is already registered")
+	}
+	r.graph = build
+	return nil
+}
+
+// GraphBuilder returns the registered platform graph builder, nil if none.
+func (r *Registry) GraphBuilder() GraphBuilder {
+	r.mx.RLock()
+	defer r.mx.RUnlock()
+	return r.graph
+}
@@ -0,0 +1,63 @@
+package extension
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+)
+
+func TestRegistry(t *testing.T) {
+	r := New()
+
+	for _, kind := range []string{"agents", "datasets", "agents"} {
+		if err := r.AddKind(kind); err != nil {
+			t.Fatalf("add kind %s: %v", kind, err)
+		}
+	}
+	for _, kind := range []string{"", "Agents", "a.b", "a/b"} {
+		if err := r.AddKind(kind); err == nil {
+			t.Errorf("expected error for kind %q", kind)
+		}
+	}
+	if kinds := r.Kinds(); len(kinds) != 2 || kinds[0] != "agents" || kinds[1] != "datasets" {
+		t.Errorf("expected sorted unique kinds, got %v", kinds)
+	}
+
+	check := func(string) ([]Issue, error) { return nil, nil }
+	if err := r.AddLintRule(LintRule{Name: "owners", Check: check}); err != nil {
+		t.Fatalf("add lint rule: %v", err)
+	}
+	if err := r.AddLintRule(LintRule{Name: "owners", Check: check}); err == nil {
+		t.Error("expected error for duplicate lint rule")
+	}
+	if err := r.AddLintRule(LintRule{Name: "licenses"}); err == nil {
+		t.Error("expected error for lint rule without check")
+	}
+	if rules := r.LintRules(); len(rules) != 1 || rules[0].Name != "owners" {
+		t.Errorf("expected owners rule, got %+v", rules)
+	}
+
+	if err := r.AddTemplate("agents", fstest.MapFS{}); err != nil {
+		t.Fatalf("add template: %v", err)
+	}
+	if err := r.AddTemplate("agents", nil); err == nil {
+		t.Error("expected error for template without files")
+	}
+	if templates := r.Templates(); len(templates) != 1 || templates["agents"] == nil {
+		t.Errorf("expected agents template, got %v", templates)
+	}
+
+	if r.GraphBuilder() != nil {
+		t.Error("expected no graph builder")
+	}
+	build := func(context.Context, string) error { return nil }
+	if err := r.SetGraphBuilder(build); err != nil {
+		t.Fatalf("set graph builder: %v", err)
+	}
+	if err := r.SetGraphBuilder(build); err == nil {
+		t.Error("expected error for second graph builder")
+	}
+	if r.GraphBuilder() == nil {
+		t.Error("expected registered graph builder")
+	}
+}
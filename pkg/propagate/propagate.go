@@ -0,0 +1,191 @@
+// Package propagate exposes component version propagation, as done by component:sync, to other plugins.
+//
+// A [Planner] computes the version changes without modifying files, an [Applier] writes them to the build.
+// Planning and applying are separate, so callers can review, filter or persist the plan in between.
+package propagate
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/launchrctl/keyring"
+	"github.com/launchrctl/launchr"
+
+	syncaction "github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/internal/sync"
+)
+
+// Domain is an additional domain-level repository contributing components and variables.
+type Domain = syncaction.Domain
+
+// VersionFormat is the template of propagated versions, `{base}-{propagated}` by default.
+type VersionFormat = sync.VersionFormat
+
+// Intent is a component version change computed by propagation.
+type Intent struct {
+	Component  string   `json:"component"`
+	OldVersion string   `json:"old_version"`
+	NewVersion string   `json:"new_version"`
+	Commit     string   `json:"commit,omitempty"`
+	Message    string   `json:"message,omitempty"`
+	Changes    []string `json:"changes,omitempty"`
+	Via        []string `json:"via,omitempty"`
+}
+
+// Plan is the list of version changes to apply to the build, ordered by component name.
+type Plan struct {
+	BuildDir string   `json:"build_dir"`
+	Intents  []Intent `json:"intents"`
+	// MissingPackages lists compose dependencies ignored because their checkout is absent.
+	MissingPackages []string `json:"missing_packages,omitempty"`
+}
+
+// Planner computes propagation of component versions from git history of domains and packages.
+type Planner struct {
+	// Target dirs.
+	BuildDir    string
+	PackagesDir string
+	DomainDir   string
+	Domains     []Domain
+
+	// Keyring stores the vault password, optional: VaultPass is used as is without it.
+	Keyring   keyring.Keyring
+	VaultPass string
+
+	// Options, see component:sync.
+	FilterByComponentUsage bool
+	TimeDepth              string
+	AllowOverride          bool
+	SkipMissingPackages    bool
+	SkipBuildCheck         bool
+	Unshallow              bool
+	VersionFormat          VersionFormat
+	NoCache                bool
+	Only                   []string
+	OnlyVars               bool
+	OnlyComponents         bool
+	// Simulate pretends the components received a new version at HEAD.
+	Simulate []string
+	// ExtraKinds are component kinds propagated in addition to the built-in ones, e.g. registered in the extension
+	// registry.
+	ExtraKinds []string
+
+	// Logger and Term default to launchr ones.
+	Logger *launchr.Logger
+	Term   *launchr.Terminal
+}
+
+// Plan computes version changes without modifying files.
+func (p *Planner) Plan() (*Plan, error) {
+	s := &syncaction.Sync{
+		Keyring: p.Keyring,
+
+		BuildDir:    p.BuildDir,
+		PackagesDir: p.PackagesDir,
+		DomainDir:   p.DomainDir,
+		Domains:     p.Domains,
+
+		DryRun:                 true,
+		AllowOverride:          p.AllowOverride,
+		FilterByComponentUsage: p.FilterByComponentUsage,
+		TimeDepth:              p.TimeDepth,
+		VaultPass:              p.VaultPass,
+		Simulate:               p.Simulate,
+		Only:                   p.Only,
+		OnlyVars:               p.OnlyVars,
+		OnlyComponents:         p.OnlyComponents,
+		SkipMissingPackages:    p.SkipMissingPackages,
+		SkipBuildCheck:         p.SkipBuildCheck,
+		Unshallow:              p.Unshallow,
+		VersionFormat:          p.VersionFormat,
+		NoCache:                p.NoCache,
+		ExtraKinds:             p.ExtraKinds,
+	}
+	s.SetLogger(p.logger())
+	s.SetTerm(p.term())
+
+	if err := s.Execute(); err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{BuildDir: p.BuildDir, Intents: []Intent{}}
+	res, ok := s.Result().(*syncaction.SyncResult)
+	if !ok || res == nil {
+		return plan, nil
+	}
+
+	plan.MissingPackages = res.MissingPackages
+	for _, c := range res.Components {
+		plan.Intents = append(plan.Intents, Intent{
+			Component:  c.Name,
+			OldVersion: c.OldVersion,
+			NewVersion: c.NewVersion,
+			Commit:     c.Commit,
+			Message:    c.Message,
+			Changes:    c.Changes,
+			Via:        c.Via,
+		})
+	}
+	sort.Slice(plan.Intents, func(i, j int) bool {
+		return plan.Intents[i].Component < plan.Intents[j].Component
+	})
+
+	return plan, nil
+}
+
+func (p *Planner) logger() *launchr.Logger {
+	if p.Logger != nil {
+		return p.Logger
+	}
+	return launchr.Log()
+}
+
+func (p *Planner) term() *launchr.Terminal {
+	if p.Term != nil {
+		return p.Term
+	}
+	return launchr.Term()
+}
+
+// Applier writes version changes of a plan to the build.
+type Applier struct {
+	// DryRun reports intents which would be applied without writing files.
+	DryRun bool
+}
+
+// Apply writes the new version of each intent to the component meta file of the plan build dir.
+// A component which version isn't the intent old version anymore fails the apply, as the plan is outdated.
+// Versions are checked for all intents before any file is written. Applied intents are returned.
+func (a *Applier) Apply(plan *Plan) ([]Intent, error) {
+	components := make([]*sync.Component, 0, len(plan.Intents))
+	for _, intent := range plan.Intents {
+		c, err := sync.NewComponent(intent.Component, plan.BuildDir)
+		if err != nil {
+			return nil, err
+		}
+
+		version, _, err := c.GetVersion()
+		if err != nil {
+			return nil, err
+		}
+
+		if version != intent.OldVersion {
+			return nil, fmt.Errorf("plan is outdated: %s version is %s, expected %s", intent.Component, version, intent.OldVersion)
+		}
+		components = append(components, c)
+	}
+
+	if a.DryRun {
+		return plan.Intents, nil
+	}
+
+	var applied []Intent
+	for i, c := range components {
+		if _, err := c.UpdateVersion(plan.Intents[i].NewVersion); err != nil {
+			return applied, fmt.Errorf("apply %s > %w", c.GetName(), err)
+		}
+		applied = append(applied, plan.Intents[i])
+	}
+
+	return applied, nil
+}
@@ -0,0 +1,51 @@
+package propagate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeMeta(t *testing.T, buildDir, name, version string) string {
+	t.Helper()
+	path := filepath.Join(append([]string{buildDir}, append(strings.Split(name, "."), "meta", "plasma.yaml")...)...)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("plasma:\n  version: "+version+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestApply(t *testing.T) {
+	buildDir := t.TempDir()
+	path := writeMeta(t, buildDir, "foundation.applications.auth", "abc")
+	writeMeta(t, buildDir, "foundation.services.postgres", "def")
+
+	plan := &Plan{BuildDir: buildDir, Intents: []Intent{
+		{Component: "foundation.applications.auth", OldVersion: "abc", NewVersion: "abc-def"},
+	}}
+
+	applied, err := (&Applier{}).Apply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(applied) != 1 {
+		t.Fatalf("expected 1 applied intent, got %d", len(applied))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "version: abc-def") {
+		t.Errorf("expected version abc-def, got %s", data)
+	}
+
+	// Applying again fails, as the plan doesn't match the build anymore.
+	if _, err = (&Applier{}).Apply(plan); err == nil || !strings.Contains(err.Error(), "plan is outdated") {
+		t.Errorf("expected outdated plan error, got %v", err)
+	}
+}
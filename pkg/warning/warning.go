@@ -0,0 +1,67 @@
+// Package warning provides structured warnings of actions. Warnings printed to the terminal are reported in action
+// results as well, so JSON consumers don't lose them.
+package warning
+
+import "fmt"
+
+// Codes of warnings, shared by actions so consumers can filter them.
+const (
+	// Skipped is a component left out of the run, e.g. frozen, denied or of a kind not allowed to propagate.
+	Skipped = "skipped"
+	// Unchanged is an operation with nothing to do, e.g. a component already attached.
+	Unchanged = "unchanged"
+	// NotFound is a component or file referred to but missing.
+	NotFound = "not-found"
+	// Order is a role attached before its dependency.
+	Order = "order"
+	// Architecture is a dependency violating the architecture matrix.
+	Architecture = "architecture"
+	// Cycle is a chain of dependencies leading back to its first component.
+	Cycle = "cycle"
+	// StaleBuild is a build not matching its sources.
+	StaleBuild = "stale-build"
+	// StaleGraph is a platform graph of which components were modified since it was generated.
+	StaleGraph = "stale-graph"
+	// Partial is a run on incomplete input, e.g. missing packages, a shallow clone or encrypted vaults.
+	Partial = "partial"
+	// Overridden is a resource of which the build value overrides the sources.
+	Overridden = "overridden"
+	// Freeze is a freeze window overridden for a component.
+	Freeze = "freeze"
+	// Conflict is a component given different versions by several changes.
+	Conflict = "conflict"
+	// State is run state which couldn't be stored or was left by an interrupted run.
+	State = "state"
+	// Confirmation is an operation not run without confirmation.
+	Confirmation = "confirmation"
+	// ManualVersion is a version not set by a bump commit.
+	ManualVersion = "manual-version"
+	// Unsigned is a component without signature.
+	Unsigned = "unsigned"
+	// Empty is a run with nothing found.
+	Empty = "empty"
+	// Fallback is a source which is unavailable and replaced by a slower one, e.g. the platform graph.
+	Fallback = "fallback"
+	// Mismatch is a value read back not matching the written one.
+	Mismatch = "mismatch"
+	// Constraint is a dependency version not satisfying the version constraint of its dependent.
+	Constraint = "constraint"
+)
+
+// Warning is a warning of an action run.
+type Warning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Subject is what the warning is about, usually a component, empty for warnings of the whole run.
+	Subject string `json:"subject,omitempty"`
+}
+
+// List is the list of warnings of an action run.
+type List []Warning
+
+// Add appends the warning with the formatted message and returns the message, to print or log it.
+func (l *List) Add(code, subject, format string, a ...any) string {
+	msg := fmt.Sprintf(format, a...)
+	*l = append(*l, Warning{Code: code, Message: msg, Subject: subject})
+	return msg
+}
@@ -6,26 +6,82 @@ import (
 	"embed"
 	"fmt"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/launchrctl/keyring"
 	"github.com/launchrctl/launchr"
 	"github.com/launchrctl/launchr/pkg/action"
 	"github.com/plasmash/plasmactl-model/pkg/model"
+	"github.com/pterm/pterm"
 
 	"github.com/plasmash/plasmactl-component/actions/attach"
 	"github.com/plasmash/plasmactl-component/actions/bump"
 	"github.com/plasmash/plasmactl-component/actions/configure"
+	"github.com/plasmash/plasmactl-component/actions/convertkind"
+	"github.com/plasmash/plasmactl-component/actions/create"
 	"github.com/plasmash/plasmactl-component/actions/depend"
 	"github.com/plasmash/plasmactl-component/actions/detach"
+	"github.com/plasmash/plasmactl-component/actions/doctor"
+	"github.com/plasmash/plasmactl-component/actions/history"
+	"github.com/plasmash/plasmactl-component/actions/lint"
 	"github.com/plasmash/plasmactl-component/actions/list"
+	"github.com/plasmash/plasmactl-component/actions/manifest"
 	"github.com/plasmash/plasmactl-component/actions/query"
+	"github.com/plasmash/plasmactl-component/actions/release"
+	"github.com/plasmash/plasmactl-component/actions/setversion"
 	"github.com/plasmash/plasmactl-component/actions/show"
 	"github.com/plasmash/plasmactl-component/actions/sync"
+	"github.com/plasmash/plasmactl-component/actions/variables"
+	"github.com/plasmash/plasmactl-component/actions/verify"
+	"github.com/plasmash/plasmactl-component/internal/architecture"
+	"github.com/plasmash/plasmactl-component/internal/freeze"
+	"github.com/plasmash/plasmactl-component/internal/graphstate"
+	"github.com/plasmash/plasmactl-component/internal/notify"
+	"github.com/plasmash/plasmactl-component/internal/provenance"
+	"github.com/plasmash/plasmactl-component/internal/repository"
+	internalsync "github.com/plasmash/plasmactl-component/internal/sync"
+	"github.com/plasmash/plasmactl-component/internal/vaultpass"
+	"github.com/plasmash/plasmactl-component/pkg/component"
+	"github.com/plasmash/plasmactl-component/pkg/extension"
 )
 
 //go:embed actions/*/*.yaml
 var actionYamlFS embed.FS
 
+const configKey = "component"
+
+// pluginConfig is the plugin section of the launchr config.
+type pluginConfig struct {
+	// Domains are additional domain repositories contributing components and variables to sync.
+	Domains []sync.Domain `yaml:"domains"`
+	// Architecture declares which layer/kind combinations may depend on which others.
+	Architecture architecture.Matrix `yaml:"architecture"`
+	// Notify configures webhooks of teams owning changed components.
+	Notify notify.Config `yaml:"notify"`
+	// Docs declares documentation required by component kind.
+	Docs lint.DocsRules `yaml:"docs"`
+	// BumpAuthors are names or emails, or /regexp/, of past bump commit authors besides the bumper.
+	BumpAuthors []string `yaml:"bump_authors"`
+	// TemplateFilters are custom Jinja2 filters available to component templates, e.g. from collections.
+	TemplateFilters []string `yaml:"template_filters"`
+	// DeprecatedModules are Ansible modules component tasks must not use.
+	DeprecatedModules []string `yaml:"deprecated_modules"`
+	// VersionFormat is the template of propagated versions, `{base}-{propagated}` by default.
+	VersionFormat internalsync.VersionFormat `yaml:"version_format"`
+	// BumpCommit customizes the bump commit message, its trailers, sign-off and signing.
+	BumpCommit repository.BumpCommit `yaml:"bump_commit"`
+	// BumpHooks are commands run before and after the bump commit.
+	BumpHooks bump.Hooks `yaml:"bump_hooks"`
+	// Provenance configures signing of bumped components and keys trusted to verify them.
+	Provenance provenance.Config `yaml:"provenance"`
+	// FreezeWindows are periods during which sync doesn't propagate to components of their chassis scopes.
+	FreezeWindows freeze.Windows `yaml:"freeze_windows"`
+	// GraphRefresh is how the platform graph is refreshed after attach, detach, depend and bump modify components.
+	GraphRefresh graphstate.Mode `yaml:"graph_refresh"`
+}
+
 func init() {
 	launchr.RegisterPlugin(&Plugin{})
 }
@@ -34,6 +90,7 @@ func init() {
 type Plugin struct {
 	k   keyring.Keyring
 	cfg launchr.Config
+	ext *extension.Registry
 }
 
 // PluginInfo implements [launchr.Plugin] interface.
@@ -47,6 +104,8 @@ func (p *Plugin) PluginInfo() launchr.PluginInfo {
 func (p *Plugin) OnAppInit(app launchr.App) error {
 	app.Services().Get(&p.cfg)
 	app.Services().Get(&p.k)
+	// The extension registry is created on first request, by this plugin or by a plugin contributing to it.
+	app.Services().Get(&p.ext)
 	return nil
 }
 
@@ -55,18 +114,94 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 	// component:bump action
 	actionBumpYaml, _ := actionYamlFS.ReadFile("actions/bump/bump.yaml")
 	ba := action.NewFromYAML("component:bump", actionBumpYaml)
-	ba.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+	ba.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
 		input := a.Input()
 		dryRun := input.Opt("dry-run").(bool)
 		last := input.Opt("last").(bool)
 
-		log, _, _, term := getLogger(a)
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		signKey := input.Opt("sign-key").(string)
+		if signKey == "" {
+			signKey = cfg.Provenance.SigningKey
+		}
+
+		commit := cfg.BumpCommit
+		if message := input.Opt("message").(string); message != "" {
+			commit.Message = message
+		}
+		for _, t := range strings.Split(input.Opt("trailer").(string), ",") {
+			if t = strings.TrimSpace(t); t != "" {
+				commit.Trailers = append(commit.Trailers, t)
+			}
+		}
+		commit.Signoff = commit.Signoff || input.Opt("signoff").(bool)
+		commit.Sign = commit.Sign || input.Opt("sign").(bool)
+
+		hooks := cfg.BumpHooks
+		if input.Opt("no-hooks").(bool) {
+			hooks = bump.Hooks{}
+		}
+
+		refresh, err := graphRefreshMode(input, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		log, _, streams, term := getLogger(a)
 
-		b := &bump.Bump{Last: last, DryRun: dryRun}
+		since := input.Opt("since").(string)
+		if fromRef := input.Opt("from-ref").(string); fromRef != "" {
+			if since != "" && since != fromRef {
+				return nil, fmt.Errorf("--since and --from-ref can't be used together")
+			}
+			since = fromRef
+		}
+
+		b := &bump.Bump{
+			Streams: streams,
+
+			Last:          last,
+			DryRun:        dryRun,
+			Staged:        input.Opt("staged").(bool),
+			Amend:         input.Opt("amend").(bool),
+			Cascade:       input.Opt("cascade").(bool),
+			Since:         since,
+			SinceDate:     input.Opt("since-date").(string),
+			Scheme:        input.Opt("scheme").(string),
+			Level:         internalsync.VersionLevel(input.Opt("level").(string)),
+			Granularity:   bump.Granularity(input.Opt("granularity").(string)),
+			VersionFormat: cfg.VersionFormat,
+			SignKey:       signKey,
+			CommitMessage: commit,
+			Hooks:         hooks,
+			ExtraKinds:    p.ext.Kinds(),
+		}
 		b.SetLogger(log)
 		b.SetTerm(term)
-		err := b.Execute()
-		return b.Result(), err
+		err = b.Execute()
+		if err != nil {
+			return b.Result(), err
+		}
+
+		var changes []notify.Change
+		if res, ok := b.Result().(*bump.BumpResult); ok && res != nil {
+			changes = res.Changes()
+			if !dryRun {
+				bumped := make([]string, 0, len(res.Components))
+				for _, c := range res.Components {
+					bumped = append(bumped, c.Name)
+				}
+				if err = graphstate.Update(ctx, ".", refresh, graphstate.Builder(p.ext.GraphBuilder()), term, &res.Warnings, "bump", bumped...); err != nil {
+					return b.Result(), err
+				}
+			}
+		}
+
+		return b.Result(), notify.Notify(term, cfg.Notify, notify.OptionsFrom(input.Opt), dryRun, changes, ".")
 	}))
 
 	// component:sync action
@@ -76,9 +211,36 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 		input := a.Input()
 		dryRun := input.Opt("dry-run").(bool)
 		allowOverride := input.Opt("allow-override").(bool)
+		confirmOverrides := input.Opt("confirm-overrides").(bool)
 		filterByComponentUsage := input.Opt("chassis").(bool)
 		timeDepth := input.Opt("time-depth").(string)
-		vaultpass := input.Opt("vault-pass").(string)
+		vaultPass := input.Opt("vault-pass").(string)
+
+		var simulate []string
+		for _, mrn := range strings.Split(input.Opt("simulate").(string), ",") {
+			if mrn = strings.TrimSpace(mrn); mrn != "" {
+				simulate = append(simulate, mrn)
+			}
+		}
+
+		var only []string
+		for _, pattern := range strings.Split(input.Opt("only").(string), ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				only = append(only, pattern)
+			}
+		}
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		bumpAuthors := cfg.BumpAuthors
+		for _, author := range strings.Split(input.Opt("bump-authors").(string), ",") {
+			if author = strings.TrimSpace(author); author != "" {
+				bumpAuthors = append(bumpAuthors, author)
+			}
+		}
 
 		log, logLevel, streams, term := getLogger(a)
 		hideProgress := input.Opt("hide-progress").(bool)
@@ -91,6 +253,7 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 			Streams: streams,
 
 			DomainDir:   ".",
+			Domains:     cfg.Domains,
 			BuildDir:    model.MergedSrcDir,
 			PackagesDir: model.PackagesDir,
 
@@ -98,31 +261,83 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 			FilterByComponentUsage: filterByComponentUsage,
 			TimeDepth:              timeDepth,
 			AllowOverride:          allowOverride,
-			VaultPass:              vaultpass,
+			ConfirmOverrides:       confirmOverrides,
+			VaultPass:              vaultPass,
+			VaultPassProvider:      vaultpass.ProviderFrom(input.Opt, ""),
 			ShowProgress:           !hideProgress,
+			Simulate:               simulate,
+			Only:                   only,
+			FromManifest:           input.Opt("from-manifest").(string),
+			SkipMissingPackages:    input.Opt("skip-missing-packages").(bool),
+			SkipBuildCheck:         input.Opt("skip-build-check").(bool),
+			SkipConstraints:        input.Opt("skip-constraints").(bool),
+			Unshallow:              input.Opt("unshallow").(bool),
+			VersionFormat:          cfg.VersionFormat,
+			FromSources:            input.Opt("from-sources").(bool),
+			Concurrency:            input.Opt("concurrency").(int),
+			OnlyVars:               input.Opt("only-vars").(bool),
+			OnlyComponents:         input.Opt("only-components").(bool),
+			DiffLast:               input.Opt("diff-last").(bool),
+			BumpAuthors:            bumpAuthors,
+			Report:                 input.Opt("report").(string),
+			ReportFile:             input.Opt("report-file").(string),
+			Patch:                  input.Opt("patch").(string),
+			NoCache:                input.Opt("no-cache").(bool),
+			Undo:                   input.Opt("undo").(bool),
+			Resume:                 input.Opt("resume").(bool),
+			NoVerify:               input.Opt("no-verify").(bool),
+			Interactive:            input.Opt("interactive").(bool),
+			ConflictStrategy:       input.Opt("conflict-strategy").(string),
+			FreezeWindows:          cfg.FreezeWindows,
+			OverrideFreeze:         input.Opt("override-freeze").(bool),
+			Branch:                 input.Opt("branch").(string),
+			ExtraKinds:             p.ext.Kinds(),
 		}
 
 		s.SetLogger(log)
 		s.SetTerm(term)
-		err := s.Execute()
-		return s.Result(), err
+		err = s.Execute()
+		if err != nil {
+			return s.Result(), err
+		}
+
+		var changes []notify.Change
+		res, ok := s.Result().(*sync.SyncResult)
+		if ok && res != nil {
+			changes = res.Changes()
+		}
+
+		return s.Result(), notify.Notify(term, cfg.Notify, notify.OptionsFrom(input.Opt), ok && res != nil && res.DryRun, changes, model.MergedSrcDir, ".")
 	}))
 
 	// component:depend action
 	actionDependYaml, _ := actionYamlFS.ReadFile("actions/depend/depend.yaml")
 	da := action.NewFromYAML("component:depend", actionDependYaml)
-	da.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
-		log, _, _, term := getLogger(a)
+	da.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
+		log, _, streams, term := getLogger(a)
 
 		input := a.Input()
 		source := input.Opt("source").(string)
 		operations := action.InputArgSlice[string](input, "operations")
 
-		// Only validate source for show mode (no operations)
-		if len(operations) == 0 {
+		// The component of the current directory is resolved first, paths are relative to its platform root.
+		domainDir := "."
+		target, _ := input.Arg("target").(string)
+		if target == "." {
+			var err error
+			if target, domainDir, err = componentHere(p.ext.Kinds()); err != nil {
+				return nil, err
+			}
+			if !filepath.IsAbs(source) {
+				source = filepath.Join(domainDir, source)
+			}
+		}
+
+		// Only validate source for show mode (no operations), components are read from the commit with a ref
+		if len(operations) == 0 && input.Opt("ref").(string) == "" && input.Opt("from-file").(string) == "" && !input.Opt("remove-from-all").(bool) {
 			if _, err := os.Stat(source); os.IsNotExist(err) {
-				term.Warning().Printfln("%s doesn't exist, fallback to current dir", source)
-				source = "."
+				term.Warning().Printfln("%s doesn't exist, fallback to %s", source, domainDir)
+				source = domainDir
 			} else {
 				log.Debug("selected source", "path", source)
 			}
@@ -136,8 +351,23 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 		if depth == 0 {
 			return nil, fmt.Errorf("depth value should not be zero")
 		}
+		if depth < -1 {
+			return nil, fmt.Errorf("depth value should be positive, or -1 for unlimited")
+		}
+		collapse := input.Opt("collapse").(int)
+		if collapse < 0 {
+			return nil, fmt.Errorf("collapse value should not be negative")
+		}
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		refresh, err := graphRefreshMode(input, cfg)
+		if err != nil {
+			return nil, err
+		}
 
-		target := input.Arg("target").(string)
 		dep := &depend.Depend{
 			Target:     target,
 			Operations: operations,
@@ -147,11 +377,49 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 			Reverse:    showReverse,
 			Depth:      depth,
 			Build:      showBuild,
+			Origin:     input.Opt("origin").(bool),
+			Status:     input.Opt("status").(bool),
+			Collapse:   collapse,
+			Ref:        input.Opt("ref").(string),
+			Format:     input.Opt("format").(string),
+
+			ResolvePaths: input.Opt("resolve-paths").(bool),
+
+			DomainDir:   domainDir,
+			PackagesDir: filepath.Join(domainDir, model.PackagesDir),
+
+			Snapshot:      input.Opt("snapshot").(string),
+			CheckSnapshot: input.Opt("check-snapshot").(string),
+
+			Architecture:      cfg.Architecture,
+			CheckArchitecture: input.Opt("check-architecture").(bool),
+			CheckCycles:       input.Opt("check-cycles").(bool),
+			Why:               input.Opt("why").(string),
+			Force:             input.Opt("force").(bool),
+
+			FromFile: input.Opt("from-file").(string),
+			Streams:  streams,
+
+			ReverseEdit:   input.Opt("reverse-edit").(bool),
+			RemoveFromAll: input.Opt("remove-from-all").(bool),
 		}
 		dep.SetLogger(log)
 		dep.SetTerm(term)
-		err := dep.Execute()
-		return dep.Result(), err
+		if err = dep.Execute(); err != nil {
+			return dep.Result(), err
+		}
+
+		if res, ok := dep.Result().(*depend.DependResult); ok && res != nil && res.Mode == "operations" {
+			var modified []string
+			for _, op := range res.Operations {
+				if op.Applied && !slices.Contains(modified, op.Target) {
+					modified = append(modified, op.Target)
+				}
+			}
+			return dep.Result(), graphstate.Update(ctx, domainDir, refresh, graphstate.Builder(p.ext.GraphBuilder()), term, &res.Warnings, "depend", modified...)
+		}
+
+		return dep.Result(), nil
 	}))
 
 	// component:configure action (unified)
@@ -172,6 +440,8 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 		}
 
 		cfg := &configure.Configure{
+			Keyring: p.k,
+
 			Key:   key,
 			Value: value,
 
@@ -179,9 +449,20 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 			List:     input.Opt("list").(bool),
 			Validate: input.Opt("validate").(bool),
 			Generate: input.Opt("generate").(bool),
+			Rekey:    input.Opt("rekey").(bool),
+
+			Packages:    input.Opt("packages").(bool),
+			PackagesDir: model.PackagesDir,
+
+			VaultPass:            input.Opt("vault-pass").(string),
+			VaultPassProvider:    vaultpass.ProviderFrom(input.Opt, ""),
+			NewVaultPassProvider: vaultpass.ProviderFrom(input.Opt, "new-"),
 
 			At: input.Opt("at").(string),
 
+			Build:    input.Opt("build").(bool),
+			BuildDir: model.MergedSrcDir,
+
 			Vault:      input.Opt("vault").(bool),
 			Format:     input.Opt("format").(string),
 			Strict:     input.Opt("strict").(bool),
@@ -196,28 +477,54 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 	// component:attach action
 	actionAttachYaml, _ := actionYamlFS.ReadFile("actions/attach/attach.yaml")
 	aa := action.NewFromYAML("component:attach", actionAttachYaml)
-	aa.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+	aa.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
 		log, _, _, term := getLogger(a)
 		input := a.Input()
 
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		refresh, err := graphRefreshMode(input, cfg)
+		if err != nil {
+			return nil, err
+		}
+
 		att := &attach.Attach{
 			Component: input.Arg("component").(string),
 			Chassis:   input.Arg("chassis").(string),
 			Source:    input.Opt("source").(string),
+			Reorder:   input.Opt("reorder").(bool),
 		}
 		att.SetLogger(log)
 		att.SetTerm(term)
-		err := att.Execute()
-		return att.Result(), err
+		if err = att.Execute(); err != nil {
+			return att.Result(), err
+		}
+
+		if res, ok := att.Result().(*attach.AttachResult); ok && res != nil && (res.Attached || res.Reordered) {
+			return att.Result(), graphstate.Update(ctx, ".", refresh, graphstate.Builder(p.ext.GraphBuilder()), term, &res.Warnings, "attach", res.Component)
+		}
+
+		return att.Result(), nil
 	}))
 
 	// component:detach action
 	actionDetachYaml, _ := actionYamlFS.ReadFile("actions/detach/detach.yaml")
 	dta := action.NewFromYAML("component:detach", actionDetachYaml)
-	dta.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+	dta.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
 		log, _, _, term := getLogger(a)
 		input := a.Input()
 
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+		refresh, err := graphRefreshMode(input, cfg)
+		if err != nil {
+			return nil, err
+		}
+
 		det := &detach.Detach{
 			Component: input.Arg("component").(string),
 			Chassis:   input.Arg("chassis").(string),
@@ -225,20 +532,72 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 		}
 		det.SetLogger(log)
 		det.SetTerm(term)
-		err := det.Execute()
-		return det.Result(), err
+		if err = det.Execute(); err != nil {
+			return det.Result(), err
+		}
+
+		if res, ok := det.Result().(*detach.DetachResult); ok && res != nil && res.Detached {
+			return det.Result(), graphstate.Update(ctx, ".", refresh, graphstate.Builder(p.ext.GraphBuilder()), term, &res.Warnings, "detach", res.Component)
+		}
+
+		return det.Result(), nil
+	}))
+
+	// component:set-version action
+	actionSetVersionYaml, _ := actionYamlFS.ReadFile("actions/setversion/setversion.yaml")
+	sva := action.NewFromYAML("component:set-version", actionSetVersionYaml)
+	sva.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		var components []string
+		for _, name := range strings.Split(input.Opt("components").(string), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				components = append(components, name)
+			}
+		}
+
+		sv := &setversion.SetVersion{
+			Version:       input.Arg("version").(string),
+			Components:    components,
+			Kind:          input.Opt("kind").(string),
+			Layer:         input.Opt("layer").(string),
+			Source:        input.Opt("source").(string),
+			DryRun:        input.Opt("dry-run").(bool),
+			VersionFormat: cfg.VersionFormat,
+		}
+		sv.SetLogger(log)
+		sv.SetTerm(term)
+		err = sv.Execute()
+		return sv.Result(), err
 	}))
 
 	// component:query action
 	actionQueryYaml, _ := actionYamlFS.ReadFile("actions/query/query.yaml")
 	qa := action.NewFromYAML("component:query", actionQueryYaml)
-	qa.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
-		log, _, _, term := getLogger(a)
+	qa.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
+		log, logLevel, _, term := getLogger(a)
 		input := a.Input()
 
+		identifier := ""
+		if v := input.Arg("identifier"); v != nil {
+			identifier = v.(string)
+		}
+
 		q := &query.Query{
-			Identifier: input.Arg("identifier").(string),
+			Identifier: identifier,
 			Kind:       input.Opt("kind").(string),
+			NoInherit:  input.Opt("no-inherit").(bool),
+			Report:     input.Opt("chassis-report").(bool),
+			Context:    ctx,
+			Progress:   loadProgress(logLevel > 0),
+
+			ResolvePaths: input.Opt("resolve-paths").(bool),
 		}
 		q.SetLogger(log)
 		q.SetTerm(term)
@@ -249,15 +608,25 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 	// component:list action
 	actionListYaml, _ := actionYamlFS.ReadFile("actions/list/list.yaml")
 	la := action.NewFromYAML("component:list", actionListYaml)
-	la.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
-		log, _, _, term := getLogger(a)
+	la.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
+		log, logLevel, _, term := getLogger(a)
 		input := a.Input()
 
 		l := &list.List{
-			Tree:    input.Opt("tree").(bool),
-			Kind:    input.Opt("kind").(string),
-			All:     input.Opt("all").(bool),
-			Orphans: input.Opt("orphans").(bool),
+			Tree:      input.Opt("tree").(bool),
+			Kind:      input.Opt("kind").(string),
+			All:       input.Opt("all").(bool),
+			Orphans:   input.Opt("orphans").(bool),
+			Chassis:   input.Opt("chassis").(string),
+			NoInherit: input.Opt("no-inherit").(bool),
+
+			ChangedSince:       input.Opt("changed-since").(string),
+			InvalidAttachments: input.Opt("invalid-attachments").(bool),
+			Ref:                input.Opt("ref").(string),
+			ResolvePaths:       input.Opt("resolve-paths").(bool),
+
+			Context:  ctx,
+			Progress: loadProgress(logLevel > 0),
 		}
 		l.SetLogger(log)
 		l.SetTerm(term)
@@ -268,17 +637,29 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 	// component:show action
 	actionShowYaml, _ := actionYamlFS.ReadFile("actions/show/show.yaml")
 	sha := action.NewFromYAML("component:show", actionShowYaml)
-	sha.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
-		log, _, _, term := getLogger(a)
+	sha.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
+		log, logLevel, _, term := getLogger(a)
 		input := a.Input()
 
 		comp := ""
 		if v := input.Arg("component"); v != nil {
 			comp = v.(string)
 		}
+		domainDir := "."
+		if input.Opt("here").(bool) || comp == "." {
+			var err error
+			if comp, domainDir, err = componentHere(p.ext.Kinds()); err != nil {
+				return nil, err
+			}
+		}
 
 		sh := &show.Show{
-			Component: comp,
+			Component:    comp,
+			DomainDir:    domainDir,
+			Ref:          input.Opt("ref").(string),
+			ResolvePaths: input.Opt("resolve-paths").(bool),
+			Context:      ctx,
+			Progress:     loadProgress(logLevel > 0),
 		}
 		sh.SetLogger(log)
 		sh.SetTerm(term)
@@ -286,7 +667,419 @@ func (p *Plugin) DiscoverActions(_ context.Context) ([]*action.Action, error) {
 		return sh.Result(), err
 	}))
 
-	return []*action.Action{ba, sa, da, ca, aa, dta, qa, la, sha}, nil
+	// component:lint action
+	actionLintYaml, _ := actionYamlFS.ReadFile("actions/lint/lint.yaml")
+	lta := action.NewFromYAML("component:lint", actionLintYaml)
+	lta.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		bumpAuthors := cfg.BumpAuthors
+		for _, author := range strings.Split(input.Opt("bump-authors").(string), ",") {
+			if author = strings.TrimSpace(author); author != "" {
+				bumpAuthors = append(bumpAuthors, author)
+			}
+		}
+
+		var extensionRules []string
+		for _, name := range strings.Split(input.Opt("rule").(string), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				extensionRules = append(extensionRules, name)
+			}
+		}
+
+		lt := &lint.Lint{
+			Source:         input.Opt("source").(string),
+			ManualVersions: input.Opt("manual-versions").(bool),
+			Architecture:   input.Opt("architecture").(bool),
+			YAML:           input.Opt("yaml").(bool),
+			Attachments:    input.Opt("attachments").(bool),
+			Docs:           input.Opt("docs").(bool),
+			Secrets:        input.Opt("secrets").(bool),
+			Templates:      input.Opt("templates").(bool),
+			Tasks:          input.Opt("tasks").(bool),
+			Versions:       input.Opt("versions").(bool),
+			ExtensionRules: extensionRules,
+			Fix:            input.Opt("fix").(bool),
+			Baseline:       input.Opt("baseline").(string),
+			WriteBaseline:  input.Opt("write-baseline").(bool),
+
+			Extensions:        p.ext.LintRules(),
+			Matrix:            cfg.Architecture,
+			DocsRules:         cfg.Docs,
+			TemplateFilters:   cfg.TemplateFilters,
+			DeprecatedModules: cfg.DeprecatedModules,
+			VersionFormat:     cfg.VersionFormat,
+			BumpAuthors:       bumpAuthors,
+		}
+		lt.SetLogger(log)
+		lt.SetTerm(term)
+		err = lt.Execute()
+		return lt.Result(), err
+	}))
+
+	// component:variables action
+	actionVariablesYaml, _ := actionYamlFS.ReadFile("actions/variables/variables.yaml")
+	va := action.NewFromYAML("component:variables", actionVariablesYaml)
+	va.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		v := &variables.Variables{
+			Keyring:           p.k,
+			Command:           action.InputArgSlice[string](input, "command"),
+			DryRun:            input.Opt("dry-run").(bool),
+			Source:            input.Opt("source").(string),
+			Filter:            input.Opt("filter").(string),
+			Unused:            input.Opt("unused").(bool),
+			VaultPass:         input.Opt("vault-pass").(string),
+			VaultPassProvider: vaultpass.ProviderFrom(input.Opt, ""),
+		}
+		v.SetLogger(log)
+		v.SetTerm(term)
+		err := v.Execute()
+		return v.Result(), err
+	}))
+
+	// component:release-manifest action
+	actionManifestYaml, _ := actionYamlFS.ReadFile("actions/manifest/manifest.yaml")
+	ma := action.NewFromYAML("component:release-manifest", actionManifestYaml)
+	ma.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		rm := &manifest.ReleaseManifest{
+			Manifests:     action.InputArgSlice[string](input, "manifests"),
+			BuildDir:      model.MergedSrcDir,
+			PackagesDir:   model.PackagesDir,
+			DomainDir:     ".",
+			Ref:           input.Opt("ref").(string),
+			Output:        input.Opt("output").(string),
+			Diff:          input.Opt("diff").(bool),
+			VersionFormat: cfg.VersionFormat,
+		}
+		rm.SetLogger(log)
+		rm.SetTerm(term)
+		err = rm.Execute()
+		return rm.Result(), err
+	}))
+
+	// component:release action
+	actionReleaseYaml, _ := actionYamlFS.ReadFile("actions/release/release.yaml")
+	ra := action.NewFromYAML("component:release", actionReleaseYaml)
+	ra.SetRuntime(action.NewFnRuntimeWithResult(func(ctx context.Context, a *action.Action) (any, error) {
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		log, logLevel, streams, term := getLogger(a)
+		hideProgress := input.Opt("hide-progress").(bool)
+		if logLevel > 0 {
+			hideProgress = true
+		}
+
+		hooks := cfg.BumpHooks
+		if input.Opt("no-hooks").(bool) {
+			hooks = bump.Hooks{}
+		}
+
+		refresh, err := graphRefreshMode(input, cfg)
+		if err != nil {
+			return nil, err
+		}
+
+		r := &release.Release{
+			Keyring: p.k,
+			Streams: streams,
+
+			DomainDir:   ".",
+			Domains:     cfg.Domains,
+			BuildDir:    model.MergedSrcDir,
+			PackagesDir: model.PackagesDir,
+
+			Last:                   input.Opt("last").(bool),
+			DryRun:                 input.Opt("dry-run").(bool),
+			Yes:                    input.Opt("yes").(bool),
+			AllowOverride:          input.Opt("allow-override").(bool),
+			SkipMissingPackages:    input.Opt("skip-missing-packages").(bool),
+			SkipBuildCheck:         input.Opt("skip-build-check").(bool),
+			SkipConstraints:        input.Opt("skip-constraints").(bool),
+			Unshallow:              input.Opt("unshallow").(bool),
+			VersionFormat:          cfg.VersionFormat,
+			BumpAuthors:            cfg.BumpAuthors,
+			SignKey:                cfg.Provenance.SigningKey,
+			BumpCommit:             cfg.BumpCommit,
+			Hooks:                  hooks,
+			FreezeWindows:          cfg.FreezeWindows,
+			FilterByComponentUsage: input.Opt("chassis").(bool),
+			TimeDepth:              input.Opt("time-depth").(string),
+			VaultPass:              input.Opt("vault-pass").(string),
+			VaultPassProvider:      vaultpass.ProviderFrom(input.Opt, ""),
+			ShowProgress:           !hideProgress,
+			ExtraKinds:             p.ext.Kinds(),
+		}
+		r.SetLogger(log)
+		r.SetTerm(term)
+		err = r.Execute()
+		if err != nil {
+			return r.Result(), err
+		}
+
+		var changes []notify.Change
+		res, ok := r.Result().(*release.ReleaseResult)
+		if ok && res != nil {
+			changes = res.Changes()
+			if !res.DryRun {
+				modified := make([]string, 0, len(res.Bumped)+len(res.Synced))
+				for _, c := range res.Bumped {
+					modified = append(modified, c.Name)
+				}
+				for _, c := range res.Synced {
+					modified = append(modified, c.Name)
+				}
+				if err = graphstate.Update(ctx, ".", refresh, graphstate.Builder(p.ext.GraphBuilder()), term, &res.Warnings, "release", modified...); err != nil {
+					return r.Result(), err
+				}
+			}
+		}
+
+		return r.Result(), notify.Notify(term, cfg.Notify, notify.OptionsFrom(input.Opt), ok && res != nil && res.DryRun, changes, model.MergedSrcDir, ".")
+	}))
+
+	// component:create action
+	actionCreateYaml, _ := actionYamlFS.ReadFile("actions/create/create.yaml")
+	cra := action.NewFromYAML("component:create", actionCreateYaml)
+	cra.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+
+		input := a.Input()
+		c := &create.Create{
+			Component:    input.Arg("component").(string),
+			Owner:        input.Opt("owner").(string),
+			Source:       input.Opt("source").(string),
+			TemplatesDir: input.Opt("templates").(string),
+			DryRun:       input.Opt("dry-run").(bool),
+			Templates:    p.ext.Templates(),
+		}
+		c.SetLogger(log)
+		c.SetTerm(term)
+		err := c.Execute()
+		return c.Result(), err
+	}))
+
+	// component:convert-kind action
+	actionConvertKindYaml, _ := actionYamlFS.ReadFile("actions/convertkind/convertkind.yaml")
+	cka := action.NewFromYAML("component:convert-kind", actionConvertKindYaml)
+	cka.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+
+		input := a.Input()
+		ck := &convertkind.ConvertKind{
+			Component:  input.Arg("component").(string),
+			Kind:       input.Arg("kind").(string),
+			Source:     input.Opt("source").(string),
+			DryRun:     input.Opt("dry-run").(bool),
+			ExtraKinds: p.ext.Kinds(),
+		}
+		ck.SetLogger(log)
+		ck.SetTerm(term)
+		err := ck.Execute()
+		return ck.Result(), err
+	}))
+
+	// component:verify action
+	actionVerifyYaml, _ := actionYamlFS.ReadFile("actions/verify/verify.yaml")
+	vfa := action.NewFromYAML("component:verify", actionVerifyYaml)
+	vfa.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		publicKeys := cfg.Provenance.PublicKeys
+		if keys := input.Opt("public-key").(string); keys != "" {
+			publicKeys = nil
+			for _, key := range strings.Split(keys, ",") {
+				if key = strings.TrimSpace(key); key != "" {
+					publicKeys = append(publicKeys, key)
+				}
+			}
+		}
+
+		var components []string
+		for _, name := range strings.Split(input.Opt("components").(string), ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				components = append(components, name)
+			}
+		}
+
+		v := &verify.Verify{
+			Provenance:       input.Opt("provenance").(bool),
+			PublicKeys:       publicKeys,
+			RequireSignature: input.Opt("require-signature").(bool),
+			Components:       components,
+			Source:           input.Opt("source").(string),
+		}
+		v.SetLogger(log)
+		v.SetTerm(term)
+		err = v.Execute()
+		return v.Result(), err
+	}))
+
+	// component:doctor action
+	actionDoctorYaml, _ := actionYamlFS.ReadFile("actions/doctor/doctor.yaml")
+	dra := action.NewFromYAML("component:doctor", actionDoctorYaml)
+	dra.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		dr := &doctor.Doctor{
+			Keyring: p.k,
+
+			DomainDir:   ".",
+			BuildDir:    model.MergedSrcDir,
+			PackagesDir: model.PackagesDir,
+
+			VaultPass:         input.Opt("vault-pass").(string),
+			VaultPassProvider: vaultpass.ProviderFrom(input.Opt, ""),
+			VersionFormat:     cfg.VersionFormat,
+		}
+		dr.SetLogger(log)
+		dr.SetTerm(term)
+		err = dr.Execute()
+		return dr.Result(), err
+	}))
+
+	// component:history action
+	actionHistoryYaml, _ := actionYamlFS.ReadFile("actions/history/history.yaml")
+	hsa := action.NewFromYAML("component:history", actionHistoryYaml)
+	hsa.SetRuntime(action.NewFnRuntimeWithResult(func(_ context.Context, a *action.Action) (any, error) {
+		log, _, _, term := getLogger(a)
+		input := a.Input()
+
+		cfg, err := p.loadConfig()
+		if err != nil {
+			return nil, err
+		}
+
+		bumpAuthors := cfg.BumpAuthors
+		for _, author := range strings.Split(input.Opt("bump-authors").(string), ",") {
+			if author = strings.TrimSpace(author); author != "" {
+				bumpAuthors = append(bumpAuthors, author)
+			}
+		}
+
+		h := &history.History{
+			At:            input.Opt("at").(string),
+			Components:    action.InputArgSlice[string](input, "components"),
+			BumpAuthors:   bumpAuthors,
+			VersionFormat: cfg.VersionFormat,
+		}
+		h.SetLogger(log)
+		h.SetTerm(term)
+		err = h.Execute()
+		return h.Result(), err
+	}))
+
+	return []*action.Action{ba, sa, da, ca, aa, dta, sva, qa, la, sha, lta, va, ma, ra, cra, cka, vfa, dra, hsa}, nil
+}
+
+// loadConfig reads and validates the plugin section of the launchr config.
+func (p *Plugin) loadConfig() (pluginConfig, error) {
+	var cfg pluginConfig
+	if err := p.cfg.Get(configKey, &cfg); err != nil {
+		return cfg, fmt.Errorf("can't read %s config > %w", configKey, err)
+	}
+
+	if err := cfg.Architecture.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid %s.architecture config > %w", configKey, err)
+	}
+
+	if err := cfg.VersionFormat.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid %s.version_format config > %w", configKey, err)
+	}
+
+	if err := cfg.FreezeWindows.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid %s.freeze_windows config > %w", configKey, err)
+	}
+
+	if err := cfg.GraphRefresh.Validate(); err != nil {
+		return cfg, fmt.Errorf("invalid %s.graph_refresh config > %w", configKey, err)
+	}
+
+	return cfg, nil
+}
+
+// graphRefreshMode returns the platform graph refresh mode of the --graph-refresh option, the configured one by default.
+func graphRefreshMode(input *action.Input, cfg pluginConfig) (graphstate.Mode, error) {
+	mode := graphstate.Mode(input.Opt("graph-refresh").(string))
+	if mode == "" {
+		return cfg.GraphRefresh, nil
+	}
+
+	return mode, mode.Validate()
+}
+
+// loadProgress returns progress of components loaded from filesystem, printed to stderr unless hidden.
+func loadProgress(hide bool) component.Progress {
+	if hide {
+		return nil
+	}
+
+	var pb *pterm.ProgressbarPrinter
+	return func(done, total int) {
+		if pb == nil {
+			pb, _ = pterm.DefaultProgressbar.WithTotal(total).WithTitle("Loading components").WithWriter(os.Stderr).WithRemoveWhenDone(true).Start()
+		}
+		pb.Add(done - pb.Current)
+		if done == total {
+			_, _ = pb.Stop()
+		}
+	}
+}
+
+// componentHere returns the name of the component the working directory belongs to and its platform root, the
+// closest parent with a git repository, to resolve paths of actions from. Components of extra kinds are found as well.
+func componentHere(extraKinds []string) (string, string, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return "", "", err
+	}
+
+	c, source := internalsync.ComponentAt(wd, extraKinds...)
+	if c == nil {
+		return "", "", fmt.Errorf("current directory %s doesn't belong to a component", wd)
+	}
+
+	for root := source; ; root = filepath.Dir(root) {
+		if _, err = os.Stat(filepath.Join(root, ".git")); err == nil {
+			return c.GetName(), root, nil
+		}
+		if filepath.Dir(root) == root {
+			return c.GetName(), source, nil
+		}
+	}
 }
 
 func getLogger(a *action.Action) (*launchr.Logger, launchr.LogLevel, launchr.Streams, *launchr.Terminal) {